@@ -7,7 +7,14 @@
 
 package main
 
-import "github.com/atc0005/go-nagios"
+import (
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+)
+
+const maintenanceModeAdvice string = "The Red Hat Satellite server reported that it is in maintenance mode (e.g., enabled via foreman-maintain during a patch window). Sync plans will be evaluated once maintenance mode is disabled."
+
+const authenticationFailedAdvice string = "The Red Hat Satellite server rejected the provided credentials or the user lacks permission to view organizations and sync plans; the request was not retried to avoid account lockout. Verify the username and password and the roles assigned to the user."
 
 // annotateError is a helper function used to add additional human-readable
 // explanation for errors encountered during plugin execution. We first apply
@@ -27,6 +34,8 @@ func annotateErrors(plugin *nagios.Plugin) {
 
 	// Override specific error with project-specific feedback.
 	// errorAdviceMap[syscall.ECONNRESET] = connectionResetByPeerAdvice
+	errorAdviceMap[rsat.ErrAuthenticationFailed] = authenticationFailedAdvice
+	errorAdviceMap[rsat.ErrMaintenanceMode] = maintenanceModeAdvice
 
 	// Apply error advice annotations.
 	plugin.AnnotateRecordedErrors(errorAdviceMap)
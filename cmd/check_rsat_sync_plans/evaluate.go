@@ -0,0 +1,496 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/netutils"
+	"github.com/atc0005/check-rsat/internal/reports"
+	"github.com/atc0005/check-rsat/internal/retrieval"
+	"github.com/atc0005/check-rsat/internal/rsat"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// serverEvaluation is the result of evaluating the sync plans for a single
+// Red Hat Satellite server.
+type serverEvaluation struct {
+	// cfg is the configuration used to evaluate the server.
+	cfg *config.Config
+
+	// stateLabel is the service state for the server.
+	stateLabel string
+
+	// message is the one-line summary of the evaluation results.
+	message string
+
+	// report is the detailed evaluation report.
+	report string
+
+	// err is the error (if any) which prevented evaluation of the server.
+	err error
+
+	// orgs is the collection of organizations evaluated.
+	orgs rsat.Organizations
+
+	// perfData is the collection of performance data metrics for the
+	// server.
+	perfData []nagios.PerformanceData
+
+	// evalTime is the evaluation reference time. This value is zero if the
+	// sync plans for the server were not evaluated.
+	evalTime time.Time
+
+	// payloads is the collection of machine-readable (JSON) reports (one per
+	// server evaluated) embedded in the plugin output if requested.
+	payloads []json.RawMessage
+}
+
+// evaluateServer retrieves and evaluates the sync plans for the Red Hat
+// Satellite server specified by the given configuration. If specified,
+// lastRun is the time of the previous plugin execution.
+func evaluateServer(ctx context.Context, cfg *config.Config, lastRun time.Time) serverEvaluation {
+	result := serverEvaluation{cfg: cfg}
+
+	logger := cfg.Log.With().
+		Str("server", cfg.Server).
+		Str("user", cfg.Username).
+		Int("port", cfg.TCPPort).
+		Str("net_type", cfg.NetworkType).
+		Str("timeout", cfg.Timeout().String()).
+		Bool("cert-validation-disabled", cfg.TrustCert).
+		Bool("ca-cert-specified", cfg.CACertificate != "").
+		Bool("client-cert-specified", cfg.ClientCertificate != "").
+		Bool("permit-tls-renegotiation", cfg.PermitTLSRenegotiation).
+		Logger()
+
+	logger.Debug().Msg("Beginning server evaluation")
+
+	// If specified, attempt to load the CA certificate associated with the
+	// Red Hat Satellite server's certificate chain.
+	var caCert []byte
+	if cfg.CACertificate != "" {
+		logger.Debug().Msg("CA Cert specified: attempting to load CA cert")
+
+		var readErr error
+		caCert, readErr = os.ReadFile(cfg.CACertificate)
+		if readErr != nil {
+			result.stateLabel = nagios.StateUNKNOWNLabel
+			result.message = "Error loading CA certificate for Red Hat Satellite instance"
+			result.err = readErr
+
+			return result
+		}
+
+		logger.Debug().Msg("Successfully loaded CA cert")
+	}
+
+	// If specified, attempt to load the client certificate presented to
+	// servers requiring mutual TLS authentication.
+	var clientCert *tls.Certificate
+	if cfg.ClientCertificate != "" {
+		logger.Debug().Msg("Client cert specified: attempting to load client cert and key")
+
+		cert, loadErr := tls.LoadX509KeyPair(cfg.ClientCertificate, cfg.ClientKey)
+		if loadErr != nil {
+			result.stateLabel = nagios.StateUNKNOWNLabel
+			result.message = "Error loading client certificate for Red Hat Satellite instance"
+			result.err = loadErr
+
+			return result
+		}
+		clientCert = &cert
+
+		logger.Debug().Msg("Successfully loaded client cert and key")
+	}
+
+	authInfo := rsat.APIAuthInfo{
+		Server:                 cfg.Server,
+		Port:                   cfg.TCPPort,
+		NetworkType:            cfg.NetworkType,
+		DNSServer:              cfg.DNSServer,
+		DNSTimeout:             cfg.DNSTimeout(),
+		ReadLimit:              cfg.ReadLimit,
+		Username:               cfg.Username,
+		Password:               cfg.Password,
+		Token:                  cfg.Token,
+		OAuthConsumerKey:       cfg.OAuthConsumerKey,
+		OAuthConsumerSecret:    cfg.OAuthConsumerSecret,
+		ResponseCacheDir:       cfg.CacheDir,
+		UserAgent:              cfg.UserAgent(),
+		TrustCert:              cfg.TrustCert,
+		PermitTLSRenegotiation: cfg.PermitTLSRenegotiation,
+		CACert:                 caCert,
+		ClientCertificate:      clientCert,
+	}
+
+	apiLimits := rsat.APILimits{
+		PerPage:             cfg.PerPageLimit(),
+		PerPageAuto:         cfg.PerPageAuto(),
+		MaxConcurrent:       cfg.MaxConcurrent,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout(),
+		DialTimeout:         cfg.DialTimeout(),
+		DialKeepAlive:       cfg.DialKeepAlive(),
+		EnableHTTP2:         cfg.EnableHTTP2,
+		StreamDecode:        cfg.StreamDecode,
+		BulkSyncPlans:       cfg.BulkSyncPlans,
+		Retry: rsat.RetrySettings{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay(),
+			Jitter:      cfg.RetryJitter,
+		},
+	}
+
+	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+
+	if cfg.DryRun {
+		return probeServer(ctx, client, result, logger)
+	}
+
+	retrievalStart := time.Now()
+	orgs, orgsSkipped, orgsFetchErr := retrieval.GetOrgsWithSyncPlans(ctx, client, cfg, logger, zerolog.DebugLevel)
+
+	// Record the retrieval time for inclusion in report summaries.
+	cfg.RetrievalTime = time.Since(retrievalStart)
+
+	// Note the time spent on each API endpoint regardless of whether
+	// retrieval succeeded to help diagnose slow service checks.
+	defer client.LogMetrics()
+
+	if orgsFetchErr == nil && cfg.CheckRecurringLogic {
+		logics, logicsErr := rsat.GetRecurringLogics(ctx, client, rsat.QueryOptions{})
+		switch {
+		case logicsErr != nil:
+			orgsFetchErr = fmt.Errorf("failed to retrieve recurring logics: %w", logicsErr)
+		default:
+			orgs.SetRecurringLogics(logics)
+		}
+	}
+
+	if orgsFetchErr == nil && cfg.OwnerOrgParameter != "" {
+		orgsFetchErr = rsat.SetOrgOwners(ctx, client, orgs, cfg.OwnerOrgParameter)
+	}
+
+	result.orgs = orgs
+
+	if orgsFetchErr != nil {
+		result.stateLabel, result.message, result.report = retrievalFailure(orgsFetchErr, cfg, logger)
+		result.err = orgsFetchErr
+
+		return result
+	}
+
+	logger.Debug().
+		Int("orgs", orgs.NumOrgs()).
+		Int("sync_plans", orgs.NumPlans()).
+		Msg("Retrieved sync plans")
+
+	orgs.SetProductSyncStateEvaluation(cfg.EvaluateProductSyncState)
+	orgs.SetMaxProductSyncAge(cfg.MaxProductSyncAge.Multiplier(), cfg.MaxProductSyncAge.Duration())
+	orgs.SetMaxIntervalDrift(cfg.MaxIntervalDrift)
+	orgs.SetSyncPlanOwners(cfg.OwnerRegexp())
+
+	// Note sync plans created or modified since the previous execution so
+	// that new problems can be correlated with recent changes.
+	if cfg.StateFile != "" {
+		orgs.SetLastRun(lastRun)
+	}
+
+	skipped := orgsSkipped
+
+	// Note optional capabilities which were requested but disabled in order
+	// to limit API calls to organization and sync plan listing endpoints.
+	for _, flagName := range cfg.BasicModeSkipped() {
+		skipped.Add(
+			rsat.SkippedItemTypeCapability,
+			flagName,
+			rsat.SkipRuleBasicMode,
+			"requires API access beyond listing organizations and sync plans",
+		)
+	}
+
+	// Honor requests from Red Hat Satellite admins to exclude specific
+	// organizations or sync plans from monitoring unless overridden.
+	if !cfg.IgnoreSuppressionTags {
+		var suppressed rsat.SkippedItems
+		orgs, suppressed = orgs.ApplySuppressionTags()
+		skipped = append(skipped, suppressed...)
+	}
+
+	skipped = append(skipped, orgs.IgnoreSyncPlans(cfg.IgnorePlans)...)
+	skipped = append(skipped, orgs.ExcludeProducts(cfg.ExcludeProducts)...)
+
+	for _, item := range skipped {
+		logger.Debug().
+			Str("type", item.Type).
+			Str("item", item.Item).
+			Str("rule", item.Rule).
+			Str("reason", item.Reason).
+			Msg("Skipped item evaluation")
+	}
+
+	result.orgs = orgs
+
+	// Use a single evaluation reference time for all sync plans so that
+	// results are consistent across performance data and report output.
+	evalTime := time.Now()
+	result.evalTime = evalTime
+
+	result.perfData = getPerfData(orgs, evalTime, cfg)
+	result.perfData = append(
+		result.perfData,
+		getQueryPerfData(client, cfg.PerfDataLabelPrefix())...,
+	)
+
+	if cfg.APIPerfData {
+		result.perfData = append(
+			result.perfData,
+			getAPIPerfData(client.Metrics(), cfg.PerfDataLabelPrefix())...,
+		)
+	}
+
+	if cfg.EmitPayload {
+		payload, err := reports.SyncPlansJSONPayload(orgs, skipped, cfg, evalTime)
+		switch {
+		case err != nil:
+			logger.Warn().Err(err).Msg("Failed to encode sync plans payload")
+		default:
+			result.payloads = append(result.payloads, payload)
+		}
+	}
+
+	result.report = syncPlansReport(orgs, cfg, evalTime, logger)
+
+	// List only problem sync plans if the full report would exceed the
+	// user-specified output size limit so that problem entries are preserved.
+	if cfg.MaxOutputBytes > 0 && len(result.report) > cfg.MaxOutputBytes && !cfg.OmitOKSyncPlans {
+		logger.Debug().
+			Int("report_size", len(result.report)).
+			Int("max_output_bytes", cfg.MaxOutputBytes).
+			Msg("Report exceeds output size limit; omitting OK sync plans")
+
+		problemsCfg := *cfg
+		problemsCfg.OmitOKSyncPlans = true
+		result.report = syncPlansReport(orgs, &problemsCfg, evalTime, logger)
+	}
+
+	if !cfg.CompactOutput {
+		// Provide details for items intentionally not evaluated so that
+		// sysadmins can audit what monitoring chose to skip.
+		if cfg.ShowVerbose {
+			result.report += reports.SkippedItemsReport(skipped)
+		}
+
+		// Provide details for the unverified certificate chain so that
+		// sysadmins can see exactly what they are trusting.
+		if cfg.TrustCert && cfg.ShowVerbose {
+			result.report += reports.TrustCertChainReport(client.PeerCertificates())
+		}
+	}
+
+	thresholds := rsat.StateThresholds{
+		DaysStuckWarning:    cfg.DaysStuckWarning,
+		DaysStuckCritical:   cfg.DaysStuckCritical,
+		StuckCountWarning:   cfg.StuckCountWarning,
+		StuckCountCritical:  cfg.StuckCountCritical,
+		StuckState:          cfg.StuckState,
+		NeverScheduledState: cfg.NeverScheduledState,
+		EmptyOrgState:       cfg.EmptyOrgState,
+	}
+
+	switch {
+	case !orgs.IsOKState(evalTime):
+		logger.Debug().Msg("Problem sync plans detected")
+
+		result.stateLabel = orgs.ServiceState(evalTime, thresholds).Label
+		result.message = fmt.Sprintf(
+			"%d problem sync plans detected for %s (evaluated %d orgs, %d sync plans)",
+			orgs.NumProblemPlans(evalTime),
+			cfg.Server,
+			orgs.NumOrgs(),
+			orgs.NumPlans(),
+		)
+
+	case strings.EqualFold(cfg.EmptyOrgState, nagios.StateWARNINGLabel) && orgs.NumOrgsWithoutPlans() > 0:
+		logger.Debug().Msg("Organizations without sync plans detected")
+
+		result.stateLabel = orgs.ServiceState(evalTime, thresholds).Label
+		result.message = fmt.Sprintf(
+			"%d organizations without sync plans detected for %s (evaluated %d orgs, %d sync plans)",
+			orgs.NumOrgsWithoutPlans(),
+			cfg.Server,
+			orgs.NumOrgs(),
+			orgs.NumPlans(),
+		)
+
+	default:
+		logger.Debug().Msg("No problems detected")
+
+		result.stateLabel = nagios.StateOKLabel
+		result.message = fmt.Sprintf(
+			"No sync plans with non-OK status detected for %s (evaluated %d orgs, %d sync plans)",
+			cfg.Server,
+			orgs.NumOrgs(),
+			orgs.NumPlans(),
+		)
+	}
+
+	return result
+}
+
+// syncPlansReport is a helper function used to generate the sync plans report
+// in the format selected by the given configuration.
+func syncPlansReport(orgs rsat.Organizations, cfg *config.Config, evalTime time.Time, logger zerolog.Logger) string {
+	if cfg.CompactOutput {
+		return reports.SyncPlansCompactReport(orgs, cfg, evalTime, logger)
+	}
+
+	return reports.SyncPlansVerboseReport(orgs, cfg, evalTime, logger)
+}
+
+// probeServer verifies connectivity to and authentication with the Red Hat
+// Satellite server associated with the given API client without retrieving
+// organizations or sync plans. The evaluation time is left unset so that
+// neither performance data nor the plugin state file are recorded.
+func probeServer(ctx context.Context, client *rsat.APIClient, result serverEvaluation, logger zerolog.Logger) serverEvaluation {
+	cfg := result.cfg
+
+	probeResult, probeErr := rsat.Probe(ctx, client)
+	result.report = reports.ProbeReport(probeResult)
+
+	if probeErr != nil {
+		var message, report string
+		result.stateLabel, message, report = retrievalFailure(probeErr, cfg, logger)
+		result.report += report
+		result.message = "Dry run failed: " + message
+
+		// The generic retrieval failure message does not apply as
+		// organizations and sync plans are not retrieved. Authentication
+		// failures retain their specific message.
+		if !rsat.IsAuthenticationFailure(probeErr) &&
+			(result.stateLabel == nagios.StateCRITICALLabel ||
+				(result.stateLabel == nagios.StateUNKNOWNLabel && rsat.IsConnectionFailure(probeErr))) {
+			result.message = fmt.Sprintf(
+				"Dry run failed for %s; connectivity not verified",
+				cfg.Server,
+			)
+		}
+		result.err = probeErr
+
+		return result
+	}
+
+	logger.Debug().Msg("Dry run succeeded")
+
+	result.stateLabel = nagios.StateOKLabel
+	result.message = fmt.Sprintf(
+		"Dry run succeeded for %s; connectivity and authentication verified",
+		cfg.Server,
+	)
+
+	return result
+}
+
+// retrievalFailure returns the service state, summary message and report
+// (if any) for the given error encountered while retrieving organizations
+// and sync plans.
+func retrievalFailure(err error, cfg *config.Config, logger zerolog.Logger) (string, string, string) {
+	if certChain, ok := rsat.CertVerificationFailure(err); ok && cfg.CertVerifyWarn {
+		logger.Debug().
+			Int("certs", len(certChain)).
+			Msg("Certificate verification failed; downgrading to WARNING state as requested")
+
+		return nagios.StateWARNINGLabel,
+			fmt.Sprintf(
+				"Certificate verification failed for %s; sync plans not evaluated",
+				cfg.Server,
+			),
+			reports.CertChainReport(certChain)
+	}
+
+	switch {
+	case errors.Is(err, rsat.ErrReadLimitReached):
+		return nagios.StateUNKNOWNLabel,
+			fmt.Sprintf(
+				"Read limit reached retrieving Red Hat Satellite sync plans;"+
+					" increase --%s (currently %d) or lower --%s (currently %d)",
+				config.ReadLimitFlagLong,
+				cfg.ReadLimit,
+				config.PerPageLimitFlagLong,
+				cfg.PerPageLimit(),
+			),
+			""
+
+	case rsat.IsAuthenticationFailure(err):
+		logger.Debug().
+			Str("state", cfg.ConnectionFailureState).
+			Msg("Authentication to Satellite failed; using requested state")
+
+		return strings.ToUpper(cfg.ConnectionFailureState),
+			fmt.Sprintf(
+				"Authentication failed for user %s on %s; verify credentials and permissions",
+				cfg.Username,
+				cfg.Server,
+			),
+			""
+
+	case rsat.IsMaintenanceMode(err):
+		logger.Debug().
+			Str("state", cfg.MaintenanceState).
+			Msg("Satellite in maintenance mode; using requested state")
+
+		return strings.ToUpper(cfg.MaintenanceState),
+			fmt.Sprintf(
+				"Satellite in maintenance mode (%s); sync plans not evaluated",
+				cfg.Server,
+			),
+			""
+
+	case errors.Is(err, rsat.ErrOrgNotFound):
+		return nagios.StateUNKNOWNLabel,
+			fmt.Sprintf(
+				"Requested organizations not found on %s; verify --%s values",
+				cfg.Server,
+				config.OrgFlagLong,
+			),
+			""
+
+	default:
+		// Provide the results of each connection attempt so that failures
+		// for servers with multiple IP Addresses are diagnosable.
+		var report string
+		if attempts, ok := netutils.ConnectionFailure(err); ok {
+			report = reports.ConnectionAttemptsReport(attempts)
+		}
+
+		stateLabel := nagios.StateCRITICALLabel
+		if rsat.IsConnectionFailure(err) {
+			logger.Debug().
+				Str("state", cfg.ConnectionFailureState).
+				Msg("Connection to Satellite failed; using requested state")
+
+			stateLabel = strings.ToUpper(cfg.ConnectionFailureState)
+		}
+
+		return stateLabel,
+			"Error retrieving Red Hat Satellite sync plans",
+			report
+	}
+}
@@ -14,10 +14,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
-	"github.com/atc0005/check-rsat/internal/reports"
 	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/check-rsat/internal/schema"
 
 	"github.com/atc0005/go-nagios"
 	"github.com/rs/zerolog"
@@ -38,6 +39,11 @@ func main() {
 
 		return
 
+	case errors.Is(cfgErr, config.ErrSchemaRequested):
+		fmt.Println(string(schema.SyncPlan()))
+
+		return
+
 	case errors.Is(cfgErr, config.ErrHelpRequested):
 		fmt.Println(cfg.Help())
 
@@ -68,6 +74,9 @@ func main() {
 	// plugin execution.
 	defer annotateErrors(plugin)
 
+	// Render time values in reports using the user-specified time zone.
+	rsat.SetDisplayLocation(cfg.DisplayLocation())
+
 	// Set context deadline equal to user-specified timeout value for
 	// runtime/execution.
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout())
@@ -79,70 +88,52 @@ func main() {
 	}
 
 	logger := cfg.Log.With().
-		Str("server", cfg.Server).
-		Str("user", cfg.Username).
-		Int("port", cfg.TCPPort).
-		Str("net_type", cfg.NetworkType).
+		Bool("multi_server", cfg.MultiServer()).
 		Str("timeout", cfg.Timeout().String()).
-		Bool("cert-validation-disabled", cfg.TrustCert).
-		Bool("ca-cert-specified", cfg.CACertificate != "").
-		Bool("permit-tls-renegotiation", cfg.PermitTLSRenegotiation).
 		Logger()
 
 	logger.Debug().Msg("Beginning plugin execution")
 
-	// If specified, attempt to load the CA certificate associated with the
-	// Red Hat Satellite server's certificate chain.
-	var caCert []byte
-	if cfg.CACertificate != "" {
-		logger.Debug().Msg("CA Cert specified: attempting to load CA cert")
-
-		var readErr error
-		caCert, readErr = os.ReadFile(cfg.CACertificate)
-		if readErr != nil {
-			setPluginOutput(
-				nagios.StateUNKNOWNLabel,
-				"Error loading CA certificate for Red Hat Satellite instance",
-				"",
-				readErr,
-				nil,
-				cfg,
-				plugin,
-			)
-
-			return
+	// Note sync plans created or modified since the previous execution so
+	// that new problems can be correlated with recent changes.
+	var lastRun time.Time
+	if cfg.StateFile != "" {
+		var err error
+		lastRun, err = readLastRun(cfg.StateFile)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("state_file", cfg.StateFile).
+				Msg("Failed to read previous execution time from state file")
 		}
-
-		logger.Debug().Msg("Successfully loaded CA cert")
 	}
 
-	authInfo := rsat.APIAuthInfo{
-		Server:                 cfg.Server,
-		Port:                   cfg.TCPPort,
-		NetworkType:            cfg.NetworkType,
-		ReadLimit:              cfg.ReadLimit,
-		Username:               cfg.Username,
-		Password:               cfg.Password,
-		UserAgent:              cfg.UserAgent(),
-		TrustCert:              cfg.TrustCert,
-		PermitTLSRenegotiation: cfg.PermitTLSRenegotiation,
-		CACert:                 caCert,
-	}
+	var result serverEvaluation
+	switch {
+	case cfg.MultiServer():
+		result = evaluateServers(ctx, cfg, lastRun)
 
-	apiLimits := rsat.APILimits{
-		PerPage: cfg.PerPageLimit,
+	default:
+		result = evaluateServer(ctx, cfg, lastRun)
 	}
 
-	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+	if cfg.StateFile != "" && !result.evalTime.IsZero() {
+		if err := writeLastRun(cfg.StateFile, result.evalTime); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("state_file", cfg.StateFile).
+				Msg("Failed to record execution time in state file")
+		}
+	}
 
-	orgs, orgsFetchErr := rsat.GetOrgsWithSyncPlans(ctx, client)
-	if orgsFetchErr != nil {
+	// Performance data is only available if sync plans were evaluated.
+	if result.evalTime.IsZero() {
 		setPluginOutput(
-			nagios.StateCRITICALLabel,
-			"Error retrieving Red Hat Satellite sync plans",
-			"",
-			orgsFetchErr,
-			orgs,
+			result.stateLabel,
+			result.message,
+			result.report,
+			result.err,
+			result.orgs,
 			cfg,
 			plugin,
 		)
@@ -150,19 +141,13 @@ func main() {
 		return
 	}
 
-	logger.Debug().
-		Int("orgs", orgs.NumOrgs()).
-		Int("sync_plans", orgs.NumPlans()).
-		Msg("Retrieved sync plans")
-
-	pd := getPerfData(orgs)
-	if err := plugin.AddPerfData(false, pd...); err != nil {
+	if err := plugin.AddPerfData(false, result.perfData...); err != nil {
 		setPluginOutput(
 			nagios.StateUNKNOWNLabel,
 			"Failed to process performance data metrics",
 			"",
 			err,
-			orgs,
+			result.orgs,
 			cfg,
 			plugin,
 		)
@@ -170,43 +155,17 @@ func main() {
 		return
 	}
 
-	switch {
-	case !orgs.IsOKState():
-		logger.Debug().Msg("Problem sync plans detected")
-
-		setPluginOutput(
-			orgs.ServiceState().Label,
-			fmt.Sprintf(
-				"%d problem sync plans detected for %s (evaluated %d orgs, %d sync plans)",
-				orgs.NumProblemPlans(),
-				cfg.Server,
-				orgs.NumOrgs(),
-				orgs.NumPlans(),
-			),
-			reports.SyncPlansVerboseReport(orgs, cfg, logger),
-			nil,
-			orgs,
-			cfg,
-			plugin,
-		)
-
-	default:
-		logger.Debug().Msg("No problems detected")
-
-		setPluginOutput(
-			nagios.StateOKLabel,
-			fmt.Sprintf(
-				"No sync plans with non-OK status detected for %s (evaluated %d orgs, %d sync plans)",
-				cfg.Server,
-				orgs.NumOrgs(),
-				orgs.NumPlans(),
-			),
-			reports.SyncPlansVerboseReport(orgs, cfg, logger),
-			nil,
-			orgs,
-			cfg,
-			plugin,
-		)
+	if cfg.EmitPayload {
+		setPluginPayload(result.payloads, plugin, logger)
 	}
 
+	setPluginOutput(
+		result.stateLabel,
+		result.message,
+		result.report,
+		result.err,
+		result.orgs,
+		cfg,
+		plugin,
+	)
 }
@@ -8,11 +8,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
 	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
 )
 
 // TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric
@@ -54,3 +58,109 @@ func TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric(t *tes
 		t.Logf("OK: Emitted performance data contains the expected time metric.")
 	}
 }
+
+// TestSetPluginPayloadEmbedsDecodablePayload asserts that machine-readable
+// reports embedded in the plugin output can be extracted and decoded as a
+// JSON array with one entry per report.
+func TestSetPluginPayloadEmbedsDecodablePayload(t *testing.T) {
+	t.Parallel()
+
+	plugin := nagios.NewPlugin()
+	plugin.ServiceOutput = "TacoTuesday"
+
+	var outputBuffer strings.Builder
+
+	plugin.SetOutputTarget(&outputBuffer)
+	plugin.SkipOSExit()
+
+	payloads := []json.RawMessage{
+		json.RawMessage(`{"server":"rsat1.example.com"}`),
+		json.RawMessage(`{"server":"rsat2.example.com"}`),
+	}
+
+	setPluginPayload(payloads, plugin, zerolog.Nop())
+
+	plugin.ReturnCheckResults()
+
+	decoded, err := nagios.ExtractAndDecodePayload(
+		outputBuffer.String(),
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if err != nil {
+		t.Fatalf("ERROR: failed to extract payload from plugin output: %v", err)
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal([]byte(decoded), &got); err != nil {
+		t.Fatalf("ERROR: failed to decode payload %q: %v", decoded, err)
+	}
+
+	if len(got) != len(payloads) || got[1]["server"] != "rsat2.example.com" {
+		t.Errorf("ERROR: unexpected payload content: %v", got)
+	}
+}
+
+// TestTruncateReportHonorsLimit asserts that reports exceeding the output
+// size limit are truncated at a line boundary with a trailer noting the
+// truncation and that the result does not exceed the limit.
+func TestTruncateReportHonorsLimit(t *testing.T) {
+	t.Parallel()
+
+	var report strings.Builder
+	for i := 0; i < 100; i++ {
+		_, _ = fmt.Fprintf(&report, "* [Name: Sync Plan %d, Days Stuck: 3]\n", i)
+	}
+
+	limit := 500
+
+	got := truncateReport(report.String(), limit)
+
+	if len(got) > limit {
+		t.Errorf("ERROR: truncated report size %d exceeds limit %d", len(got), limit)
+	}
+
+	if !strings.Contains(got, "[output truncated;") {
+		t.Errorf("ERROR: truncated report is missing trailer:\n%s", got)
+	}
+
+	body := got[:strings.Index(got, nagios.CheckOutputEOL+"[output truncated;")]
+	if !strings.HasSuffix(body, "]\n") {
+		t.Errorf("ERROR: report not truncated at a line boundary:\n%s", got)
+	}
+
+	if short := "short report\n"; truncateReport(short, limit) != short {
+		t.Errorf("ERROR: report within limit was modified")
+	}
+}
+
+// TestRetrievalFailureAuthenticationUsesConnectionFailureState asserts that
+// authentication failures result in a CRITICAL state by default and honor
+// the requested connection failure state.
+func TestRetrievalFailureAuthenticationUsesConnectionFailureState(t *testing.T) {
+	t.Parallel()
+
+	authErr := fmt.Errorf("failed to retrieve organizations: %w", rsat.ErrAuthenticationFailed)
+
+	tests := []struct {
+		connectionFailureState string
+		want                   string
+	}{
+		{connectionFailureState: nagios.StateCRITICALLabel, want: nagios.StateCRITICALLabel},
+		{connectionFailureState: "unknown", want: nagios.StateUNKNOWNLabel},
+	}
+
+	for _, tt := range tests {
+		cfg := &config.Config{ConnectionFailureState: tt.connectionFailureState}
+
+		got, message, _ := retrievalFailure(authErr, cfg, zerolog.Nop())
+		if got != tt.want {
+			t.Errorf("ERROR: connection failure state %q: got state %s, want %s", tt.connectionFailureState, got, tt.want)
+		}
+
+		if !strings.Contains(message, "Authentication failed") {
+			t.Errorf("ERROR: connection failure state %q: unexpected message %q", tt.connectionFailureState, message)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/go-nagios"
+)
+
+// stateSeverity ranks service states so that the most severe state across
+// multiple servers is reported.
+var stateSeverity = map[string]int{
+	nagios.StateOKLabel:       0,
+	nagios.StateUNKNOWNLabel:  1,
+	nagios.StateWARNINGLabel:  2,
+	nagios.StateCRITICALLabel: 3,
+}
+
+// evaluateServers evaluates each Red Hat Satellite server from the
+// user-specified list of servers and rolls the results into a single
+// evaluation. The most severe state across all servers is used as the
+// service state and the report provides a section for each server in the
+// same order as the list of servers.
+func evaluateServers(ctx context.Context, cfg *config.Config, lastRun time.Time) serverEvaluation {
+	results := make([]serverEvaluation, len(cfg.ServerList))
+
+	var wg sync.WaitGroup
+	for i, server := range cfg.ServerList {
+		wg.Add(1)
+
+		go func(i int, server string) {
+			defer wg.Done()
+
+			results[i] = evaluateServer(ctx, cfg.ForServer(server), lastRun)
+		}(i, server)
+	}
+	wg.Wait()
+
+	combined := serverEvaluation{
+		cfg:        cfg,
+		stateLabel: nagios.StateOKLabel,
+	}
+
+	var report strings.Builder
+	var errs []error
+	var numNonOK int
+	var numProblemPlans int
+
+	for _, result := range results {
+		stateLabel := strings.ToUpper(result.stateLabel)
+
+		if stateSeverity[stateLabel] > stateSeverity[combined.stateLabel] {
+			combined.stateLabel = stateLabel
+		}
+
+		if stateLabel != nagios.StateOKLabel {
+			numNonOK++
+		}
+
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.cfg.Server, result.err))
+		}
+
+		if !result.evalTime.IsZero() {
+			numProblemPlans += result.orgs.NumProblemPlans(result.evalTime)
+
+			// Use the earliest evaluation time so that changes made while
+			// evaluating servers are noted by the next plugin execution.
+			if combined.evalTime.IsZero() || result.evalTime.Before(combined.evalTime) {
+				combined.evalTime = result.evalTime
+			}
+		}
+
+		combined.orgs = append(combined.orgs, result.orgs...)
+		combined.perfData = append(combined.perfData, result.perfData...)
+		combined.payloads = append(combined.payloads, result.payloads...)
+
+		writeServerSection(&report, result)
+	}
+
+	combined.err = errors.Join(errs...)
+	combined.report = report.String()
+
+	switch {
+	case cfg.DryRun && numNonOK == 0:
+		combined.message = fmt.Sprintf(
+			"Dry run succeeded for %d servers; connectivity and authentication verified",
+			len(results),
+		)
+
+	case cfg.DryRun:
+		combined.message = fmt.Sprintf(
+			"Dry run failed for %d of %d servers",
+			numNonOK,
+			len(results),
+		)
+
+	case numNonOK == 0:
+		combined.message = fmt.Sprintf(
+			"No sync plans with non-OK status detected for %d servers (evaluated %d orgs, %d sync plans)",
+			len(results),
+			combined.orgs.NumOrgs(),
+			combined.orgs.NumPlans(),
+		)
+
+	default:
+		combined.message = fmt.Sprintf(
+			"%d of %d servers with non-OK status (%d problem sync plans detected; evaluated %d orgs, %d sync plans)",
+			numNonOK,
+			len(results),
+			numProblemPlans,
+			combined.orgs.NumOrgs(),
+			combined.orgs.NumPlans(),
+		)
+	}
+
+	return combined
+}
+
+// writeServerSection writes the evaluation results for a single server as a
+// section of the combined report.
+func writeServerSection(w *strings.Builder, result serverEvaluation) {
+	separator := strings.Repeat("=", 60)
+
+	_, _ = fmt.Fprintf(
+		w,
+		"%s%sSERVER: %s (%s)%s%s%s%s",
+		separator,
+		nagios.CheckOutputEOL,
+		result.cfg.Server,
+		strings.ToUpper(result.stateLabel),
+		nagios.CheckOutputEOL,
+		separator,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	_, _ = fmt.Fprintf(
+		w,
+		"%s: %s%s",
+		strings.ToUpper(result.stateLabel),
+		result.message,
+		nagios.CheckOutputEOL,
+	)
+
+	if result.report != "" {
+		_, _ = fmt.Fprintf(w, "%s%s", nagios.CheckOutputEOL, result.report)
+	}
+
+	_, _ = fmt.Fprint(w, nagios.CheckOutputEOL)
+}
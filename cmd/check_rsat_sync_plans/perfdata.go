@@ -9,46 +9,142 @@ package main
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
 	"github.com/atc0005/go-nagios"
 )
 
-// getPerfData gathers performance data metrics that we wish to report.
-func getPerfData(orgs rsat.Organizations) []nagios.PerformanceData {
+// getPerfData gathers performance data metrics that we wish to report using
+// the given evaluation reference time. The label prefix from the given
+// configuration (if any) is applied to each metric label and the
+// user-specified stuck sync plan count thresholds (if any) are applied to
+// the stuck sync plans metric.
+func getPerfData(orgs rsat.Organizations, evalTime time.Time, cfg *config.Config) []nagios.PerformanceData {
 	switch {
 	case len(orgs) == 0:
 		return []nagios.PerformanceData{}
 
 	default:
-		return []nagios.PerformanceData{
+		numPlans := fmt.Sprintf("%d", orgs.NumPlans())
+
+		pd := []nagios.PerformanceData{
 			// The `time` (runtime) metric is appended at plugin exit, so do not
 			// duplicate it here.
 			{
 				Label: "organizations",
 				Value: fmt.Sprintf("%d", orgs.NumOrgs()),
+				Min:   "0",
 			},
 			{
 				Label: "sync_plans_total",
-				Value: fmt.Sprintf("%d", orgs.NumPlans()),
+				Value: numPlans,
+				Min:   "0",
 			},
 			{
 				Label: "sync_plans_enabled",
 				Value: fmt.Sprintf("%d", orgs.NumPlansEnabled()),
+				Min:   "0",
+				Max:   numPlans,
 			},
 			{
 				Label: "sync_plans_disabled",
 				Value: fmt.Sprintf("%d", orgs.NumPlansDisabled()),
+				Min:   "0",
+				Max:   numPlans,
 			},
 			{
 				Label: "sync_plans_stuck",
-				Value: fmt.Sprintf("%d", orgs.NumPlansStuck()),
+				Value: fmt.Sprintf("%d", orgs.NumPlansStuck(evalTime)),
+				Warn:  cfg.StuckCountWarning.String(),
+				Crit:  cfg.StuckCountCritical.String(),
+				Min:   "0",
+				Max:   numPlans,
 			},
 			{
 				Label: "sync_plans_problems",
-				Value: fmt.Sprintf("%d", orgs.NumProblemPlans()),
+				Value: fmt.Sprintf("%d", orgs.NumProblemPlans(evalTime)),
+				Min:   "0",
+				Max:   numPlans,
+			},
+			{
+				Label: "days_stuck_max",
+				Value: fmt.Sprintf("%d", orgs.MaxDaysStuck(evalTime)),
+				Warn:  cfg.DaysStuckWarning.String(),
+				Crit:  cfg.DaysStuckCritical.String(),
+				Min:   "0",
 			},
 		}
+
+		labelPrefix := cfg.PerfDataLabelPrefix()
+		for i := range pd {
+			pd[i].Label = labelPrefix + pd[i].Label
+		}
+
+		return pd
+	}
+
+}
+
+// getQueryPerfData gathers performance data metrics for the elapsed time
+// spent retrieving organizations and sync plans using the given client so
+// that slow Red Hat Satellite API behavior can be trended. The given prefix
+// (if any) is applied to each metric label. No metrics are gathered if
+// organizations were not retrieved using the client (e.g., if cached values
+// were used).
+func getQueryPerfData(client *rsat.APIClient, labelPrefix string) []nagios.PerformanceData {
+	orgsQueryTime := client.QueryTime(rsat.MetricsEndpointOrganizations)
+	if orgsQueryTime == 0 {
+		return []nagios.PerformanceData{}
+	}
+
+	pd := []nagios.PerformanceData{
+		{
+			Label:             "orgs_query_time",
+			Value:             fmt.Sprintf("%d", orgsQueryTime.Milliseconds()),
+			UnitOfMeasurement: "ms",
+			Min:               "0",
+		},
+		{
+			Label:             "syncplans_query_time",
+			Value:             fmt.Sprintf("%d", client.QueryTime(rsat.MetricsEndpointSyncPlans).Milliseconds()),
+			UnitOfMeasurement: "ms",
+			Min:               "0",
+		},
+	}
+
+	for i := range pd {
+		pd[i].Label = labelPrefix + pd[i].Label
+	}
+
+	return pd
+}
+
+// getAPIPerfData gathers performance data metrics for the API requests
+// submitted by the given client. The given prefix (if any) is applied to
+// each metric label.
+func getAPIPerfData(metrics rsat.APIMetrics, labelPrefix string) []nagios.PerformanceData {
+	pd := []nagios.PerformanceData{
+		{
+			Label: "api_calls",
+			Value: fmt.Sprintf("%d", metrics.Requests()),
+		},
+		{
+			Label:             "api_time_orgs",
+			Value:             fmt.Sprintf("%d", metrics.Time(rsat.MetricsEndpointOrganizations).Milliseconds()),
+			UnitOfMeasurement: "ms",
+		},
+		{
+			Label:             "api_time_syncplans",
+			Value:             fmt.Sprintf("%d", metrics.Time(rsat.MetricsEndpointSyncPlans).Milliseconds()),
+			UnitOfMeasurement: "ms",
+		},
+	}
+
+	for i := range pd {
+		pd[i].Label = labelPrefix + pd[i].Label
 	}
 
+	return pd
 }
@@ -0,0 +1,92 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+)
+
+// TestGetPerfDataAppliesThresholds asserts that the user-specified stuck
+// sync plan count thresholds and the expected minimum and maximum values are
+// applied to emitted performance data and that the result passes validation.
+func TestGetPerfDataAppliesThresholds(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	orgs := rsat.Organizations{
+		{
+			Name: "Alpha Org",
+			SyncPlans: rsat.SyncPlans{
+				{Name: "Daily", Enabled: true, NextSync: rsat.SyncTime(now.Add(-72 * time.Hour))},
+				{Name: "Weekly", Enabled: true, NextSync: rsat.SyncTime(now.Add(24 * time.Hour))},
+			},
+		},
+	}
+
+	warning, err := config.NewThreshold("1")
+	if err != nil {
+		t.Fatalf("failed to parse warning threshold: %v", err)
+	}
+
+	critical, err := config.NewThreshold("5")
+	if err != nil {
+		t.Fatalf("failed to parse critical threshold: %v", err)
+	}
+
+	daysWarning, err := config.NewThreshold("1")
+	if err != nil {
+		t.Fatalf("failed to parse days stuck warning threshold: %v", err)
+	}
+
+	daysCritical, err := config.NewThreshold("6")
+	if err != nil {
+		t.Fatalf("failed to parse days stuck critical threshold: %v", err)
+	}
+
+	cfg := &config.Config{
+		DaysStuckWarning:   daysWarning,
+		DaysStuckCritical:  daysCritical,
+		StuckCountWarning:  warning,
+		StuckCountCritical: critical,
+	}
+
+	want := map[string]nagios.PerformanceData{
+		"sync_plans_stuck": {Label: "sync_plans_stuck", Value: "1", Warn: "1", Crit: "5", Min: "0", Max: "2"},
+		"days_stuck_max":   {Label: "days_stuck_max", Value: "3", Warn: "1", Crit: "6", Min: "0"},
+	}
+
+	pd := getPerfData(orgs, now, cfg)
+
+	for _, metric := range pd {
+		wantMetric, ok := want[metric.Label]
+		if !ok {
+			continue
+		}
+
+		delete(want, metric.Label)
+
+		if metric != wantMetric {
+			t.Errorf("ERROR: unexpected metric %s; want %+v, got %+v", metric.Label, wantMetric, metric)
+		}
+	}
+
+	for label := range want {
+		t.Errorf("ERROR: %s metric not emitted", label)
+	}
+
+	plugin := nagios.NewPlugin()
+	if err := plugin.AddPerfData(false, pd...); err != nil {
+		t.Errorf("ERROR: performance data failed validation: %v", err)
+	}
+}
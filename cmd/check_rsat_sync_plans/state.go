@@ -0,0 +1,81 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pluginState is the state recorded between plugin executions.
+type pluginState struct {
+	// LastRun is the evaluation reference time of the most recent plugin
+	// execution.
+	LastRun time.Time `json:"last_run"`
+}
+
+// readLastRun returns the time of the previous plugin execution as recorded
+// in the given state file. A zero value is returned if the state file does
+// not yet exist (e.g., on first execution).
+func readLastRun(path string) (time.Time, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state pluginState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode state file %s: %w", path, err)
+	}
+
+	return state.LastRun, nil
+}
+
+// writeLastRun records the time of the current plugin execution in the
+// given state file. The state file is replaced atomically so that concurrent
+// or interrupted executions do not leave a partially written file.
+func writeLastRun(path string, lastRun time.Time) error {
+	content, err := json.Marshal(pluginState{LastRun: lastRun.UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	path = filepath.Clean(path)
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+
+	tmpName := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace state file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	lastRun, err := readLastRun(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading missing state file: %v", err)
+	}
+
+	if !lastRun.IsZero() {
+		t.Errorf("got %v for missing state file, want zero value", lastRun)
+	}
+
+	want := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	if err := writeLastRun(path, want); err != nil {
+		t.Fatalf("unexpected error writing state file: %v", err)
+	}
+
+	got, err := readLastRun(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading state file: %v", err)
+	}
+
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
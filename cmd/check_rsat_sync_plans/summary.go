@@ -8,12 +8,17 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
 	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
 )
 
 // setPluginOutput is a helper function used to set plugin output and state
@@ -27,7 +32,13 @@ func setPluginOutput(
 	cfg *config.Config,
 	plugin *nagios.Plugin,
 ) {
-	if err != nil {
+	// Record each error separately if multiple errors were encountered
+	// (e.g., while evaluating multiple servers).
+	var joined interface{ Unwrap() []error }
+	switch {
+	case errors.As(err, &joined):
+		plugin.AddError(joined.Unwrap()...)
+	case err != nil:
 		plugin.AddError(err)
 	}
 
@@ -40,16 +51,74 @@ func setPluginOutput(
 	)
 
 	if cfg != nil {
-		setLongServiceOutput(extendedMessage, orgs, cfg, plugin)
+		setLongServiceOutput(extendedMessage, orgs, cfg, err != nil, plugin)
+	}
+
+}
+
+// setPluginPayload is a helper function used to embed the given
+// machine-readable (JSON) reports in the plugin output as an encoded payload.
+// The payload is a JSON array with one report per server evaluated.
+func setPluginPayload(payloads []json.RawMessage, plugin *nagios.Plugin, logger zerolog.Logger) {
+	if len(payloads) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(payloads)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to encode sync plans payload")
+
+		return
+	}
+
+	if _, err := plugin.SetPayloadBytes(data); err != nil {
+		logger.Warn().Err(err).Msg("Failed to embed sync plans payload in plugin output")
+	}
+}
+
+// truncateReport is a helper function used to truncate the given report at a
+// line boundary so that the report (including a trailer noting the
+// truncation) does not exceed the given size limit in bytes. The report is
+// returned as-is if the limit is not set or is not exceeded.
+func truncateReport(report string, limit int) string {
+	if limit <= 0 || len(report) <= limit {
+		return report
 	}
 
+	trailerFormat := "%s[output truncated; %d of %d bytes omitted]%s"
+
+	// Use the full report size to reserve enough room for the trailer.
+	maxTrailer := fmt.Sprintf(trailerFormat, nagios.CheckOutputEOL, len(report), len(report), nagios.CheckOutputEOL)
+
+	budget := limit - len(maxTrailer)
+	if budget < 0 {
+		budget = 0
+	}
+
+	cut := strings.LastIndex(report[:budget], "\n") + 1
+	if cut == 0 {
+		cut = budget
+		for cut > 0 && !utf8.RuneStart(report[cut]) {
+			cut--
+		}
+	}
+
+	return report[:cut] + fmt.Sprintf(
+		trailerFormat,
+		nagios.CheckOutputEOL,
+		len(report)-cut,
+		len(report),
+		nagios.CheckOutputEOL,
+	)
 }
 
-func setLongServiceOutput(report string, _ rsat.Organizations, cfg *config.Config, plugin *nagios.Plugin) {
+func setLongServiceOutput(report string, _ rsat.Organizations, cfg *config.Config, showConfig bool, plugin *nagios.Plugin) {
 	var output strings.Builder
 
 	// If provided, put the report content first.
 	if report != "" {
+		report = truncateReport(report, cfg.MaxOutputBytes)
+
 		_, _ = fmt.Fprintf(
 			&output,
 			"%s%s",
@@ -58,7 +127,11 @@ func setLongServiceOutput(report string, _ rsat.Organizations, cfg *config.Confi
 		)
 	}
 
-	if cfg.ShowVerbose {
+	// Include the configuration settings if requested or if an error
+	// occurred. The latter is intended to help sysadmins determine which
+	// settings the service check used without having to review the service
+	// definition on the monitoring system.
+	if cfg.ShowVerbose || showConfig {
 		_, _ = fmt.Fprintf(&output, "%s", nagios.CheckOutputEOL)
 
 		_, _ = fmt.Fprintf(
@@ -69,55 +142,112 @@ func setLongServiceOutput(report string, _ rsat.Organizations, cfg *config.Confi
 			nagios.CheckOutputEOL,
 		)
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"Configuration settings: %s%s",
-			nagios.CheckOutputEOL,
-			nagios.CheckOutputEOL,
-		)
+		writeConfigSettings(&output, cfg)
+	}
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"* Server: %v%s",
-			cfg.Server,
-			nagios.CheckOutputEOL,
-		)
+	plugin.LongServiceOutput = output.String()
+}
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"* Port: %v%s",
-			cfg.TCPPort,
-			nagios.CheckOutputEOL,
-		)
+// writeConfigSettings writes the effective configuration settings to the
+// given writer. Sensitive values (e.g., the password) are redacted.
+func writeConfigSettings(w io.Writer, cfg *config.Config) {
+	redacted := func(value string) string {
+		if value == "" {
+			return "(not set)"
+		}
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"* Username: %v%s",
-			cfg.Username,
-			nagios.CheckOutputEOL,
-		)
+		return "(redacted)"
+	}
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"* NetworkType: %v%s",
-			cfg.NetworkType,
-			nagios.CheckOutputEOL,
-		)
+	settings := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "Server", value: cfg.Server},
+		{name: "Port", value: cfg.TCPPort},
+		{name: "Username", value: cfg.Username},
+		{name: "Password", value: redacted(cfg.Password)},
+		{name: "PasswordFile", value: cfg.PasswordFile},
+		{name: "Token", value: redacted(cfg.Token)},
+		{name: "OAuthConsumerKey", value: cfg.OAuthConsumerKey},
+		{name: "OAuthConsumerSecret", value: redacted(cfg.OAuthConsumerSecret)},
+		{name: "HammerConfig", value: cfg.HammerConfig},
+		{name: "NetworkType", value: cfg.NetworkType},
+		{name: "DNSServer", value: cfg.DNSServer},
+		{name: "DNSTimeout", value: cfg.DNSTimeout()},
+		{name: "Timeout", value: cfg.Timeout()},
+		{name: "CACertificate", value: cfg.CACertificate},
+		{name: "ClientCertificate", value: cfg.ClientCertificate},
+		{name: "ClientKey", value: cfg.ClientKey},
+		{name: "TrustCert", value: cfg.TrustCert},
+		{name: "PermitTLSRenegotiation", value: cfg.PermitTLSRenegotiation},
+		{name: "CertVerifyWarn", value: cfg.CertVerifyWarn},
+		{name: "MaintenanceState", value: cfg.MaintenanceState},
+		{name: "StuckState", value: cfg.StuckState},
+		{name: "NeverScheduledState", value: cfg.NeverScheduledState},
+		{name: "EmptyOrgState", value: cfg.EmptyOrgState},
+		{name: "ConnectionFailureState", value: cfg.ConnectionFailureState},
+		{name: "ReadLimit", value: cfg.ReadLimit},
+		{name: "PerPageLimit", value: cfg.PerPageLimit()},
+		{name: "PerPageAuto", value: cfg.PerPageAuto()},
+		{name: "MaxConcurrent", value: cfg.MaxConcurrent},
+		{name: "MaxIdleConns", value: cfg.MaxIdleConns},
+		{name: "MaxIdleConnsPerHost", value: cfg.MaxIdleConnsPerHost},
+		{name: "IdleConnTimeout", value: cfg.IdleConnTimeout()},
+		{name: "DialTimeout", value: cfg.DialTimeout()},
+		{name: "DialKeepAlive", value: cfg.DialKeepAlive()},
+		{name: "EnableHTTP2", value: cfg.EnableHTTP2},
+		{name: "StreamDecode", value: cfg.StreamDecode},
+		{name: "BulkSyncPlans", value: cfg.BulkSyncPlans},
+		{name: "RetryMaxAttempts", value: cfg.RetryMaxAttempts},
+		{name: "RetryBaseDelay", value: cfg.RetryBaseDelay()},
+		{name: "RetryJitter", value: cfg.RetryJitter},
+		{name: "DaysStuckWarning", value: cfg.DaysStuckWarning.String()},
+		{name: "DaysStuckCritical", value: cfg.DaysStuckCritical.String()},
+		{name: "StuckCountWarning", value: cfg.StuckCountWarning.String()},
+		{name: "StuckCountCritical", value: cfg.StuckCountCritical.String()},
+		{name: "EvaluateProductSyncState", value: cfg.EvaluateProductSyncState},
+		{name: "BasicMode", value: cfg.BasicMode},
+		{name: "CheckRecurringLogic", value: cfg.CheckRecurringLogic},
+		{name: "MaxProductSyncAge", value: cfg.MaxProductSyncAge.String()},
+		{name: "MaxIntervalDrift", value: cfg.MaxIntervalDrift},
+		{name: "OwnerPattern", value: cfg.OwnerPattern},
+		{name: "OwnerOrgParameter", value: cfg.OwnerOrgParameter},
+		{name: "Orgs", value: strings.Join(cfg.Orgs, ", ")},
+		{name: "ExcludeOrgs", value: strings.Join(cfg.ExcludeOrgs, ", ")},
+		{name: "IgnoreSuppressionTags", value: cfg.IgnoreSuppressionTags},
+		{name: "IgnorePlans", value: strings.Join(cfg.IgnorePlans, ", ")},
+		{name: "EnabledOnly", value: cfg.EnabledOnly},
+		{name: "ExcludeProducts", value: strings.Join(cfg.ExcludeProducts, ", ")},
+		{name: "CacheSocket", value: cfg.CacheSocket},
+		{name: "CacheDir", value: cfg.CacheDir},
+		{name: "CacheTTL", value: cfg.CacheTTL()},
+		{name: "OmitOKSyncPlans", value: cfg.OmitOKSyncPlans},
+		{name: "TopStuck", value: cfg.TopStuck},
+		{name: "CompactOutput", value: cfg.CompactOutput},
+		{name: "StateFile", value: cfg.StateFile},
+		{name: "PerfDataLabelPrefix", value: cfg.PerfDataLabelPrefix()},
+		{name: "APIPerfData", value: cfg.APIPerfData},
+		{name: "EmitPayload", value: cfg.EmitPayload},
+		{name: "MaxOutputBytes", value: cfg.MaxOutputBytes},
+		{name: "LoggingLevel", value: cfg.LoggingLevel},
+		{name: "UserAgent", value: cfg.UserAgent()},
+	}
 
-		_, _ = fmt.Fprintf(
-			&output,
-			"* Timeout: %v%s",
-			cfg.Timeout(),
-			nagios.CheckOutputEOL,
-		)
+	_, _ = fmt.Fprintf(
+		w,
+		"Configuration settings: %s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
 
+	for _, setting := range settings {
 		_, _ = fmt.Fprintf(
-			&output,
-			"* UserAgent: %v%s",
-			cfg.UserAgent(),
+			w,
+			"* %s: %v%s",
+			setting.name,
+			setting.value,
 			nagios.CheckOutputEOL,
 		)
 	}
-
-	plugin.LongServiceOutput = output.String()
 }
@@ -8,6 +8,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"os"
 	"path/filepath"
 
@@ -38,17 +39,48 @@ func getAuthInfo(cfg *config.Config, logger zerolog.Logger) (rsat.APIAuthInfo, e
 		logger.Info().Msg("Successfully loaded CA cert")
 	}
 
+	// If specified, attempt to load the client certificate presented to
+	// servers requiring mutual TLS authentication.
+	var clientCert *tls.Certificate
+	if cfg.ClientCertificate != "" {
+		logger.Info().
+			Str("client-cert", cfg.ClientCertificate).
+			Msg("Attempting to load specified client cert and key")
+
+		cert, loadErr := tls.LoadX509KeyPair(
+			filepath.Clean(cfg.ClientCertificate),
+			filepath.Clean(cfg.ClientKey),
+		)
+		if loadErr != nil {
+			logger.Error().
+				Err(loadErr).
+				Msg("Error loading client certificate for Red Hat Satellite instance")
+			return rsat.APIAuthInfo{}, loadErr
+		}
+		clientCert = &cert
+
+		logger.Info().Msg("Successfully loaded client cert and key")
+	}
+
 	authInfo := rsat.APIAuthInfo{
 		Server:                 cfg.Server,
 		Port:                   cfg.TCPPort,
 		NetworkType:            cfg.NetworkType,
+		DNSServer:              cfg.DNSServer,
+		DNSTimeout:             cfg.DNSTimeout(),
 		ReadLimit:              cfg.ReadLimit,
 		Username:               cfg.Username,
 		Password:               cfg.Password,
+		Token:                  cfg.Token,
+		OAuthConsumerKey:       cfg.OAuthConsumerKey,
+		OAuthConsumerSecret:    cfg.OAuthConsumerSecret,
+		FixturesDir:            cfg.FixturesDir,
+		ResponseCacheDir:       cfg.CacheDir,
 		UserAgent:              cfg.UserAgent(),
 		TrustCert:              cfg.TrustCert,
 		PermitTLSRenegotiation: cfg.PermitTLSRenegotiation,
 		CACert:                 caCert,
+		ClientCertificate:      clientCert,
 	}
 
 	return authInfo, nil
@@ -0,0 +1,154 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// serverResult is the result of evaluating a single Red Hat Satellite server
+// in batch mode.
+type serverResult struct {
+	cfg      *config.Config
+	entry    config.ServerEntry
+	orgs     rsat.Organizations
+	skipped  rsat.SkippedItems
+	evalTime time.Time
+	err      error
+	logger   zerolog.Logger
+}
+
+// runBatch evaluates each Red Hat Satellite server from the user-specified
+// servers list and emits a combined report to the given writer. Servers are
+// evaluated concurrently up to the user-specified limit, but results are
+// always reported in the same order as the servers list. The application
+// exit code is returned.
+func runBatch(ctx context.Context, w io.Writer, cfg *config.Config, logger zerolog.Logger) int {
+	entries, entriesErr := cfg.ServerEntries()
+	if entriesErr != nil {
+		logger.Error().
+			Err(entriesErr).
+			Msg("Error reading servers list")
+
+		return config.ExitCodeCatchall
+	}
+
+	logger.Info().
+		Int("servers", len(entries)).
+		Int("concurrency", cfg.BatchConcurrency).
+		Msg("Evaluating servers in batch mode")
+
+	results := make([]serverResult, len(entries))
+	semaphore := make(chan struct{}, cfg.BatchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, entry config.ServerEntry) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			results[i] = evaluateServerEntry(ctx, cfg, entry, logger)
+		}(i, entry)
+	}
+	wg.Wait()
+
+	// Machine-readable reports are emitted one after another without
+	// section headings or a summary so that the output remains parsable;
+	// failures are logged instead.
+	machineReadable := cfg.MachineReadableOutput()
+
+	var numFailed int
+	for _, result := range results {
+		if !machineReadable {
+			_, _ = fmt.Fprintf(
+				w,
+				"\n%s\nSERVER: %s\n%s\n",
+				strings.Repeat("=", 60),
+				result.entry.Server,
+				strings.Repeat("=", 60),
+			)
+		}
+
+		if result.err != nil {
+			numFailed++
+
+			logger.Error().
+				Err(result.err).
+				Str("server", result.entry.Server).
+				Msg("Error evaluating server")
+
+			if !machineReadable {
+				_, _ = fmt.Fprintf(w, "\nError evaluating server: %v\n", result.err)
+			}
+
+			continue
+		}
+
+		generateReport(w, result.orgs, result.skipped, result.cfg, result.evalTime, result.logger)
+	}
+
+	logger.Info().
+		Int("servers", len(results)).
+		Int("succeeded", len(results)-numFailed).
+		Int("failed", numFailed).
+		Msg("Completed batch evaluation")
+
+	if !machineReadable {
+		_, _ = fmt.Fprintf(
+			w,
+			"\nEvaluated %d servers (%d succeeded, %d failed)\n",
+			len(results),
+			len(results)-numFailed,
+			numFailed,
+		)
+	}
+
+	if numFailed > 0 {
+		return config.ExitCodeCatchall
+	}
+
+	return 0
+}
+
+// evaluateServerEntry is a helper function used to retrieve sync plans for a
+// single Red Hat Satellite server in batch mode.
+func evaluateServerEntry(ctx context.Context, cfg *config.Config, entry config.ServerEntry, logger zerolog.Logger) serverResult {
+	result := serverResult{
+		entry: entry,
+	}
+
+	entryCfg, cfgErr := cfg.ForServerEntry(entry)
+	if cfgErr != nil {
+		result.err = cfgErr
+
+		return result
+	}
+
+	result.cfg = entryCfg
+	result.logger = logger.With().
+		Str("server", entryCfg.Server).
+		Int("port", entryCfg.TCPPort).
+		Logger()
+
+	result.orgs, result.skipped, _, result.err = retrieveOrgs(ctx, entryCfg, result.logger)
+	result.evalTime = time.Now()
+
+	return result
+}
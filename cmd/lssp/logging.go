@@ -23,6 +23,7 @@ func setupLogger(cfg *config.Config) zerolog.Logger {
 		Str("timeout", cfg.Timeout().String()).
 		Bool("cert-validation-disabled", cfg.TrustCert).
 		Bool("ca-cert-specified", cfg.CACertificate != "").
+		Bool("client-cert-specified", cfg.ClientCertificate != "").
 		Bool("permit-tls-renegotiation", cfg.PermitTLSRenegotiation).
 		Str("version", config.Version()).
 		Logger()
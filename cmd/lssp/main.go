@@ -14,9 +14,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/reports"
 	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/check-rsat/internal/schema"
 
 	"github.com/rs/zerolog"
 )
@@ -31,6 +34,17 @@ func main() {
 
 		return
 
+	// The report schema references the sync plan schema.
+	case errors.Is(cfgErr, config.ErrSchemaRequested) && cfg.MachineReadableOutput():
+		fmt.Println(string(schema.Report()))
+
+		return
+
+	case errors.Is(cfgErr, config.ErrSchemaRequested):
+		fmt.Println(string(schema.SyncPlan()))
+
+		return
+
 	case errors.Is(cfgErr, config.ErrHelpRequested):
 		fmt.Println(cfg.Help())
 
@@ -59,6 +73,9 @@ func main() {
 		os.Exit(exitCode)
 	}(&appExitCode)
 
+	// Render time values in reports using the user-specified time zone.
+	rsat.SetDisplayLocation(cfg.DisplayLocation())
+
 	// Set context deadline equal to user-specified timeout value for
 	// runtime/execution.
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout())
@@ -66,60 +83,60 @@ func main() {
 
 	logger := setupLogger(cfg)
 
-	authInfo, authErr := getAuthInfo(cfg, logger)
-	if authErr != nil {
-		logger.Error().
-			Err(authErr).
-			Msg("Error preparing auth info for Red Hat Satellite instance")
-
+	output, closeOutput, outputErr := reportOutput(cfg, logger)
+	if outputErr != nil {
 		appExitCode = config.ExitCodeCatchall
 
 		return
 	}
+	defer closeOutput()
 
-	apiLimits := rsat.APILimits{
-		PerPage: cfg.PerPageLimit,
-	}
+	if cfg.DryRun {
+		appExitCode = runProbe(ctx, output, cfg, logger)
 
-	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+		return
+	}
 
-	logger.Info().
-		Str("timeout", cfg.Timeout().String()).
-		Msg("Retrieving Red Hat Satellite sync plans (this may take a while)")
+	if cfg.BatchMode() {
+		appExitCode = runBatch(ctx, output, cfg, logger)
 
-	orgs, orgsFetchErr := rsat.GetOrgsWithSyncPlans(ctx, client)
-	if orgsFetchErr != nil {
-		logger.Error().
-			Err(orgsFetchErr).
-			Msg("Error retrieving Red Hat Satellite sync plans")
+		return
+	}
 
+	orgs, skipped, client, retrieveErr := retrieveOrgs(ctx, cfg, logger)
+	if retrieveErr != nil {
 		appExitCode = config.ExitCodeCatchall
 
 		return
 	}
 
-	logger.Info().
-		Int("organizations", orgs.NumOrgs()).
-		Int("sync_plans", orgs.NumPlans()).
-		Msg("Retrieved sync plans")
+	// Use a single evaluation reference time for all sync plans so that
+	// results are consistent throughout the report.
+	evalTime := time.Now()
 
 	logger.Info().Msg("Evaluating sync plans")
 
 	switch {
-	case !orgs.IsOKState():
+	case !orgs.IsOKState(evalTime):
 		logger.Warn().
 			Int("total", orgs.NumPlans()).
 			Int("enabled", orgs.NumPlansEnabled()).
 			Int("disabled", orgs.NumPlansDisabled()).
-			Int("problematic", orgs.NumProblemPlans()).
+			Int("problematic", orgs.NumProblemPlans(evalTime)).
 			Msg("Problem sync plans detected")
 
-		generateReport(os.Stdout, orgs, cfg, logger)
+		generateReport(output, orgs, skipped, cfg, evalTime, logger)
 
 	default:
 		logger.Info().Msg("No problems detected")
 
-		generateReport(os.Stdout, orgs, cfg, logger)
+		generateReport(output, orgs, skipped, cfg, evalTime, logger)
+	}
+
+	// Provide details for the unverified certificate chain so that sysadmins
+	// can see exactly what they are trusting.
+	if cfg.TrustCert && cfg.InspectorOutputFormat == config.InspectorOutputFormatVerbose {
+		_, _ = fmt.Fprintln(output, reports.TrustCertChainReport(client.PeerCertificates()))
 	}
 
 }
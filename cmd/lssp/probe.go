@@ -0,0 +1,81 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/reports"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// runProbe is a helper function used to verify connectivity to and
+// authentication with the Red Hat Satellite server specified in the given
+// configuration without retrieving organizations or sync plans. The results
+// are written to the given output and an exit code is returned.
+func runProbe(ctx context.Context, w io.Writer, cfg *config.Config, logger zerolog.Logger) int {
+	authInfo, authErr := getAuthInfo(cfg, logger)
+	if authErr != nil {
+		logger.Error().
+			Err(authErr).
+			Msg("Error preparing auth info for Red Hat Satellite instance")
+
+		return config.ExitCodeCatchall
+	}
+
+	apiLimits := rsat.APILimits{
+		PerPage:             cfg.PerPageLimit(),
+		PerPageAuto:         cfg.PerPageAuto(),
+		MaxConcurrent:       cfg.MaxConcurrent,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout(),
+		DialTimeout:         cfg.DialTimeout(),
+		DialKeepAlive:       cfg.DialKeepAlive(),
+		EnableHTTP2:         cfg.EnableHTTP2,
+		StreamDecode:        cfg.StreamDecode,
+		BulkSyncPlans:       cfg.BulkSyncPlans,
+		Retry: rsat.RetrySettings{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay(),
+			Jitter:      cfg.RetryJitter,
+		},
+	}
+
+	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+
+	logger.Info().Msg("Verifying connectivity and authentication (dry run)")
+
+	result, probeErr := rsat.Probe(ctx, client)
+
+	_, _ = fmt.Fprintln(w, reports.ProbeReport(result))
+
+	switch {
+	case rsat.IsAuthenticationFailure(probeErr):
+		logger.Error().
+			Err(probeErr).
+			Msg("Dry run failed; authentication failed, verify credentials and permissions")
+
+		return config.ExitCodeCatchall
+
+	case probeErr != nil:
+		logger.Error().
+			Err(probeErr).
+			Msg("Dry run failed")
+
+		return config.ExitCodeCatchall
+	}
+
+	logger.Info().Msg("Dry run succeeded; connectivity and authentication verified")
+
+	return 0
+}
@@ -10,6 +10,9 @@ package main
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/reports"
@@ -17,21 +20,73 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func generateReport(w io.Writer, orgs rsat.Organizations, cfg *config.Config, logger zerolog.Logger) {
+func generateReport(w io.Writer, orgs rsat.Organizations, skipped rsat.SkippedItems, cfg *config.Config, evalTime time.Time, logger zerolog.Logger) {
 	logger.Info().Msg("Generating sync plans report")
 
 	switch cfg.InspectorOutputFormat {
 	case config.InspectorOutputFormatOverview:
-		_, _ = fmt.Fprintln(w, reports.SyncPlansOverviewReport(orgs, cfg, logger))
+		_, _ = fmt.Fprintln(w, reports.SyncPlansOverviewReport(orgs, cfg, evalTime, logger))
 
 	case config.InspectorOutputFormatSimpleTable:
-		_, _ = fmt.Fprintln(w, reports.SyncPlansSimpleTableReport(orgs, cfg, logger))
+		_, _ = fmt.Fprintln(w, reports.SyncPlansSimpleTableReport(orgs, cfg, evalTime, logger))
 
 	case config.InspectorOutputFormatPrettyTable:
-		_, _ = fmt.Fprintln(w, reports.SyncPlansPrettyTableReport(orgs, cfg, logger))
+		_, _ = fmt.Fprintln(w, reports.SyncPlansPrettyTableReport(orgs, cfg, evalTime, logger))
 
 	case config.InspectorOutputFormatVerbose:
-		_, _ = fmt.Fprintln(w, reports.SyncPlansVerboseReport(orgs, cfg, logger))
+		_, _ = fmt.Fprintln(w, reports.SyncPlansVerboseReport(orgs, cfg, evalTime, logger))
+
+	case config.InspectorOutputFormatJSON:
+		_, _ = fmt.Fprintln(w, reports.SyncPlansJSONReport(orgs, skipped, cfg, evalTime, logger))
+
+	case config.InspectorOutputFormatCSV:
+		_, _ = fmt.Fprint(w, reports.SyncPlansCSVReport(orgs, cfg, evalTime, logger))
+
+	case config.InspectorOutputFormatMarkdown:
+		_, _ = fmt.Fprint(w, reports.SyncPlansMarkdownReport(orgs, cfg, evalTime, logger))
+
+	case config.InspectorOutputFormatHTML:
+		_, _ = fmt.Fprint(w, reports.SyncPlansHTMLReport(orgs, cfg, evalTime, logger))
+
+	case config.InspectorOutputFormatByProduct:
+		_, _ = fmt.Fprintln(w, reports.SyncPlansByProductReport(orgs, cfg, evalTime, logger))
+	}
+
+}
+
+// reportOutput is a helper function used to provide the destination for
+// generated reports. If the user specified an output file, that file is
+// created (or truncated) and used, otherwise stdout is used. A function is
+// returned for the caller to close the destination once reports have been
+// written.
+func reportOutput(cfg *config.Config, logger zerolog.Logger) (io.Writer, func(), error) {
+	if cfg.OutputFile == "" {
+		return os.Stdout, func() {}, nil
+	}
+
+	logger.Info().
+		Str("output_file", cfg.OutputFile).
+		Str("output_format", cfg.InspectorOutputFormat).
+		Msg("Writing report to specified output file")
+
+	fh, err := os.Create(filepath.Clean(cfg.OutputFile))
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("output_file", cfg.OutputFile).
+			Msg("Error creating output file")
+
+		return nil, nil, err
+	}
+
+	closeFunc := func() {
+		if closeErr := fh.Close(); closeErr != nil {
+			logger.Error().
+				Err(closeErr).
+				Str("output_file", cfg.OutputFile).
+				Msg("Error closing output file")
+		}
 	}
 
+	return fh, closeFunc, nil
 }
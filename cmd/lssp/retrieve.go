@@ -0,0 +1,183 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/retrieval"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// retrieveOrgs is a helper function used to prepare an API client for the
+// Red Hat Satellite server specified in the given configuration and retrieve
+// all organizations along with their sync plans.
+func retrieveOrgs(ctx context.Context, cfg *config.Config, logger zerolog.Logger) (rsat.Organizations, rsat.SkippedItems, *rsat.APIClient, error) {
+	authInfo, authErr := getAuthInfo(cfg, logger)
+	if authErr != nil {
+		logger.Error().
+			Err(authErr).
+			Msg("Error preparing auth info for Red Hat Satellite instance")
+
+		return nil, nil, nil, authErr
+	}
+
+	apiLimits := rsat.APILimits{
+		PerPage:             cfg.PerPageLimit(),
+		PerPageAuto:         cfg.PerPageAuto(),
+		MaxConcurrent:       cfg.MaxConcurrent,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout(),
+		DialTimeout:         cfg.DialTimeout(),
+		DialKeepAlive:       cfg.DialKeepAlive(),
+		EnableHTTP2:         cfg.EnableHTTP2,
+		StreamDecode:        cfg.StreamDecode,
+		BulkSyncPlans:       cfg.BulkSyncPlans,
+		Retry: rsat.RetrySettings{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay(),
+			Jitter:      cfg.RetryJitter,
+		},
+	}
+
+	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+
+	if cfg.FixturesMode() {
+		logger.Info().
+			Str("fixtures_dir", cfg.FixturesDir).
+			Msg("Reading saved API responses instead of submitting requests")
+	}
+
+	logger.Info().
+		Str("timeout", cfg.Timeout().String()).
+		Msg("Retrieving Red Hat Satellite sync plans (this may take a while)")
+
+	retrievalStart := time.Now()
+	orgs, orgsSkipped, orgsFetchErr := retrieval.GetOrgsWithSyncPlans(ctx, client, cfg, logger, zerolog.InfoLevel)
+
+	// Record the retrieval time for inclusion in report summaries.
+	cfg.RetrievalTime = time.Since(retrievalStart)
+
+	// Note the time spent on each API endpoint regardless of whether
+	// retrieval succeeded to help diagnose slow retrieval.
+	defer client.LogMetrics()
+
+	if errors.Is(orgsFetchErr, rsat.ErrReadLimitReached) {
+		logger.Error().
+			Err(orgsFetchErr).
+			Int64("read_limit", cfg.ReadLimit).
+			Int("page_limit", cfg.PerPageLimit()).
+			Msgf(
+				"Read limit reached; increase --%s or lower --%s",
+				config.ReadLimitFlagLong,
+				config.PerPageLimitFlagLong,
+			)
+
+		return nil, nil, client, orgsFetchErr
+	}
+
+	if rsat.IsAuthenticationFailure(orgsFetchErr) {
+		logger.Error().
+			Err(orgsFetchErr).
+			Msg("Authentication failed; verify credentials and permissions")
+
+		return nil, nil, client, orgsFetchErr
+	}
+
+	if rsat.IsMaintenanceMode(orgsFetchErr) {
+		logger.Error().
+			Err(orgsFetchErr).
+			Msg("Satellite in maintenance mode; sync plans not retrieved")
+
+		return nil, nil, client, orgsFetchErr
+	}
+
+	if orgsFetchErr != nil {
+		logger.Error().
+			Err(orgsFetchErr).
+			Msg("Error retrieving Red Hat Satellite sync plans")
+
+		return nil, nil, client, orgsFetchErr
+	}
+
+	if cfg.CheckRecurringLogic {
+		logger.Info().Msg("Retrieving Red Hat Satellite recurring logics")
+
+		logics, logicsErr := rsat.GetRecurringLogics(ctx, client, rsat.QueryOptions{})
+		if logicsErr != nil {
+			logger.Error().
+				Err(logicsErr).
+				Msg("Error retrieving Red Hat Satellite recurring logics")
+
+			return nil, nil, client, logicsErr
+		}
+
+		orgs.SetRecurringLogics(logics)
+	}
+
+	if cfg.OwnerOrgParameter != "" {
+		logger.Info().Msg("Retrieving Red Hat Satellite organization parameters")
+
+		if err := rsat.SetOrgOwners(ctx, client, orgs, cfg.OwnerOrgParameter); err != nil {
+			logger.Error().
+				Err(err).
+				Msg("Error retrieving Red Hat Satellite organization parameters")
+
+			return nil, nil, client, err
+		}
+	}
+
+	logger.Info().
+		Int("organizations", orgs.NumOrgs()).
+		Int("sync_plans", orgs.NumPlans()).
+		Msg("Retrieved sync plans")
+
+	orgs.SetProductSyncStateEvaluation(cfg.EvaluateProductSyncState)
+	orgs.SetMaxProductSyncAge(cfg.MaxProductSyncAge.Multiplier(), cfg.MaxProductSyncAge.Duration())
+	orgs.SetMaxIntervalDrift(cfg.MaxIntervalDrift)
+	orgs.SetSyncPlanOwners(cfg.OwnerRegexp())
+
+	skipped := orgsSkipped
+
+	// Note optional capabilities which were requested but disabled in order
+	// to limit API calls to organization and sync plan listing endpoints.
+	for _, flagName := range cfg.BasicModeSkipped() {
+		skipped.Add(
+			rsat.SkippedItemTypeCapability,
+			flagName,
+			rsat.SkipRuleBasicMode,
+			"requires API access beyond listing organizations and sync plans",
+		)
+	}
+
+	// Honor requests from Red Hat Satellite admins to exclude specific
+	// organizations or sync plans from monitoring unless overridden.
+	if !cfg.IgnoreSuppressionTags {
+		var suppressed rsat.SkippedItems
+		orgs, suppressed = orgs.ApplySuppressionTags()
+		skipped = append(skipped, suppressed...)
+	}
+
+	skipped = append(skipped, orgs.IgnoreSyncPlans(cfg.IgnorePlans)...)
+	skipped = append(skipped, orgs.ExcludeProducts(cfg.ExcludeProducts)...)
+	for _, item := range skipped {
+		logger.Info().
+			Str("type", item.Type).
+			Str("item", item.Item).
+			Str("rule", item.Rule).
+			Str("reason", item.Reason).
+			Msg("Skipped item evaluation")
+	}
+
+	return orgs, skipped, client, nil
+}
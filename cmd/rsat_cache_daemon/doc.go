@@ -0,0 +1,24 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Shared cache daemon for Red Hat Satellite sync plans.
+//
+// This daemon retrieves organizations and sync plans from a Red Hat Satellite
+// server at most once per cache TTL and shares them via a Unix socket with
+// plugins and CLI apps from this project running on the same system. This
+// reduces API load when multiple plugins evaluate the same Red Hat Satellite
+// server.
+//
+// See our [GitHub repo]:
+//
+//   - to review documentation (including examples)
+//   - for the latest code
+//   - to file an issue or submit improvements for review and potential
+//     inclusion into the project
+//
+// [GitHub repo]: https://github.com/atc0005/check-rsat
+package main
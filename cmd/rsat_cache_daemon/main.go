@@ -0,0 +1,162 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:generate go-winres make --product-version=git-tag --file-version=git-tag
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/atc0005/check-rsat/internal/cache"
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/check-rsat/internal/schema"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	// Setup configuration by parsing user-provided flags.
+	cfg, cfgErr := config.New(config.AppType{CacheDaemon: true})
+
+	switch {
+	case errors.Is(cfgErr, config.ErrVersionRequested):
+		fmt.Println(config.Version())
+
+		return
+
+	case errors.Is(cfgErr, config.ErrSchemaRequested):
+		fmt.Println(string(schema.SyncPlan()))
+
+		return
+
+	case errors.Is(cfgErr, config.ErrHelpRequested):
+		fmt.Println(cfg.Help())
+
+		return
+
+	case cfgErr != nil:
+		// We make some assumptions when setting up our logger as we do not
+		// have a working configuration based on sysadmin-specified choices.
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}
+		logger := zerolog.New(consoleWriter).With().Timestamp().Caller().Logger()
+
+		logger.Err(cfgErr).Msg("Error initializing application")
+		os.Exit(config.ExitCodeCatchall)
+	}
+
+	logger := cfg.Log.With().
+		Str("server", cfg.Server).
+		Str("user", cfg.Username).
+		Int("port", cfg.TCPPort).
+		Str("socket", cfg.CacheSocket).
+		Str("cache_ttl", cfg.CacheTTL().String()).
+		Logger()
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error().Err(err).Msg("Cache daemon stopped")
+		os.Exit(config.ExitCodeCatchall)
+	}
+
+	logger.Info().Msg("Cache daemon stopped")
+}
+
+// run prepares an API client for the Red Hat Satellite server specified in
+// the given configuration and serves cached organizations and sync plans
+// until an interrupt or termination signal is received.
+func run(cfg *config.Config, logger zerolog.Logger) error {
+	// If specified, attempt to load the CA certificate associated with the
+	// Red Hat Satellite server's certificate chain.
+	var caCert []byte
+	if cfg.CACertificate != "" {
+		var readErr error
+		caCert, readErr = os.ReadFile(filepath.Clean(cfg.CACertificate))
+		if readErr != nil {
+			return fmt.Errorf("failed to load CA certificate: %w", readErr)
+		}
+
+		logger.Debug().Msg("Successfully loaded CA cert")
+	}
+
+	// If specified, attempt to load the client certificate presented to
+	// servers requiring mutual TLS authentication.
+	var clientCert *tls.Certificate
+	if cfg.ClientCertificate != "" {
+		cert, loadErr := tls.LoadX509KeyPair(
+			filepath.Clean(cfg.ClientCertificate),
+			filepath.Clean(cfg.ClientKey),
+		)
+		if loadErr != nil {
+			return fmt.Errorf("failed to load client certificate: %w", loadErr)
+		}
+		clientCert = &cert
+
+		logger.Debug().Msg("Successfully loaded client cert and key")
+	}
+
+	authInfo := rsat.APIAuthInfo{
+		Server:                 cfg.Server,
+		Port:                   cfg.TCPPort,
+		NetworkType:            cfg.NetworkType,
+		DNSServer:              cfg.DNSServer,
+		DNSTimeout:             cfg.DNSTimeout(),
+		ReadLimit:              cfg.ReadLimit,
+		Username:               cfg.Username,
+		Password:               cfg.Password,
+		Token:                  cfg.Token,
+		OAuthConsumerKey:       cfg.OAuthConsumerKey,
+		OAuthConsumerSecret:    cfg.OAuthConsumerSecret,
+		UserAgent:              cfg.UserAgent(),
+		TrustCert:              cfg.TrustCert,
+		PermitTLSRenegotiation: cfg.PermitTLSRenegotiation,
+		CACert:                 caCert,
+		ClientCertificate:      clientCert,
+	}
+
+	apiLimits := rsat.APILimits{
+		PerPage:             cfg.PerPageLimit(),
+		PerPageAuto:         cfg.PerPageAuto(),
+		MaxConcurrent:       cfg.MaxConcurrent,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.IdleConnTimeout(),
+		DialTimeout:         cfg.DialTimeout(),
+		DialKeepAlive:       cfg.DialKeepAlive(),
+		EnableHTTP2:         cfg.EnableHTTP2,
+		StreamDecode:        cfg.StreamDecode,
+		BulkSyncPlans:       cfg.BulkSyncPlans,
+		Retry: rsat.RetrySettings{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay(),
+			Jitter:      cfg.RetryJitter,
+		},
+	}
+
+	client := rsat.NewAPIClient(authInfo, apiLimits, logger)
+
+	srv := cache.NewServer(client, cfg.CacheTTL(), cfg.Timeout(), logger)
+
+	listener, err := cache.Listen(cfg.CacheSocket)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info().Msg("Cache daemon listening for requests")
+
+	return srv.Serve(ctx, listener)
+}
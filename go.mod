@@ -12,6 +12,7 @@ go 1.20
 require (
 	github.com/atc0005/go-nagios v0.19.0
 	github.com/rs/zerolog v1.33.0
+	golang.org/x/sys v0.28.0
 	zgo.at/acidtab v1.1.0
 )
 
@@ -19,7 +20,6 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	golang.org/x/sys v0.28.0 // indirect
 	zgo.at/runewidth v0.1.0 // indirect
 	zgo.at/termtext v1.5.0 // indirect
 )
@@ -0,0 +1,378 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// testSocketPath returns a path for a Unix socket in a temporary directory
+// removed when the test completes. The path is kept short as Unix socket
+// paths are limited to roughly 100 characters.
+func testSocketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "rsat")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "cache.sock")
+}
+
+// startServer starts serving requests using the given server on a new Unix
+// socket and returns the socket path. The server is stopped when the test
+// completes.
+func startServer(t *testing.T, srv *Server) string {
+	t.Helper()
+
+	socketPath := testSocketPath(t)
+
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := srv.Serve(ctx, listener); err != nil {
+			t.Errorf("unexpected error from Serve: %v", err)
+		}
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+
+	return socketPath
+}
+
+// countingFetch returns a FetchFunc which returns the given organizations
+// and error along with a function reporting the number of calls made.
+func countingFetch(orgs rsat.Organizations, err error) (FetchFunc, func() int) {
+	var mu sync.Mutex
+	var calls int
+
+	fetch := func(context.Context) (rsat.Organizations, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+
+		return orgs, err
+	}
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return calls
+	}
+
+	return fetch, count
+}
+
+func testOrgs() rsat.Organizations {
+	return rsat.Organizations{
+		{
+			ID:    1,
+			Name:  "Alpha Org",
+			Label: "alpha",
+			Title: "Alpha Org",
+			SyncPlans: rsat.SyncPlans{
+				{
+					ID:              10,
+					Name:            "Daily RHEL",
+					Interval:        "daily",
+					Enabled:         true,
+					MinutePrecision: true,
+					NextSync:        rsat.SyncTime(time.Date(2023, 6, 1, 2, 0, 0, 0, time.UTC)),
+					Products: rsat.Products{
+						{ID: 100, Name: "RHEL", SyncState: "Syncing Complete."},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestServerSharesRetrievedSyncPlans(t *testing.T) {
+	t.Parallel()
+
+	fetch, calls := countingFetch(testOrgs(), nil)
+
+	srv := &Server{
+		Fetch:   fetch,
+		Logger:  zerolog.Nop(),
+		Server:  "rsat.example.com",
+		Port:    443,
+		TTL:     time.Minute,
+		Timeout: time.Minute,
+	}
+
+	socketPath := startServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			orgs, retrievedAt, err := GetOrgsWithSyncPlans(ctx, socketPath, "RSAT.example.com", 443)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if retrievedAt.IsZero() {
+				t.Error("want retrieval time, got zero value")
+			}
+
+			if got := orgs.NumPlans(); got != 1 {
+				t.Errorf("want 1 sync plan, got %d", got)
+				return
+			}
+
+			plan := orgs[0].SyncPlans[0]
+			switch {
+			case plan.OrganizationName != "Alpha Org":
+				t.Errorf("want organization name restored, got %q", plan.OrganizationName)
+			case !plan.MinutePrecision:
+				t.Error("want minute precision preserved")
+			case len(plan.Products) != 1:
+				t.Errorf("want 1 product, got %d", len(plan.Products))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls(); got != 1 {
+		t.Errorf("want 1 retrieval for concurrent requests, got %d", got)
+	}
+}
+
+func TestServerRejectsMismatchedServer(t *testing.T) {
+	t.Parallel()
+
+	fetch, calls := countingFetch(testOrgs(), nil)
+
+	srv := &Server{
+		Fetch:   fetch,
+		Logger:  zerolog.Nop(),
+		Server:  "rsat.example.com",
+		Port:    443,
+		TTL:     time.Minute,
+		Timeout: time.Minute,
+	}
+
+	socketPath := startServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := GetOrgsWithSyncPlans(ctx, socketPath, "other.example.com", 443)
+	if !errors.Is(err, ErrServerMismatch) {
+		t.Fatalf("want error %v, got %v", ErrServerMismatch, err)
+	}
+
+	if got := calls(); got != 0 {
+		t.Errorf("want no retrieval for rejected request, got %d", got)
+	}
+}
+
+func TestServerDoesNotCacheFailedRetrieval(t *testing.T) {
+	t.Parallel()
+
+	fetch, calls := countingFetch(nil, errors.New("connection refused"))
+
+	srv := &Server{
+		Fetch:   fetch,
+		Logger:  zerolog.Nop(),
+		Server:  "rsat.example.com",
+		Port:    443,
+		TTL:     time.Minute,
+		Timeout: time.Minute,
+	}
+
+	socketPath := startServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		_, _, err := GetOrgsWithSyncPlans(ctx, socketPath, "rsat.example.com", 443)
+		if !errors.Is(err, ErrRetrievalFailed) {
+			t.Fatalf("want error %v, got %v", ErrRetrievalFailed, err)
+		}
+	}
+
+	if got := calls(); got != 2 {
+		t.Errorf("want 2 retrievals, got %d", got)
+	}
+}
+
+func TestServerReusesAuthenticationFailure(t *testing.T) {
+	t.Parallel()
+
+	fetch, calls := countingFetch(nil, fmt.Errorf("response 401: %w", rsat.ErrAuthenticationFailed))
+
+	srv := &Server{
+		Fetch:   fetch,
+		Logger:  zerolog.Nop(),
+		Server:  "rsat.example.com",
+		Port:    443,
+		TTL:     time.Minute,
+		Timeout: time.Minute,
+	}
+
+	socketPath := startServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		_, _, err := GetOrgsWithSyncPlans(ctx, socketPath, "rsat.example.com", 443)
+		if !errors.Is(err, ErrRetrievalFailed) {
+			t.Fatalf("want error %v, got %v", ErrRetrievalFailed, err)
+		}
+	}
+
+	if got := calls(); got != 1 {
+		t.Errorf("want 1 retrieval attempt with invalid credentials, got %d", got)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	t.Parallel()
+
+	socketPath := testSocketPath(t)
+
+	first, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	// A second listener is refused while the first is active.
+	if second, err := Listen(socketPath); err == nil {
+		_ = second.Close()
+		t.Fatal("want error listening on active socket, got nil")
+	}
+
+	// Simulate an unclean shutdown by leaving the socket file behind.
+	if unixListener, ok := first.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+	_ = first.Close()
+
+	if _, err := os.Stat(socketPath); err != nil {
+		t.Fatalf("want stale socket file to remain: %v", err)
+	}
+
+	replacement, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("want stale socket replaced, got %v", err)
+	}
+	_ = replacement.Close()
+}
+
+func TestGetOrgsWithSyncPlansUnavailableDaemon(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := GetOrgsWithSyncPlans(ctx, testSocketPath(t), "rsat.example.com", 443)
+	if err == nil {
+		t.Fatal("want error for unavailable cache daemon, got nil")
+	}
+}
+
+func TestDiskCacheRoundTrip(t *testing.T) {
+	dc := DiskCache{Dir: filepath.Join(t.TempDir(), "cache"), TTL: time.Minute}
+
+	if _, _, err := dc.Read("rsat.example.com", 443); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v for missing cache file, want %v", err, os.ErrNotExist)
+	}
+
+	retrievedAt := time.Now().Add(-30 * time.Second)
+	if err := dc.Write("rsat.example.com", 443, testOrgs(), retrievedAt); err != nil {
+		t.Fatalf("failed to write cache file: %v", err)
+	}
+
+	orgs, gotRetrievedAt, err := dc.Read("RSAT.example.com", 443)
+	if err != nil {
+		t.Fatalf("failed to read cache file: %v", err)
+	}
+
+	if !gotRetrievedAt.Equal(retrievedAt) {
+		t.Errorf("got retrieval time %v, want %v", gotRetrievedAt, retrievedAt)
+	}
+
+	if got, want := orgs.NumPlans(), testOrgs().NumPlans(); got != want {
+		t.Errorf("got %d sync plans, want %d", got, want)
+	}
+
+	if _, _, err := dc.Read("rsat.example.com", 8443); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v for different port, want %v", err, os.ErrNotExist)
+	}
+
+	stale := DiskCache{Dir: dc.Dir, TTL: 10 * time.Second}
+	if _, _, err := stale.Read("rsat.example.com", 443); !errors.Is(err, ErrCacheStale) {
+		t.Errorf("got error %v for stale cache file, want %v", err, ErrCacheStale)
+	}
+}
+
+func TestDiskCacheSeparatesAccounts(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	admin := DiskCache{Dir: dir, TTL: time.Minute, Username: "admin"}
+	limited := DiskCache{Dir: dir, TTL: time.Minute, Username: "monitoring"}
+
+	if err := admin.Write("rsat.example.com", 443, testOrgs(), time.Now()); err != nil {
+		t.Fatalf("failed to write cache file: %v", err)
+	}
+
+	if _, _, err := limited.Read("rsat.example.com", 443); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got error %v for different account, want %v", err, os.ErrNotExist)
+	}
+
+	if _, _, err := admin.Read("rsat.example.com", 443); err != nil {
+		t.Errorf("failed to read cache file for same account: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read cache directory: %v", err)
+	}
+
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), admin.Username) {
+			t.Errorf("cache file name %q exposes account name %q", entry.Name(), admin.Username)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+)
+
+// maxResponseSize is the maximum size in bytes of a response read from the
+// cache daemon. The limit is set OVERLY generous as a response contains all
+// organizations and sync plans for a Red Hat Satellite server.
+const maxResponseSize int64 = 100 * 1048576
+
+// GetOrgsWithSyncPlans retrieves all organizations along with their sync
+// plans for the given Red Hat Satellite server and port from the cache
+// daemon listening on the given Unix socket. The time that the organizations
+// and sync plans were retrieved from the Red Hat Satellite server is also
+// returned.
+//
+// An error is returned if the cache daemon is unavailable, serves a
+// different server or was unable to retrieve the requested values. Callers
+// are expected to fall back to retrieving organizations and sync plans
+// directly from the Red Hat Satellite server.
+func GetOrgsWithSyncPlans(ctx context.Context, socketPath string, server string, port int) (rsat.Organizations, time.Time, error) {
+	if socketPath == "" {
+		return nil, time.Time{}, fmt.Errorf(
+			"required socket path was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unix", socketPath)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf(
+			"failed to connect to cache daemon: %w",
+			err,
+		)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := Request{
+		Server: server,
+		Port:   port,
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, time.Time{}, fmt.Errorf(
+			"failed to send request to cache daemon: %w",
+			err,
+		)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(io.LimitReader(conn, maxResponseSize)).Decode(&resp); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, time.Time{}, fmt.Errorf(
+			"failed to decode response from cache daemon: %w",
+			err,
+		)
+	}
+
+	switch {
+	case !req.matches(resp.Server, resp.Port):
+		return nil, time.Time{}, fmt.Errorf(
+			"%w: requested %s:%d, cache daemon serves %s:%d",
+			ErrServerMismatch,
+			server,
+			port,
+			resp.Server,
+			resp.Port,
+		)
+
+	case resp.Error != "":
+		return nil, time.Time{}, fmt.Errorf(
+			"%w: %s",
+			ErrRetrievalFailed,
+			resp.Error,
+		)
+	}
+
+	return decodeOrgs(resp.Organizations), resp.RetrievedAt, nil
+}
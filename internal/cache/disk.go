@@ -0,0 +1,201 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+)
+
+// diskCacheVersion is the version of the on-disk cache file format. Cache
+// files using a different format version are ignored.
+const diskCacheVersion int = 1
+
+// diskCacheFilePrefix is the prefix used for cache file names.
+const diskCacheFilePrefix string = "sync-plans_"
+
+// unsafeFileNameChars matches characters which are replaced when deriving a
+// cache file name from a server name.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+// DiskCache is a cache of Red Hat Satellite organizations and sync plans
+// stored as files within a directory so that frequently scheduled plugins
+// (or CLI apps) reuse recently retrieved values instead of repeatedly
+// querying a slow Red Hat Satellite server. A separate file is used for
+// each Red Hat Satellite server, port and user account so that accounts
+// with different access (e.g., to a single organization) do not share
+// cached values.
+type DiskCache struct {
+	// Dir is the directory where cache files are stored.
+	Dir string
+
+	// TTL is the maximum age of cached values before they are considered
+	// stale and retrieved again.
+	TTL time.Duration
+
+	// Username is the user account used to retrieve the cached values from
+	// the Red Hat Satellite server. Only a hash of this value is recorded.
+	Username string
+}
+
+// diskCacheEntry is the encoded form of a cache file.
+type diskCacheEntry struct {
+	// Version is the cache file format version.
+	Version int `json:"version"`
+
+	// Server is the Red Hat Satellite server FQDN or IP Address.
+	Server string `json:"server"`
+
+	// RetrievedAt is when the cached organizations and sync plans were
+	// retrieved from the Red Hat Satellite server.
+	RetrievedAt time.Time `json:"retrieved_at"`
+
+	// Organizations is the cached collection of organizations along with
+	// their sync plans.
+	Organizations []organization `json:"organizations"`
+
+	// Port is the port used by the Red Hat Satellite server API.
+	Port int `json:"port"`
+
+	// Account is the hash of the user account used to retrieve the cached
+	// organizations and sync plans.
+	Account string `json:"account"`
+}
+
+// account returns the hash of the user account used to retrieve cached
+// values. A hash is used so that the account name is not exposed via cache
+// file names.
+func (dc DiskCache) account() string {
+	sum := sha256.Sum256([]byte(dc.Username))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// path returns the path to the cache file for the given Red Hat Satellite
+// server and port.
+func (dc DiskCache) path(server string, port int) string {
+	name := unsafeFileNameChars.ReplaceAllString(strings.ToLower(server), "_")
+
+	return filepath.Join(
+		dc.Dir,
+		diskCacheFilePrefix+name+"_"+strconv.Itoa(port)+"_"+dc.account()+".json",
+	)
+}
+
+// Read retrieves the cached organizations along with their sync plans for
+// the given Red Hat Satellite server and port along with when they were
+// retrieved from the server. An error is returned if the cache file does
+// not exist, cannot be decoded or is older than the cache TTL.
+func (dc DiskCache) Read(server string, port int) (rsat.Organizations, time.Time, error) {
+	path := dc.path(server, port)
+
+	content, readErr := os.ReadFile(filepath.Clean(path))
+	if readErr != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read cache file: %w", readErr)
+	}
+
+	var entry diskCacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to decode cache file %s: %w", path, err)
+	}
+
+	switch {
+	case entry.Version != diskCacheVersion:
+		return nil, time.Time{}, fmt.Errorf(
+			"cache file %s uses format version %d, expected %d: %w",
+			path,
+			entry.Version,
+			diskCacheVersion,
+			ErrCacheStale,
+		)
+
+	case !strings.EqualFold(entry.Server, server) || entry.Port != port:
+		return nil, time.Time{}, fmt.Errorf(
+			"cache file %s is for %s:%d: %w",
+			path,
+			entry.Server,
+			entry.Port,
+			ErrServerMismatch,
+		)
+
+	case entry.Account != dc.account():
+		return nil, time.Time{}, fmt.Errorf(
+			"cache file %s is for a different user account: %w",
+			path,
+			ErrServerMismatch,
+		)
+
+	case time.Since(entry.RetrievedAt) > dc.TTL:
+		return nil, time.Time{}, fmt.Errorf(
+			"cache file %s retrieved at %s is older than %s: %w",
+			path,
+			entry.RetrievedAt.Format(time.RFC3339),
+			dc.TTL,
+			ErrCacheStale,
+		)
+	}
+
+	return decodeOrgs(entry.Organizations), entry.RetrievedAt, nil
+}
+
+// Write stores the given organizations along with their sync plans for the
+// given Red Hat Satellite server and port. The cache directory is created
+// if it does not already exist. The cache file is replaced atomically so
+// that concurrent readers do not observe a partially written file.
+func (dc DiskCache) Write(server string, port int, orgs rsat.Organizations, retrievedAt time.Time) error {
+	entry := diskCacheEntry{
+		Version:       diskCacheVersion,
+		Server:        server,
+		Port:          port,
+		Account:       dc.account(),
+		RetrievedAt:   retrievedAt,
+		Organizations: encodeOrgs(orgs),
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache file: %w", err)
+	}
+
+	if err := os.MkdirAll(dc.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dc.Dir, diskCacheFilePrefix+"*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary cache file: %w", err)
+	}
+
+	// Remove the temporary file if it was not renamed.
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temporary cache file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary cache file: %w", err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), dc.path(server, port)); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+
+	return nil
+}
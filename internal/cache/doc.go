@@ -0,0 +1,25 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package cache provides a shared cache of Red Hat Satellite organizations and
+// sync plans served over a Unix socket.
+//
+// When multiple plugins (or CLI apps) from this project run on the same
+// system against the same Red Hat Satellite server, each normally retrieves
+// the same organizations and sync plans independently. A cache daemon uses
+// the Server type provided by this package to retrieve organizations and
+// sync plans once per cache TTL and share the results with each client
+// requesting them via the GetOrgsWithSyncPlans function.
+//
+// Alternatively, the DiskCache type stores organizations and sync plans as
+// files within a directory so that frequently scheduled plugins (or CLI
+// apps) reuse recently retrieved values without running a cache daemon.
+//
+// Only the values retrieved from the Red Hat Satellite API are shared;
+// clients apply their own evaluation settings (e.g., product sync state
+// evaluation, owner annotation) to the retrieved values.
+package cache
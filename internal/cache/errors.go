@@ -0,0 +1,29 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import "errors"
+
+var (
+	// ErrMissingValue indicates that an expected value was missing.
+	ErrMissingValue = errors.New("missing expected value")
+
+	// ErrServerMismatch indicates that the cache daemon serves a different
+	// Red Hat Satellite server than the one requested.
+	ErrServerMismatch = errors.New("cache daemon serves a different server")
+
+	// ErrCacheStale indicates that cached organizations and sync plans are
+	// older than the cache TTL (or use an unsupported format) and should be
+	// retrieved again.
+	ErrCacheStale = errors.New("cached sync plans are stale")
+
+	// ErrRetrievalFailed indicates that the cache daemon was unable to
+	// retrieve organizations and sync plans from the Red Hat Satellite
+	// server.
+	ErrRetrievalFailed = errors.New("cache daemon failed to retrieve sync plans")
+)
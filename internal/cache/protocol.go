@@ -0,0 +1,121 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import (
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+)
+
+// Request is sent by a client to the cache daemon to request organizations
+// and sync plans for a Red Hat Satellite server. Each request is encoded as
+// a single JSON object.
+type Request struct {
+	// Server is the Red Hat Satellite server FQDN or IP Address that the
+	// client is configured to evaluate.
+	Server string `json:"server"`
+
+	// Port is the port used by the Red Hat Satellite server API that the
+	// client is configured to evaluate.
+	Port int `json:"port"`
+}
+
+// Response is sent by the cache daemon in reply to a Request. Each response
+// is encoded as a single JSON object.
+type Response struct {
+	// Server is the Red Hat Satellite server FQDN or IP Address served by
+	// the cache daemon.
+	Server string `json:"server"`
+
+	// Error is the reason the request could not be fulfilled. This value is
+	// empty for successful requests.
+	Error string `json:"error,omitempty"`
+
+	// RetrievedAt is when the cached organizations and sync plans were
+	// retrieved from the Red Hat Satellite server.
+	RetrievedAt time.Time `json:"retrieved_at"`
+
+	// Organizations is the cached collection of organizations along with
+	// their sync plans.
+	Organizations []organization `json:"organizations,omitempty"`
+
+	// Port is the port used by the Red Hat Satellite server API served by
+	// the cache daemon.
+	Port int `json:"port"`
+}
+
+// organization is the encoded form of an organization along with its sync
+// plans. Sync plans are not encoded as part of the organization when using
+// the rsat.Organization type as-is.
+type organization struct {
+	Organization rsat.Organization `json:"organization"`
+	SyncPlans    []syncPlan        `json:"sync_plans"`
+}
+
+// syncPlan is the encoded form of a sync plan along with values determined
+// at retrieval time which are not otherwise encoded.
+type syncPlan struct {
+	SyncPlan        rsat.SyncPlan `json:"sync_plan"`
+	MinutePrecision bool          `json:"minute_precision"`
+}
+
+// matches indicates whether the request is for the given Red Hat Satellite
+// server and port.
+func (r Request) matches(server string, port int) bool {
+	return strings.EqualFold(r.Server, server) && r.Port == port
+}
+
+// encodeOrgs converts the given organizations to their encoded form.
+func encodeOrgs(orgs rsat.Organizations) []organization {
+	encoded := make([]organization, 0, len(orgs))
+
+	for _, org := range orgs {
+		plans := make([]syncPlan, 0, len(org.SyncPlans))
+		for _, plan := range org.SyncPlans {
+			plans = append(plans, syncPlan{
+				SyncPlan:        plan,
+				MinutePrecision: plan.MinutePrecision,
+			})
+		}
+
+		encoded = append(encoded, organization{
+			Organization: org,
+			SyncPlans:    plans,
+		})
+	}
+
+	return encoded
+}
+
+// decodeOrgs converts the given encoded organizations to an
+// rsat.Organizations collection, restoring values that are not encoded as
+// part of each organization or sync plan.
+func decodeOrgs(encoded []organization) rsat.Organizations {
+	orgs := make(rsat.Organizations, 0, len(encoded))
+
+	for _, entry := range encoded {
+		org := entry.Organization
+		org.SyncPlans = make(rsat.SyncPlans, 0, len(entry.SyncPlans))
+
+		for _, plan := range entry.SyncPlans {
+			sp := plan.SyncPlan
+			sp.MinutePrecision = plan.MinutePrecision
+			sp.OrganizationName = org.Name
+			sp.OrganizationLabel = org.Label
+			sp.OrganizationTitle = org.Title
+
+			org.SyncPlans = append(org.SyncPlans, sp)
+		}
+
+		orgs = append(orgs, org)
+	}
+
+	return orgs
+}
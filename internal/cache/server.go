@@ -0,0 +1,303 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxRequestSize is the maximum size in bytes of a request read from a
+	// client. Requests are small, so anything larger than this is invalid.
+	maxRequestSize int64 = 4096
+
+	// requestReadTimeout is the time permitted for a client to send its
+	// request once connected.
+	requestReadTimeout time.Duration = 10 * time.Second
+
+	// socketPermissions are the permissions applied to the Unix socket so
+	// that plugins running as a different user within the same group (e.g.,
+	// nagios) are able to connect.
+	socketPermissions os.FileMode = 0660
+)
+
+// FetchFunc retrieves all Red Hat Satellite organizations along with their
+// sync plans.
+type FetchFunc func(ctx context.Context) (rsat.Organizations, error)
+
+// Server serves cached Red Hat Satellite organizations and sync plans to
+// clients connecting via a Unix socket. Organizations and sync plans are
+// retrieved on demand and reused for subsequent requests until the cache TTL
+// has elapsed. Concurrent requests received while a retrieval is in progress
+// wait for (and share) the results of that retrieval.
+type Server struct {
+	// Fetch is used to retrieve organizations and sync plans when the cache
+	// is empty or has expired.
+	Fetch FetchFunc
+
+	// Logger is the logger used by the cache daemon.
+	Logger zerolog.Logger
+
+	// Server is the Red Hat Satellite server FQDN or IP Address served by
+	// the cache daemon. Requests for other servers are rejected.
+	Server string
+
+	// Port is the port used by the Red Hat Satellite server API served by
+	// the cache daemon. Requests for other ports are rejected.
+	Port int
+
+	// TTL is the time that retrieved organizations and sync plans are
+	// reused before being retrieved again.
+	TTL time.Duration
+
+	// Timeout is the time permitted for each retrieval of organizations and
+	// sync plans.
+	Timeout time.Duration
+
+	mu          sync.Mutex
+	orgs        []organization
+	retrievedAt time.Time
+
+	// authErr is the most recent authentication failure and authErrAt when
+	// it occurred. Authentication failures are reused until the cache TTL
+	// has elapsed to avoid repeatedly submitting invalid credentials.
+	authErr   error
+	authErrAt time.Time
+}
+
+// NewServer returns a Server which uses the given API client to retrieve
+// organizations and sync plans from the Red Hat Satellite server associated
+// with the client.
+func NewServer(client *rsat.APIClient, ttl time.Duration, timeout time.Duration, logger zerolog.Logger) *Server {
+	return &Server{
+		Fetch: func(ctx context.Context) (rsat.Organizations, error) {
+			// Metrics accumulate for the lifetime of the daemon.
+			defer client.LogMetrics()
+
+			return rsat.GetOrgsWithSyncPlans(ctx, client, rsat.QueryOptions{})
+		},
+		Logger:  logger,
+		Server:  client.AuthInfo.Server,
+		Port:    client.AuthInfo.Port,
+		TTL:     ttl,
+		Timeout: timeout,
+	}
+}
+
+// Listen creates a Unix socket listener at the given path. A stale socket
+// file left behind by a previous cache daemon instance is removed. An error
+// is returned if another cache daemon is already listening on the socket.
+func Listen(socketPath string) (net.Listener, error) {
+	if socketPath == "" {
+		return nil, fmt.Errorf(
+			"required socket path was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	if info, err := os.Stat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf(
+				"refusing to replace non-socket file %s",
+				socketPath,
+			)
+		}
+
+		conn, dialErr := net.DialTimeout("unix", socketPath, time.Second)
+		if dialErr == nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf(
+				"socket %s is in use by another process",
+				socketPath,
+			)
+		}
+
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf(
+				"failed to remove stale socket %s: %w",
+				socketPath,
+				err,
+			)
+		}
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to listen on socket %s: %w",
+			socketPath,
+			err,
+		)
+	}
+
+	if err := os.Chmod(socketPath, socketPermissions); err != nil {
+		_ = listener.Close()
+
+		return nil, fmt.Errorf(
+			"failed to set permissions on socket %s: %w",
+			socketPath,
+			err,
+		)
+	}
+
+	return listener, nil
+}
+
+// Serve accepts connections on the given listener and responds to requests
+// until the given context is canceled. The listener is closed when Serve
+// returns.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	if s.Fetch == nil {
+		return fmt.Errorf(
+			"required fetch function was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		go s.handle(ctx, conn)
+	}
+}
+
+// handle responds to a single client request.
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(requestReadTimeout))
+
+	var req Request
+	if err := json.NewDecoder(io.LimitReader(conn, maxRequestSize)).Decode(&req); err != nil {
+		s.Logger.Warn().Err(err).Msg("Failed to decode client request")
+
+		return
+	}
+
+	logger := s.Logger.With().
+		Str("requested_server", req.Server).
+		Int("requested_port", req.Port).
+		Logger()
+
+	resp := Response{
+		Server: s.Server,
+		Port:   s.Port,
+	}
+
+	switch {
+	case !req.matches(s.Server, s.Port):
+		logger.Warn().Msg("Rejecting request for unserved server")
+
+		resp.Error = fmt.Sprintf("%v: %s:%d", ErrServerMismatch, s.Server, s.Port)
+
+	default:
+		orgs, retrievedAt, err := s.organizations(ctx, logger)
+		switch {
+		case err != nil:
+			resp.Error = err.Error()
+		default:
+			resp.Organizations = orgs
+			resp.RetrievedAt = retrievedAt
+		}
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(requestReadTimeout))
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		logger.Warn().Err(err).Msg("Failed to send response to client")
+
+		return
+	}
+
+	logger.Debug().
+		Int("orgs", len(resp.Organizations)).
+		Str("error", resp.Error).
+		Msg("Sent response to client")
+}
+
+// organizations returns the cached organizations and sync plans along with
+// when they were retrieved, retrieving them first if the cache is empty or
+// has expired. Failed retrievals are not cached with the exception of
+// authentication failures which are reused until the cache TTL has elapsed
+// to prevent account lockout.
+func (s *Server) organizations(ctx context.Context, logger zerolog.Logger) ([]organization, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.orgs != nil && time.Since(s.retrievedAt) < s.TTL {
+		logger.Debug().
+			Str("age", time.Since(s.retrievedAt).String()).
+			Msg("Using cached sync plans")
+
+		return s.orgs, s.retrievedAt, nil
+	}
+
+	if s.authErr != nil && time.Since(s.authErrAt) < s.TTL {
+		logger.Debug().
+			Str("age", time.Since(s.authErrAt).String()).
+			Msg("Using cached authentication failure")
+
+		return nil, time.Time{}, s.authErr
+	}
+
+	logger.Info().Msg("Retrieving sync plans from Red Hat Satellite server")
+
+	fetchCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	orgs, err := s.Fetch(fetchCtx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to retrieve sync plans")
+
+		if rsat.IsAuthenticationFailure(err) {
+			s.authErr = err
+			s.authErrAt = time.Now()
+		}
+
+		return nil, time.Time{}, err
+	}
+
+	s.authErr = nil
+	s.orgs = encodeOrgs(orgs)
+	s.retrievedAt = time.Now()
+
+	logger.Info().
+		Int("orgs", orgs.NumOrgs()).
+		Int("sync_plans", orgs.NumPlans()).
+		Str("runtime", time.Since(start).String()).
+		Msg("Retrieved sync plans")
+
+	return s.orgs, s.retrievedAt, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+// applyBasicMode disables optional capabilities which require API access
+// beyond listing organizations and sync plans if basic mode was requested.
+// The flags for requested capabilities which were disabled are recorded so
+// that they can be noted in output.
+func (c *Config) applyBasicMode() {
+	if !c.BasicMode {
+		return
+	}
+
+	if c.CheckRecurringLogic {
+		c.CheckRecurringLogic = false
+		c.basicModeSkipped = append(c.basicModeSkipped, RecurringLogicFlagLong)
+	}
+
+	if c.OwnerOrgParameter != "" {
+		c.OwnerOrgParameter = ""
+		c.basicModeSkipped = append(c.basicModeSkipped, OwnerOrgParameterFlagLong)
+	}
+
+	if c.EvaluateProductSyncState {
+		c.EvaluateProductSyncState = false
+		c.basicModeSkipped = append(c.basicModeSkipped, ProductSyncStateFlagLong)
+	}
+
+	if c.MaxProductSyncAge.IsSet() {
+		c.MaxProductSyncAge = MaxSyncAge{}
+		c.basicModeSkipped = append(c.basicModeSkipped, MaxProductSyncAgeFlagLong)
+	}
+
+	if c.MaxIntervalDrift > 0 {
+		c.MaxIntervalDrift = 0
+		c.basicModeSkipped = append(c.basicModeSkipped, MaxIntervalDriftFlagLong)
+	}
+}
+
+// BasicModeSkipped returns the flags for requested capabilities which were
+// disabled because basic mode was enabled. An empty collection is returned
+// if basic mode was not enabled or no optional capabilities were requested.
+func (c Config) BasicModeSkipped() []string {
+	return c.basicModeSkipped
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ServersListStdin is the keyword used to indicate that the list of servers
+// for batch mode should be read from stdin.
+const ServersListStdin string = "-"
+
+// serverEntryCommentPrefix is the prefix used to indicate that a line in a
+// servers list is a comment and should be ignored.
+const serverEntryCommentPrefix string = "#"
+
+// ServerEntry represents a single Red Hat Satellite server listed for
+// evaluation in batch mode. Optional values override the settings specified
+// via flag for this specific server.
+//
+// Each server entry is provided on a separate line using the format:
+//
+//	server[:port] [username [password]]
+type ServerEntry struct {
+	// Server is the Red Hat Satellite server FQDN or IP Address.
+	Server string
+
+	// Username is the optional user for this specific server.
+	Username string
+
+	// Password is the optional password for this specific server.
+	Password string
+
+	// TCPPort is the optional port for this specific server.
+	TCPPort int
+}
+
+// ServerEntries is a collection of Red Hat Satellite servers listed for
+// evaluation in batch mode.
+type ServerEntries []ServerEntry
+
+// BatchMode indicates whether the user requested evaluation of a list of Red
+// Hat Satellite servers.
+func (c Config) BatchMode() bool {
+	return c.Servers != ""
+}
+
+// validateBatchOutputFormat asserts that the user-specified output format
+// supports batch mode. The CSV format provides a single header row and no
+// server column, so reports for multiple servers can not be combined into a
+// single valid CSV document.
+func (c Config) validateBatchOutputFormat() error {
+	if c.BatchMode() && strings.EqualFold(c.InspectorOutputFormat, InspectorOutputFormatCSV) {
+		return fmt.Errorf(
+			"invalid combination of flags; %s output format is not supported with the %s flag: %w",
+			InspectorOutputFormatCSV,
+			ServersFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+// ServerEntries reads the user-specified list of Red Hat Satellite servers
+// from either stdin or the specified file.
+func (c Config) ServerEntries() (ServerEntries, error) {
+	switch {
+	case !c.BatchMode():
+		return nil, fmt.Errorf(
+			"servers list not specified: %w",
+			ErrUnsupportedOption,
+		)
+
+	case c.Servers == ServersListStdin:
+		return ParseServerEntries(os.Stdin)
+
+	default:
+		fh, err := os.Open(filepath.Clean(c.Servers))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to open servers list %s: %w",
+				c.Servers,
+				err,
+			)
+		}
+		defer func() {
+			_ = fh.Close()
+		}()
+
+		return ParseServerEntries(fh)
+	}
+}
+
+// ParseServerEntries parses newline-delimited Red Hat Satellite server
+// entries from the given reader. Blank lines and lines beginning with a #
+// character are ignored.
+func ParseServerEntries(r io.Reader) (ServerEntries, error) {
+	var entries ServerEntries
+
+	scanner := bufio.NewScanner(r)
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, serverEntryCommentPrefix) {
+			continue
+		}
+
+		entry, err := parseServerEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse servers list line %d: %w",
+				lineNum,
+				err,
+			)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read servers list: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(
+			"no server entries found in servers list: %w",
+			ErrUnsupportedOption,
+		)
+	}
+
+	return entries, nil
+}
+
+// parseServerEntry is a helper function used to parse a single server entry.
+func parseServerEntry(line string) (ServerEntry, error) {
+	var entry ServerEntry
+
+	fields := strings.Fields(line)
+
+	switch {
+	case len(fields) > 3:
+		return ServerEntry{}, fmt.Errorf(
+			"%w: unexpected number of fields; got %d, expected at most 3",
+			ErrUnsupportedOption,
+			len(fields),
+		)
+	case len(fields) == 3:
+		entry.Password = fields[2]
+		fallthrough
+	case len(fields) == 2:
+		entry.Username = fields[1]
+	}
+
+	entry.Server = fields[0]
+
+	// Only attempt to split out a port if one appears to be provided; bare
+	// IPv6 addresses contain multiple colons.
+	if host, port, err := net.SplitHostPort(fields[0]); err == nil {
+		portNum, convErr := strconv.Atoi(port)
+		if convErr != nil || portNum <= 0 {
+			return ServerEntry{}, fmt.Errorf(
+				"%w: invalid TCP port number %q",
+				ErrUnsupportedOption,
+				port,
+			)
+		}
+
+		entry.Server = host
+		entry.TCPPort = portNum
+	}
+
+	return entry, nil
+}
+
+// ForServerEntry returns a copy of the configuration with settings overridden
+// by values from the given server entry.
+func (c Config) ForServerEntry(entry ServerEntry) (*Config, error) {
+	entryCfg := c
+	entryCfg.Servers = ""
+	entryCfg.Server = entry.Server
+	entryCfg.ServerList = []string{entry.Server}
+
+	if entry.TCPPort != 0 {
+		entryCfg.TCPPort = entry.TCPPort
+	}
+
+	if entry.Username != "" {
+		entryCfg.Username = entry.Username
+	}
+
+	// A password specific to the server takes precedence over a token or
+	// OAuth consumer key and secret specified for all servers.
+	if entry.Password != "" {
+		entryCfg.Password = entry.Password
+		entryCfg.Token = ""
+		entryCfg.OAuthConsumerKey = ""
+		entryCfg.OAuthConsumerSecret = ""
+	}
+
+	switch {
+	case strings.TrimSpace(entryCfg.Username) == "":
+		return nil, fmt.Errorf(
+			"%w: missing username for server %s",
+			ErrUnsupportedOption,
+			entry.Server,
+		)
+
+	case strings.TrimSpace(entryCfg.Password) == "" && strings.TrimSpace(entryCfg.Token) == "" && !entryCfg.UsesOAuth():
+		return nil, fmt.Errorf(
+			"%w: missing password, token or OAuth consumer key and secret for server %s",
+			ErrUnsupportedOption,
+			entry.Server,
+		)
+	}
+
+	return &entryCfg, nil
+}
+
+// MultiServer indicates whether the user specified multiple Red Hat
+// Satellite servers via the server flag.
+func (c Config) MultiServer() bool {
+	return len(c.ServerList) > 1
+}
+
+// ForServer returns a copy of the configuration for evaluating the given Red
+// Hat Satellite server from the user-specified list of servers. Performance
+// data metric labels are prefixed by the server name (following any
+// user-specified prefix) so that metrics for each server are distinct.
+func (c Config) ForServer(server string) *Config {
+	serverCfg := c
+	serverCfg.Server = server
+	serverCfg.ServerList = []string{server}
+
+	if c.MultiServer() && !strings.EqualFold(c.perfDataLabelPrefix, PerfDataLabelPrefixAuto) {
+		serverCfg.perfDataLabelPrefix = c.perfDataLabelPrefix + serverLabelPrefix(server)
+	}
+
+	return &serverCfg
+}
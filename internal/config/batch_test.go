@@ -0,0 +1,83 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForServerPerfDataLabelPrefix(t *testing.T) {
+	tests := map[string]struct {
+		servers []string
+		prefix  string
+		want    string
+	}{
+		"single server, user prefix": {
+			servers: []string{"rsat.example.com"},
+			prefix:  "site1_",
+			want:    "site1_",
+		},
+		"multiple servers, no prefix": {
+			servers: []string{"rsat1.example.com", "rsat.example.com"},
+			want:    "rsat_example_com_",
+		},
+		"multiple servers, user prefix": {
+			servers: []string{"rsat1.example.com", "rsat.example.com"},
+			prefix:  "site1_",
+			want:    "site1_rsat_example_com_",
+		},
+		"multiple servers, auto prefix": {
+			servers: []string{"rsat1.example.com", "rsat.example.com"},
+			prefix:  PerfDataLabelPrefixAuto,
+			want:    "rsat_example_com_",
+		},
+	}
+
+	for name, tt := range tests {
+		c := Config{
+			Server:              tt.servers[0],
+			ServerList:          tt.servers,
+			perfDataLabelPrefix: tt.prefix,
+		}
+
+		serverCfg := c.ForServer("rsat.example.com")
+
+		if got := serverCfg.PerfDataLabelPrefix(); got != tt.want {
+			t.Errorf("%s: got prefix %q, want %q", name, got, tt.want)
+		}
+
+		if serverCfg.MultiServer() {
+			t.Errorf("%s: server specific configuration reports multiple servers", name)
+		}
+	}
+}
+
+func TestValidateBatchOutputFormat(t *testing.T) {
+	tests := map[string]struct {
+		servers string
+		format  string
+		wantErr error
+	}{
+		"batch mode, csv":        {servers: "servers.txt", format: InspectorOutputFormatCSV, wantErr: ErrUnsupportedOption},
+		"batch mode, json":       {servers: "servers.txt", format: InspectorOutputFormatJSON},
+		"single server, csv":     {format: InspectorOutputFormatCSV},
+		"batch mode, stdin, csv": {servers: ServersListStdin, format: "CSV", wantErr: ErrUnsupportedOption},
+		"batch mode, table":      {servers: "servers.txt", format: InspectorOutputFormatPrettyTable},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := Config{Servers: tt.servers, InspectorOutputFormat: tt.format}
+
+			if err := c.validateBatchOutputFormat(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -32,6 +33,10 @@ var (
 	// help/usage information.
 	ErrHelpRequested = errors.New("help/usage information requested")
 
+	// ErrSchemaRequested indicates that the user requested the JSON Schema
+	// for the machine-readable output formats.
+	ErrSchemaRequested = errors.New("output schema requested")
+
 	// ErrUnsupportedOption indicates that an unsupported option was specified.
 	ErrUnsupportedOption = errors.New("unsupported option")
 
@@ -56,6 +61,12 @@ type AppType struct {
 	// intended for examining a small set of targets for
 	// informational/troubleshooting purposes.
 	Inspector bool
+
+	// CacheDaemon represents a long-running helper process which retrieves
+	// organizations and sync plans from a Red Hat Satellite server and
+	// shares them with plugins and CLI apps on the same system via a Unix
+	// socket.
+	CacheDaemon bool
 }
 
 // Config represents the application configuration as specified via
@@ -74,14 +85,58 @@ type Config struct {
 	// applications.
 	InspectorOutputFormat string
 
+	// OutputFile is the optional path to a file where Inspector type
+	// application output is written instead of stdout. If an output format
+	// is not explicitly specified it is inferred from the file extension.
+	OutputFile string
+
+	// NoColor indicates whether the user opted to omit ANSI color and
+	// formatting escape sequences from report and log output.
+	NoColor bool
+
+	// ASCIIOnly indicates whether the user opted to render table reports
+	// using only plain ASCII characters.
+	ASCIIOnly bool
+
+	// FixturesDir is the optional path to a directory of saved API responses
+	// read by Inspector type applications instead of submitting requests to
+	// the Red Hat Satellite server.
+	FixturesDir string
+
+	// DryRun is a flag indicating whether the user opted to only verify
+	// connectivity to and authentication with the Red Hat Satellite server
+	// without retrieving organizations or sync plans.
+	DryRun bool
+
 	// NetworkType indicates whether an attempt should be made to connect to
 	// only IPv4, only IPv6 or Red Hat Satellite API endpoints listening on
 	// either of IPv4 or IPv6 addresses ("auto").
 	NetworkType string
 
-	// Server is the Red Hat Satellite API endpoint FQDN or IP Address.
+	// DNSServer is the optional DNS server used to resolve the Red Hat
+	// Satellite server name instead of the nameservers configured for the
+	// system.
+	DNSServer string
+
+	// dnsTimeout is the number of seconds name resolution of the Red Hat
+	// Satellite server is permitted to take. See DNSTimeout for the
+	// converted value.
+	dnsTimeout int
+
+	// Server is the Red Hat Satellite API endpoint FQDN or IP Address. If
+	// multiple servers are specified this is the first server.
 	Server string
 
+	// ServerList is the collection of Red Hat Satellite servers specified
+	// via the server flag. Plugins evaluate each server if multiple servers
+	// are specified.
+	ServerList []string
+
+	// Servers is the path to a file (or the keyword "-" for stdin)
+	// containing a newline-delimited list of Red Hat Satellite servers to
+	// evaluate in batch mode.
+	Servers string
+
 	// Username is the valid user for the given Red Hat Satellite API
 	// endpoint.
 	Username string
@@ -89,10 +144,69 @@ type Config struct {
 	// Password is the valid password for the specified user.
 	Password string
 
+	// PasswordFile is the optional path to a file containing the password
+	// for the specified user.
+	PasswordFile string
+
+	// passwordFileWarning is a warning (e.g., insecure permissions) noted
+	// while reading the password file. This is logged once logging has been
+	// configured.
+	passwordFileWarning string
+
+	// configFileWarning is a warning (e.g., insecure permissions for a file
+	// providing credentials) noted while reading the configuration file.
+	// This is logged once logging has been configured.
+	configFileWarning string
+
+	// deprecationWarnings are warnings noted for deprecated flags specified
+	// via command-line, environment variable or configuration file. These
+	// warnings are logged once logging is configured.
+	deprecationWarnings []string
+
+	// Token is the optional Personal Access Token for the specified user.
+	// If specified, the token is sent in place of a password.
+	Token string
+
+	// OAuthConsumerKey is the optional OAuth consumer key configured for the
+	// Red Hat Satellite server. If specified (along with the consumer
+	// secret), requests are signed using OAuth 1.0a in place of a password.
+	OAuthConsumerKey string
+
+	// OAuthConsumerSecret is the optional OAuth consumer secret configured
+	// for the Red Hat Satellite server.
+	OAuthConsumerSecret string
+
+	// HammerConfig is the optional path to a Hammer CLI configuration file
+	// providing the server, username and password.
+	HammerConfig string
+
+	// PasswordPrompt indicates whether the user should be prompted for the
+	// password for the specified user.
+	PasswordPrompt bool
+
+	// CacheSocket is the path to the Unix socket used by the shared cache
+	// daemon. Plugins and CLI apps retrieve organizations and sync plans
+	// from the cache daemon listening on this socket; the cache daemon
+	// listens for requests on this socket.
+	CacheSocket string
+
+	// CacheDir is the optional path to a directory where plugins and CLI
+	// apps cache organizations and sync plans on disk.
+	CacheDir string
+
 	// CACertificate is the path to a CA certificate used to validate the
 	// certificate chain used by the Red Hat Satellite server.
 	CACertificate string
 
+	// ClientCertificate is the optional path to a client certificate
+	// presented to Red Hat Satellite servers (or fronting proxies) which
+	// require mutual TLS authentication.
+	ClientCertificate string
+
+	// ClientKey is the optional path to the private key for the client
+	// certificate.
+	ClientKey string
+
 	// TCPPort is the port used by the Red Hat Satellite API endpoint.
 	TCPPort int
 
@@ -106,10 +220,90 @@ type Config struct {
 	// generous and is unlikely to be met unless something is broken.
 	ReadLimit int64
 
-	// PerPageLimit overrides the default pagination limit for API calls. If
+	// cacheTTL is the number of seconds that organizations and sync plans
+	// retrieved by the cache daemon (or cached on disk) are reused before
+	// being retrieved again.
+	cacheTTL int
+
+	// BatchConcurrency is the number of Red Hat Satellite servers evaluated
+	// concurrently in batch mode.
+	BatchConcurrency int
+
+	// perPageLimit overrides the default pagination limit for API calls. If
 	// not specified by the client the remote API uses a per-page default
-	// value of 20 results.
-	PerPageLimit int
+	// value of 20 results. The value is either a whole number or the auto
+	// keyword.
+	perPageLimit string
+
+	// MaxConcurrent is the maximum number of organizations for which sync
+	// plans are retrieved concurrently.
+	MaxConcurrent int
+
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections
+	// retained for reuse across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is the maximum number of idle (keep-alive)
+	// connections retained for reuse per host.
+	MaxIdleConnsPerHost int
+
+	// idleConnTimeout is the number of seconds an idle (keep-alive)
+	// connection is retained for reuse before it is closed. See
+	// IdleConnTimeout for the converted value.
+	idleConnTimeout int
+
+	// dialTimeout is the number of seconds a connection attempt to each IP
+	// Address for the Red Hat Satellite server may take. See DialTimeout
+	// for the converted value.
+	dialTimeout int
+
+	// dialKeepAlive is the number of seconds between keep-alive probes for
+	// active connections. See DialKeepAlive for the converted value.
+	dialKeepAlive int
+
+	// EnableHTTP2 indicates whether HTTP/2 is attempted when connecting to
+	// the Red Hat Satellite server.
+	EnableHTTP2 bool
+
+	// StreamDecode indicates whether the results within each page of API
+	// query responses are decoded as they are read instead of buffering the
+	// whole page.
+	StreamDecode bool
+
+	// BulkSyncPlans indicates whether sync plans for all organizations are
+	// retrieved using a single (paged) query instead of one query per
+	// organization.
+	BulkSyncPlans bool
+
+	// RetryMaxAttempts is the maximum number of attempts made for each API
+	// request which fails due to a transient problem.
+	RetryMaxAttempts int
+
+	// retryBaseDelay is the number of seconds to wait before the first retry
+	// of a failed API request. See RetryBaseDelay for the converted value.
+	retryBaseDelay int
+
+	// RetryJitter is the fraction of each retry delay randomly added or
+	// subtracted.
+	RetryJitter float64
+
+	// DaysStuckWarning is the optional threshold for the number of days a
+	// sync plan has been in a stuck state which triggers a WARNING state. Any
+	// stuck sync plan triggers a WARNING state if not set.
+	DaysStuckWarning Threshold
+
+	// DaysStuckCritical is the optional threshold for the number of days a
+	// sync plan has been in a stuck state which triggers a CRITICAL state.
+	// CRITICAL state evaluation for stuck sync plans is disabled if not set.
+	DaysStuckCritical Threshold
+
+	// StuckCountWarning is the optional threshold for the number of stuck
+	// sync plans which triggers a WARNING state.
+	StuckCountWarning Threshold
+
+	// StuckCountCritical is the optional threshold for the number of stuck
+	// sync plans which triggers a CRITICAL state.
+	StuckCountCritical Threshold
 
 	// Log is an embedded zerolog Logger initialized via config.New().
 	Log zerolog.Logger
@@ -122,10 +316,139 @@ type Config struct {
 	// request TLS renegotiation.
 	PermitTLSRenegotiation bool
 
+	// CertVerifyWarn indicates whether certificate verification failures
+	// for the Red Hat Satellite server should result in a WARNING state
+	// (with certificate chain details) instead of a CRITICAL state.
+	CertVerifyWarn bool
+
+	// MaintenanceState is the plugin state used when the Red Hat Satellite
+	// server reports that it is in maintenance mode.
+	MaintenanceState string
+
+	// StuckState is the plugin state used when the warning thresholds for
+	// stuck sync plans are met.
+	StuckState string
+
+	// NeverScheduledState is the optional plugin state used if any enabled
+	// sync plans have never been scheduled. If not set, these sync plans
+	// are evaluated the same as other stuck sync plans.
+	NeverScheduledState string
+
+	// EmptyOrgState is the plugin state used if any organizations do not
+	// have any sync plans.
+	EmptyOrgState string
+
+	// ConnectionFailureState is the plugin state used when connecting to,
+	// communicating with or authenticating to the Red Hat Satellite server
+	// fails.
+	ConnectionFailureState string
+
 	// OmitOKSyncPlans indicates whether the user opted to omit sync plans
 	// with a non-problematic or "OK" state from the output.
 	OmitOKSyncPlans bool
 
+	// EnabledOnly indicates whether the user opted to retrieve only enabled
+	// sync plans.
+	EnabledOnly bool
+
+	// TopStuck is the number of stuck sync plans (most days stuck first)
+	// listed in a dedicated section of the report. A value of 0 disables the
+	// section.
+	TopStuck int
+
+	// MaxWidth is the maximum width (in characters) of each column in table
+	// reports. A value of 0 disables truncation.
+	MaxWidth int
+
+	// MaxProblemsShown is the maximum number of problem sync plans (the
+	// longest stuck first) listed in detail in reports. A value of 0 lists
+	// all problem sync plans.
+	MaxProblemsShown int
+
+	// ShowProducts indicates whether the user opted to list the products
+	// associated with each sync plan in the verbose report.
+	ShowProducts bool
+
+	// DisplayTimezone is the time zone (UTC, Local or an IANA time zone
+	// name) used when rendering time values in reports.
+	DisplayTimezone string
+
+	// TimeFormat indicates how next sync and last sync times are rendered in
+	// reports (absolute timestamps, relative durations or both).
+	TimeFormat string
+
+	// RetrievalTime is the time spent retrieving organizations and sync
+	// plans. This value is not set via flags; applications record it after
+	// retrieval so that it can be included in report summaries. A zero value
+	// indicates that the retrieval time was not recorded.
+	RetrievalTime time.Duration
+
+	// SortBy is the value used to sort organizations and sync plans in
+	// reports.
+	SortBy string
+
+	// SortOrder is the order in which organizations and sync plans are
+	// sorted in reports. If not specified, the natural order of the SortBy
+	// value is used.
+	SortOrder string
+
+	// EvaluateProductSyncState indicates whether the user opted to consider
+	// the sync state of products associated with sync plans when evaluating
+	// whether a sync plan is in a non-OK state.
+	EvaluateProductSyncState bool
+
+	// CheckRecurringLogic indicates whether the user opted to retrieve and
+	// evaluate the recurring logic used to trigger each sync plan.
+	CheckRecurringLogic bool
+
+	// BasicMode indicates whether the user opted to restrict API calls to
+	// only the organization and sync plan listing endpoints. Optional
+	// capabilities requiring additional API access are disabled.
+	BasicMode bool
+
+	// basicModeSkipped is the collection of flags for requested capabilities
+	// which were disabled because basic mode was enabled.
+	basicModeSkipped []string
+
+	// MaxProductSyncAge is the optional maximum age for the last sync of
+	// products associated with enabled sync plans.
+	MaxProductSyncAge MaxSyncAge
+
+	// MaxIntervalDrift is the optional maximum number of sync plan intervals
+	// permitted since the most recent product sync of enabled sync plans.
+	MaxIntervalDrift float64
+
+	// OwnerPattern is the optional regular expression applied to sync plan
+	// names to determine the owning team for each sync plan.
+	OwnerPattern string
+
+	// OwnerOrgParameter is the optional name of the organization parameter
+	// used to determine the owning team for sync plans in the organization.
+	OwnerOrgParameter string
+
+	// Orgs is the optional list of organizations (by name, label or ID)
+	// to evaluate.
+	Orgs []string
+
+	// ExcludeOrgs is the optional list of organizations (by name, label or
+	// ID) to skip.
+	ExcludeOrgs []string
+
+	// IgnoreSuppressionTags indicates whether the user opted to evaluate
+	// organizations and sync plans tagged within Red Hat Satellite as
+	// suppressed from monitoring.
+	IgnoreSuppressionTags bool
+
+	// IgnorePlans is the list of sync plan name patterns (shell-style glob
+	// or regular expressions enclosed in forward slashes) excluded from
+	// evaluation and reports.
+	IgnorePlans []string
+
+	// ExcludeProducts is the list of product name or label patterns
+	// (shell-style glob or regular expressions enclosed in forward slashes)
+	// excluded from product-based evaluation.
+	ExcludeProducts []string
+
 	// EmitBranding controls whether "generated by" text is included at the
 	// bottom of application output. This output is included in the Nagios
 	// dashboard and notifications. This output may not mix well with branding
@@ -137,10 +460,46 @@ type Config struct {
 	// the version string and then immediately exit the application.
 	ShowVersion bool
 
+	// ShowSchema is a flag indicating whether the user opted to display only
+	// the JSON Schema for the machine-readable output formats and then
+	// immediately exit the application.
+	ShowSchema bool
+
 	// ShowVerbose is a flag indicating whether the user opted to display
 	// verbose details in the final plugin output.
 	ShowVerbose bool
 
+	// ConfigFile is the optional path to a configuration file providing
+	// settings for flags not specified via command-line or environment
+	// variable.
+	ConfigFile string
+
+	// CompactOutput is a flag indicating whether the user opted to display
+	// a compact report (one line per organization) in the final plugin
+	// output.
+	CompactOutput bool
+
+	// StateFile is the optional path to a file used to record the time of
+	// each plugin execution.
+	StateFile string
+
+	// perfDataLabelPrefix is the optional prefix applied to performance data
+	// metric labels. See PerfDataLabelPrefix for the resolved value.
+	perfDataLabelPrefix string
+
+	// APIPerfData indicates whether performance data metrics are emitted for
+	// API request counts and latency.
+	APIPerfData bool
+
+	// EmitPayload indicates whether a machine-readable (JSON) representation
+	// of the evaluated sync plans is embedded in the plugin output as an
+	// encoded payload.
+	EmitPayload bool
+
+	// MaxOutputBytes is the optional maximum size in bytes of the detailed
+	// report included in the plugin output. A value of 0 disables the limit.
+	MaxOutputBytes int
+
 	// ShowHelp indicates whether the user opted to display usage information
 	// and exit the application.
 	ShowHelp bool
@@ -185,6 +544,13 @@ func Usage(flagSet *flag.FlagSet, w io.Writer) func() {
 			_, _ = fmt.Fprintln(flag.CommandLine.Output(), "\n"+Version()+"\n")
 			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
 			flagSet.PrintDefaults()
+			_, _ = fmt.Fprintf(
+				flag.CommandLine.Output(),
+				"\nFlags may also be specified via environment variables using the %s prefix"+
+					" (e.g., %s); flags take precedence over environment variables.\n",
+				EnvVarPrefix,
+				EnvVarName(PasswordFlagLong),
+			)
 		}
 	}
 }
@@ -216,6 +582,10 @@ func (c *Config) Help() string {
 // provided flag and config file values. It is responsible for validating
 // user-provided values and initializing the logging settings used by this
 // application.
+//
+// If validation of user-provided values (or logging initialization) fails the
+// partially initialized Config is returned along with the error so that the
+// caller may report the effective settings for troubleshooting purposes.
 func New(appType AppType) (*Config, error) {
 	var config Config
 
@@ -231,29 +601,70 @@ func New(appType AppType) (*Config, error) {
 		)
 	}
 
+	config.deprecationWarnings = config.deprecatedFlagWarnings()
+
+	if len(config.ServerList) > 0 {
+		config.Server = config.ServerList[0]
+	}
+
+	if appType.Inspector {
+		config.inferOutputFormat()
+	}
+
 	switch {
 	// The configuration was successfully initialized, so we're good with
 	// returning it for use by the caller.
 	case config.ShowVersion:
 		return &config, ErrVersionRequested
 
+	// The configuration was successfully initialized, so we're good with
+	// returning it for use by the caller.
+	case config.ShowSchema:
+		return &config, ErrSchemaRequested
+
 	// The configuration was successfully initialized, so we're good with
 	// returning it for use by the caller.
 	case config.ShowHelp:
 		return &config, ErrHelpRequested
 	}
 
+	if err := config.loadPasswordFile(); err != nil {
+		return &config, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if err := config.readPasswordPrompt(); err != nil {
+		return &config, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if err := config.applyHammerConfig(); err != nil {
+		return &config, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
 	if err := config.validate(appType); err != nil {
-		return nil, fmt.Errorf("configuration validation failed: %w", err)
+		return &config, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	config.applyBasicMode()
+
 	// initialize logging just as soon as validation is complete
 	if err := config.setupLogging(appType); err != nil {
-		return nil, fmt.Errorf(
+		return &config, fmt.Errorf(
 			"failed to set logging configuration: %w",
 			err,
 		)
 	}
 
+	if config.passwordFileWarning != "" {
+		config.Log.Warn().Msg(config.passwordFileWarning)
+	}
+
+	if config.configFileWarning != "" {
+		config.Log.Warn().Msg(config.configFileWarning)
+	}
+
+	for _, warning := range config.deprecationWarnings {
+		config.Log.Warn().Msg(warning)
+	}
+
 	return &config, nil
 }
@@ -7,6 +7,8 @@
 
 package config
 
+import "github.com/atc0005/go-nagios"
+
 const myAppName string = "check-rsat"
 const myAppURL string = "https://github.com/atc0005/check-rsat"
 
@@ -19,31 +21,109 @@ const ExitCodeCatchall int = 1
 const (
 	helpFlagHelp                   string = "Emit this help text"
 	versionFlagHelp                string = "Whether to display application version and then immediately exit application."
+	printSchemaFlagHelp            string = "Whether to display the JSON Schema describing the machine-readable (JSON) sync plan output and then immediately exit application."
 	logLevelFlagHelp               string = "Sets log level."
+	configFileFlagHelp             string = "Optional path to a configuration file (TOML format) providing settings for any flags (by long flag name). If not specified, $XDG_CONFIG_HOME/check-rsat/config.toml (or equivalent) and /etc/check-rsat/config.toml are searched. Flags take precedence over environment variables which take precedence over the configuration file."
 	brandingFlagHelp               string = "Toggles emission of branding details with plugin status details. This output is disabled by default."
-	trustCertFlagHelp              string = "Whether the certificate should be trusted as-is without validation. WARNING: TLS is susceptible to man-in-the-middle attacks if enabling this option."
-	serverFlagHelp                 string = "The Red Hat Satellite server FQDN or IP Address."
+	insecureSkipVerifyFlagHelp     string = "Whether the certificate should be trusted as-is without validation. WARNING: TLS is susceptible to man-in-the-middle attacks if enabling this option."
+	serverFlagHelp                 string = "The Red Hat Satellite server FQDN or IP Address. Plugins accept multiple servers (via repeated flag or comma-separated list), evaluating each server and reporting the most severe state."
 	usernameFlagHelp               string = "The valid user for the given Red Hat Satellite server."
-	passwordFlagHelp               string = "The valid password for the specified user." //nolint:gosec
+	passwordFileFlagHelp           string = "Optional path to a file containing the valid password for the specified user. Trailing newlines are removed. A warning is logged if the file is accessible by users other than the owner. Incompatible with the password flag."
+	hammerConfigFlagHelp           string = "Optional path to an existing Hammer CLI configuration file (e.g., ~/.hammer/cli.modules.d/foreman.yml) providing the server, username and password. Settings specified via flag, environment variable, configuration file or password file/prompt take precedence. The password is not used if a different username is specified."
+	passwordPromptFlagHelp         string = "Prompt for the password for the specified user. Input is not echoed if read from a terminal; otherwise the first line of standard input is used. Avoids exposing the password via command-line arguments or shell history. Incompatible with the password and password-file flags and with reading the servers list from stdin (servers flag set to -)."
+	passwordFlagHelp               string = "The valid password for the specified user."                                                                                                               //nolint:gosec
+	tokenFlagHelp                  string = "Personal Access Token (Red Hat Satellite 6.10+) for the specified user, sent in place of a password. Incompatible with flags used to specify a password." //nolint:gosec
+	oauthConsumerKeyFlagHelp       string = "OAuth consumer key configured for the Red Hat Satellite server (e.g., via satellite-installer --foreman-oauth-consumer-key). Requests are signed using OAuth 1.0a instead of sending a password; the specified user is sent via the FOREMAN-USER header for OAuth user mapping. Requires the oauth-consumer-secret flag. Incompatible with flags used to specify a password or token."
+	oauthConsumerSecretFlagHelp    string = "OAuth consumer secret configured for the Red Hat Satellite server. Requires the oauth-consumer-key flag." //nolint:gosec
 	tcpPortFlagHelp                string = "The port used by the Red Hat Satellite server API."
-	networkTypeFlagHelp            string = "Limits network connections to one of tcp4 (IPv4-only), tcp6 (IPv6-only) or auto (either)."
-	perPageLimitFlagHelp           string = "Overrides the default pagination limit for API calls. Satellite API defaults to a per-page limit of 20 results."
+	networkTypeFlagHelp            string = "Limits network connections to one of tcp4 (IPv4-only), tcp6 (IPv6-only) or auto (either; connection attempts to IPv4 and IPv6 addresses are raced so that broken connectivity for one address family does not delay connecting using the other)."
+	dnsServerFlagHelp              string = "Optional DNS server (IP Address or hostname with optional port, e.g., 192.0.2.53 or 192.0.2.53:5353) used to resolve the Red Hat Satellite server name instead of the nameservers configured for the system (e.g., via resolv.conf). Useful if the system nameservers are unable to resolve internal Red Hat Satellite server names."
+	dnsTimeoutFlagHelp             string = "The number of seconds name resolution of the Red Hat Satellite server is permitted to take before it is abandoned. Zero means no limit beyond the resolver defaults."
+	perPageLimitFlagHelp           string = "Overrides the default pagination limit for API calls (1 - 1000). Satellite API defaults to a per-page limit of 20 results. Specify auto to start with a large limit and step down if the server rejects the request, the response exceeds the read limit or the results are truncated; the chosen limit is logged."
+	maxConcurrentFlagHelp          string = "The maximum number of organizations for which sync plans are retrieved concurrently. Increase to reduce retrieval time for Red Hat Satellite servers with many organizations; decrease to limit load on the server."
+	maxIdleConnsFlagHelp           string = "The maximum number of idle (keep-alive) connections to the Red Hat Satellite server retained for reuse. Zero means no limit."
+	maxIdleConnsPerHostFlagHelp    string = "The maximum number of idle (keep-alive) connections retained for reuse per host. Should be at least the max-concurrent value to permit reuse of connections by concurrent requests. Zero uses the Go standard library default of 2."
+	dialTimeoutFlagHelp            string = "The number of seconds a connection attempt to each IP Address for the Red Hat Satellite server may take before it is abandoned. Increase for remote Red Hat Satellite servers reached over slow WAN links."
+	dialKeepAliveFlagHelp          string = "The number of seconds between keep-alive probes for active connections to the Red Hat Satellite server."
+	idleConnTimeoutFlagHelp        string = "The number of seconds an idle (keep-alive) connection is retained for reuse before it is closed. Increase for slow Red Hat Satellite servers with long delays between requests. Zero means no limit."
+	enableHTTP2FlagHelp            string = "Whether HTTP/2 is attempted when connecting to the Red Hat Satellite server. By default HTTP/1.1 is used. Some Red Hat Satellite servers fronted by a proxy perform significantly better over HTTP/2. Connections fall back to HTTP/1.1 if the server does not support HTTP/2."
+	streamDecodeFlagHelp           string = "Whether the results within each page of API query responses are decoded as they are read instead of buffering the whole page. Reduces peak memory use when the page-limit and read-limit values are raised."
+	bulkSyncPlansFlagHelp          string = "Whether sync plans for all organizations are retrieved using a single (paged) query instead of one query per organization. This reduces the number of API requests for Red Hat Satellite servers with many organizations. Sync plans are retrieved per organization if the server does not support the query."
+	retryMaxAttemptsFlagHelp       string = "The maximum number of attempts made for each API request (including the initial attempt) which fails due to a transient problem (e.g., connection reset, 502, 503 or 504 response or timeout) or is rate limited (429 response; the Retry-After delay is honored if it ends before the timeout). Authentication failures and maintenance mode responses are not retried."
+	retryBaseDelayFlagHelp         string = "The number of seconds to wait before the first retry of a failed API request. The delay doubles for each subsequent retry."
+	retryJitterFlagHelp            string = "The fraction (between 0 and 1) of each retry delay randomly added or subtracted so that concurrent requests do not retry in lockstep."
 	caCertificateFlagHelp          string = "CA Certificate used to validate the certificate chain used by the Red Hat Satellite server."
+	clientCertificateFlagHelp      string = "Optional path to a PEM encoded client certificate presented to Red Hat Satellite servers (or fronting proxies) which require mutual TLS (mTLS) authentication. Requires the client-key flag."
+	clientKeyFlagHelp              string = "Optional path to the PEM encoded private key for the client certificate. Requires the client-cert flag."
 	permitTLSRenegotiationFlagHelp string = "Whether support for accepting renegotiation requests from the Red Hat Satellite server are permitted. This support is disabled by default. Renegotiation is not supported for TLS 1.3."
 	omitOKSyncPlansHelp            string = "Whether sync plans listed in plugin output should be limited to just those in a non-OK state."
+	maxWidthFlagHelp               string = "Maximum width (in characters) of each column in the table reports. Longer values (e.g., organization or sync plan names) are truncated with an ellipsis so that they do not wrap and destroy alignment. A value of 0 disables truncation."
+	topStuckFlagHelp               string = "Number of stuck sync plans (across all organizations, most days stuck first) to list in a dedicated section of the report so that the most urgent items appear first regardless of organization name ordering. A value of 0 disables the section."
+	showProductsFlagHelp           string = "Whether each sync plan listed in the verbose report should be expanded to list its associated products (name, repository count and last sync)."
+	maxProblemsShownFlagHelp       string = "Maximum number of problem sync plans (the longest stuck first) listed in detail in the verbose and table reports. Remaining problem sync plans are summarized with an \"and X more\" trailer. A value of 0 lists all problem sync plans."
+	displayTimezoneFlagHelp        string = "The time zone used when rendering time values in reports. Specify UTC, Local (the time zone of the system running the application) or an IANA time zone name (e.g., America/Chicago)."
+	timeFormatFlagHelp             string = "How next sync and last sync times are rendered in reports: as absolute timestamps, as relative durations (e.g., overdue by 3d 4h, in 6h) or both."
+	sortByFlagHelp                 string = "The value used to sort organizations and sync plans in all report formats. Sync plans are grouped by organization; organizations are ordered by their first sync plan when sorting by a sync plan value. Sync plans are listed in retrieval order when sorting by organization name."
+	sortOrderFlagHelp              string = "The order in which organizations and sync plans are sorted in all report formats. If not specified, sorting by days stuck uses descending order (most days stuck first) and all other sort values use ascending order."
+	productSyncStateFlagHelp       string = "Whether sync plans with one or more products whose most recent sync did not complete successfully (or which have never synced) should be considered to be in a non-OK state."
+	recurringLogicFlagHelp         string = "Whether the recurring logic used to trigger each sync plan should be retrieved and evaluated. Enabled sync plans whose recurring logic is no longer active (e.g., cancelled) are considered stuck. This requires an additional API request."
+	maxIntervalDriftFlagHelp       string = "Optional maximum number of sync plan intervals (e.g., 3) permitted since the most recent sync of any product associated with an enabled sync plan. Sync plans exceeding this limit are considered to be drifting and in a non-OK state even if the next sync time is in the future. Sync plans using a custom cron interval are not evaluated. A value of 0 disables evaluation of interval drift."
+	basicModeFlagHelp              string = "Whether API calls should be restricted to only the organization and sync plan listing endpoints. Intended for accounts granted the minimum roles needed to view organizations and sync plans. Optional capabilities requiring additional API access (recurring logic, organization parameters, product sync state and age, interval drift) are skipped and noted in verbose output."
+	maxProductSyncAgeFlagHelp      string = "Optional maximum age for the last sync of products associated with enabled sync plans, specified as a multiplier of the sync plan interval (e.g., 2x) or as a fixed duration (e.g., 36h). Sync plans with products which have not synced within this window are considered to be in a non-OK state. Interval multipliers are not applied to sync plans using a custom cron interval."
+	ownerPatternFlagHelp           string = "Optional regular expression applied to sync plan names to determine the owning team for each sync plan (e.g., '^(?P<owner>[^-]+)-'). The named capture group 'owner' (or the first capture group) provides the owner value."
+	ownerOrgParameterFlagHelp      string = "Optional name of the organization parameter used to determine the owning team for sync plans in the organization. Sync plan names matching the owner pattern take precedence. This requires an additional API request per organization."
+	excludeProductsFlagHelp        string = "Products (by name or label) excluded from product-based evaluation (e.g., product sync state). Shell-style glob patterns (e.g., 'RHEL 7*') and regular expressions enclosed in forward slashes (e.g., '/^RHEL [78] /') are supported. Matching is case-insensitive. May be repeated or specified as a comma-separated list."
+	orgFlagHelp                    string = "Organizations (by name, label or ID) to evaluate. If specified, sync plans are retrieved and evaluated only for the listed organizations. May be repeated or specified as a comma-separated list."
+	excludeOrgFlagHelp             string = "Organizations (by name, label or ID) to skip (e.g., organizations being decommissioned or used only for testing). Sync plans for these organizations are not retrieved or evaluated. May be repeated or specified as a comma-separated list."
+	ignoreSuppressionTagsFlagHelp  string = "Whether the monitoring:ignore tag within organization and sync plan descriptions should be ignored. By default organizations and sync plans with this tag are excluded from evaluation and reports."
+	ignorePlansFlagHelp            string = "Sync plans (by name) excluded from evaluation and reports (e.g., known-bad or intentionally paused sync plans). Shell-style glob patterns (e.g., 'Legacy*') and regular expressions enclosed in forward slashes (e.g., '/^(legacy|test)-/') are supported. Matching is case-insensitive. May be repeated or specified as a comma-separated list."
+	enabledOnlyFlagHelp            string = "Whether only enabled sync plans are retrieved from the Red Hat Satellite server (via a scoped search of enabled = true) so that disabled sync plans are not transferred. Disabled sync plans are omitted from evaluation and reports."
+	cacheSocketFlagHelp            string = "Optional path to the Unix socket of a shared cache daemon (rsat_cache_daemon) serving the same Red Hat Satellite server. If specified, organizations and sync plans are retrieved from the cache daemon, falling back to the Red Hat Satellite server if the cache daemon is unavailable."
+	cacheDirFlagHelp               string = "Optional path to a directory where organizations and sync plans retrieved from the Red Hat Satellite server are cached on disk. If specified, cached values are used while fresher than the cache-ttl value, otherwise organizations and sync plans are retrieved from the Red Hat Satellite server and the cache updated. All organizations are cached so that checks with different organization filters share the cache. API responses providing validators (ETag or Last-Modified headers) are also stored so that later requests are submitted as conditional requests and unmodified responses reused. Incompatible with the cache-socket flag."
+	diskCacheTTLFlagHelp           string = "The number of seconds organizations and sync plans cached in the cache-dir directory are reused before being retrieved again from the Red Hat Satellite server."
 	verboseFlagHelp                string = "Whether to display verbose details in the final plugin output."
+	compactFlagHelp                string = "Whether to display a compact report in the final plugin output with exactly one line per organization listing problem sync plans inline. Intended for short notification templates (e.g., SMS). Verbose details are not included."
+	stateFileFlagHelp              string = "Optional path to a file used to record the time of each plugin execution. If specified, sync plans created or modified since the previous execution are noted in the report. Use a separate state file for each service check."
+	perfDataLabelPrefixFlagHelp    string = "Optional prefix applied to all performance data metric labels (except the time metric) so that metrics from multiple Red Hat Satellite service checks may be aggregated without label collisions (e.g., rsat1_ for rsat1_sync_plans_stuck). Specify auto to derive the prefix from the server name."
+	maxOutputBytesFlagHelp         string = "Optional maximum size in bytes of the detailed report included in the plugin output (e.g., to stay within Nagios or NRPE output size limits). If the report would exceed this size, sync plans in an OK state are omitted so that problem sync plans are listed first; the report is then truncated at a line boundary if still too large. A value of 0 disables this limit."
+	payloadFlagHelp                string = "Whether a machine-readable (JSON) representation of the evaluated sync plans is embedded in the plugin output as an encoded payload. This allows downstream tooling to extract structured results from the monitoring history without re-querying the Red Hat Satellite server."
+	apiPerfDataFlagHelp            string = "Whether performance data metrics are emitted for the number of API requests submitted (api_calls) and the time spent waiting for responses when retrieving organizations (api_time_orgs) and sync plans (api_time_syncplans). Useful for identifying the Red Hat Satellite API endpoint responsible for slow service checks."
+	dryRunFlagHelp                 string = "Whether to perform DNS resolution, a TLS handshake and a single authenticated request to verify connectivity to and authentication with the Red Hat Satellite server, report the result and exit without retrieving organizations or sync plans. Incompatible with the servers and cache-socket flags."
 )
 
 // CLI App flags help text.
 const (
 	cliAppTimeoutFlagHelp         string = "Timeout value in seconds before application execution is abandoned and an error returned."
 	inspectorOutputFormatFlagHelp string = "Sets output format."
+	serversFlagHelp               string = "Path to a file (or - for stdin) containing a newline-delimited list of Red Hat Satellite servers to evaluate in batch mode. Each line uses the format: server[:port] [username [password]]. Optional values override those specified via flag. Incompatible with the server flag."
+	batchConcurrencyFlagHelp      string = "The number of Red Hat Satellite servers evaluated concurrently in batch mode. The default evaluates servers sequentially."
+	noColorFlagHelp               string = "Whether ANSI color and formatting escape sequences should be omitted from report and log output (e.g., for output embedded into ticketing systems or non-ANSI terminals). Also enabled if the NO_COLOR environment variable is set to a non-empty value."
+	asciiOnlyFlagHelp             string = "Whether the pretty-table report should be rendered using only plain ASCII characters for table borders and status indicators (e.g., for terminals and notification channels that mangle Unicode)."
+	outputFileFlagHelp            string = "Path to a file where the report is written instead of stdout. If an output format is not explicitly specified it is inferred from the file extension."
+	fixturesDirFlagHelp           string = "Path to a directory of saved API responses (JSON) read instead of submitting requests to the Red Hat Satellite server; useful for report prototyping, troubleshooting from captured data and demos. Each response is read from a file named for the API endpoint path (e.g., api/v2/organizations.json) with a .page-N suffix for pages beyond the first (e.g., api/v2/organizations.page-2.json). The server and credentials flags are not required. Incompatible with the servers, cache-socket and cache-dir flags."
 )
 
 // Plugin flags help text.
 const (
-	readLimitFlagHelp     string = "Limit in bytes used to help prevent abuse when reading input that could be larger than expected."
-	pluginTimeoutFlagHelp string = "Timeout value in seconds before plugin execution is abandoned and an error returned."
+	readLimitFlagHelp              string = "Limit in bytes used to help prevent abuse when reading input that could be larger than expected."
+	pluginTimeoutFlagHelp          string = "Timeout value in seconds before plugin execution is abandoned and an error returned."
+	certVerifyWarnFlagHelp         string = "Whether certificate verification failures for the Red Hat Satellite server should result in a WARNING state (with certificate chain details) instead of a CRITICAL state. Intended for use as a grace period during planned certificate rotations. Sync plans are NOT evaluated while certificate verification fails."
+	daysStuckWarningFlagHelp       string = "Optional WARNING threshold for the number of days a sync plan has been in a stuck state specified using Nagios range syntax (e.g., 2 triggers a WARNING state for sync plans stuck 3 or more days). If not specified, a WARNING state is triggered for any stuck sync plan."
+	daysStuckCriticalFlagHelp      string = "Optional CRITICAL threshold for the number of days a sync plan has been in a stuck state specified using Nagios range syntax (e.g., 6 triggers a CRITICAL state for sync plans stuck 7 or more days). If not specified, CRITICAL state evaluation for stuck sync plans is disabled."
+	stuckCountWarningFlagHelp      string = "Optional WARNING threshold for the number of stuck sync plans specified using Nagios range syntax (e.g., 0 triggers a WARNING state for 1 or more stuck sync plans). If specified, a WARNING state is triggered only if this threshold is also exceeded."
+	stuckCountCriticalFlagHelp     string = "Optional CRITICAL threshold for the number of stuck sync plans specified using Nagios range syntax (e.g., 4 triggers a CRITICAL state for 5 or more stuck sync plans). If specified, a CRITICAL state is triggered if this threshold is exceeded."
+	stuckStateFlagHelp             string = "The plugin state used when the warning thresholds for stuck sync plans are met. Specify CRITICAL to align stuck sync plans with paging policies which only act on CRITICAL states."
+	neverScheduledStateFlagHelp    string = "Optional plugin state used if any enabled sync plans have never been scheduled (no next sync time). If specified, these sync plans are not subject to the stuck sync plan thresholds. If not specified, these sync plans are evaluated the same as other stuck sync plans."
+	emptyOrgStateFlagHelp          string = "The plugin state used if any organizations do not have any sync plans (a possible misconfiguration). If only enabled sync plans are retrieved, organizations with only disabled sync plans are also considered to be without sync plans."
+	connectionFailureStateFlagHelp string = "The plugin state used when connecting to or communicating with the Red Hat Satellite server fails (e.g., name resolution, network connectivity, TLS handshake or timeout failures). Specify UNKNOWN to route infrastructure issues separately from sync plan problems. This state is also used for authentication failures."
+	maintenanceStateFlagHelp       string = "The plugin state used when the Red Hat Satellite server reports that it is in maintenance mode (e.g., via foreman-maintain during patch windows). Sync plans are NOT evaluated while the server is in maintenance mode."
+)
+
+// Cache daemon flags help text.
+const (
+	cacheDaemonTimeoutFlagHelp string = "Timeout value in seconds before retrieval of organizations and sync plans from the Red Hat Satellite server is abandoned and an error returned to waiting clients."
+	cacheDaemonSocketFlagHelp  string = "Path to the Unix socket where the cache daemon listens for requests from plugins and CLI apps. A stale socket file left behind by a previous instance is removed."
+	cacheTTLFlagHelp           string = "The number of seconds organizations and sync plans are cached before being retrieved again from the Red Hat Satellite server. This value should be less than or equal to the check interval of plugins using the cache daemon."
 )
 
 // shorthandFlagSuffix is appended to short flag help text to emphasize that
@@ -56,42 +136,164 @@ const (
 	HelpFlagLong                   string = "help"
 	HelpFlagShort                  string = "h"
 	VersionFlagLong                string = "version"
+	PrintSchemaFlagLong            string = "print-schema"
+	PasswordFileFlagLong           string = "password-file"
+	PasswordPromptFlagLong         string = "password-prompt"
+	ConfigFileFlagLong             string = "config"
+	HammerConfigFlagLong           string = "hammer-config"
 	VerboseFlagLong                string = "verbose"
+	CompactFlagLong                string = "compact"
+	StateFileFlagLong              string = "state-file"
+	PerfDataLabelPrefixFlagLong    string = "perfdata-label-prefix"
+	APIPerfDataFlagLong            string = "api-perfdata"
+	PayloadFlagLong                string = "payload"
+	MaxOutputBytesFlagLong         string = "max-output-bytes"
 	BrandingFlag                   string = "branding"
-	TrustCertFlagLong              string = "trust-cert"
+	InsecureSkipVerifyFlagLong     string = "insecure-skip-verify"
 	TimeoutFlagLong                string = "timeout"
 	TimeoutFlagShort               string = "t"
 	ReadLimitFlagLong              string = "read-limit"
 	PerPageLimitFlagLong           string = "page-limit"
+	MaxConcurrentFlagLong          string = "max-concurrent"
+	MaxIdleConnsFlagLong           string = "max-idle-conns"
+	MaxIdleConnsPerHostFlagLong    string = "max-idle-conns-per-host"
+	IdleConnTimeoutFlagLong        string = "idle-conn-timeout"
+	DialTimeoutFlagLong            string = "dial-timeout"
+	DialKeepAliveFlagLong          string = "dial-keepalive"
+	EnableHTTP2FlagLong            string = "enable-http2"
+	StreamDecodeFlagLong           string = "stream-decode"
+	BulkSyncPlansFlagLong          string = "bulk-sync-plans"
+	RetryMaxAttemptsFlagLong       string = "retry-max-attempts"
+	RetryBaseDelayFlagLong         string = "retry-base-delay"
+	RetryJitterFlagLong            string = "retry-jitter"
 	LogLevelFlagLong               string = "log-level"
 	LogLevelFlagShort              string = "ll"
 	ServerFlagLong                 string = "server"
 	UsernameFlagLong               string = "username"
 	PasswordFlagLong               string = "password"
+	TokenFlagLong                  string = "token"
+	OAuthConsumerKeyFlagLong       string = "oauth-consumer-key"
+	OAuthConsumerSecretFlagLong    string = "oauth-consumer-secret"
 	PortFlagLong                   string = "port"
 	NetTypeFlagLong                string = "net-type"
+	DNSServerFlagLong              string = "dns-server"
+	DNSTimeoutFlagLong             string = "dns-timeout"
 	CACertificateFlagLong          string = "ca-cert"
+	ClientCertificateFlagLong      string = "client-cert"
+	ClientKeyFlagLong              string = "client-key"
 	PermitTLSRenegotiationFlagLong string = "permit-tls-renegotiation"
 	OmitOKSyncPlansFlagLong        string = "omit-ok"
+	TopStuckFlagLong               string = "top-stuck"
+	MaxWidthFlagLong               string = "max-width"
+	SortByFlagLong                 string = "sort-by"
+	DisplayTimezoneFlagLong        string = "display-timezone"
+	TimeFormatFlagLong             string = "time-format"
+	ShowProductsFlagLong           string = "show-products"
+	MaxProblemsShownFlagLong       string = "max-problems-shown"
+	SortOrderFlagLong              string = "sort-order"
+	ProductSyncStateFlagLong       string = "evaluate-product-sync-state"
+	ExcludeProductsFlagLong        string = "exclude-products"
+	IgnorePlanFlagLong             string = "ignore-plan"
+	EnabledOnlyFlagLong            string = "enabled-only"
+	OrgFlagLong                    string = "org"
+	ExcludeOrgFlagLong             string = "exclude-org"
+	IgnoreSuppressionTagsFlagLong  string = "ignore-suppression-tags"
+	RecurringLogicFlagLong         string = "check-recurring-logic"
+	BasicModeFlagLong              string = "basic"
+	MaxProductSyncAgeFlagLong      string = "max-product-sync-age"
+	MaxIntervalDriftFlagLong       string = "max-interval-drift"
+	OwnerPatternFlagLong           string = "owner-pattern"
+	OwnerOrgParameterFlagLong      string = "owner-org-parameter"
 	InspectorOutputFormatFlagLong  string = "output-format"
+	CertVerifyWarnFlagLong         string = "warn-on-cert-verify-failure"
+	MaintenanceStateFlagLong       string = "maintenance-state"
+	StuckStateFlagLong             string = "stuck-state"
+	NeverScheduledStateFlagLong    string = "never-scheduled-state"
+	EmptyOrgStateFlagLong          string = "empty-org-state"
+	ConnectionFailureStateFlagLong string = "connection-failure-state"
+	ServersFlagLong                string = "servers"
+	BatchConcurrencyFlagLong       string = "batch-concurrency"
+	OutputFileFlagLong             string = "output-file"
+	NoColorFlagLong                string = "no-color"
+	ASCIIOnlyFlagLong              string = "ascii"
+	FixturesDirFlagLong            string = "fixtures-dir"
+	DryRunFlagLong                 string = "dry-run"
+	DaysStuckWarningFlagLong       string = "days-stuck-warning"
+	DaysStuckCriticalFlagLong      string = "days-stuck-critical"
+	StuckCountWarningFlagLong      string = "stuck-count-warning"
+	StuckCountCriticalFlagLong     string = "stuck-count-critical"
+	CacheSocketFlagLong            string = "cache-socket"
+	CacheTTLFlagLong               string = "cache-ttl"
+	CacheDirFlagLong               string = "cache-dir"
+)
+
+// Deprecated flag names. These flags are registered as aliases for the flags
+// which replace them so that existing command definitions continue to work.
+const (
+	TrustCertFlagLong string = "trust-cert"
 )
 
 // Default flag settings if not overridden by user input
 const (
-	defaultHelp                   bool   = false
-	defaultLogLevel               string = "info"
-	defaultVerbose                bool   = false
-	defaultEmitBranding           bool   = false
-	defaultDisplayVersionAndExit  bool   = false
-	defaultTrustCert              bool   = false
-	defaultPermitTLSRenegotiation bool   = false
-	defaultOmitOKSyncPlans        bool   = false
-	defaultServer                 string = ""
-	defaultUsername               string = ""
-	defaultPassword               string = ""
-	defaultTCPPort                int    = 443
-	defaultNetworkType            string = netTypeTCPAuto
-	defaultCACertificate          string = ""
+	defaultHelp                   bool    = false
+	defaultLogLevel               string  = "info"
+	defaultVerbose                bool    = false
+	defaultCompact                bool    = false
+	defaultStateFile              string  = ""
+	defaultPerfDataLabelPrefix    string  = ""
+	defaultAPIPerfData            bool    = false
+	defaultPayload                bool    = false
+	defaultMaxOutputBytes         int     = 0
+	defaultEmitBranding           bool    = false
+	defaultDisplayVersionAndExit  bool    = false
+	defaultPrintSchema            bool    = false
+	defaultConfigFile             string  = ""
+	defaultHammerConfig           string  = ""
+	defaultTrustCert              bool    = false
+	defaultPermitTLSRenegotiation bool    = false
+	defaultOmitOKSyncPlans        bool    = false
+	defaultEnabledOnly            bool    = false
+	defaultTopStuck               int     = 0
+	defaultMaxWidth               int     = 0
+	defaultSortBy                 string  = SortByOrgName
+	defaultDisplayTimezone        string  = "Local"
+	defaultTimeFormat             string  = TimeFormatAbsolute
+	defaultShowProducts           bool    = false
+	defaultMaxProblemsShown       int     = 0
+	defaultSortOrder              string  = ""
+	defaultProductSyncState       bool    = false
+	defaultRecurringLogic         bool    = false
+	defaultMaxIntervalDrift       float64 = 0
+	defaultBasicMode              bool    = false
+	defaultIgnoreSuppressionTags  bool    = false
+	defaultOwnerPattern           string  = ""
+	defaultOwnerOrgParameter      string  = ""
+	defaultCertVerifyWarn         bool    = false
+	defaultMaintenanceState       string  = nagios.StateWARNINGLabel
+	defaultStuckState             string  = nagios.StateWARNINGLabel
+	defaultNeverScheduledState    string  = ""
+	defaultEmptyOrgState          string  = nagios.StateOKLabel
+	defaultConnectionFailureState string  = nagios.StateCRITICALLabel
+	defaultServers                string  = ""
+	defaultNoColor                bool    = false
+	defaultASCIIOnly              bool    = false
+	defaultBatchConcurrency       int     = 1
+	defaultUsername               string  = ""
+	defaultPassword               string  = ""
+	defaultToken                  string  = ""
+	defaultOAuthConsumerKey       string  = ""
+	defaultOAuthConsumerSecret    string  = ""
+	defaultPasswordFile           string  = ""
+	defaultPasswordPrompt         bool    = false
+	defaultTCPPort                int     = 443
+	defaultNetworkType            string  = netTypeTCPAuto
+	defaultDNSServer              string  = ""
+	defaultDNSTimeout             int     = 0
+	defaultCACertificate          string  = ""
+	defaultClientCertificate      string  = ""
+	defaultClientKey              string  = ""
+	defaultCacheSocket            string  = ""
+	defaultCacheDir               string  = ""
 
 	// Red Hat Satellite API response times can be slow, so best to set a
 	// generous default timeout.
@@ -101,6 +303,14 @@ const (
 	// generous default timeout.
 	defaultPluginTimeout int = 240
 
+	// Red Hat Satellite API response times can be slow, so best to set a
+	// generous default timeout.
+	defaultCacheDaemonTimeout int = 300
+
+	// Match the typical check interval for a Nagios service so that
+	// organizations and sync plans are retrieved once per interval.
+	defaultCacheTTL int = 300
+
 	// Set a read limit to help prevent abuse from unexpected/overly large
 	// input. The limit set here is OVERLY generous and is unlikely to be met
 	// unless something is broken.
@@ -111,7 +321,41 @@ const (
 	// instances "out of the box".
 	defaultPerPageLimit int = 30
 
+	// defaultMaxConcurrent is kept small to limit load on the Red Hat
+	// Satellite server while still reducing retrieval time for servers with
+	// many organizations.
+	defaultMaxConcurrent int = 3
+
+	// Retain enough idle connections for reuse by concurrent retrieval of
+	// sync plans (see defaultMaxConcurrent).
+	defaultMaxIdleConns        int  = 10
+	defaultMaxIdleConnsPerHost int  = defaultMaxConcurrent
+	defaultIdleConnTimeout     int  = 30
+	defaultDialTimeout         int  = 2
+	defaultDialKeepAlive       int  = 2
+	defaultEnableHTTP2         bool = false
+	defaultStreamDecode        bool = false
+	defaultBulkSyncPlans       bool = false
+
+	// Retry transient failures a limited number of times so that a single
+	// blip does not fail retrieval without risking the plugin timeout.
+	defaultRetryMaxAttempts int     = 3
+	defaultRetryBaseDelay   int     = 1
+	defaultRetryJitter      float64 = 0.2
+
 	defaultInspectorOutputFormat string = InspectorOutputFormatPrettyTable
+
+	defaultOutputFile string = ""
+
+	defaultFixturesDir string = ""
+
+	defaultDryRun bool = false
+
+	// Any stuck sync plan triggers a WARNING state by default. Sites wishing
+	// to escalate long stuck sync plans (e.g., stuck for a week or longer)
+	// opt in by specifying a CRITICAL threshold.
+	defaultDaysStuckWarning  string = ""
+	defaultDaysStuckCritical string = ""
 )
 
 const (
@@ -129,15 +373,54 @@ const (
 const (
 	appTypePlugin    string = "plugin"
 	appTypeInspector string = "Inspector"
+	appTypeDaemon    string = "cache-daemon"
 )
 
+// PerPageLimitAuto is the per-page limit value used to request that the
+// pagination limit is determined automatically.
+const PerPageLimitAuto string = "auto"
+
+// maxPerPageLimit is the largest supported per-page limit. This matches the
+// largest limit attempted when the limit is determined automatically.
+const maxPerPageLimit int = 1000
+
+// PerfDataLabelPrefixAuto is the performance data label prefix value used to
+// request a prefix derived from the server name.
+const PerfDataLabelPrefixAuto string = "auto"
+
 // MB represents 1 Megabyte
 const MB int64 = 1048576
 
+// Supported report sort values.
+const (
+	SortByOrgName   string = "org-name"
+	SortByPlanName  string = "plan-name"
+	SortByDaysStuck string = "days-stuck"
+	SortByNextSync  string = "next-sync"
+)
+
+// Supported report time formats.
+const (
+	TimeFormatAbsolute string = "absolute"
+	TimeFormatRelative string = "relative"
+	TimeFormatBoth     string = "both"
+)
+
+// Supported report sort orders.
+const (
+	SortOrderAscending  string = "asc"
+	SortOrderDescending string = "desc"
+)
+
 // Supported Inspector type application output formats
 const (
 	InspectorOutputFormatOverview    string = "overview"
 	InspectorOutputFormatPrettyTable string = "pretty-table"
 	InspectorOutputFormatSimpleTable string = "simple-table"
 	InspectorOutputFormatVerbose     string = "verbose"
+	InspectorOutputFormatJSON        string = "json"
+	InspectorOutputFormatCSV         string = "csv"
+	InspectorOutputFormatMarkdown    string = "markdown"
+	InspectorOutputFormatHTML        string = "html"
+	InspectorOutputFormatByProduct   string = "by-product"
 )
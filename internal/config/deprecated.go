@@ -0,0 +1,90 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// deprecatedFlags maps deprecated flag names to the (long) flag name which
+// replaces them. Deprecated flags are registered as aliases sharing the
+// setting of the replacement flag so that flags may be renamed without
+// breaking existing command definitions, environment variables or
+// configuration files. A warning is logged if a deprecated flag is used.
+var deprecatedFlags = map[string]string{
+	TrustCertFlagLong: InsecureSkipVerifyFlagLong,
+}
+
+// deprecatedFlagHelp returns the help text for a deprecated flag alias.
+func deprecatedFlagHelp(replacement string) string {
+	return fmt.Sprintf("DEPRECATED: Use the %s flag instead.", replacement)
+}
+
+// addDeprecatedFlags registers an alias for each deprecated flag whose
+// replacement flag is supported by the application. This flag group is
+// registered after all other flag groups.
+func (c *Config) addDeprecatedFlags() {
+	for deprecated, replacement := range deprecatedFlags {
+		f := c.flagSet.Lookup(replacement)
+		if f == nil {
+			continue
+		}
+
+		c.flagSet.Var(f.Value, deprecated, deprecatedFlagHelp(replacement))
+	}
+}
+
+// markDeprecatedFlags marks a deprecated flag and its replacement as
+// specified if either one of them was specified. This prevents a lower
+// precedence source (e.g., configuration file) from overriding a setting
+// specified using the other flag name.
+func markDeprecatedFlags(specified map[string]bool) {
+	for deprecated, replacement := range deprecatedFlags {
+		if specified[deprecated] || specified[replacement] {
+			specified[deprecated] = true
+			specified[replacement] = true
+		}
+	}
+}
+
+// deprecatedEnvVarShadowed indicates whether the given flag is deprecated
+// and the environment variable for its replacement flag is also set. The
+// environment variable for the replacement flag takes precedence.
+func deprecatedEnvVarShadowed(flagName string) bool {
+	replacement, ok := deprecatedFlags[flagName]
+	if !ok {
+		return false
+	}
+
+	_, found := os.LookupEnv(EnvVarName(replacement))
+
+	return found
+}
+
+// deprecatedFlagWarnings returns a warning for each deprecated flag
+// specified via command-line, environment variable or configuration file.
+func (c *Config) deprecatedFlagWarnings() []string {
+	var warnings []string
+
+	c.flagSet.Visit(func(f *flag.Flag) {
+		replacement, ok := deprecatedFlags[f.Name]
+		if !ok {
+			return
+		}
+
+		warnings = append(warnings, fmt.Sprintf(
+			"the %s flag is deprecated and will be removed in a future release; use the %s flag instead",
+			f.Name,
+			replacement,
+		))
+	})
+
+	return warnings
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+// newTestConfig returns a configuration with flags registered for the given
+// application type.
+func newTestConfig(appType AppType) *Config {
+	c := Config{flagSet: flag.NewFlagSet("", flag.ContinueOnError)}
+
+	for _, addFlags := range flagGroups(appType) {
+		addFlags(&c)
+	}
+
+	return &c
+}
+
+func TestDeprecatedFlagAlias(t *testing.T) {
+	c := newTestConfig(AppType{Plugin: true})
+
+	if err := c.flagSet.Parse([]string{"--" + TrustCertFlagLong}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	if !c.TrustCert {
+		t.Errorf("deprecated %s flag did not apply setting", TrustCertFlagLong)
+	}
+
+	specified := c.specifiedFlags()
+	if !specified[InsecureSkipVerifyFlagLong] {
+		t.Errorf("%s flag not marked as specified via deprecated alias", InsecureSkipVerifyFlagLong)
+	}
+
+	warnings := c.deprecatedFlagWarnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], InsecureSkipVerifyFlagLong) {
+		t.Errorf("got warnings %q, want one warning noting %s flag", warnings, InsecureSkipVerifyFlagLong)
+	}
+}
+
+func TestDeprecatedFlagEnvVarShadowed(t *testing.T) {
+	t.Setenv(EnvVarName(TrustCertFlagLong), "true")
+	t.Setenv(EnvVarName(InsecureSkipVerifyFlagLong), "false")
+
+	c := newTestConfig(AppType{Plugin: true})
+
+	if err := c.flagSet.Parse(nil); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	if err := c.applyEnvironment(); err != nil {
+		t.Fatalf("failed to apply environment: %v", err)
+	}
+
+	if c.TrustCert {
+		t.Errorf("deprecated environment variable took precedence over %s", EnvVarName(InsecureSkipVerifyFlagLong))
+	}
+
+	if warnings := c.deprecatedFlagWarnings(); len(warnings) != 0 {
+		t.Errorf("got warnings %q for unused deprecated environment variable", warnings)
+	}
+}
+
+func TestDeprecatedFlagReplacementRegistered(t *testing.T) {
+	for deprecated, replacement := range deprecatedFlags {
+		if !allFlagNames()[replacement] {
+			t.Errorf("replacement %s for deprecated flag %s is not registered", replacement, deprecated)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvVarPrefix is the prefix applied to the name of each flag to determine
+// the environment variable which may be used to specify the flag value.
+const EnvVarPrefix string = "CHECK_RSAT_"
+
+// NoColorEnvVar is the name of the (de facto standard) environment variable
+// used to request that ANSI color output is omitted. Color output is omitted
+// if this environment variable is set to a non-empty value.
+//
+// See also https://no-color.org/
+const NoColorEnvVar string = "NO_COLOR"
+
+// shorthandFlags maps shorthand flag names to the long flag name sharing the
+// same setting. Shorthand flags are not exposed via environment variables.
+var shorthandFlags = map[string]string{
+	HelpFlagShort:     HelpFlagLong,
+	TimeoutFlagShort:  TimeoutFlagLong,
+	LogLevelFlagShort: LogLevelFlagLong,
+}
+
+// EnvVarName returns the environment variable name used to specify the value
+// for the given (long) flag name (e.g., CHECK_RSAT_SERVER for the server
+// flag).
+func EnvVarName(flagName string) string {
+	return EnvVarPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnvironment applies values from environment variables for any flags
+// not specified on the command-line. Flags take precedence over environment
+// variables. This allows sensitive values (e.g., passwords) to be provided
+// without exposing them on the command-line where they are visible to other
+// users (e.g., via ps).
+func (c *Config) applyEnvironment() error {
+	specified := c.specifiedFlags()
+
+	var envErr error
+	c.flagSet.VisitAll(func(f *flag.Flag) {
+		if envErr != nil {
+			return
+		}
+
+		if _, isShorthand := shorthandFlags[f.Name]; isShorthand || specified[f.Name] {
+			return
+		}
+
+		if deprecatedEnvVarShadowed(f.Name) {
+			return
+		}
+
+		name := EnvVarName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if err := c.flagSet.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf(
+				"invalid value for environment variable %s: %v: %w",
+				name,
+				err,
+				ErrUnsupportedOption,
+			)
+		}
+	})
+
+	return envErr
+}
+
+// noColorRequested indicates whether the NoColorEnvVar environment variable
+// is set to a non-empty value.
+func noColorRequested() bool {
+	value, ok := os.LookupEnv(NoColorEnvVar)
+
+	return ok && value != ""
+}
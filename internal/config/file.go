@@ -0,0 +1,404 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// configFileName is the name of the configuration file searched for in the
+// default configuration file locations.
+const configFileName string = "config.toml"
+
+// systemConfigDir is the system-wide directory searched for a configuration
+// file if one is not found in the user's configuration directory.
+const systemConfigDir string = "/etc/" + myAppName
+
+// secretConfigFileKeys is the collection of settings which provide
+// credentials. A warning is logged if a configuration file providing any of
+// these settings is accessible by other users.
+var secretConfigFileKeys = map[string]bool{
+	PasswordFlagLong:            true,
+	TokenFlagLong:               true,
+	OAuthConsumerSecretFlagLong: true,
+}
+
+// configFileEntry is a single setting from a configuration file.
+type configFileEntry struct {
+	// Key is the (long) flag name for the setting.
+	Key string
+
+	// Values is the collection of values for the setting. Array values
+	// provide one entry per array element.
+	Values []string
+
+	// Line is the line number of the setting within the configuration file.
+	Line int
+}
+
+// defaultConfigFiles returns the configuration file locations searched (in
+// order) if a configuration file is not explicitly specified.
+func defaultConfigFiles() []string {
+	var paths []string
+
+	if userConfigDir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(userConfigDir, myAppName, configFileName))
+	}
+
+	return append(paths, filepath.Join(systemConfigDir, configFileName))
+}
+
+// specifiedFlags returns the names of all flags which have been set (via
+// command-line or otherwise), treating a shorthand flag as also setting the
+// associated long flag.
+func (c *Config) specifiedFlags() map[string]bool {
+	specified := make(map[string]bool)
+
+	c.flagSet.Visit(func(f *flag.Flag) {
+		specified[f.Name] = true
+
+		if long, ok := shorthandFlags[f.Name]; ok {
+			specified[long] = true
+		}
+	})
+
+	markDeprecatedFlags(specified)
+
+	return specified
+}
+
+// applyConfigFile applies settings from the configuration file for any flags
+// not already specified via command-line or environment variable. If a
+// configuration file was not explicitly specified the default locations are
+// searched and the first configuration file found is used; it is not an
+// error if no configuration file is found.
+//
+// Settings for flags which are valid for other applications in this project
+// are ignored so that a single configuration file may be shared.
+func (c *Config) applyConfigFile() error {
+	path := c.ConfigFile
+
+	if path == "" {
+		for _, candidate := range defaultConfigFiles() {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+	}
+
+	if path == "" {
+		return nil
+	}
+
+	fh, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf(
+				"configuration file %s not found: %w",
+				path,
+				ErrUnsupportedOption,
+			)
+		}
+
+		return fmt.Errorf("failed to open configuration file %s: %w", path, err)
+	}
+	defer func() { _ = fh.Close() }()
+
+	entries, err := parseConfigFile(fh)
+	if err != nil {
+		return fmt.Errorf("failed to parse configuration file %s: %w", path, err)
+	}
+
+	if err := c.checkConfigFilePermissions(fh, path, entries); err != nil {
+		return err
+	}
+
+	knownFlags := allFlagNames()
+	specified := c.specifiedFlags()
+
+	for _, entry := range entries {
+		_, isShorthand := shorthandFlags[entry.Key]
+
+		switch {
+		case isShorthand || entry.Key == ConfigFileFlagLong || !knownFlags[entry.Key]:
+			return fmt.Errorf(
+				"unsupported setting %q on line %d of configuration file %s: %w",
+				entry.Key,
+				entry.Line,
+				path,
+				ErrUnsupportedOption,
+			)
+
+		// Valid for another application in this project.
+		case c.flagSet.Lookup(entry.Key) == nil:
+			continue
+
+		// Flags and environment variables take precedence.
+		case specified[entry.Key]:
+			continue
+		}
+
+		for _, value := range entry.Values {
+			if err := c.flagSet.Set(entry.Key, value); err != nil {
+				return fmt.Errorf(
+					"invalid value for setting %q on line %d of configuration file %s: %v: %w",
+					entry.Key,
+					entry.Line,
+					path,
+					err,
+					ErrUnsupportedOption,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkConfigFilePermissions notes a warning if the given configuration file
+// provides credentials and is accessible by other users. The warning is
+// logged once logging has been configured.
+func (c *Config) checkConfigFilePermissions(fh *os.File, path string, entries []configFileEntry) error {
+	// Windows does not provide meaningful Unix permission bits.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	var secrets []string
+	for _, entry := range entries {
+		if secretConfigFileKeys[entry.Key] {
+			secrets = append(secrets, entry.Key)
+		}
+	}
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	info, err := fh.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to access configuration file %s: %w", path, err)
+	}
+
+	if info.Mode().Perm()&0o077 != 0 {
+		c.configFileWarning = fmt.Sprintf(
+			"configuration file %s providing %s is accessible by other users (mode %s); restrict permissions (e.g., chmod 0600)",
+			path,
+			strings.Join(secrets, ", "),
+			info.Mode().Perm(),
+		)
+	}
+
+	return nil
+}
+
+// allFlagNames returns the names of all flags supported by any application
+// type in this project.
+func allFlagNames() map[string]bool {
+	names := make(map[string]bool)
+
+	for _, appType := range []AppType{
+		{Plugin: true},
+		{Inspector: true},
+		{CacheDaemon: true},
+	} {
+		c := Config{flagSet: flag.NewFlagSet("", flag.ContinueOnError)}
+
+		for _, addFlags := range flagGroups(appType) {
+			addFlags(&c)
+		}
+
+		c.flagSet.VisitAll(func(f *flag.Flag) {
+			names[f.Name] = true
+		})
+	}
+
+	return names
+}
+
+// parseConfigFile parses configuration file content using a subset of the
+// TOML format: one "key = value" setting per line where values are quoted
+// strings, bare values (e.g., numbers, true or false) or single-line arrays
+// of these values. Blank lines and comments (beginning with #) are ignored.
+// Tables (sections) are not supported.
+func parseConfigFile(r io.Reader) ([]configFileEntry, error) {
+	var entries []configFileEntry
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf(
+				"line %d: tables are not supported: %w",
+				lineNum,
+				ErrUnsupportedOption,
+			)
+		}
+
+		key, rawValue, found := strings.Cut(line, "=")
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		rawValue = strings.TrimSpace(rawValue)
+
+		if !found || key == "" || rawValue == "" {
+			return nil, fmt.Errorf(
+				"line %d: expected key = value: %w",
+				lineNum,
+				ErrUnsupportedOption,
+			)
+		}
+
+		values, err := parseConfigValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		entries = append(entries, configFileEntry{
+			Key:    key,
+			Values: values,
+			Line:   lineNum,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// parseConfigValue parses a configuration file value. A collection with one
+// entry per array element is returned for array values.
+func parseConfigValue(raw string) ([]string, error) {
+	if !strings.HasPrefix(raw, "[") {
+		value, err := parseConfigScalar(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{value}, nil
+	}
+
+	if !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf(
+			"unterminated array %s: %w",
+			raw,
+			ErrUnsupportedOption,
+		)
+	}
+
+	var values []string
+
+	for _, item := range splitConfigArray(raw[1 : len(raw)-1]) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		value, err := parseConfigScalar(item)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// parseConfigScalar parses a single (non-array) configuration file value.
+// Basic (double-quoted) strings support common escape sequences while
+// literal (single-quoted) strings are used as-is.
+func parseConfigScalar(raw string) (string, error) {
+	switch {
+	case len(raw) >= 2 && strings.HasPrefix(raw, `'`) && strings.HasSuffix(raw, `'`):
+		return raw[1 : len(raw)-1], nil
+
+	case len(raw) >= 2 && strings.HasPrefix(raw, `"`) && strings.HasSuffix(raw, `"`):
+		replacer := strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\t`, "\t")
+		return replacer.Replace(raw[1 : len(raw)-1]), nil
+
+	case strings.ContainsAny(raw, `"'`):
+		return "", fmt.Errorf(
+			"invalid quoted value %s: %w",
+			raw,
+			ErrUnsupportedOption,
+		)
+
+	default:
+		return raw, nil
+	}
+}
+
+// splitConfigArray splits the content of an array value on commas which are
+// not within a quoted string.
+func splitConfigArray(s string) []string {
+	var items []string
+	var quote rune
+	var escaped bool
+	start := 0
+
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(items, s[start:])
+}
+
+// stripComment removes a trailing comment (beginning with #) from the given
+// line. A # character within a quoted string is not treated as a comment.
+func stripComment(line string) string {
+	var quote rune
+	var escaped bool
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	content := `
+# Connection settings
+server = "rsat.example.com"
+port = 443 # inline comment
+password = 'p#ss"word'
+trust-cert = false
+exclude-products = ["RHEL 7*", '/^EPEL, legacy/', "Tools \"beta\""]
+`
+
+	want := []configFileEntry{
+		{Key: "server", Values: []string{"rsat.example.com"}, Line: 3},
+		{Key: "port", Values: []string{"443"}, Line: 4},
+		{Key: "password", Values: []string{`p#ss"word`}, Line: 5},
+		{Key: "trust-cert", Values: []string{"false"}, Line: 6},
+		{Key: "exclude-products", Values: []string{"RHEL 7*", "/^EPEL, legacy/", `Tools "beta"`}, Line: 7},
+	}
+
+	got, err := parseConfigFile(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConfigFile() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseConfigFileInvalid(t *testing.T) {
+	tests := []string{
+		"[section]",
+		"server",
+		"server =",
+		`server = "unterminated`,
+		`exclude-products = ["a", "b"`,
+	}
+
+	for _, content := range tests {
+		if _, err := parseConfigFile(strings.NewReader(content)); !errors.Is(err, ErrUnsupportedOption) {
+			t.Errorf("parseConfigFile(%q): got error %v, want %v", content, err, ErrUnsupportedOption)
+		}
+	}
+}
+
+func TestApplyConfigFilePermissionsWarning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not provide meaningful Unix permission bits")
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		mode        os.FileMode
+		wantWarning bool
+	}{
+		{name: "password readable by others", content: `password = "secret"`, mode: 0o644, wantWarning: true},
+		{name: "token readable by group", content: `token = "secret"`, mode: 0o640, wantWarning: true},
+		{name: "oauth secret readable by others", content: `oauth-consumer-secret = "secret"`, mode: 0o604, wantWarning: true},
+		{name: "password restricted", content: `password = "secret"`, mode: 0o600, wantWarning: false},
+		{name: "no credentials", content: `server = "rsat.example.com"`, mode: 0o644, wantWarning: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), configFileName)
+			if err := os.WriteFile(path, []byte(tt.content+"\n"), tt.mode); err != nil {
+				t.Fatalf("failed to write configuration file: %v", err)
+			}
+
+			// Explicitly set permissions to avoid the effects of umask.
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("failed to set configuration file permissions: %v", err)
+			}
+
+			c := Config{
+				ConfigFile: path,
+				flagSet:    flag.NewFlagSet(tt.name, flag.ContinueOnError),
+			}
+
+			if err := c.applyConfigFile(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := c.configFileWarning != ""; got != tt.wantWarning {
+				t.Errorf("got warning %q, want warning: %t", c.configFileWarning, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -10,8 +10,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
+// flagGroup registers a related set of flags (e.g., connection, TLS or
+// threshold settings) for an application. Applications assemble their flag
+// set from these groups so that flag names, defaults and help text are
+// consistent across all applications in this project.
+type flagGroup func(c *Config)
+
 // supportedValuesFlagHelpText is a flag package helper function that combines
 // base help text with a list of supported values for the flag.
 func supportedValuesFlagHelpText(baseHelpText string, supportedValues []string) string {
@@ -22,6 +29,59 @@ func supportedValuesFlagHelpText(baseHelpText string, supportedValues []string)
 	)
 }
 
+// flagGroups returns the collection of flag groups exposed for the specified
+// application type. A set of groups common to all application types is
+// returned along with groups specific to the application type.
+func flagGroups(appType AppType) []flagGroup {
+	groups := []flagGroup{
+		(*Config).addGeneralFlags,
+		(*Config).addConnectionFlags,
+		(*Config).addAuthFlags,
+		(*Config).addTLSFlags,
+		(*Config).addEvaluationFlags,
+		(*Config).addFilterFlags,
+		(*Config).addOutputFlags,
+	}
+
+	switch {
+	case appType.CacheDaemon:
+		groups = append(
+			groups,
+			timeoutFlags(defaultCacheDaemonTimeout, cacheDaemonTimeoutFlagHelp),
+			(*Config).addCacheDaemonFlags,
+		)
+
+	case appType.Inspector:
+		groups = append(
+			groups,
+			timeoutFlags(defaultCLIAppTimeout, cliAppTimeoutFlagHelp),
+			(*Config).addCacheClientFlags,
+			(*Config).addInspectorAuthFlags,
+			(*Config).addInspectorOutputFlags,
+			(*Config).addBatchFlags,
+			(*Config).addFixturesFlags,
+			(*Config).addDryRunFlags,
+		)
+
+	case appType.Plugin:
+		groups = append(
+			groups,
+			timeoutFlags(defaultPluginTimeout, pluginTimeoutFlagHelp),
+			(*Config).addCacheClientFlags,
+			(*Config).addPluginOutputFlags,
+			(*Config).addThresholdFlags,
+			(*Config).addDryRunFlags,
+		)
+
+	default:
+		groups = append(groups, (*Config).addCacheClientFlags)
+	}
+
+	// Deprecated flag aliases are registered last so that the flags which
+	// replace them are already registered.
+	return append(groups, (*Config).addDeprecatedFlags)
+}
+
 // handleFlagsConfig handles toggling the exposure of specific configuration
 // flags to the user. This behavior is controlled via the specified
 // application type as set by each cmd. Based on the application's specified
@@ -35,11 +95,48 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		)
 	}
 
-	// shared flags
+	for _, addFlags := range flagGroups(appType) {
+		addFlags(c)
+	}
+
+	// Allow our function to override the default Help output.
+	//
+	// Override default of stderr as destination for help output. This allows
+	// Nagios XI and similar monitoring systems to call plugins with the
+	// `--help` flag and have it display within the Admin web UI.
+	c.flagSet.Usage = Usage(c.flagSet, os.Stdout)
+
+	// parse flag definitions from the argument list
+	if err := c.flagSet.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	// Apply settings from environment variables for any flags not specified
+	// on the command-line.
+	if err := c.applyEnvironment(); err != nil {
+		return err
+	}
+
+	// Skip loading the configuration file if we're only going to display
+	// help, version or schema details.
+	if c.ShowHelp || c.ShowVersion || c.ShowSchema {
+		return nil
+	}
+
+	// Apply settings from the configuration file for any flags not specified
+	// via command-line or environment variable.
+	return c.applyConfigFile()
+}
+
+// addGeneralFlags registers flags for help, version, configuration file and
+// logging settings.
+func (c *Config) addGeneralFlags() {
 	c.flagSet.BoolVar(&c.ShowHelp, HelpFlagShort, defaultHelp, helpFlagHelp+shorthandFlagSuffix)
 	c.flagSet.BoolVar(&c.ShowHelp, HelpFlagLong, defaultHelp, helpFlagHelp)
 
 	c.flagSet.BoolVar(&c.ShowVersion, VersionFlagLong, defaultDisplayVersionAndExit, versionFlagHelp)
+	c.flagSet.BoolVar(&c.ShowSchema, PrintSchemaFlagLong, defaultPrintSchema, printSchemaFlagHelp)
+	c.flagSet.StringVar(&c.ConfigFile, ConfigFileFlagLong, defaultConfigFile, configFileFlagHelp)
 
 	c.flagSet.StringVar(
 		&c.LoggingLevel,
@@ -53,10 +150,12 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		defaultLogLevel,
 		supportedValuesFlagHelpText(logLevelFlagHelp, supportedLogLevels()),
 	)
+}
 
-	c.flagSet.StringVar(&c.Server, ServerFlagLong, defaultServer, serverFlagHelp)
-	c.flagSet.StringVar(&c.Username, UsernameFlagLong, defaultUsername, usernameFlagHelp)
-	c.flagSet.StringVar(&c.Password, PasswordFlagLong, defaultPassword, passwordFlagHelp)
+// addConnectionFlags registers flags for connecting to the Red Hat Satellite
+// server and limiting the API responses read.
+func (c *Config) addConnectionFlags() {
+	c.flagSet.Var((*multiValueStringFlag)(&c.ServerList), ServerFlagLong, serverFlagHelp)
 	c.flagSet.IntVar(&c.TCPPort, PortFlagLong, defaultTCPPort, tcpPortFlagHelp)
 
 	c.flagSet.StringVar(
@@ -66,39 +165,224 @@ func (c *Config) handleFlagsConfig(appType AppType) error {
 		supportedValuesFlagHelpText(networkTypeFlagHelp, supportedNetworkTypes()),
 	)
 
-	c.flagSet.BoolVar(&c.OmitOKSyncPlans, OmitOKSyncPlansFlagLong, defaultOmitOKSyncPlans, omitOKSyncPlansHelp)
-	c.flagSet.BoolVar(&c.TrustCert, TrustCertFlagLong, defaultTrustCert, trustCertFlagHelp)
+	c.flagSet.StringVar(&c.DNSServer, DNSServerFlagLong, defaultDNSServer, dnsServerFlagHelp)
+	c.flagSet.IntVar(&c.dnsTimeout, DNSTimeoutFlagLong, defaultDNSTimeout, dnsTimeoutFlagHelp)
+
+	c.flagSet.Int64Var(&c.ReadLimit, ReadLimitFlagLong, defaultReadLimit, readLimitFlagHelp)
+	c.flagSet.StringVar(&c.perPageLimit, PerPageLimitFlagLong, strconv.Itoa(defaultPerPageLimit), perPageLimitFlagHelp)
+	c.flagSet.IntVar(&c.MaxConcurrent, MaxConcurrentFlagLong, defaultMaxConcurrent, maxConcurrentFlagHelp)
+	c.flagSet.IntVar(&c.MaxIdleConns, MaxIdleConnsFlagLong, defaultMaxIdleConns, maxIdleConnsFlagHelp)
+	c.flagSet.IntVar(&c.MaxIdleConnsPerHost, MaxIdleConnsPerHostFlagLong, defaultMaxIdleConnsPerHost, maxIdleConnsPerHostFlagHelp)
+	c.flagSet.IntVar(&c.idleConnTimeout, IdleConnTimeoutFlagLong, defaultIdleConnTimeout, idleConnTimeoutFlagHelp)
+	c.flagSet.IntVar(&c.dialTimeout, DialTimeoutFlagLong, defaultDialTimeout, dialTimeoutFlagHelp)
+	c.flagSet.IntVar(&c.dialKeepAlive, DialKeepAliveFlagLong, defaultDialKeepAlive, dialKeepAliveFlagHelp)
+	c.flagSet.BoolVar(&c.EnableHTTP2, EnableHTTP2FlagLong, defaultEnableHTTP2, enableHTTP2FlagHelp)
+	c.flagSet.BoolVar(&c.StreamDecode, StreamDecodeFlagLong, defaultStreamDecode, streamDecodeFlagHelp)
+	c.flagSet.BoolVar(&c.BulkSyncPlans, BulkSyncPlansFlagLong, defaultBulkSyncPlans, bulkSyncPlansFlagHelp)
+	c.flagSet.IntVar(&c.RetryMaxAttempts, RetryMaxAttemptsFlagLong, defaultRetryMaxAttempts, retryMaxAttemptsFlagHelp)
+	c.flagSet.IntVar(&c.retryBaseDelay, RetryBaseDelayFlagLong, defaultRetryBaseDelay, retryBaseDelayFlagHelp)
+	c.flagSet.Float64Var(&c.RetryJitter, RetryJitterFlagLong, defaultRetryJitter, retryJitterFlagHelp)
+}
+
+// addAuthFlags registers flags for authenticating to the Red Hat Satellite
+// server.
+func (c *Config) addAuthFlags() {
+	c.flagSet.StringVar(&c.Username, UsernameFlagLong, defaultUsername, usernameFlagHelp)
+	c.flagSet.StringVar(&c.Password, PasswordFlagLong, defaultPassword, passwordFlagHelp)
+	c.flagSet.StringVar(&c.PasswordFile, PasswordFileFlagLong, defaultPasswordFile, passwordFileFlagHelp)
+	c.flagSet.StringVar(&c.Token, TokenFlagLong, defaultToken, tokenFlagHelp)
+	c.flagSet.StringVar(&c.OAuthConsumerKey, OAuthConsumerKeyFlagLong, defaultOAuthConsumerKey, oauthConsumerKeyFlagHelp)
+	c.flagSet.StringVar(&c.OAuthConsumerSecret, OAuthConsumerSecretFlagLong, defaultOAuthConsumerSecret, oauthConsumerSecretFlagHelp)
+	c.flagSet.StringVar(&c.HammerConfig, HammerConfigFlagLong, defaultHammerConfig, hammerConfigFlagHelp)
+}
+
+// addTLSFlags registers flags for validating the Red Hat Satellite server's
+// certificate chain and TLS behavior.
+func (c *Config) addTLSFlags() {
+	c.flagSet.BoolVar(&c.TrustCert, InsecureSkipVerifyFlagLong, defaultTrustCert, insecureSkipVerifyFlagHelp)
 	c.flagSet.BoolVar(&c.PermitTLSRenegotiation, PermitTLSRenegotiationFlagLong, defaultPermitTLSRenegotiation, permitTLSRenegotiationFlagHelp)
 	c.flagSet.StringVar(&c.CACertificate, CACertificateFlagLong, defaultCACertificate, caCertificateFlagHelp)
-	c.flagSet.Int64Var(&c.ReadLimit, ReadLimitFlagLong, defaultReadLimit, readLimitFlagHelp)
-	c.flagSet.IntVar(&c.PerPageLimit, PerPageLimitFlagLong, defaultPerPageLimit, perPageLimitFlagHelp)
+	c.flagSet.StringVar(&c.ClientCertificate, ClientCertificateFlagLong, defaultClientCertificate, clientCertificateFlagHelp)
+	c.flagSet.StringVar(&c.ClientKey, ClientKeyFlagLong, defaultClientKey, clientKeyFlagHelp)
+}
 
-	switch {
-	case appType.Inspector:
-		c.flagSet.IntVar(&c.timeout, TimeoutFlagShort, defaultCLIAppTimeout, cliAppTimeoutFlagHelp+shorthandFlagSuffix)
-		c.flagSet.IntVar(&c.timeout, TimeoutFlagLong, defaultCLIAppTimeout, cliAppTimeoutFlagHelp)
-
-		c.flagSet.StringVar(
-			&c.InspectorOutputFormat,
-			InspectorOutputFormatFlagLong,
-			defaultInspectorOutputFormat,
-			supportedValuesFlagHelpText(inspectorOutputFormatFlagHelp, supportedInspectorOutputFormats()),
-		)
+// addEvaluationFlags registers flags for optional sync plan evaluation
+// behavior.
+func (c *Config) addEvaluationFlags() {
+	c.flagSet.BoolVar(&c.EvaluateProductSyncState, ProductSyncStateFlagLong, defaultProductSyncState, productSyncStateFlagHelp)
+	c.flagSet.BoolVar(&c.CheckRecurringLogic, RecurringLogicFlagLong, defaultRecurringLogic, recurringLogicFlagHelp)
+	c.flagSet.BoolVar(&c.BasicMode, BasicModeFlagLong, defaultBasicMode, basicModeFlagHelp)
+	c.flagSet.Var(&c.MaxProductSyncAge, MaxProductSyncAgeFlagLong, maxProductSyncAgeFlagHelp)
+	c.flagSet.Float64Var(&c.MaxIntervalDrift, MaxIntervalDriftFlagLong, defaultMaxIntervalDrift, maxIntervalDriftFlagHelp)
+	c.flagSet.StringVar(&c.OwnerPattern, OwnerPatternFlagLong, defaultOwnerPattern, ownerPatternFlagHelp)
+	c.flagSet.StringVar(&c.OwnerOrgParameter, OwnerOrgParameterFlagLong, defaultOwnerOrgParameter, ownerOrgParameterFlagHelp)
+}
 
-	case appType.Plugin:
-		c.flagSet.BoolVar(&c.ShowVerbose, VerboseFlagLong, defaultVerbose, verboseFlagHelp)
-		c.flagSet.IntVar(&c.timeout, TimeoutFlagShort, defaultPluginTimeout, pluginTimeoutFlagHelp+shorthandFlagSuffix)
-		c.flagSet.IntVar(&c.timeout, TimeoutFlagLong, defaultPluginTimeout, pluginTimeoutFlagHelp)
+// addFilterFlags registers flags for limiting the organizations, sync plans
+// and products evaluated.
+func (c *Config) addFilterFlags() {
+	c.flagSet.Var((*multiValueStringFlag)(&c.ExcludeProducts), ExcludeProductsFlagLong, excludeProductsFlagHelp)
+	c.flagSet.Var((*multiValueStringFlag)(&c.Orgs), OrgFlagLong, orgFlagHelp)
+	c.flagSet.Var((*multiValueStringFlag)(&c.ExcludeOrgs), ExcludeOrgFlagLong, excludeOrgFlagHelp)
+	c.flagSet.Var((*multiValueStringFlag)(&c.IgnorePlans), IgnorePlanFlagLong, ignorePlansFlagHelp)
+	c.flagSet.BoolVar(&c.IgnoreSuppressionTags, IgnoreSuppressionTagsFlagLong, defaultIgnoreSuppressionTags, ignoreSuppressionTagsFlagHelp)
+	c.flagSet.BoolVar(&c.EnabledOnly, EnabledOnlyFlagLong, defaultEnabledOnly, enabledOnlyFlagHelp)
+}
 
+// addOutputFlags registers flags for output settings common to all
+// application types.
+func (c *Config) addOutputFlags() {
+	c.flagSet.BoolVar(&c.OmitOKSyncPlans, OmitOKSyncPlansFlagLong, defaultOmitOKSyncPlans, omitOKSyncPlansHelp)
+	c.flagSet.IntVar(&c.TopStuck, TopStuckFlagLong, defaultTopStuck, topStuckFlagHelp)
+	c.flagSet.BoolVar(&c.ShowProducts, ShowProductsFlagLong, defaultShowProducts, showProductsFlagHelp)
+	c.flagSet.IntVar(&c.MaxProblemsShown, MaxProblemsShownFlagLong, defaultMaxProblemsShown, maxProblemsShownFlagHelp)
+
+	c.flagSet.StringVar(&c.DisplayTimezone, DisplayTimezoneFlagLong, defaultDisplayTimezone, displayTimezoneFlagHelp)
+
+	c.flagSet.StringVar(
+		&c.TimeFormat,
+		TimeFormatFlagLong,
+		defaultTimeFormat,
+		supportedValuesFlagHelpText(timeFormatFlagHelp, supportedTimeFormats()),
+	)
+
+	c.flagSet.StringVar(
+		&c.SortBy,
+		SortByFlagLong,
+		defaultSortBy,
+		supportedValuesFlagHelpText(sortByFlagHelp, supportedSortByValues()),
+	)
+
+	c.flagSet.StringVar(
+		&c.SortOrder,
+		SortOrderFlagLong,
+		defaultSortOrder,
+		supportedValuesFlagHelpText(sortOrderFlagHelp, supportedSortOrders()),
+	)
+}
+
+// timeoutFlags returns a flag group which registers the timeout flags using
+// the given application specific default value and help text.
+func timeoutFlags(defaultTimeout int, helpText string) flagGroup {
+	return func(c *Config) {
+		c.flagSet.IntVar(&c.timeout, TimeoutFlagShort, defaultTimeout, helpText+shorthandFlagSuffix)
+		c.flagSet.IntVar(&c.timeout, TimeoutFlagLong, defaultTimeout, helpText)
 	}
+}
 
-	// Allow our function to override the default Help output.
-	//
-	// Override default of stderr as destination for help output. This allows
-	// Nagios XI and similar monitoring systems to call plugins with the
-	// `--help` flag and have it display within the Admin web UI.
-	c.flagSet.Usage = Usage(c.flagSet, os.Stdout)
+// addCacheDaemonFlags registers flags for serving organizations and sync
+// plans via a shared cache daemon.
+func (c *Config) addCacheDaemonFlags() {
+	c.flagSet.StringVar(&c.CacheSocket, CacheSocketFlagLong, defaultCacheSocket, cacheDaemonSocketFlagHelp)
+	c.flagSet.IntVar(&c.cacheTTL, CacheTTLFlagLong, defaultCacheTTL, cacheTTLFlagHelp)
+}
 
-	// parse flag definitions from the argument list
-	return c.flagSet.Parse(os.Args[1:])
+// addCacheClientFlags registers flags for retrieving organizations and sync
+// plans from a shared cache daemon.
+func (c *Config) addCacheClientFlags() {
+	c.flagSet.StringVar(&c.CacheSocket, CacheSocketFlagLong, defaultCacheSocket, cacheSocketFlagHelp)
+	c.flagSet.StringVar(&c.CacheDir, CacheDirFlagLong, defaultCacheDir, cacheDirFlagHelp)
+	c.flagSet.IntVar(&c.cacheTTL, CacheTTLFlagLong, defaultCacheTTL, diskCacheTTLFlagHelp)
+}
+
+// addInspectorAuthFlags registers flags for authenticating to the Red Hat
+// Satellite server specific to (interactive) Inspector type applications.
+func (c *Config) addInspectorAuthFlags() {
+	c.flagSet.BoolVar(&c.PasswordPrompt, PasswordPromptFlagLong, defaultPasswordPrompt, passwordPromptFlagHelp)
+}
+
+// addInspectorOutputFlags registers flags for output settings specific to
+// Inspector type applications.
+func (c *Config) addInspectorOutputFlags() {
+	c.flagSet.StringVar(
+		&c.InspectorOutputFormat,
+		InspectorOutputFormatFlagLong,
+		defaultInspectorOutputFormat,
+		supportedValuesFlagHelpText(inspectorOutputFormatFlagHelp, supportedInspectorOutputFormats()),
+	)
+
+	c.flagSet.StringVar(&c.OutputFile, OutputFileFlagLong, defaultOutputFile, outputFileFlagHelp)
+	c.flagSet.BoolVar(&c.NoColor, NoColorFlagLong, defaultNoColor, noColorFlagHelp)
+	c.flagSet.BoolVar(&c.ASCIIOnly, ASCIIOnlyFlagLong, defaultASCIIOnly, asciiOnlyFlagHelp)
+	c.flagSet.IntVar(&c.MaxWidth, MaxWidthFlagLong, defaultMaxWidth, maxWidthFlagHelp)
+}
+
+// addBatchFlags registers flags for evaluating multiple Red Hat Satellite
+// servers in batch mode.
+func (c *Config) addBatchFlags() {
+	c.flagSet.StringVar(&c.Servers, ServersFlagLong, defaultServers, serversFlagHelp)
+	c.flagSet.IntVar(&c.BatchConcurrency, BatchConcurrencyFlagLong, defaultBatchConcurrency, batchConcurrencyFlagHelp)
+}
+
+// addFixturesFlags registers flags for reading saved API responses from disk
+// instead of submitting requests to the Red Hat Satellite server.
+func (c *Config) addFixturesFlags() {
+	c.flagSet.StringVar(&c.FixturesDir, FixturesDirFlagLong, defaultFixturesDir, fixturesDirFlagHelp)
+}
+
+// addDryRunFlags registers flags for verifying connectivity to and
+// authentication with the Red Hat Satellite server without retrieving
+// organizations or sync plans.
+func (c *Config) addDryRunFlags() {
+	c.flagSet.BoolVar(&c.DryRun, DryRunFlagLong, defaultDryRun, dryRunFlagHelp)
+}
+
+// addPluginOutputFlags registers flags for output settings specific to
+// Plugin type applications.
+func (c *Config) addPluginOutputFlags() {
+	c.flagSet.BoolVar(&c.ShowVerbose, VerboseFlagLong, defaultVerbose, verboseFlagHelp)
+	c.flagSet.BoolVar(&c.CompactOutput, CompactFlagLong, defaultCompact, compactFlagHelp)
+	c.flagSet.StringVar(&c.StateFile, StateFileFlagLong, defaultStateFile, stateFileFlagHelp)
+	c.flagSet.StringVar(&c.perfDataLabelPrefix, PerfDataLabelPrefixFlagLong, defaultPerfDataLabelPrefix, perfDataLabelPrefixFlagHelp)
+	c.flagSet.BoolVar(&c.APIPerfData, APIPerfDataFlagLong, defaultAPIPerfData, apiPerfDataFlagHelp)
+	c.flagSet.BoolVar(&c.EmitPayload, PayloadFlagLong, defaultPayload, payloadFlagHelp)
+	c.flagSet.IntVar(&c.MaxOutputBytes, MaxOutputBytesFlagLong, defaultMaxOutputBytes, maxOutputBytesFlagHelp)
+}
+
+// addThresholdFlags registers flags for the thresholds and state mappings
+// used by Plugin type applications to determine the service check state.
+func (c *Config) addThresholdFlags() {
+	c.DaysStuckWarning = mustThreshold(defaultDaysStuckWarning)
+	c.DaysStuckCritical = mustThreshold(defaultDaysStuckCritical)
+
+	c.flagSet.Var(&c.DaysStuckWarning, DaysStuckWarningFlagLong, daysStuckWarningFlagHelp)
+	c.flagSet.Var(&c.DaysStuckCritical, DaysStuckCriticalFlagLong, daysStuckCriticalFlagHelp)
+	c.flagSet.Var(&c.StuckCountWarning, StuckCountWarningFlagLong, stuckCountWarningFlagHelp)
+	c.flagSet.Var(&c.StuckCountCritical, StuckCountCriticalFlagLong, stuckCountCriticalFlagHelp)
+	c.flagSet.BoolVar(&c.CertVerifyWarn, CertVerifyWarnFlagLong, defaultCertVerifyWarn, certVerifyWarnFlagHelp)
+
+	c.flagSet.StringVar(
+		&c.MaintenanceState,
+		MaintenanceStateFlagLong,
+		defaultMaintenanceState,
+		supportedValuesFlagHelpText(maintenanceStateFlagHelp, supportedMaintenanceStates()),
+	)
+
+	c.flagSet.StringVar(
+		&c.StuckState,
+		StuckStateFlagLong,
+		defaultStuckState,
+		supportedValuesFlagHelpText(stuckStateFlagHelp, supportedStuckStates()),
+	)
+
+	c.flagSet.StringVar(
+		&c.NeverScheduledState,
+		NeverScheduledStateFlagLong,
+		defaultNeverScheduledState,
+		supportedValuesFlagHelpText(neverScheduledStateFlagHelp, supportedNeverScheduledStates()),
+	)
+
+	c.flagSet.StringVar(
+		&c.EmptyOrgState,
+		EmptyOrgStateFlagLong,
+		defaultEmptyOrgState,
+		supportedValuesFlagHelpText(emptyOrgStateFlagHelp, supportedEmptyOrgStates()),
+	)
+
+	c.flagSet.StringVar(
+		&c.ConnectionFailureState,
+		ConnectionFailureStateFlagLong,
+		defaultConnectionFailureState,
+		supportedValuesFlagHelpText(connectionFailureStateFlagHelp, supportedConnectionFailureStates()),
+	)
 }
@@ -8,8 +8,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/atc0005/go-nagios"
 )
 
 // Timeout converts the user-specified connection timeout value in seconds to
@@ -18,6 +24,100 @@ func (c Config) Timeout() time.Duration {
 	return time.Duration(c.timeout) * time.Second
 }
 
+// CacheTTL converts the user-specified cache TTL value in seconds to an
+// appropriate time duration value for use by the cache daemon.
+func (c Config) CacheTTL() time.Duration {
+	return time.Duration(c.cacheTTL) * time.Second
+}
+
+// IdleConnTimeout converts the user-specified idle connection timeout value
+// in seconds to an appropriate time duration value for use by the HTTP
+// transport.
+func (c Config) IdleConnTimeout() time.Duration {
+	return time.Duration(c.idleConnTimeout) * time.Second
+}
+
+// DNSTimeout converts the user-specified DNS timeout value in seconds to an
+// appropriate time duration value for use by the resolver.
+func (c Config) DNSTimeout() time.Duration {
+	return time.Duration(c.dnsTimeout) * time.Second
+}
+
+// DialTimeout converts the user-specified dial timeout value in seconds to
+// an appropriate time duration value for use by the network dialer.
+func (c Config) DialTimeout() time.Duration {
+	return time.Duration(c.dialTimeout) * time.Second
+}
+
+// DialKeepAlive converts the user-specified dial keep-alive value in seconds
+// to an appropriate time duration value for use by the network dialer.
+func (c Config) DialKeepAlive() time.Duration {
+	return time.Duration(c.dialKeepAlive) * time.Second
+}
+
+// RetryBaseDelay converts the user-specified retry base delay value in
+// seconds to an appropriate time duration value for use by the API client.
+func (c Config) RetryBaseDelay() time.Duration {
+	return time.Duration(c.retryBaseDelay) * time.Second
+}
+
+// PerPageLimit returns the user-specified pagination limit for API calls.
+// Zero is returned if the limit is to be determined automatically or if the
+// limit is invalid.
+func (c Config) PerPageLimit() int {
+	limit, err := strconv.Atoi(strings.TrimSpace(c.perPageLimit))
+	if err != nil {
+		return 0
+	}
+
+	return limit
+}
+
+// PerPageAuto indicates whether the pagination limit for API calls is to be
+// determined automatically.
+func (c Config) PerPageAuto() bool {
+	return strings.EqualFold(strings.TrimSpace(c.perPageLimit), PerPageLimitAuto)
+}
+
+// MachineReadableOutput indicates whether the Inspector type application
+// output format is intended for processing by other tools (e.g., jq) instead
+// of review by sysadmins.
+func (c Config) MachineReadableOutput() bool {
+	switch c.InspectorOutputFormat {
+	case InspectorOutputFormatJSON, InspectorOutputFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// PerfDataLabelPrefix returns the prefix applied to performance data metric
+// labels. If requested, the prefix is derived from the server name with
+// characters other than letters and digits replaced by underscores (e.g.,
+// rsat_example_com_ for rsat.example.com).
+func (c Config) PerfDataLabelPrefix() string {
+	if !strings.EqualFold(c.perfDataLabelPrefix, PerfDataLabelPrefixAuto) {
+		return c.perfDataLabelPrefix
+	}
+
+	return serverLabelPrefix(c.Server)
+}
+
+// serverLabelPrefix returns a performance data metric label prefix derived
+// from the given server name.
+func serverLabelPrefix(server string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, server)
+
+	return sanitized + "_"
+}
+
 // supportedLogLevels returns a list of valid log levels supported by tools in
 // this project.
 func supportedLogLevels() []string {
@@ -51,6 +151,160 @@ func supportedInspectorOutputFormats() []string {
 		InspectorOutputFormatSimpleTable,
 		InspectorOutputFormatPrettyTable,
 		InspectorOutputFormatVerbose,
+		InspectorOutputFormatJSON,
+		InspectorOutputFormatCSV,
+		InspectorOutputFormatMarkdown,
+		InspectorOutputFormatHTML,
+		InspectorOutputFormatByProduct,
+	}
+}
+
+// Ellipsis returns the value used to indicate that text in a report has been
+// truncated. Plain ASCII characters are used if requested.
+func (c Config) Ellipsis() string {
+	if c.ASCIIOnly {
+		return "..."
+	}
+
+	return "…"
+}
+
+// DisplayLocation returns the location (time zone) used when rendering time
+// values in reports. The local time zone is returned if the user-specified
+// time zone is invalid; this is prevented by validation.
+func (c Config) DisplayLocation() *time.Location {
+	loc, err := c.loadDisplayLocation()
+	if err != nil {
+		return time.Local
+	}
+
+	return loc
+}
+
+// displayLocationErr returns any error encountered when loading the
+// user-specified display time zone.
+func (c Config) displayLocationErr() error {
+	_, err := c.loadDisplayLocation()
+
+	return err
+}
+
+// loadDisplayLocation loads the location for the user-specified display time
+// zone. The UTC and Local keywords are case-insensitive.
+func (c Config) loadDisplayLocation() (*time.Location, error) {
+	switch {
+	case strings.EqualFold(c.DisplayTimezone, "UTC"):
+		return time.UTC, nil
+	case strings.EqualFold(c.DisplayTimezone, "Local"):
+		return time.Local, nil
+	case strings.TrimSpace(c.DisplayTimezone) == "":
+		return nil, errors.New("time zone not specified")
+	default:
+		return time.LoadLocation(c.DisplayTimezone)
+	}
+}
+
+// ColorDisabled indicates whether ANSI color and formatting escape sequences
+// should be omitted from report and log output. This is the case if the user
+// specified the no-color flag or if the NO_COLOR environment variable is set
+// to a non-empty value.
+func (c Config) ColorDisabled() bool {
+	return c.NoColor || noColorRequested()
+}
+
+// supportedSortByValues returns a list of valid values used to sort
+// organizations and sync plans in reports.
+func supportedSortByValues() []string {
+	return []string{
+		SortByOrgName,
+		SortByPlanName,
+		SortByDaysStuck,
+		SortByNextSync,
+	}
+}
+
+// supportedTimeFormats returns a list of valid formats used to render next
+// sync and last sync times in reports.
+func supportedTimeFormats() []string {
+	return []string{
+		TimeFormatAbsolute,
+		TimeFormatRelative,
+		TimeFormatBoth,
+	}
+}
+
+// supportedSortOrders returns a list of valid orders used to sort
+// organizations and sync plans in reports.
+func supportedSortOrders() []string {
+	return []string{
+		SortOrderAscending,
+		SortOrderDescending,
+	}
+}
+
+// SortReversed indicates whether organizations and sync plans in reports
+// should be listed in the reverse of the natural order for the specified
+// sort value. Sorting by days stuck is naturally in descending order (most
+// days stuck first); all other sort values are naturally in ascending order.
+func (c Config) SortReversed() bool {
+	if c.SortOrder == "" {
+		return false
+	}
+
+	naturalOrder := SortOrderAscending
+	if strings.EqualFold(c.SortBy, SortByDaysStuck) {
+		naturalOrder = SortOrderDescending
+	}
+
+	return !strings.EqualFold(c.SortOrder, naturalOrder)
+}
+
+// supportedMaintenanceStates returns a list of valid plugin states which may
+// be used when the Red Hat Satellite server is in maintenance mode.
+func supportedMaintenanceStates() []string {
+	return []string{
+		nagios.StateOKLabel,
+		nagios.StateWARNINGLabel,
+		nagios.StateCRITICALLabel,
+		nagios.StateUNKNOWNLabel,
+	}
+}
+
+// supportedStuckStates returns a list of valid plugin states which may be
+// used when the warning thresholds for stuck sync plans are met.
+func supportedStuckStates() []string {
+	return []string{
+		nagios.StateWARNINGLabel,
+		nagios.StateCRITICALLabel,
+	}
+}
+
+// supportedNeverScheduledStates returns a list of valid plugin states which
+// may be used if any enabled sync plans have never been scheduled.
+func supportedNeverScheduledStates() []string {
+	return []string{
+		nagios.StateOKLabel,
+		nagios.StateWARNINGLabel,
+		nagios.StateCRITICALLabel,
+	}
+}
+
+// supportedEmptyOrgStates returns a list of valid plugin states which may be
+// used if any organizations do not have any sync plans.
+func supportedEmptyOrgStates() []string {
+	return []string{
+		nagios.StateOKLabel,
+		nagios.StateWARNINGLabel,
+	}
+}
+
+// supportedConnectionFailureStates returns a list of valid plugin states
+// which may be used when connecting to or communicating with the Red Hat
+// Satellite server fails.
+func supportedConnectionFailureStates() []string {
+	return []string{
+		nagios.StateCRITICALLabel,
+		nagios.StateUNKNOWNLabel,
 	}
 }
 
@@ -65,3 +319,31 @@ func (c Config) UserAgent() string {
 		version,
 	)
 }
+
+// OwnerRegexp returns the compiled user-specified sync plan owner naming
+// convention pattern. Nil is returned if a pattern was not specified (or is
+// invalid; the pattern is checked during config validation).
+func (c Config) OwnerRegexp() *regexp.Regexp {
+	if c.OwnerPattern == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(c.OwnerPattern)
+	if err != nil {
+		return nil
+	}
+
+	return re
+}
+
+// UsesOAuth indicates whether the user opted to sign requests using OAuth
+// 1.0a by specifying both an OAuth consumer key and secret.
+func (c Config) UsesOAuth() bool {
+	return c.OAuthConsumerKey != "" && c.OAuthConsumerSecret != ""
+}
+
+// FixturesMode indicates whether the user opted to read saved API responses
+// from disk instead of submitting requests to the Red Hat Satellite server.
+func (c Config) FixturesMode() bool {
+	return c.FixturesDir != ""
+}
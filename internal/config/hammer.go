@@ -0,0 +1,218 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// hammerForemanSection is the name of the Hammer CLI configuration section
+// providing Foreman (Red Hat Satellite) connection settings.
+const hammerForemanSection string = "foreman"
+
+// hammerSettings is the collection of Red Hat Satellite connection settings
+// provided by a Hammer CLI configuration file.
+type hammerSettings struct {
+	// Host is the URL of the Red Hat Satellite server (e.g.,
+	// https://rsat.example.com/).
+	Host string
+
+	// Username is the user used to authenticate to the server.
+	Username string
+
+	// Password is the password for the specified user.
+	Password string
+}
+
+// applyHammerConfig applies the server, port, username and password settings
+// from the user-specified Hammer CLI configuration file (if any) for any of
+// these settings not already specified via command-line, environment
+// variable, configuration file or password file/prompt. The server is not
+// applied if a list of servers was specified for batch evaluation and the
+// password is not applied if a different user was specified.
+func (c *Config) applyHammerConfig() error {
+	if c.HammerConfig == "" {
+		return nil
+	}
+
+	path := c.HammerConfig
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to resolve Hammer CLI configuration file path: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	fh, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to open Hammer CLI configuration file: %w", err)
+	}
+	defer func() { _ = fh.Close() }()
+
+	settings, err := parseHammerConfig(fh)
+	if err != nil {
+		return fmt.Errorf("failed to parse Hammer CLI configuration file %s: %w", path, err)
+	}
+
+	specified := c.specifiedFlags()
+
+	if settings.Host != "" && c.Server == "" && !c.BatchMode() {
+		host, port, err := parseHammerHost(settings.Host)
+		if err != nil {
+			return fmt.Errorf("invalid host in Hammer CLI configuration file %s: %w", path, err)
+		}
+
+		c.Server = host
+		c.ServerList = []string{host}
+
+		if port != 0 && !specified[PortFlagLong] {
+			c.TCPPort = port
+		}
+	}
+
+	// The password is only applied for the user specified by the Hammer CLI
+	// configuration file so that a different user's password is not sent.
+	sameUser := c.Username == "" || c.Username == settings.Username
+
+	if c.Username == "" {
+		c.Username = settings.Username
+	}
+
+	// A token or OAuth signature is sent in place of a password.
+	if sameUser && c.Password == "" && c.Token == "" && !c.UsesOAuth() {
+		c.Password = settings.Password
+	}
+
+	return nil
+}
+
+// parseHammerHost parses the host URL from a Hammer CLI configuration file,
+// returning the server name and TCP port. A zero port is returned if the URL
+// does not specify a port.
+func parseHammerHost(rawURL string) (string, int, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if parsed.Hostname() == "" {
+		return "", 0, fmt.Errorf("%w: missing server name in %q", ErrUnsupportedOption, rawURL)
+	}
+
+	var port int
+	if p := parsed.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: invalid port in %q", ErrUnsupportedOption, rawURL)
+		}
+	}
+
+	return parsed.Hostname(), port, nil
+}
+
+// parseHammerConfig parses the subset of the Hammer CLI configuration file
+// (YAML) format used to provide connection settings:
+//
+//	:foreman:
+//	  :host: 'https://rsat.example.com/'
+//	  :username: 'admin'
+//	  :password: 'example'
+//
+// Settings outside of the foreman section and nested structures are ignored.
+func parseHammerConfig(r io.Reader) (hammerSettings, error) {
+	var settings hammerSettings
+	var inForeman bool
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimPrefix(line, ":"), ":")
+		if !found {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		// YAML requires whitespace before the # character of a comment
+		// following an unquoted value.
+		switch {
+		case strings.HasPrefix(value, "'") || strings.HasPrefix(value, `"`):
+			value = strings.TrimSpace(stripComment(value))
+		case strings.HasPrefix(value, "#"):
+			value = ""
+		default:
+			if before, _, found := strings.Cut(value, " #"); found {
+				value = strings.TrimSpace(before)
+			}
+		}
+
+		// A top-level key starts a new section.
+		if raw[0] != ' ' && raw[0] != '\t' {
+			inForeman = key == hammerForemanSection
+			continue
+		}
+
+		if !inForeman {
+			continue
+		}
+
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return hammerSettings{}, fmt.Errorf(
+					"line %d: invalid quoted value: %w",
+					lineNum,
+					ErrUnsupportedOption,
+				)
+			}
+			value = unquoted
+		}
+
+		switch key {
+		case "host":
+			settings.Host = value
+		case "username":
+			settings.Username = value
+		case "password":
+			settings.Password = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return hammerSettings{}, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseHammerConfig(t *testing.T) {
+	content := `---
+# Hammer CLI settings
+:ui:
+  :per_page: 20
+
+:foreman:
+  :enable_module: true
+  :host: 'https://rsat.example.com:8443/' # comment
+  :username: admin
+  :password: 'it''s#secret'
+
+:other:
+  :username: 'ignored'
+`
+
+	want := hammerSettings{
+		Host:     "https://rsat.example.com:8443/",
+		Username: "admin",
+		Password: "it's#secret",
+	}
+
+	got, err := parseHammerConfig(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("parseHammerConfig() = %#v, want %#v", got, want)
+	}
+
+	host, port, err := parseHammerHost(got.Host)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if host != "rsat.example.com" || port != 8443 {
+		t.Errorf("parseHammerHost(%q) = %q, %d; want %q, %d", got.Host, host, port, "rsat.example.com", 8443)
+	}
+}
+
+func TestApplyHammerConfigServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foreman.yml")
+	content := ":foreman:\n  :host: 'https://rsat.example.com:8443/'\n  :username: admin\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write Hammer CLI configuration file: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		servers        string
+		wantServerList []string
+	}{
+		{name: "single server", wantServerList: []string{"rsat.example.com"}},
+		{name: "batch mode", servers: "servers.txt", wantServerList: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{
+				HammerConfig: path,
+				Servers:      tt.servers,
+				flagSet:      flag.NewFlagSet(tt.name, flag.ContinueOnError),
+			}
+
+			if err := c.applyHammerConfig(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(c.ServerList) != len(tt.wantServerList) {
+				t.Fatalf("got server list %q, want %q", c.ServerList, tt.wantServerList)
+			}
+
+			for i := range tt.wantServerList {
+				if c.ServerList[i] != tt.wantServerList[i] {
+					t.Errorf("got server list %q, want %q", c.ServerList, tt.wantServerList)
+				}
+			}
+
+			var wantServer string
+			if len(tt.wantServerList) > 0 {
+				wantServer = tt.wantServerList[0]
+			}
+
+			if c.Server != wantServer {
+				t.Errorf("got server %q, want %q", c.Server, wantServer)
+			}
+
+			if c.Username != "admin" {
+				t.Errorf("got username %q, want %q", c.Username, "admin")
+			}
+		})
+	}
+}
+
+func TestApplyHammerConfigPassword(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foreman.yml")
+	content := ":foreman:\n  :host: 'https://rsat.example.com/'\n  :username: admin\n  :password: 'hammer-secret'\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write Hammer CLI configuration file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		username     string
+		wantUsername string
+		wantPassword string
+	}{
+		{name: "username not specified", wantUsername: "admin", wantPassword: "hammer-secret"},
+		{name: "same username", username: "admin", wantUsername: "admin", wantPassword: "hammer-secret"},
+		{name: "different username", username: "nagios-ro", wantUsername: "nagios-ro", wantPassword: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{
+				HammerConfig: path,
+				Username:     tt.username,
+				flagSet:      flag.NewFlagSet(tt.name, flag.ContinueOnError),
+			}
+
+			if err := c.applyHammerConfig(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.Username != tt.wantUsername {
+				t.Errorf("got username %q, want %q", c.Username, tt.wantUsername)
+			}
+
+			if c.Password != tt.wantPassword {
+				t.Errorf("got password %q, want %q", c.Password, tt.wantPassword)
+			}
+		})
+	}
+}
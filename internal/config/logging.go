@@ -83,14 +83,32 @@ func (c *Config) setupLogging(appType AppType) error {
 	switch {
 	case appType.Inspector:
 		// CLI app logging uses ConsoleWriter to generate human-friendly,
-		// colorized output to stdout.
-		consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout, NoColor: false}
+		// colorized output to stdout. Log output is sent to stderr instead
+		// if a machine-readable report is written to stdout so that the
+		// report remains parsable (e.g., by jq).
+		logOutput := os.Stdout
+		if c.MachineReadableOutput() && c.OutputFile == "" {
+			logOutput = os.Stderr
+		}
+
+		consoleWriter := zerolog.ConsoleWriter{Out: logOutput, NoColor: c.ColorDisabled()}
 		c.Log = zerolog.New(consoleWriter).With().Timestamp().Logger()
 		// c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
 		// Str("version", Version()).
 		// Str("logging_level", c.LoggingLevel).
 		// Str("app_type", appTypeInspector).
 
+	case appType.CacheDaemon:
+		// Cache daemon logging uses ConsoleWriter to generate
+		// human-friendly, uncolorized output to stderr for collection by
+		// the service manager (e.g., journald).
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}
+		c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
+			Str("version", Version()).
+			Str("logging_level", c.LoggingLevel).
+			Str("app_type", appTypeDaemon).
+			Logger()
+
 	case appType.Plugin:
 		// Plugin logging uses ConsoleWriter to generate human-friendly, (but
 		// for this app type) uncolorized output to stderr. Log output is sent
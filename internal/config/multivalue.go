@@ -0,0 +1,41 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import "strings"
+
+// multiValueStringFlag is a flag.Value used to collect values from a flag
+// which may be specified multiple times. Each flag value may also provide a
+// comma-separated list of values.
+type multiValueStringFlag []string
+
+// String implements the fmt.Stringer interface and satisfies the flag.Value
+// interface.
+func (mvs *multiValueStringFlag) String() string {
+	if mvs == nil {
+		return ""
+	}
+
+	return strings.Join(*mvs, ", ")
+}
+
+// Set satisfies the flag.Value interface by appending the given
+// (comma-separated) values to the collection. Leading and trailing
+// whitespace is removed and empty values are ignored.
+func (mvs *multiValueStringFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		*mvs = append(*mvs, item)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+)
+
+// outputFileExtensionFormats returns a mapping of (lowercase) output file
+// extensions to the Inspector type application output format inferred for
+// each.
+func outputFileExtensionFormats() map[string]string {
+	return map[string]string{
+		".txt":  InspectorOutputFormatSimpleTable,
+		".json": InspectorOutputFormatJSON,
+		".csv":  InspectorOutputFormatCSV,
+		".md":   InspectorOutputFormatMarkdown,
+		".html": InspectorOutputFormatHTML,
+		".htm":  InspectorOutputFormatHTML,
+	}
+}
+
+// isFlagSet indicates whether the flag with the given name was explicitly
+// specified by the user.
+func (c Config) isFlagSet(name string) bool {
+	if c.flagSet == nil {
+		return false
+	}
+
+	var found bool
+	c.flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// inferOutputFormat applies an output format inferred from the extension of
+// the user-specified output file. An explicitly specified output format is
+// always used as-is.
+func (c *Config) inferOutputFormat() {
+	if c.OutputFile == "" || c.isFlagSet(InspectorOutputFormatFlagLong) {
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(c.OutputFile))
+	if format, ok := outputFileExtensionFormats()[ext]; ok {
+		c.InspectorOutputFormat = format
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// loadPasswordFile reads the password from the user-specified password file
+// (if any). Trailing newline characters are removed. If the password file is
+// readable by users other than the owner a warning is recorded for later
+// logging.
+func (c *Config) loadPasswordFile() error {
+	if c.PasswordFile == "" {
+		return nil
+	}
+
+	if c.Password != "" {
+		return fmt.Errorf(
+			"invalid combination of flags; only one of %s or %s flags are permitted: %w",
+			PasswordFlagLong,
+			PasswordFileFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	path := filepath.Clean(c.PasswordFile)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to access password file: %w", err)
+	}
+
+	// Windows does not provide meaningful Unix permission bits.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		c.passwordFileWarning = fmt.Sprintf(
+			"password file %s is accessible by other users (mode %s); restrict permissions (e.g., chmod 0600)",
+			path,
+			info.Mode().Perm(),
+		)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read password file: %w", err)
+	}
+
+	password := strings.TrimRight(string(content), "\r\n")
+	if password == "" {
+		return fmt.Errorf(
+			"%w: password file %s is empty",
+			ErrUnsupportedOption,
+			path,
+		)
+	}
+
+	c.Password = password
+
+	return nil
+}
+
+// readPasswordPrompt prompts for the password for the specified user (if
+// requested). Input is not echoed if read from a terminal; otherwise the
+// first line of standard input is used (e.g., when piped from a password
+// manager).
+func (c *Config) readPasswordPrompt() error {
+	if !c.PasswordPrompt {
+		return nil
+	}
+
+	if c.Password != "" || c.PasswordFile != "" {
+		return fmt.Errorf(
+			"invalid combination of flags; only one of %s, %s or %s flags are permitted: %w",
+			PasswordFlagLong,
+			PasswordFileFlagLong,
+			PasswordPromptFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	// Reject the combination before reading so that server entries are not
+	// consumed as the password.
+	if err := c.validatePasswordPromptStdin(); err != nil {
+		return err
+	}
+
+	prompt := "Password: "
+	if c.Username != "" {
+		prompt = fmt.Sprintf("Password for %s: ", c.Username)
+	}
+
+	password, err := readPassword(os.Stdin, os.Stderr, prompt)
+	if err != nil {
+		return err
+	}
+
+	c.Password = password
+
+	return nil
+}
+
+// readPassword reads a password from the given input. If the input is a
+// terminal the prompt is written to the given output and input is not
+// echoed. Trailing newline characters are removed.
+func readPassword(in *os.File, out io.Writer, prompt string) (string, error) {
+	if restore, err := disableEcho(in.Fd()); err == nil {
+		_, _ = fmt.Fprint(out, prompt)
+
+		defer func() {
+			restore()
+
+			// The newline entered by the user is not echoed.
+			_, _ = fmt.Fprintln(out)
+		}()
+	}
+
+	line, err := readLine(in)
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("%w: empty password provided", ErrUnsupportedOption)
+	}
+
+	return password, nil
+}
+
+// readLine reads a single line (including the trailing newline, if present)
+// from the given input. Input is read one byte at a time so that input
+// following the line is left unread for other consumers.
+func readLine(in io.Reader) (string, error) {
+	var line strings.Builder
+
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			line.WriteByte(buf[0])
+			if buf[0] == '\n' {
+				return line.String(), nil
+			}
+		}
+
+		if err != nil {
+			return line.String(), err
+		}
+	}
+}
+
+// validatePasswordPromptStdin asserts that the password prompt and the list
+// of servers for batch mode are not both read from stdin.
+func (c Config) validatePasswordPromptStdin() error {
+	if c.PasswordPrompt && c.Servers == ServersListStdin {
+		return fmt.Errorf(
+			"invalid combination of flags; %s flag is not supported when reading the %s list from stdin: %w",
+			PasswordPromptFlagLong,
+			ServersFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadPasswordFromPipe(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "p@ss word\n", want: "p@ss word"},
+		{input: "secret\r\nignored\n", want: "secret"},
+		{input: "no-newline", want: "no-newline"},
+		{input: "\n", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("failed to create pipe: %v", err)
+		}
+
+		_, _ = io.WriteString(w, tt.input)
+		_ = w.Close()
+
+		var out strings.Builder
+		got, err := readPassword(r, &out, "Password: ")
+		_ = r.Close()
+
+		switch {
+		case tt.wantErr && err == nil:
+			t.Errorf("readPassword(%q): expected error, got %q", tt.input, got)
+		case !tt.wantErr && err != nil:
+			t.Errorf("readPassword(%q): unexpected error: %v", tt.input, err)
+		case got != tt.want:
+			t.Errorf("readPassword(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+
+		// A prompt is only displayed for terminal input.
+		if out.Len() != 0 {
+			t.Errorf("readPassword(%q): unexpected prompt output %q", tt.input, out.String())
+		}
+	}
+}
+
+// TestReadPasswordLeavesRemainingInput asserts that only the first line of
+// piped input is consumed when reading the password.
+func TestReadPasswordLeavesRemainingInput(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	_, _ = io.WriteString(w, "secret\nrsat1.example.com\nrsat2.example.com\n")
+	_ = w.Close()
+
+	var out strings.Builder
+	if _, err := readPassword(r, &out, "Password: "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remaining, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read remaining input: %v", err)
+	}
+
+	if want := "rsat1.example.com\nrsat2.example.com\n"; string(remaining) != want {
+		t.Errorf("remaining input = %q, want %q", remaining, want)
+	}
+}
+
+// TestPasswordPromptRejectedWithServersFromStdin asserts that the password
+// prompt may not be combined with reading the servers list from stdin.
+func TestPasswordPromptRejectedWithServersFromStdin(t *testing.T) {
+	c := Config{PasswordPrompt: true, Servers: ServersListStdin}
+
+	if err := c.validatePasswordPromptStdin(); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("want error %v, got %v", ErrUnsupportedOption, err)
+	}
+
+	if err := c.readPasswordPrompt(); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("want error %v from password prompt, got %v", ErrUnsupportedOption, err)
+	}
+
+	c.Servers = "servers.txt"
+	if err := c.validatePasswordPromptStdin(); err != nil {
+		t.Errorf("unexpected error for servers list file: %v", err)
+	}
+}
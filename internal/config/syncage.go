@@ -0,0 +1,89 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSyncAge represents a user-specified maximum age for the last sync of a
+// product. The maximum age is expressed either as a multiplier of the sync
+// plan interval (e.g., 2x) or as a fixed duration (e.g., 36h).
+type MaxSyncAge struct {
+	// raw is the maximum age as specified by the user.
+	raw string
+
+	// multiplier is the multiplier applied to the sync plan interval.
+	multiplier float64
+
+	// duration is the fixed maximum age.
+	duration time.Duration
+}
+
+// String implements the fmt.Stringer interface and satisfies the flag.Value
+// interface.
+func (msa *MaxSyncAge) String() string {
+	if msa == nil {
+		return ""
+	}
+
+	return msa.raw
+}
+
+// Set satisfies the flag.Value interface by parsing the given value as
+// either a multiplier of the sync plan interval (e.g., 2x) or a fixed
+// duration (e.g., 36h). An error is returned if the value is invalid.
+func (msa *MaxSyncAge) Set(value string) error {
+	value = strings.TrimSpace(value)
+
+	invalidErr := fmt.Errorf(
+		"%w: invalid maximum sync age %q; expected interval multiplier (e.g., 2x) or duration (e.g., 36h)",
+		ErrUnsupportedOption,
+		value,
+	)
+
+	switch {
+	case strings.HasSuffix(strings.ToLower(value), "x"):
+		multiplier, err := strconv.ParseFloat(value[:len(value)-1], 64)
+		if err != nil || multiplier <= 0 {
+			return invalidErr
+		}
+
+		*msa = MaxSyncAge{raw: value, multiplier: multiplier}
+
+	default:
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return invalidErr
+		}
+
+		*msa = MaxSyncAge{raw: value, duration: duration}
+	}
+
+	return nil
+}
+
+// IsSet indicates whether a maximum sync age was specified.
+func (msa MaxSyncAge) IsSet() bool {
+	return msa.raw != ""
+}
+
+// Multiplier returns the multiplier applied to the sync plan interval. Zero
+// is returned if a fixed duration was specified instead.
+func (msa MaxSyncAge) Multiplier() float64 {
+	return msa.multiplier
+}
+
+// Duration returns the fixed maximum age. Zero is returned if an interval
+// multiplier was specified instead.
+func (msa MaxSyncAge) Duration() time.Duration {
+	return msa.duration
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import "golang.org/x/sys/unix"
+
+// disableEcho disables echo of input for the terminal associated with the
+// given file descriptor. A function which restores the original terminal
+// state is returned. An error is returned if the file descriptor is not
+// associated with a terminal.
+func disableEcho(fd uintptr) (func(), error) {
+	termios, err := unix.IoctlGetTermios(int(fd), unix.TCGETS)
+	if err != nil {
+		return nil, err
+	}
+
+	original := *termios
+	termios.Lflag &^= unix.ECHO
+
+	if err := unix.IoctlSetTermios(int(fd), unix.TCSETS, termios); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = unix.IoctlSetTermios(int(fd), unix.TCSETS, &original) }, nil
+}
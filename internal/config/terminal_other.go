@@ -0,0 +1,18 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package config
+
+import "errors"
+
+// disableEcho is not supported on this platform. Input is read without
+// prompting as if it were not provided via a terminal.
+func disableEcho(_ uintptr) (func(), error) {
+	return nil, errors.New("disabling terminal echo is not supported on this platform")
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import "syscall"
+
+// enableEchoInput is the console mode flag which enables echo of input.
+const enableEchoInput uint32 = 0x0004
+
+// procSetConsoleMode is the Windows API function used to set the console
+// input mode.
+var procSetConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// setConsoleMode sets the input mode of the console associated with the
+// given handle.
+func setConsoleMode(handle syscall.Handle, mode uint32) error {
+	result, _, err := procSetConsoleMode.Call(uintptr(handle), uintptr(mode))
+	if result == 0 {
+		return err
+	}
+
+	return nil
+}
+
+// disableEcho disables echo of input for the console associated with the
+// given handle. A function which restores the original console mode is
+// returned. An error is returned if the handle is not associated with a
+// console.
+func disableEcho(fd uintptr) (func(), error) {
+	handle := syscall.Handle(fd)
+
+	var original uint32
+	if err := syscall.GetConsoleMode(handle, &original); err != nil {
+		return nil, err
+	}
+
+	if err := setConsoleMode(handle, original&^enableEchoInput); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = setConsoleMode(handle, original) }, nil
+}
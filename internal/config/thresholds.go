@@ -0,0 +1,106 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Threshold represents a user-specified WARNING or CRITICAL threshold
+// expressed using the standard Nagios range syntax (e.g., 10, 10:, ~:10,
+// @10:20).
+//
+// See https://nagios-plugins.org/doc/guidelines.html#THRESHOLDFORMAT
+type Threshold struct {
+	// raw is the threshold value as specified by the user.
+	raw string
+
+	// parsed is the range parsed from the user-specified value.
+	parsed *nagios.Range
+}
+
+// NewThreshold parses the given Nagios range syntax value and returns a
+// Threshold. An error is returned if the value is invalid.
+func NewThreshold(value string) (Threshold, error) {
+	var t Threshold
+	if err := t.Set(value); err != nil {
+		return Threshold{}, err
+	}
+
+	return t, nil
+}
+
+// String implements the fmt.Stringer interface and satisfies the flag.Value
+// interface.
+func (t *Threshold) String() string {
+	if t == nil {
+		return ""
+	}
+
+	return t.raw
+}
+
+// Set satisfies the flag.Value interface by parsing the given Nagios range
+// syntax value. An empty value clears the threshold. An error is returned if
+// the value is invalid.
+func (t *Threshold) Set(value string) error {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		*t = Threshold{}
+
+		return nil
+	}
+
+	parsed := nagios.ParseRangeString(value)
+	if parsed == nil {
+		return fmt.Errorf(
+			"%w: invalid threshold %q; expected Nagios range syntax (e.g., 10, 10:, ~:10, @10:20)",
+			ErrUnsupportedOption,
+			value,
+		)
+	}
+
+	t.raw = value
+	t.parsed = parsed
+
+	return nil
+}
+
+// mustThreshold parses the given Nagios range syntax value and returns a
+// Threshold. This function panics if the value is invalid and is intended for
+// use with default values only.
+func mustThreshold(value string) Threshold {
+	t, err := NewThreshold(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// IsSet indicates whether a threshold value was specified.
+func (t Threshold) IsSet() bool {
+	return t.parsed != nil
+}
+
+// Exceeded indicates whether the given value falls outside of the
+// acceptable range defined by the threshold (or inside the range if the
+// range is inverted via the @ prefix). False is always returned if a
+// threshold value was not specified.
+func (t Threshold) Exceeded(value float64) bool {
+	if !t.IsSet() {
+		return false
+	}
+
+	return t.parsed.CheckRange(strconv.FormatFloat(value, 'f', -1, 64))
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestThresholdSet(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantSet bool
+		wantErr bool
+	}{
+		{value: "10", want: "10", wantSet: true},
+		{value: "10:", want: "10:", wantSet: true},
+		{value: "~:10", want: "~:10", wantSet: true},
+		{value: "@10:20", want: "@10:20", wantSet: true},
+		{value: " 5 ", want: "5", wantSet: true},
+		{value: "", want: ""},
+		{value: "abc", wantErr: true},
+		{value: "10:abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		threshold, err := NewThreshold(tt.value)
+
+		switch {
+		case tt.wantErr:
+			if !errors.Is(err, ErrUnsupportedOption) {
+				t.Errorf("NewThreshold(%q) error = %v, want %v", tt.value, err, ErrUnsupportedOption)
+			}
+
+			continue
+
+		case err != nil:
+			t.Errorf("NewThreshold(%q) returned unexpected error: %v", tt.value, err)
+
+			continue
+		}
+
+		if got := threshold.String(); got != tt.want {
+			t.Errorf("NewThreshold(%q).String() = %q, want %q", tt.value, got, tt.want)
+		}
+
+		if got := threshold.IsSet(); got != tt.wantSet {
+			t.Errorf("NewThreshold(%q).IsSet() = %t, want %t", tt.value, got, tt.wantSet)
+		}
+	}
+}
+
+func TestThresholdSetClearsValue(t *testing.T) {
+	threshold := mustThreshold("6")
+
+	if err := threshold.Set(""); err != nil {
+		t.Fatalf("Set(%q) returned unexpected error: %v", "", err)
+	}
+
+	if threshold.IsSet() {
+		t.Errorf("Set(%q) did not clear threshold; got %q", "", threshold.String())
+	}
+
+	if threshold.Exceeded(100) {
+		t.Error("Exceeded() = true for cleared threshold, want false")
+	}
+}
+
+func TestThresholdExceeded(t *testing.T) {
+	tests := []struct {
+		threshold string
+		value     float64
+		want      bool
+	}{
+		// Outside of 0 .. 10.
+		{threshold: "10", value: 0, want: false},
+		{threshold: "10", value: 10, want: false},
+		{threshold: "10", value: 11, want: true},
+		{threshold: "10", value: -1, want: true},
+
+		// Outside of 10 .. infinity.
+		{threshold: "10:", value: 9, want: true},
+		{threshold: "10:", value: 10, want: false},
+		{threshold: "10:", value: 100, want: false},
+
+		// Outside of -infinity .. 10.
+		{threshold: "~:10", value: -100, want: false},
+		{threshold: "~:10", value: 10, want: false},
+		{threshold: "~:10", value: 11, want: true},
+
+		// Inside of 10 .. 20 (inclusive).
+		{threshold: "@10:20", value: 9, want: false},
+		{threshold: "@10:20", value: 10, want: true},
+		{threshold: "@10:20", value: 20, want: true},
+		{threshold: "@10:20", value: 21, want: false},
+
+		// Fractional values.
+		{threshold: "6", value: 6.5, want: true},
+	}
+
+	for _, tt := range tests {
+		threshold := mustThreshold(tt.threshold)
+
+		if got := threshold.Exceeded(tt.value); got != tt.want {
+			t.Errorf("Threshold(%q).Exceeded(%v) = %t, want %t", tt.threshold, tt.value, got, tt.want)
+		}
+	}
+
+	var unset Threshold
+	if unset.Exceeded(100) {
+		t.Error("Exceeded() = true for unset threshold, want false")
+	}
+}
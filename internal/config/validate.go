@@ -9,6 +9,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
 
 	"github.com/atc0005/check-rsat/internal/textutils"
@@ -20,21 +22,88 @@ func (c Config) validate(appType AppType) error {
 
 	// Shared validation
 	switch {
-	case strings.TrimSpace(c.Server) == "":
+	// Batch mode permits specifying the server (and optionally credentials)
+	// for each server entry instead of via flag. Saved API responses are
+	// read without connecting to a server.
+	case strings.TrimSpace(c.Server) == "" && !c.BatchMode() && !c.FixturesMode():
 		return fmt.Errorf(
 			"%w: missing server FQDN or IP Address",
 			ErrUnsupportedOption,
 		)
 
-	case strings.TrimSpace(c.Username) == "":
+	case c.CacheDir != "" && c.CacheSocket != "":
+		return fmt.Errorf(
+			"invalid combination of flags; only one of %s or %s flags are permitted: %w",
+			CacheDirFlagLong,
+			CacheSocketFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case c.CacheDir != "" && c.CacheTTL() <= 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			CacheTTLFlagLong,
+			c.cacheTTL,
+			ErrUnsupportedOption,
+		)
+
+	case c.DryRun && (c.BatchMode() || c.CacheSocket != ""):
+		return fmt.Errorf(
+			"invalid combination of flags; %s flag is incompatible with %s and %s flags: %w",
+			DryRunFlagLong,
+			ServersFlagLong,
+			CacheSocketFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case c.MultiServer() && !appType.Plugin:
+		return fmt.Errorf(
+			"%w: multiple %s flag values are only supported by plugins",
+			ErrUnsupportedOption,
+			ServerFlagLong,
+		)
+
+	case strings.TrimSpace(c.Username) == "" && !c.BatchMode() && !c.FixturesMode():
 		return fmt.Errorf(
 			"%w: missing username",
 			ErrUnsupportedOption,
 		)
 
-	case strings.TrimSpace(c.Password) == "":
+	case c.validatePasswordPromptStdin() != nil:
+		return c.validatePasswordPromptStdin()
+
+	case c.Token != "" && c.Password != "":
+		return fmt.Errorf(
+			"invalid combination of flags; only one of %s (or %s, %s) or %s flags are permitted: %w",
+			PasswordFlagLong,
+			PasswordFileFlagLong,
+			PasswordPromptFlagLong,
+			TokenFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case (c.OAuthConsumerKey == "") != (c.OAuthConsumerSecret == ""):
+		return fmt.Errorf(
+			"invalid combination of flags; %s and %s flags must be specified together: %w",
+			OAuthConsumerKeyFlagLong,
+			OAuthConsumerSecretFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case c.UsesOAuth() && (c.Password != "" || c.Token != ""):
 		return fmt.Errorf(
-			"%w: missing password",
+			"invalid combination of flags; only one of %s (or %s, %s), %s or %s flags are permitted: %w",
+			PasswordFlagLong,
+			PasswordFileFlagLong,
+			PasswordPromptFlagLong,
+			TokenFlagLong,
+			OAuthConsumerKeyFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case strings.TrimSpace(c.Password) == "" && strings.TrimSpace(c.Token) == "" && !c.UsesOAuth() && !c.BatchMode() && !c.FixturesMode():
+		return fmt.Errorf(
+			"%w: missing password, token or OAuth consumer key and secret",
 			ErrUnsupportedOption,
 		)
 
@@ -55,10 +124,116 @@ func (c Config) validate(appType AppType) error {
 			ErrUnsupportedOption,
 		)
 
-	case c.PerPageLimit <= 0:
+	case !c.PerPageAuto() && (c.PerPageLimit() < 1 || c.PerPageLimit() > maxPerPageLimit):
 		return fmt.Errorf(
-			"invalid per-page limit value %d provided: %w",
-			c.PerPageLimit,
+			"invalid per-page limit value %q provided; expected %s or a whole number between 1 and %d: %w",
+			c.perPageLimit,
+			PerPageLimitAuto,
+			maxPerPageLimit,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxConcurrent <= 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			MaxConcurrentFlagLong,
+			c.MaxConcurrent,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxIdleConns < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			MaxIdleConnsFlagLong,
+			c.MaxIdleConns,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxIdleConnsPerHost < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			MaxIdleConnsPerHostFlagLong,
+			c.MaxIdleConnsPerHost,
+			ErrUnsupportedOption,
+		)
+
+	case c.idleConnTimeout < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			IdleConnTimeoutFlagLong,
+			c.idleConnTimeout,
+			ErrUnsupportedOption,
+		)
+
+	case c.dialTimeout < 1:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			DialTimeoutFlagLong,
+			c.dialTimeout,
+			ErrUnsupportedOption,
+		)
+
+	case c.dialKeepAlive < 1:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			DialKeepAliveFlagLong,
+			c.dialKeepAlive,
+			ErrUnsupportedOption,
+		)
+
+	case c.dnsTimeout < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			DNSTimeoutFlagLong,
+			c.dnsTimeout,
+			ErrUnsupportedOption,
+		)
+
+	case c.RetryMaxAttempts < 1:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			RetryMaxAttemptsFlagLong,
+			c.RetryMaxAttempts,
+			ErrUnsupportedOption,
+		)
+
+	case c.retryBaseDelay < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			RetryBaseDelayFlagLong,
+			c.retryBaseDelay,
+			ErrUnsupportedOption,
+		)
+
+	case c.RetryJitter < 0 || c.RetryJitter > 1:
+		return fmt.Errorf(
+			"invalid %s value %v provided; expected value between 0 and 1: %w",
+			RetryJitterFlagLong,
+			c.RetryJitter,
+			ErrUnsupportedOption,
+		)
+
+	case c.TopStuck < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			TopStuckFlagLong,
+			c.TopStuck,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxProblemsShown < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			MaxProblemsShownFlagLong,
+			c.MaxProblemsShown,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxWidth < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided: %w",
+			MaxWidthFlagLong,
+			c.MaxWidth,
 			ErrUnsupportedOption,
 		)
 
@@ -69,14 +244,30 @@ func (c Config) validate(appType AppType) error {
 			ErrUnsupportedOption,
 		)
 
+	case c.MaxIntervalDrift < 0:
+		return fmt.Errorf(
+			"invalid %s value %v provided: %w",
+			MaxIntervalDriftFlagLong,
+			c.MaxIntervalDrift,
+			ErrUnsupportedOption,
+		)
+
 	case c.TrustCert && c.CACertificate != "":
 		return fmt.Errorf(
 			"invalid combination of flags; only one of %s or %s flags are permitted: %w",
-			TrustCertFlagLong,
+			InsecureSkipVerifyFlagLong,
 			CACertificateFlagLong,
 			ErrUnsupportedOption,
 		)
 
+	case (c.ClientCertificate == "") != (c.ClientKey == ""):
+		return fmt.Errorf(
+			"invalid combination of flags; %s and %s flags must be specified together: %w",
+			ClientCertificateFlagLong,
+			ClientKeyFlagLong,
+			ErrUnsupportedOption,
+		)
+
 	case !textutils.InList(c.NetworkType, supportedNetworkTypes(), true):
 		return fmt.Errorf(
 			"%w: invalid network type; got %v, expected one of %v",
@@ -92,9 +283,93 @@ func (c Config) validate(appType AppType) error {
 			c.LoggingLevel,
 			supportedLogLevels(),
 		)
+
+	case c.displayLocationErr() != nil:
+		return fmt.Errorf(
+			"%w: invalid %s value %q: %v",
+			ErrUnsupportedOption,
+			DisplayTimezoneFlagLong,
+			c.DisplayTimezone,
+			c.displayLocationErr(),
+		)
+
+	case !textutils.InList(c.TimeFormat, supportedTimeFormats(), true):
+		return fmt.Errorf(
+			"%w: invalid %s value; got %v, expected one of %v",
+			ErrUnsupportedOption,
+			TimeFormatFlagLong,
+			c.TimeFormat,
+			supportedTimeFormats(),
+		)
+
+	case !textutils.InList(c.SortBy, supportedSortByValues(), true):
+		return fmt.Errorf(
+			"%w: invalid %s value; got %v, expected one of %v",
+			ErrUnsupportedOption,
+			SortByFlagLong,
+			c.SortBy,
+			supportedSortByValues(),
+		)
+
+	case c.SortOrder != "" && !textutils.InList(c.SortOrder, supportedSortOrders(), true):
+		return fmt.Errorf(
+			"%w: invalid %s value; got %v, expected one of %v",
+			ErrUnsupportedOption,
+			SortOrderFlagLong,
+			c.SortOrder,
+			supportedSortOrders(),
+		)
+	}
+
+	if err := validatePatterns(ExcludeProductsFlagLong, c.ExcludeProducts); err != nil {
+		return err
+	}
+
+	if err := validatePatterns(IgnorePlanFlagLong, c.IgnorePlans); err != nil {
+		return err
+	}
+
+	if c.OwnerPattern != "" {
+		re, err := regexp.Compile(c.OwnerPattern)
+		switch {
+		case err != nil:
+			return fmt.Errorf(
+				"invalid %s pattern %q provided: %v: %w",
+				OwnerPatternFlagLong,
+				c.OwnerPattern,
+				err,
+				ErrUnsupportedOption,
+			)
+
+		case re.NumSubexp() < 1:
+			return fmt.Errorf(
+				"invalid %s pattern %q provided; a capture group for the owner value is required: %w",
+				OwnerPatternFlagLong,
+				c.OwnerPattern,
+				ErrUnsupportedOption,
+			)
+		}
 	}
 
 	switch {
+	case appType.CacheDaemon:
+
+		switch {
+		case strings.TrimSpace(c.CacheSocket) == "":
+			return fmt.Errorf(
+				"%w: missing cache daemon socket path",
+				ErrUnsupportedOption,
+			)
+
+		case c.CacheTTL() <= 0:
+			return fmt.Errorf(
+				"invalid %s value %d provided: %w",
+				CacheTTLFlagLong,
+				c.cacheTTL,
+				ErrUnsupportedOption,
+			)
+		}
+
 	case appType.Inspector:
 
 		supportedFormats := supportedInspectorOutputFormats()
@@ -107,12 +382,166 @@ func (c Config) validate(appType AppType) error {
 			)
 		}
 
+		if c.BatchMode() && strings.TrimSpace(c.Server) != "" {
+			return fmt.Errorf(
+				"invalid combination of flags; only one of %s or %s flags are permitted: %w",
+				ServerFlagLong,
+				ServersFlagLong,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if c.BatchMode() && c.CacheSocket != "" {
+			return fmt.Errorf(
+				"invalid combination of flags; only one of %s or %s flags are permitted: %w",
+				CacheSocketFlagLong,
+				ServersFlagLong,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if err := c.validateBatchOutputFormat(); err != nil {
+			return err
+		}
+
+		if c.FixturesMode() && (c.BatchMode() || c.CacheSocket != "" || c.CacheDir != "") {
+			return fmt.Errorf(
+				"invalid combination of flags; %s flag is incompatible with %s, %s and %s flags: %w",
+				FixturesDirFlagLong,
+				ServersFlagLong,
+				CacheSocketFlagLong,
+				CacheDirFlagLong,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if c.FixturesMode() {
+			info, err := os.Stat(c.FixturesDir)
+			switch {
+			case err != nil:
+				return fmt.Errorf(
+					"%w: failed to access %s directory: %v",
+					ErrUnsupportedOption,
+					FixturesDirFlagLong,
+					err,
+				)
+			case !info.IsDir():
+				return fmt.Errorf(
+					"%w: %s value %q is not a directory",
+					ErrUnsupportedOption,
+					FixturesDirFlagLong,
+					c.FixturesDir,
+				)
+			}
+		}
+
+		if c.BatchConcurrency <= 0 {
+			return fmt.Errorf(
+				"invalid batch concurrency value %d provided: %w",
+				c.BatchConcurrency,
+				ErrUnsupportedOption,
+			)
+		}
+
 	case appType.Plugin:
 
-		// Placeholder for future plugin-specific validation.
+		if c.CertVerifyWarn && c.TrustCert {
+			return fmt.Errorf(
+				"invalid combination of flags; only one of %s or %s flags are permitted: %w",
+				InsecureSkipVerifyFlagLong,
+				CertVerifyWarnFlagLong,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if !textutils.InList(c.MaintenanceState, supportedMaintenanceStates(), true) {
+			return fmt.Errorf(
+				"%w: invalid %s value; got %v, expected one of %v",
+				ErrUnsupportedOption,
+				MaintenanceStateFlagLong,
+				c.MaintenanceState,
+				supportedMaintenanceStates(),
+			)
+		}
+
+		if !textutils.InList(c.StuckState, supportedStuckStates(), true) {
+			return fmt.Errorf(
+				"%w: invalid %s value; got %v, expected one of %v",
+				ErrUnsupportedOption,
+				StuckStateFlagLong,
+				c.StuckState,
+				supportedStuckStates(),
+			)
+		}
+
+		if c.NeverScheduledState != "" &&
+			!textutils.InList(c.NeverScheduledState, supportedNeverScheduledStates(), true) {
+			return fmt.Errorf(
+				"%w: invalid %s value; got %v, expected one of %v",
+				ErrUnsupportedOption,
+				NeverScheduledStateFlagLong,
+				c.NeverScheduledState,
+				supportedNeverScheduledStates(),
+			)
+		}
+
+		if !textutils.InList(c.EmptyOrgState, supportedEmptyOrgStates(), true) {
+			return fmt.Errorf(
+				"%w: invalid %s value; got %v, expected one of %v",
+				ErrUnsupportedOption,
+				EmptyOrgStateFlagLong,
+				c.EmptyOrgState,
+				supportedEmptyOrgStates(),
+			)
+		}
+
+		if !textutils.InList(c.ConnectionFailureState, supportedConnectionFailureStates(), true) {
+			return fmt.Errorf(
+				"%w: invalid %s value; got %v, expected one of %v",
+				ErrUnsupportedOption,
+				ConnectionFailureStateFlagLong,
+				c.ConnectionFailureState,
+				supportedConnectionFailureStates(),
+			)
+		}
 
+		if c.MaxOutputBytes < 0 {
+			return fmt.Errorf(
+				"invalid %s value %d provided: %w",
+				MaxOutputBytesFlagLong,
+				c.MaxOutputBytes,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if strings.ContainsAny(c.perfDataLabelPrefix, `='`) {
+			return fmt.Errorf(
+				"%w: invalid %s value %q; equals sign and single quote characters are not permitted",
+				ErrUnsupportedOption,
+				PerfDataLabelPrefixFlagLong,
+				c.perfDataLabelPrefix,
+			)
+		}
 	}
 
 	// Optimist
 	return nil
 }
+
+// validatePatterns asserts that each of the given shell-style glob or
+// regular expression patterns specified via the given flag is valid.
+func validatePatterns(flagName string, patterns []string) error {
+	for _, pattern := range patterns {
+		if err := textutils.ValidatePattern(pattern); err != nil {
+			return fmt.Errorf(
+				"invalid %s pattern %q provided: %v: %w",
+				flagName,
+				pattern,
+				err,
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	return nil
+}
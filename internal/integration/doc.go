@@ -0,0 +1,34 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package integration provides an opt-in end-to-end test suite which runs the
+// applications provided by this project against a live (disposable) Red Hat
+// Satellite, Foreman/Katello or compatible instance.
+//
+// The test suite is excluded from normal builds and is enabled using the
+// integration build tag:
+//
+//	go test -tags integration ./internal/integration/...
+//
+// Connection details for the target instance are provided via the following
+// environment variables. Tests are skipped if a server is not specified.
+//
+//	RSAT_INTEGRATION_SERVER     (required) FQDN or IP Address of the instance
+//	RSAT_INTEGRATION_PORT       (optional) TCP port; defaults to 443
+//	RSAT_INTEGRATION_USERNAME   (required) user with permission to manage organizations
+//	RSAT_INTEGRATION_PASSWORD   (required) password for the user
+//	RSAT_INTEGRATION_CA_CERT    (optional) CA certificate bundle used to validate the certificate chain
+//	RSAT_INTEGRATION_TRUST_CERT (optional) set to true to skip certificate validation
+//
+// Each test seeds uniquely named organizations and sync plans via the API,
+// runs the compiled applications against the instance and asserts on the
+// resulting plugin states, performance data and reports. Seeded organizations
+// are removed when each test completes.
+//
+// WARNING: Do not target a production instance. Seeded organizations and
+// sync plans are visible to other users of the instance while tests run.
+package integration
@@ -0,0 +1,371 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+)
+
+// Environment variables used to specify the target instance.
+const (
+	envServer    string = "RSAT_INTEGRATION_SERVER"
+	envPort      string = "RSAT_INTEGRATION_PORT"
+	envUsername  string = "RSAT_INTEGRATION_USERNAME"
+	envPassword  string = "RSAT_INTEGRATION_PASSWORD"
+	envCACert    string = "RSAT_INTEGRATION_CA_CERT"
+	envTrustCert string = "RSAT_INTEGRATION_TRUST_CERT"
+)
+
+// seedTimeout is the timeout applied to each API request used to seed or
+// remove test data.
+const seedTimeout time.Duration = 2 * time.Minute
+
+// binaries is the collection of applications built for use by the test
+// suite.
+var binaries = []string{"check_rsat_sync_plans", "lssp"}
+
+// binDir is the directory containing the applications built for use by the
+// test suite.
+var binDir string
+
+// nameSeq provides a sequence number for names of seeded test data.
+var nameSeq atomic.Int64
+
+// instance represents the disposable Red Hat Satellite (or compatible)
+// instance targeted by the test suite.
+type instance struct {
+	server    string
+	port      int
+	username  string
+	password  string
+	caCert    string
+	trustCert bool
+	client    *http.Client
+}
+
+// organization is the subset of fields for a seeded organization.
+type organization struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+// syncPlan is the subset of fields for a seeded sync plan.
+type syncPlan struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	RecurringLogicID int    `json:"foreman_tasks_recurring_logic_id"`
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envServer) == "" {
+		fmt.Printf("%s not set; skipping integration tests\n", envServer)
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "check-rsat-integration-")
+	if err != nil {
+		fmt.Printf("failed to create build directory: %v\n", err)
+		os.Exit(1)
+	}
+	binDir = dir
+
+	for _, name := range binaries {
+		build := exec.Command(
+			"go", "build", "-mod=vendor",
+			"-o", filepath.Join(binDir, name),
+			"./cmd/"+name,
+		)
+		build.Dir = filepath.Join("..", "..")
+
+		if output, err := build.CombinedOutput(); err != nil {
+			fmt.Printf("failed to build %s: %v\n%s\n", name, err, output)
+			_ = os.RemoveAll(binDir)
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(binDir)
+	os.Exit(code)
+}
+
+// targetInstance returns the instance specified via environment variables.
+// The test is skipped if the instance is not fully specified.
+func targetInstance(t *testing.T) instance {
+	t.Helper()
+
+	inst := instance{
+		server:   os.Getenv(envServer),
+		port:     443,
+		username: os.Getenv(envUsername),
+		password: os.Getenv(envPassword),
+		caCert:   os.Getenv(envCACert),
+	}
+
+	if inst.server == "" || inst.username == "" || inst.password == "" {
+		t.Skipf("%s, %s and %s are required", envServer, envUsername, envPassword)
+	}
+
+	if port := os.Getenv(envPort); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			t.Fatalf("invalid %s value %q: %v", envPort, port, err)
+		}
+		inst.port = p
+	}
+
+	if trust := os.Getenv(envTrustCert); trust != "" {
+		b, err := strconv.ParseBool(trust)
+		if err != nil {
+			t.Fatalf("invalid %s value %q: %v", envTrustCert, trust, err)
+		}
+		inst.trustCert = b
+	}
+
+	tlsConfig := &tls.Config{
+		// #nosec G402; explicitly requested for disposable test instances
+		InsecureSkipVerify: inst.trustCert,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if inst.caCert != "" {
+		pem, err := os.ReadFile(filepath.Clean(inst.caCert))
+		if err != nil {
+			t.Fatalf("failed to read CA certificate: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			t.Fatalf("no certificates found in %s", inst.caCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	inst.client = &http.Client{
+		Timeout:   seedTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	return inst
+}
+
+// uniqueName returns a name for seeded test data which is unlikely to
+// collide with existing data or data seeded by concurrent test runs.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf(
+		"check-rsat-it-%s-%d-%d",
+		prefix,
+		time.Now().UnixNano(),
+		nameSeq.Add(1),
+	)
+}
+
+// apiRequest submits a request to the instance API and decodes the JSON
+// response into result (if provided).
+func (inst instance) apiRequest(method string, path string, body any, result any) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	url := fmt.Sprintf("https://%s:%d%s", inst.server, inst.port, path)
+
+	request, err := http.NewRequest(method, url, payload)
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %w", err)
+	}
+
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Content-Type", "application/json;charset=utf-8")
+	request.SetBasicAuth(inst.username, inst.password)
+
+	response, err := inst.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to submit %s request to %s: %w", method, path, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	respBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected status %q for %s request to %s: %s",
+			response.Status,
+			method,
+			path,
+			respBody,
+		)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// createOrg seeds a new organization. The organization (and any sync plans
+// seeded for it) is removed when the test completes.
+func (inst instance) createOrg(t *testing.T) organization {
+	t.Helper()
+
+	name := uniqueName("org")
+
+	var org organization
+	err := inst.apiRequest(
+		http.MethodPost,
+		"/katello/api/organizations",
+		map[string]any{
+			"organization": map[string]any{
+				"name":  name,
+				"label": strings.ReplaceAll(name, "-", "_"),
+			},
+		},
+		&org,
+	)
+	if err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+
+	t.Cleanup(func() {
+		err := inst.apiRequest(
+			http.MethodDelete,
+			fmt.Sprintf("/katello/api/organizations/%d", org.ID),
+			nil,
+			nil,
+		)
+		if err != nil {
+			t.Errorf("failed to remove organization %s: %v", org.Name, err)
+		}
+	})
+
+	return org
+}
+
+// createSyncPlan seeds a new daily sync plan for the given organization
+// starting at the given time.
+func (inst instance) createSyncPlan(t *testing.T, org organization, enabled bool, start time.Time) syncPlan {
+	t.Helper()
+
+	var sp syncPlan
+	err := inst.apiRequest(
+		http.MethodPost,
+		fmt.Sprintf("/katello/api/organizations/%d/sync_plans", org.ID),
+		map[string]any{
+			"name":      uniqueName("plan"),
+			"interval":  "daily",
+			"sync_date": start.UTC().Format("2006-01-02 15:04:05 -0700"),
+			"enabled":   enabled,
+		},
+		&sp,
+	)
+	if err != nil {
+		t.Fatalf("failed to create sync plan for organization %s: %v", org.Name, err)
+	}
+
+	return sp
+}
+
+// cancelRecurringLogic cancels the recurring logic used to trigger execution
+// of the given sync plan, leaving the sync plan enabled but "stuck".
+func (inst instance) cancelRecurringLogic(t *testing.T, sp syncPlan) {
+	t.Helper()
+
+	err := inst.apiRequest(
+		http.MethodPost,
+		fmt.Sprintf("/foreman_tasks/api/recurring_logics/%d/cancel", sp.RecurringLogicID),
+		nil,
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("failed to cancel recurring logic for sync plan %s: %v", sp.Name, err)
+	}
+}
+
+// run executes the named application against the instance using the given
+// additional arguments and returns the combined output and exit code.
+//
+// Settings from environment variables and configuration files present on
+// the test system are not applied so that results are reproducible.
+func (inst instance) run(t *testing.T, name string, args ...string) (string, int) {
+	t.Helper()
+
+	// An explicitly specified (empty) configuration file prevents use of
+	// any configuration file in the default locations.
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configFile, nil, 0o600); err != nil {
+		t.Fatalf("failed to create configuration file: %v", err)
+	}
+
+	connArgs := []string{
+		"--config", configFile,
+		"--server", inst.server,
+		"--port", strconv.Itoa(inst.port),
+		"--username", inst.username,
+		"--password", inst.password,
+	}
+
+	switch {
+	case inst.trustCert:
+		connArgs = append(connArgs, "--insecure-skip-verify")
+	case inst.caCert != "":
+		connArgs = append(connArgs, "--ca-cert", inst.caCert)
+	}
+
+	cmd := exec.Command(filepath.Join(binDir, name), append(connArgs, args...)...)
+
+	for _, envVar := range os.Environ() {
+		if !strings.HasPrefix(envVar, config.EnvVarPrefix) {
+			cmd.Env = append(cmd.Env, envVar)
+		}
+	}
+
+	output, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return string(output), exitErr.ExitCode()
+	case err != nil:
+		t.Fatalf("failed to run %s: %v", name, err)
+	}
+
+	return string(output), 0
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build integration
+
+package integration
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// assertContains reports an error for each wanted value missing from the
+// application output.
+func assertContains(t *testing.T, output string, want ...string) {
+	t.Helper()
+
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("output does not contain %q\n%s", w, output)
+		}
+	}
+}
+
+func TestPluginHealthySyncPlans(t *testing.T) {
+	inst := targetInstance(t)
+	org := inst.createOrg(t)
+
+	start := time.Now().Add(time.Hour)
+
+	var plans []syncPlan
+	for i := 0; i < 3; i++ {
+		plans = append(plans, inst.createSyncPlan(t, org, true, start))
+	}
+	plans = append(plans, inst.createSyncPlan(t, org, false, start))
+
+	// A page size smaller than the number of sync plans exercises
+	// pagination of API results.
+	output, exitCode := inst.run(
+		t,
+		"check_rsat_sync_plans",
+		"--org", org.Name,
+		"--page-limit", "1",
+	)
+
+	if exitCode != nagios.StateOKExitCode {
+		t.Errorf("got exit code %d, want %d\n%s", exitCode, nagios.StateOKExitCode, output)
+	}
+
+	assertContains(
+		t,
+		output,
+		org.Name,
+		"'organizations'=1",
+		"'sync_plans_total'=4",
+		"'sync_plans_enabled'=3",
+		"'sync_plans_disabled'=1",
+		"'sync_plans_stuck'=0",
+	)
+
+	for _, sp := range plans {
+		assertContains(t, output, sp.Name)
+	}
+}
+
+func TestPluginStuckSyncPlan(t *testing.T) {
+	inst := targetInstance(t)
+	org := inst.createOrg(t)
+
+	start := time.Now().Add(time.Hour)
+
+	stuck := inst.createSyncPlan(t, org, true, start)
+	healthy := inst.createSyncPlan(t, org, true, start)
+
+	inst.cancelRecurringLogic(t, stuck)
+
+	output, exitCode := inst.run(
+		t,
+		"check_rsat_sync_plans",
+		"--org", org.Name,
+		"--check-recurring-logic",
+	)
+
+	if exitCode != nagios.StateWARNINGExitCode && exitCode != nagios.StateCRITICALExitCode {
+		t.Errorf("got exit code %d, want WARNING or CRITICAL\n%s", exitCode, output)
+	}
+
+	assertContains(
+		t,
+		output,
+		stuck.Name,
+		healthy.Name,
+		"'sync_plans_total'=2",
+		"'sync_plans_stuck'=1",
+	)
+
+	// Without recurring logic evaluation the sync plan is not known to be
+	// stuck until the next sync time passes.
+	output, exitCode = inst.run(
+		t,
+		"check_rsat_sync_plans",
+		"--org", org.Name,
+		"--basic",
+	)
+
+	if exitCode != nagios.StateOKExitCode {
+		t.Errorf("got exit code %d, want %d\n%s", exitCode, nagios.StateOKExitCode, output)
+	}
+}
+
+func TestPluginUnknownOrganization(t *testing.T) {
+	inst := targetInstance(t)
+
+	output, exitCode := inst.run(
+		t,
+		"check_rsat_sync_plans",
+		"--org", uniqueName("missing"),
+	)
+
+	if exitCode == nagios.StateOKExitCode {
+		t.Errorf("got exit code %d, want non-OK\n%s", exitCode, output)
+	}
+}
+
+func TestInspectorPagination(t *testing.T) {
+	inst := targetInstance(t)
+	org := inst.createOrg(t)
+
+	const numPlans = 5
+
+	start := time.Now().Add(time.Hour)
+
+	var plans []syncPlan
+	for i := 0; i < numPlans; i++ {
+		plans = append(plans, inst.createSyncPlan(t, org, true, start))
+	}
+
+	for _, pageLimit := range []int{1, 2, numPlans + 1} {
+		output, exitCode := inst.run(
+			t,
+			"lssp",
+			"--org", org.Name,
+			"--page-limit", strconv.Itoa(pageLimit),
+			"--output-format", "simple-table",
+		)
+
+		if exitCode != 0 {
+			t.Errorf("page limit %d: got exit code %d, want 0\n%s", pageLimit, exitCode, output)
+		}
+
+		for _, sp := range plans {
+			if !strings.Contains(output, sp.Name) {
+				t.Errorf("page limit %d: output does not contain sync plan %q\n%s", pageLimit, sp.Name, output)
+			}
+		}
+	}
+}
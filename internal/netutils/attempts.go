@@ -0,0 +1,121 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Known classes of connection attempt errors. These are intended to provide
+// a brief summary of why a connection attempt failed.
+const (
+	ConnectionErrorClassNone        string = "none"
+	ConnectionErrorClassCanceled    string = "canceled"
+	ConnectionErrorClassTimeout     string = "timeout"
+	ConnectionErrorClassRefused     string = "refused"
+	ConnectionErrorClassUnreachable string = "unreachable"
+	ConnectionErrorClassOther       string = "other"
+)
+
+// ConnectionAttempt records the result of an attempt to connect to a
+// specific IP Address.
+type ConnectionAttempt struct {
+	// Err is the error (if any) returned from the connection attempt.
+	Err error
+
+	// Address is the IP Address and port (in host:port format) used for the
+	// connection attempt.
+	Address string
+
+	// Elapsed is the time spent on the connection attempt.
+	Elapsed time.Duration
+}
+
+// ErrorClass provides a brief summary of why the connection attempt failed
+// (e.g., timeout, refused, unreachable).
+func (ca ConnectionAttempt) ErrorClass() string {
+	var netErr net.Error
+
+	switch {
+	case ca.Err == nil:
+		return ConnectionErrorClassNone
+
+	case errors.Is(ca.Err, context.Canceled):
+		return ConnectionErrorClassCanceled
+
+	case errors.Is(ca.Err, context.DeadlineExceeded):
+		return ConnectionErrorClassTimeout
+
+	case errors.Is(ca.Err, syscall.ECONNREFUSED):
+		return ConnectionErrorClassRefused
+
+	case errors.Is(ca.Err, syscall.ENETUNREACH),
+		errors.Is(ca.Err, syscall.EHOSTUNREACH):
+		return ConnectionErrorClassUnreachable
+
+	case errors.As(ca.Err, &netErr) && netErr.Timeout():
+		return ConnectionErrorClassTimeout
+
+	default:
+		return ConnectionErrorClassOther
+	}
+}
+
+// ConnectionError indicates that all attempts to connect to the IP Addresses
+// resolved for a server failed. The result of each attempt is recorded for
+// troubleshooting purposes.
+type ConnectionError struct {
+	// Attempts is the collection of failed connection attempts in the order
+	// they were made.
+	Attempts []ConnectionAttempt
+}
+
+// Error satisfies the error interface.
+func (ce *ConnectionError) Error() string {
+	addrs := make([]string, 0, len(ce.Attempts))
+	for _, attempt := range ce.Attempts {
+		addrs = append(addrs, attempt.Address)
+	}
+
+	var lastErr error
+	if len(ce.Attempts) > 0 {
+		lastErr = ce.Attempts[len(ce.Attempts)-1].Err
+	}
+
+	return fmt.Sprintf(
+		"failed to connect to server using any of %d IP Addresses (%s); last error: %v: %v",
+		len(ce.Attempts),
+		strings.Join(addrs, ", "),
+		lastErr,
+		ErrNetworkConnectionFailed,
+	)
+}
+
+// Unwrap returns the ErrNetworkConnectionFailed sentinel error so that
+// callers may use errors.Is to evaluate connection failures.
+func (ce *ConnectionError) Unwrap() error {
+	return ErrNetworkConnectionFailed
+}
+
+// ConnectionFailure evaluates the given error and indicates whether it is
+// the result of a failure to connect to any IP Address for a server. If so,
+// the results of each connection attempt are also returned for review.
+func ConnectionFailure(err error) ([]ConnectionAttempt, bool) {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return connErr.Attempts, true
+	}
+
+	return nil, false
+}
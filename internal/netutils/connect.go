@@ -17,75 +17,159 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// Default values used to open network connections if not specified.
+const (
+	// DefaultDialTimeout is the default maximum amount of time a connection
+	// attempt to a single IP Address may take.
+	DefaultDialTimeout time.Duration = 2 * time.Second
+
+	// DefaultDialKeepAlive is the default interval between keep-alive probes
+	// for an active network connection.
+	DefaultDialKeepAlive time.Duration = 2 * time.Second
+)
+
+// connectionAttemptDelay is the time to wait for a connection attempt to
+// succeed before starting a connection attempt to the next IP Address when
+// racing connection attempts. This is the delay recommended by RFC 8305.
+const connectionAttemptDelay time.Duration = 250 * time.Millisecond
+
 // HTTPTransportDialContextFunc represents a function that is compatible with
 // the http.Transport DialContext field.
 type HTTPTransportDialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
 
-// DialerWithContext returns a function for use with the http.Transport
-// DialContext field. Use of this function allows the caller to override the
-// default "auto" network type selection behavior used by the net.Dial
-// function when opening a network connection to the specified address/port.
-func DialerWithContext(networkType string, logger zerolog.Logger) HTTPTransportDialContextFunc {
+// Connector opens network connections to a specified address/port using a
+// chosen network type (e.g., IPv4-only, IPv6-only or either). Hostnames are
+// resolved to IP Addresses using the Resolver and filtered to the chosen
+// network type before connection attempts are made using the Dialer.
+type Connector struct {
+	// Resolver is used to resolve hostnames to IP Addresses.
+	Resolver Resolver
 
-	// This function is provided with an address value in host:port format.
-	return func(ctx context.Context, network string, address string) (net.Conn, error) {
-		logger = logger.With().
-			Str("address", address).
-			Str("net_type_original", network).
-			Str("net_type_overridden", networkType).
-			Logger()
-
-		logger.Debug().Msg("resolving hostname")
-
-		host, port, splitErr := net.SplitHostPort(address)
-		if splitErr != nil {
-			return nil, fmt.Errorf(
-				"failed to split given pattern %q into host and port pair: %w",
-				address,
-				splitErr,
-			)
-		}
+	// Dialer is used to open a network connection to each resolved IP
+	// Address until a connection attempt is successful.
+	Dialer Dialer
 
-		addrs, resolveErr := resolveIPAddresses(ctx, host, networkType, logger)
-		if resolveErr != nil {
-			return nil, fmt.Errorf(
-				"resolve hostname %s to %s IPs: %w",
-				host,
-				networkTypeToIPTypeStr(networkType),
-				resolveErr,
-			)
-		}
+	// NetworkType is the network type used to filter resolved IP Addresses
+	// and open network connections. This overrides the network type provided
+	// by the caller of the DialContext method.
+	NetworkType string
 
-		conn, connectErr := openConnection(
-			ctx,
-			addrs,
-			port,
-			networkType,
-			logger,
+	// Logger is the logger used by the connector.
+	Logger zerolog.Logger
+}
+
+// NewDialer returns a dialer which limits each connection attempt to the
+// given timeout and uses the given keep-alive interval for active network
+// connections. The default values are used for any value not specified.
+func NewDialer(timeout time.Duration, keepAlive time.Duration) *net.Dialer {
+	if timeout <= 0 {
+		timeout = DefaultDialTimeout
+	}
+
+	if keepAlive <= 0 {
+		keepAlive = DefaultDialKeepAlive
+	}
+
+	// Ensure that dialer has required KeepAlive and Timeout values to
+	// prevent connections from hanging indefinitely.
+	//
+	// TODO: Research & confirm whether this is still true. For now, play
+	// it safe and use the suggested settings to enable reasonable network
+	// timeout behavior.
+	//
+	// https://joshrendek.com/2015/09/using-a-custom-http-dialer-in-go/
+	// https://pkg.go.dev/net#Dialer
+	return &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: keepAlive,
+	}
+}
+
+// NewConnector returns a Connector which uses the default resolver and a
+// dialer with conservative timeout values to open network connections using
+// the given network type.
+func NewConnector(networkType string, logger zerolog.Logger) *Connector {
+	return &Connector{
+		Resolver:    &net.Resolver{},
+		Dialer:      NewDialer(DefaultDialTimeout, DefaultDialKeepAlive),
+		NetworkType: networkType,
+		Logger:      logger,
+	}
+}
+
+// DialContext opens a network connection to the given address (in host:port
+// format) using the network type specified for the Connector. The given
+// network value is recorded for troubleshooting purposes, but is otherwise
+// ignored. This method is compatible with the http.Transport DialContext
+// field.
+func (c *Connector) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	if c.Resolver == nil || c.Dialer == nil {
+		return nil, fmt.Errorf(
+			"required resolver or dialer not provided: %w",
+			ErrMissingValue,
 		)
+	}
 
-		if connectErr != nil {
-			return nil, fmt.Errorf(
-				"failed to create client connection to %s (port %s): %w",
-				host,
-				port,
-				connectErr,
-			)
-		}
+	logger := c.Logger.With().
+		Str("address", address).
+		Str("net_type_original", network).
+		Str("net_type_overridden", c.NetworkType).
+		Logger()
+
+	logger.Debug().Msg("resolving hostname")
+
+	host, port, splitErr := net.SplitHostPort(address)
+	if splitErr != nil {
+		return nil, fmt.Errorf(
+			"failed to split given pattern %q into host and port pair: %w",
+			address,
+			splitErr,
+		)
+	}
+
+	addrs, resolveErr := resolveIPAddresses(ctx, c.Resolver, host, c.NetworkType, logger)
+	if resolveErr != nil {
+		return nil, fmt.Errorf(
+			"resolve hostname %s to %s IPs: %w",
+			host,
+			networkTypeToIPTypeStr(c.NetworkType),
+			resolveErr,
+		)
+	}
 
-		return conn, nil
+	conn, connectErr := openConnection(
+		ctx,
+		c.Dialer,
+		addrs,
+		port,
+		c.NetworkType,
+		logger,
+	)
 
+	if connectErr != nil {
+		return nil, fmt.Errorf(
+			"failed to create client connection to %s (port %s): %w",
+			host,
+			port,
+			connectErr,
+		)
 	}
+
+	return conn, nil
 }
 
-// func DialContext() HTTPTransportDialContextFunc {
-//
-// }
+// DialerWithContext returns a function for use with the http.Transport
+// DialContext field. Use of this function allows the caller to override the
+// default "auto" network type selection behavior used by the net.Dial
+// function when opening a network connection to the specified address/port.
+func DialerWithContext(networkType string, logger zerolog.Logger) HTTPTransportDialContextFunc {
+	return NewConnector(networkType, logger).DialContext
+}
 
 // openConnection receives a list of IP Addresses and returns a net.Conn value
 // for the first successful connection attempt. An error is returned instead
 // if one occurs.
-func openConnection(ctx context.Context, addrs []string, port string, netType string, logger zerolog.Logger) (net.Conn, error) {
+func openConnection(ctx context.Context, dialer Dialer, addrs []string, port string, netType string, logger zerolog.Logger) (net.Conn, error) {
 	if len(addrs) < 1 {
 		logger.Error().Msg("empty list of IP Addresses received")
 
@@ -95,11 +179,25 @@ func openConnection(ctx context.Context, addrs []string, port string, netType st
 		)
 	}
 
+	// Unless sysadmin explicitly requested one of IPv4 or IPv6 network
+	// types we race connection attempts to IPv4 and IPv6 addresses so that
+	// broken connectivity for one address family does not delay connecting
+	// using the other.
+	switch strings.ToLower(netType) {
+	case NetTypeTCP4, NetTypeTCP6:
+	default:
+		if hasMixedAddressFamilies(addrs) {
+			return raceConnections(ctx, dialer, addrs, port, logger)
+		}
+	}
+
 	var (
 		c          net.Conn
 		connectErr error
 	)
 
+	attempts := make([]ConnectionAttempt, 0, len(addrs))
+
 	for _, addr := range addrs {
 		logger.Debug().
 			Str("ip_address", addr).
@@ -122,27 +220,23 @@ func openConnection(ctx context.Context, addrs []string, port string, netType st
 			netType = NetTypeTCPAuto
 		}
 
-		// Ensure that dialer has required KeepAlive and Timeout values to
-		// prevent connections from hanging indefinitely.
-		//
-		// TODO: Research & confirm whether this is still true. For now, play
-		// it safe and use the suggested settings to enable reasonable network
-		// timeout behavior.
-		//
-		// https://joshrendek.com/2015/09/using-a-custom-http-dialer-in-go/
-		// https://pkg.go.dev/net#Dialer
-		dialer := &net.Dialer{
-			Timeout:   2 * time.Second,
-			KeepAlive: 2 * time.Second,
-		}
-
 		// Attempt to connect to the given IP Address.
-		c, connectErr = dialer.Dial(netType, s)
+		attemptStart := time.Now()
+		c, connectErr = dialer.DialContext(ctx, netType, s)
 
 		if connectErr != nil {
+			attempt := ConnectionAttempt{
+				Address: s,
+				Err:     connectErr,
+				Elapsed: time.Since(attemptStart),
+			}
+			attempts = append(attempts, attempt)
+
 			logger.Debug().
 				Err(connectErr).
 				Str("ip_address", addr).
+				Str("error_class", attempt.ErrorClass()).
+				Str("elapsed", attempt.Elapsed.String()).
 				Msg("error connecting to server")
 
 			continue
@@ -158,26 +252,197 @@ func openConnection(ctx context.Context, addrs []string, port string, netType st
 		return c, nil
 	}
 
-	// If all connection attempts failed, report the last connection error.
-	// Log all failed IP Addresses for review.
+	// If all connection attempts failed, report the results of each attempt
+	// along with the last connection error. Log all failed IP Addresses for
+	// review.
 	if connectErr != nil {
-		errMsg := fmt.Sprintf(
-			"failed to connect to server using any of %d IP Addresses (%s)",
-			len(addrs),
-			strings.Join(addrs, ", "),
-		)
 		logger.Debug().
 			Err(connectErr).
 			Str("failed_ip_addresses", strings.Join(addrs, ", ")).
-			Msg(errMsg)
+			Msgf("failed to connect to server using any of %d IP Addresses", len(addrs))
 
-		return nil, fmt.Errorf(
-			"%s; last error: %v: %w",
-			errMsg,
-			connectErr,
-			ErrNetworkConnectionFailed,
-		)
+		return nil, &ConnectionError{Attempts: attempts}
 	}
 
 	return c, nil
 }
+
+// isIPv6 indicates whether the given IP Address is an IPv6 address.
+func isIPv6(addr string) bool {
+	ip := net.ParseIP(addr)
+
+	return ip != nil && ip.To4() == nil
+}
+
+// hasMixedAddressFamilies indicates whether the given IP Addresses include
+// both IPv4 and IPv6 addresses.
+func hasMixedAddressFamilies(addrs []string) bool {
+	var ipv4, ipv6 bool
+	for _, addr := range addrs {
+		switch {
+		case isIPv6(addr):
+			ipv6 = true
+		default:
+			ipv4 = true
+		}
+	}
+
+	return ipv4 && ipv6
+}
+
+// interleaveAddressFamilies orders the given IP Addresses so that IPv4 and
+// IPv6 addresses alternate, starting with the address family of the first
+// IP Address (RFC 8305, section 4). The relative order of addresses within
+// each address family is preserved.
+func interleaveAddressFamilies(addrs []string) []string {
+	if len(addrs) == 0 {
+		return addrs
+	}
+
+	var first, second []string
+	firstIsIPv6 := isIPv6(addrs[0])
+
+	for _, addr := range addrs {
+		switch {
+		case isIPv6(addr) == firstIsIPv6:
+			first = append(first, addr)
+		default:
+			second = append(second, addr)
+		}
+	}
+
+	ordered := make([]string, 0, len(addrs))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			ordered = append(ordered, first[i])
+		}
+		if i < len(second) {
+			ordered = append(ordered, second[i])
+		}
+	}
+
+	return ordered
+}
+
+// raceConnections receives a list of IPv4 and IPv6 addresses and returns a
+// net.Conn value for the first successful connection attempt. Connection
+// attempts alternate between address families and are started in turn
+// without waiting for earlier attempts to fail; the next attempt is started
+// once an earlier attempt fails or after a short delay (RFC 8305, section
+// 5). Remaining connection attempts are abandoned once a connection attempt
+// succeeds. An error is returned instead if all connection attempts fail.
+func raceConnections(ctx context.Context, dialer Dialer, addrs []string, port string, logger zerolog.Logger) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type dialResult struct {
+		conn    net.Conn
+		attempt ConnectionAttempt
+	}
+
+	ordered := interleaveAddressFamilies(addrs)
+
+	// Buffered so that abandoned connection attempts do not block.
+	results := make(chan dialResult, len(ordered))
+
+	var next, pending int
+
+	startAttempt := func() {
+		addr := ordered[next]
+		next++
+		pending++
+
+		logger.Debug().
+			Str("ip_address", addr).
+			Msg("Connecting to server")
+
+		s := net.JoinHostPort(addr, port)
+
+		go func() {
+			attemptStart := time.Now()
+			conn, err := dialer.DialContext(ctx, NetTypeTCPAuto, s)
+
+			results <- dialResult{
+				conn: conn,
+				attempt: ConnectionAttempt{
+					Address: s,
+					Err:     err,
+					Elapsed: time.Since(attemptStart),
+				},
+			}
+		}()
+	}
+
+	attempts := make([]ConnectionAttempt, 0, len(ordered))
+
+	timer := time.NewTimer(connectionAttemptDelay)
+	defer timer.Stop()
+
+	// Discard any pending expiration so that the full delay is applied to
+	// the next attempt.
+	resetTimer := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(connectionAttemptDelay)
+	}
+
+	startAttempt()
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if next < len(ordered) && ctx.Err() == nil {
+				startAttempt()
+				resetTimer()
+			}
+
+		case result := <-results:
+			pending--
+
+			if result.attempt.Err == nil {
+				logger.Debug().
+					Str("address", result.attempt.Address).
+					Str("elapsed", result.attempt.Elapsed.String()).
+					Msg("Connected to server")
+
+				// Close any connections opened by abandoned attempts which
+				// succeeded before being canceled.
+				cancel()
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if late := <-results; late.conn != nil {
+							_ = late.conn.Close()
+						}
+					}
+				}(pending)
+
+				return result.conn, nil
+			}
+
+			attempts = append(attempts, result.attempt)
+
+			logger.Debug().
+				Err(result.attempt.Err).
+				Str("address", result.attempt.Address).
+				Str("error_class", result.attempt.ErrorClass()).
+				Str("elapsed", result.attempt.Elapsed.String()).
+				Msg("error connecting to server")
+
+			// Start the next attempt without waiting for the delay.
+			if next < len(ordered) && ctx.Err() == nil {
+				startAttempt()
+				resetTimer()
+			}
+		}
+	}
+
+	logger.Debug().
+		Str("failed_ip_addresses", strings.Join(ordered, ", ")).
+		Msgf("failed to connect to server using any of %d IP Addresses", len(ordered))
+
+	return nil, &ConnectionError{Attempts: attempts}
+}
@@ -7,4 +7,15 @@
 
 // Package netutils provides types and functions to perform common network
 // operations.
+//
+// The primary component provided by this package is the Connector type. A
+// Connector resolves a hostname to IP Addresses using a Resolver, filters
+// those IP Addresses to the chosen network type (e.g., IPv4-only, IPv6-only
+// or either) and then attempts to connect to each remaining IP Address in
+// turn using a Dialer until a connection is established.
+//
+// The Resolver and Dialer interfaces are satisfied by the net.Resolver and
+// net.Dialer types from the standard library. Alternate implementations may
+// be provided to override name resolution or connection behavior (e.g., for
+// testing purposes).
 package netutils
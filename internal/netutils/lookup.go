@@ -12,11 +12,78 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
-func lookupIPs(ctx context.Context, server string, logger zerolog.Logger) ([]string, error) {
+// defaultDNSPort is the port used to query a DNS server if a port is not
+// specified.
+const defaultDNSPort string = "53"
+
+// timeoutResolver is a Resolver which limits the time spent on each lookup.
+type timeoutResolver struct {
+	resolver Resolver
+	timeout  time.Duration
+}
+
+// LookupHost satisfies the Resolver interface.
+func (tr timeoutResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	return tr.resolver.LookupHost(ctx, host)
+}
+
+// DNSServerAddress returns the given DNS server (IP Address or hostname with
+// optional port) in host:port format. The default DNS port is used if a port
+// is not specified.
+func DNSServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+
+	return net.JoinHostPort(strings.Trim(server, "[]"), defaultDNSPort)
+}
+
+// newDNSServerResolver returns a resolver which sends all queries to the
+// DNS server at the given address (in host:port format) instead of the
+// nameservers configured for the system (e.g., via resolv.conf).
+func newDNSServerResolver(address string) *net.Resolver {
+	var dialer net.Dialer
+
+	return &net.Resolver{
+		// The Go resolver is required in order to override the nameserver.
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// NewResolver returns a Resolver which queries the given DNS server (IP
+// Address or hostname with optional port) instead of the nameservers
+// configured for the system. The system default resolver is used if a DNS
+// server is not specified. If a timeout is specified each lookup is
+// abandoned after the timeout is reached.
+func NewResolver(dnsServer string, timeout time.Duration) Resolver {
+	var resolver Resolver = &net.Resolver{}
+
+	if dnsServer = strings.TrimSpace(dnsServer); dnsServer != "" {
+		resolver = newDNSServerResolver(DNSServerAddress(dnsServer))
+	}
+
+	if timeout > 0 {
+		resolver = timeoutResolver{
+			resolver: resolver,
+			timeout:  timeout,
+		}
+	}
+
+	return resolver
+}
+
+func lookupIPs(ctx context.Context, resolver Resolver, server string, logger zerolog.Logger) ([]string, error) {
 	if err := ctx.Err(); err != nil {
 		logger.Debug().Msg("context has expired")
 
@@ -25,7 +92,6 @@ func lookupIPs(ctx context.Context, server string, logger zerolog.Logger) ([]str
 
 	logger.Debug().Str("host", server).Msg("Performing name resolution")
 
-	resolver := &net.Resolver{}
 	lookupResults, lookupErr := resolver.LookupHost(ctx, server)
 	if lookupErr != nil {
 		logger.Error().
@@ -71,14 +137,14 @@ func lookupIPs(ctx context.Context, server string, logger zerolog.Logger) ([]str
 	return lookupResults, nil
 }
 
-func resolveIPAddresses(ctx context.Context, server string, networkType string, logger zerolog.Logger) ([]string, error) {
+func resolveIPAddresses(ctx context.Context, resolver Resolver, server string, networkType string, logger zerolog.Logger) ([]string, error) {
 	if err := ctx.Err(); err != nil {
 		logger.Debug().Msg("context has expired")
 
 		return nil, fmt.Errorf("failed to resolve IPs: %w", err)
 	}
 
-	lookupResults, lookupErr := lookupIPs(ctx, server, logger)
+	lookupResults, lookupErr := lookupIPs(ctx, resolver, server, logger)
 	if lookupErr != nil {
 		return nil, lookupErr
 	}
@@ -97,3 +163,12 @@ func resolveIPAddresses(ctx context.Context, server string, networkType string,
 
 	return ipStrings, nil
 }
+
+// ResolveServer resolves the given server name to IP Addresses using the
+// given resolver, filtering the results to the given network type (e.g.,
+// IPv4-only). This is the same resolution process used when opening network
+// connections and is intended for diagnostic purposes (e.g., verifying name
+// resolution separately from connectivity).
+func ResolveServer(ctx context.Context, resolver Resolver, server string, networkType string, logger zerolog.Logger) ([]string, error) {
+	return resolveIPAddresses(ctx, resolver, server, networkType, logger)
+}
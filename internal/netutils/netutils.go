@@ -8,7 +8,9 @@
 package netutils
 
 import (
+	"context"
 	"errors"
+	"net"
 )
 
 //
@@ -51,3 +53,15 @@ var (
 	// connection to the specified host.
 	ErrNetworkConnectionFailed = errors.New("failed to establish network connection")
 )
+
+// Resolver is used to resolve a hostname to IP Addresses. This interface is
+// satisfied by the net.Resolver type.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Dialer is used to open a network connection to a given address. This
+// interface is satisfied by the net.Dialer type.
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (net.Conn, error)
+}
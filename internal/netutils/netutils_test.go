@@ -0,0 +1,705 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeResolver is a Resolver which returns predefined lookup results.
+type fakeResolver struct {
+	results map[string][]string
+	err     error
+}
+
+// LookupHost satisfies the Resolver interface.
+func (fr fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if fr.err != nil {
+		return nil, fr.err
+	}
+
+	return fr.results[host], nil
+}
+
+// blockingResolver is a Resolver which blocks until the given context is
+// done.
+type blockingResolver struct{}
+
+// LookupHost satisfies the Resolver interface.
+func (blockingResolver) LookupHost(ctx context.Context, _ string) ([]string, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+// fakeDialer is a Dialer which records connection attempts and only
+// "connects" to the specified reachable addresses.
+type fakeDialer struct {
+	mu        sync.Mutex
+	reachable map[string]bool
+	attempts  []string
+	networks  []string
+}
+
+// DialContext satisfies the Dialer interface.
+func (fd *fakeDialer) DialContext(_ context.Context, network string, address string) (net.Conn, error) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+
+	fd.attempts = append(fd.attempts, address)
+	fd.networks = append(fd.networks, network)
+
+	if !fd.reachable[address] {
+		return nil, errors.New("connection refused")
+	}
+
+	client, server := net.Pipe()
+	_ = server.Close()
+
+	return client, nil
+}
+
+// blackholeDialer is a Dialer which "connects" to the specified reachable
+// addresses and blocks connection attempts to all other addresses until the
+// given context is done (e.g., to emulate broken IPv6 connectivity).
+type blackholeDialer struct {
+	fakeDialer
+}
+
+// DialContext satisfies the Dialer interface.
+func (bd *blackholeDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	conn, err := bd.fakeDialer.DialContext(ctx, network, address)
+	if err != nil {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	return conn, nil
+}
+
+// TestFilterNetIPsToNetworkType asserts that IP Addresses are filtered to
+// the chosen network type and that an error is returned if no IP Addresses
+// remain after filtering.
+func TestFilterNetIPsToNetworkType(t *testing.T) {
+	t.Parallel()
+
+	mixedIPs := []string{"192.0.2.10", "2001:db8::10", "192.0.2.11", "2001:db8::11"}
+
+	tests := []struct {
+		name    string
+		input   []string
+		netType string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "tcp4 keeps only IPv4 addresses",
+			input:   mixedIPs,
+			netType: NetTypeTCP4,
+			want:    []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:    "tcp6 keeps only IPv6 addresses",
+			input:   mixedIPs,
+			netType: NetTypeTCP6,
+			want:    []string{"2001:db8::10", "2001:db8::11"},
+		},
+		{
+			name:    "auto keeps all addresses in original order",
+			input:   mixedIPs,
+			netType: NetTypeTCPAuto,
+			want:    mixedIPs,
+		},
+		{
+			name:    "network type is matched case-insensitively",
+			input:   mixedIPs,
+			netType: "TCP4",
+			want:    []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:    "unknown network type falls back to auto behavior",
+			input:   mixedIPs,
+			netType: "udp",
+			want:    mixedIPs,
+		},
+		{
+			name:    "IPv4-mapped IPv6 address is treated as IPv4",
+			input:   []string{"::ffff:192.0.2.10", "2001:db8::10"},
+			netType: NetTypeTCP4,
+			want:    []string{"192.0.2.10"},
+		},
+		{
+			name:    "tcp4 with only IPv6 addresses fails",
+			input:   []string{"2001:db8::10"},
+			netType: NetTypeTCP4,
+			wantErr: ErrNoIPAddressesForChosenNetworkType,
+		},
+		{
+			name:    "tcp6 with only IPv4 addresses fails",
+			input:   []string{"192.0.2.10"},
+			netType: NetTypeTCP6,
+			wantErr: ErrNoIPAddressesForChosenNetworkType,
+		},
+		{
+			name:    "auto with no addresses fails",
+			input:   []string{},
+			netType: NetTypeTCPAuto,
+			wantErr: ErrNoIPAddressesForChosenNetworkType,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			netIPs := make([]net.IP, 0, len(tt.input))
+			for _, ip := range tt.input {
+				netIPs = append(netIPs, net.ParseIP(ip))
+			}
+
+			got, err := filterNetIPsToNetworkType(netIPs, tt.netType, zerolog.Nop())
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want error %v, got %v", tt.wantErr, err)
+				}
+
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+
+			default:
+				if gotStrs := netIPsToIPStrings(got); !reflect.DeepEqual(gotStrs, tt.want) {
+					t.Errorf("want %v, got %v", tt.want, gotStrs)
+				}
+			}
+		})
+	}
+}
+
+// TestIPStringsToNetIPs asserts that DNS lookup results are converted to
+// net.IP values and that invalid or empty results are rejected.
+func TestIPStringsToNetIPs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   []string
+		wantErr error
+	}{
+		{
+			name:  "valid IPv4 and IPv6 addresses",
+			input: []string{"192.0.2.10", "2001:db8::10"},
+		},
+		{
+			name:    "invalid address",
+			input:   []string{"192.0.2.10", "not-an-ip"},
+			wantErr: ErrIPAddressParsingFailed,
+		},
+		{
+			name:    "empty input",
+			input:   []string{},
+			wantErr: ErrIPAddressParsingFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ipStringsToNetIPs(tt.input, zerolog.Nop())
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want error %v, got %v", tt.wantErr, err)
+				}
+
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+
+			default:
+				if gotStrs := netIPsToIPStrings(got); !reflect.DeepEqual(gotStrs, tt.input) {
+					t.Errorf("want %v, got %v", tt.input, gotStrs)
+				}
+			}
+		})
+	}
+}
+
+// TestResolveIPAddressesUsesProvidedResolver asserts that the given Resolver
+// is used for name resolution and that lookup failures are reported.
+func TestResolveIPAddressesUsesProvidedResolver(t *testing.T) {
+	t.Parallel()
+
+	resolver := fakeResolver{
+		results: map[string][]string{
+			"rsat.example.com":  {"192.0.2.10", "2001:db8::10"},
+			"empty.example.com": {},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		host     string
+		netType  string
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:     "auto returns all resolved addresses",
+			resolver: resolver,
+			host:     "rsat.example.com",
+			netType:  NetTypeTCPAuto,
+			want:     []string{"192.0.2.10", "2001:db8::10"},
+		},
+		{
+			name:     "tcp6 returns only IPv6 addresses",
+			resolver: resolver,
+			host:     "rsat.example.com",
+			netType:  NetTypeTCP6,
+			want:     []string{"2001:db8::10"},
+		},
+		{
+			name:     "no lookup results fails",
+			resolver: resolver,
+			host:     "empty.example.com",
+			netType:  NetTypeTCPAuto,
+			wantErr:  ErrDNSLookupFailed,
+		},
+		{
+			name:     "resolver error fails",
+			resolver: fakeResolver{err: errors.New("no such host")},
+			host:     "rsat.example.com",
+			netType:  NetTypeTCPAuto,
+			wantErr:  ErrDNSLookupFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := resolveIPAddresses(context.Background(), tt.resolver, tt.host, tt.netType, zerolog.Nop())
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want error %v, got %v", tt.wantErr, err)
+				}
+
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+
+			default:
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("want %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestConnectorDialContext asserts that a Connector attempts connections to
+// resolved IP Addresses of the chosen network type in order until a
+// connection attempt succeeds.
+func TestConnectorDialContext(t *testing.T) {
+	t.Parallel()
+
+	resolver := fakeResolver{
+		results: map[string][]string{
+			"rsat.example.com": {"192.0.2.10", "2001:db8::10", "192.0.2.11"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		netType      string
+		reachable    []string
+		wantAttempts []string
+		wantNetwork  string
+		wantErr      error
+	}{
+		{
+			name:         "first reachable address is used",
+			netType:      NetTypeTCPAuto,
+			reachable:    []string{"192.0.2.10:443"},
+			wantAttempts: []string{"192.0.2.10:443"},
+			wantNetwork:  NetTypeTCPAuto,
+		},
+		{
+			name:         "unreachable addresses are skipped",
+			netType:      NetTypeTCPAuto,
+			reachable:    []string{"192.0.2.11:443"},
+			wantAttempts: []string{"192.0.2.10:443", "[2001:db8::10]:443", "192.0.2.11:443"},
+			wantNetwork:  NetTypeTCPAuto,
+		},
+		{
+			name:         "tcp4 skips IPv6 addresses",
+			netType:      NetTypeTCP4,
+			reachable:    []string{"192.0.2.11:443"},
+			wantAttempts: []string{"192.0.2.10:443", "192.0.2.11:443"},
+			wantNetwork:  NetTypeTCP4,
+		},
+		{
+			name:         "tcp6 attempts only IPv6 addresses",
+			netType:      NetTypeTCP6,
+			reachable:    []string{"[2001:db8::10]:443"},
+			wantAttempts: []string{"[2001:db8::10]:443"},
+			wantNetwork:  NetTypeTCP6,
+		},
+		{
+			name:         "all addresses unreachable fails",
+			netType:      NetTypeTCPAuto,
+			reachable:    nil,
+			wantAttempts: []string{"192.0.2.10:443", "[2001:db8::10]:443", "192.0.2.11:443"},
+			wantNetwork:  NetTypeTCPAuto,
+			wantErr:      ErrNetworkConnectionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dialer := &fakeDialer{reachable: make(map[string]bool)}
+			for _, addr := range tt.reachable {
+				dialer.reachable[addr] = true
+			}
+
+			connector := &Connector{
+				Resolver:    resolver,
+				Dialer:      dialer,
+				NetworkType: tt.netType,
+				Logger:      zerolog.Nop(),
+			}
+
+			conn, err := connector.DialContext(context.Background(), NetTypeTCPAuto, "rsat.example.com:443")
+			if conn != nil {
+				_ = conn.Close()
+			}
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("want error %v, got %v", tt.wantErr, err)
+				}
+
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(dialer.attempts, tt.wantAttempts) {
+				t.Errorf("want attempts %v, got %v", tt.wantAttempts, dialer.attempts)
+			}
+
+			for _, network := range dialer.networks {
+				if network != tt.wantNetwork {
+					t.Errorf("want network %q, got %q", tt.wantNetwork, network)
+				}
+			}
+		})
+	}
+}
+
+// TestConnectorDialContextRequiresResolverAndDialer asserts that a Connector
+// without a Resolver or Dialer returns an error instead of panicking.
+func TestConnectorDialContextRequiresResolverAndDialer(t *testing.T) {
+	t.Parallel()
+
+	connector := &Connector{NetworkType: NetTypeTCPAuto, Logger: zerolog.Nop()}
+
+	_, err := connector.DialContext(context.Background(), NetTypeTCPAuto, "rsat.example.com:443")
+	if !errors.Is(err, ErrMissingValue) {
+		t.Fatalf("want error %v, got %v", ErrMissingValue, err)
+	}
+}
+
+// TestConnectorDialContextHonorsCanceledContext asserts that no name
+// resolution or connection attempts are made using an expired context.
+func TestConnectorDialContextHonorsCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	connector := &Connector{
+		Resolver:    fakeResolver{},
+		Dialer:      &fakeDialer{},
+		NetworkType: NetTypeTCPAuto,
+		Logger:      zerolog.Nop(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := connector.DialContext(ctx, NetTypeTCPAuto, "rsat.example.com:443")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want error %v, got %v", context.Canceled, err)
+	}
+}
+
+// TestConnectionAttemptErrorClass asserts that connection attempt errors are
+// summarized using the expected error class.
+func TestConnectionAttemptErrorClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "no error", err: nil, want: ConnectionErrorClassNone},
+		{name: "canceled", err: context.Canceled, want: ConnectionErrorClassCanceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: ConnectionErrorClassTimeout},
+		{
+			name: "refused",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			want: ConnectionErrorClassRefused,
+		},
+		{
+			name: "host unreachable",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.EHOSTUNREACH},
+			want: ConnectionErrorClassUnreachable,
+		},
+		{name: "other", err: errors.New("unexpected failure"), want: ConnectionErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			attempt := ConnectionAttempt{Address: "192.0.2.10:443", Err: tt.err}
+			if got := attempt.ErrorClass(); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestConnectionFailureReportsAllAttempts asserts that the results of all
+// failed connection attempts are available from the returned error.
+func TestConnectionFailureReportsAllAttempts(t *testing.T) {
+	t.Parallel()
+
+	connector := &Connector{
+		Resolver: fakeResolver{
+			results: map[string][]string{
+				"rsat.example.com": {"192.0.2.10", "2001:db8::10"},
+			},
+		},
+		Dialer:      &fakeDialer{},
+		NetworkType: NetTypeTCPAuto,
+		Logger:      zerolog.Nop(),
+	}
+
+	_, err := connector.DialContext(context.Background(), NetTypeTCPAuto, "rsat.example.com:443")
+
+	attempts, ok := ConnectionFailure(err)
+	if !ok {
+		t.Fatalf("want connection failure, got %v", err)
+	}
+
+	want := []string{"192.0.2.10:443", "[2001:db8::10]:443"}
+	got := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		got = append(got, attempt.Address)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want attempts %v, got %v", want, got)
+	}
+}
+
+// TestDNSServerAddress asserts that the default DNS port is applied to DNS
+// servers specified without a port.
+func TestDNSServerAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"192.0.2.53":        "192.0.2.53:53",
+		"192.0.2.53:5353":   "192.0.2.53:5353",
+		"dns.example.com":   "dns.example.com:53",
+		"2001:db8::53":      "[2001:db8::53]:53",
+		"[2001:db8::53]":    "[2001:db8::53]:53",
+		"[2001:db8::53]:54": "[2001:db8::53]:54",
+	}
+
+	for server, want := range tests {
+		if got := DNSServerAddress(server); got != want {
+			t.Errorf("want %q for %q, got %q", want, server, got)
+		}
+	}
+}
+
+// TestNewDNSServerResolverQueriesGivenServer asserts that the resolver
+// connects to the given DNS server instead of the system nameservers.
+func TestNewDNSServerResolverQueriesGivenServer(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for DNS queries: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	resolver := newDNSServerResolver(listener.LocalAddr().String())
+
+	if !resolver.PreferGo || resolver.Dial == nil {
+		t.Fatal("want Go resolver with custom dial function")
+	}
+
+	conn, err := resolver.Dial(context.Background(), "udp", "198.51.100.1:53")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if got, want := conn.RemoteAddr().String(), listener.LocalAddr().String(); got != want {
+		t.Errorf("want connection to %s, got %s", want, got)
+	}
+}
+
+// TestNewResolverAppliesTimeout asserts that lookups are abandoned once the
+// given timeout is reached and that the system default resolver is used if
+// a DNS server and timeout are not specified.
+func TestNewResolverAppliesTimeout(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := NewResolver("", 0).(*net.Resolver); !ok {
+		t.Error("want system default resolver")
+	}
+
+	resolver, ok := NewResolver("", 10*time.Millisecond).(timeoutResolver)
+	if !ok {
+		t.Fatal("want timeout resolver")
+	}
+
+	resolver.resolver = blockingResolver{}
+
+	_, err := resolver.LookupHost(context.Background(), "rsat.example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want error %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+// TestInterleaveAddressFamilies asserts that IPv4 and IPv6 addresses
+// alternate starting with the address family of the first address.
+func TestInterleaveAddressFamilies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		addrs []string
+		want  []string
+	}{
+		{
+			name:  "IPv6 first",
+			addrs: []string{"2001:db8::10", "2001:db8::11", "192.0.2.10", "192.0.2.11"},
+			want:  []string{"2001:db8::10", "192.0.2.10", "2001:db8::11", "192.0.2.11"},
+		},
+		{
+			name:  "IPv4 first with extra IPv4 addresses",
+			addrs: []string{"192.0.2.10", "192.0.2.11", "192.0.2.12", "2001:db8::10"},
+			want:  []string{"192.0.2.10", "2001:db8::10", "192.0.2.11", "192.0.2.12"},
+		},
+		{
+			name:  "single address family",
+			addrs: []string{"192.0.2.10", "192.0.2.11"},
+			want:  []string{"192.0.2.10", "192.0.2.11"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := interleaveAddressFamilies(tt.addrs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestConnectorDialContextRacesAddressFamilies asserts that a connection
+// attempt to an unresponsive IPv6 address does not block connecting using
+// an IPv4 address when the network type is auto.
+func TestConnectorDialContextRacesAddressFamilies(t *testing.T) {
+	t.Parallel()
+
+	dialer := &blackholeDialer{
+		fakeDialer: fakeDialer{
+			reachable: map[string]bool{"192.0.2.10:443": true},
+		},
+	}
+
+	connector := &Connector{
+		Resolver: fakeResolver{
+			results: map[string][]string{
+				"rsat.example.com": {"2001:db8::10", "2001:db8::11", "192.0.2.10"},
+			},
+		},
+		Dialer:      dialer,
+		NetworkType: NetTypeTCPAuto,
+		Logger:      zerolog.Nop(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	conn, err := connector.DialContext(ctx, NetTypeTCPAuto, "rsat.example.com:443")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = conn.Close()
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("want connection within the connection attempt delay, got %v", elapsed)
+	}
+
+	dialer.mu.Lock()
+	defer dialer.mu.Unlock()
+
+	want := []string{"[2001:db8::10]:443", "192.0.2.10:443"}
+	if !reflect.DeepEqual(dialer.attempts, want) {
+		t.Errorf("want attempts %v, got %v", want, dialer.attempts)
+	}
+}
+
+// TestNewDialer asserts that the given timeout and keep-alive values are
+// applied and that the default values are used if not specified.
+func TestNewDialer(t *testing.T) {
+	t.Parallel()
+
+	dialer := NewDialer(30*time.Second, 15*time.Second)
+	if dialer.Timeout != 30*time.Second || dialer.KeepAlive != 15*time.Second {
+		t.Errorf("want timeout 30s and keep-alive 15s, got %v and %v", dialer.Timeout, dialer.KeepAlive)
+	}
+
+	dialer = NewDialer(0, 0)
+	if dialer.Timeout != DefaultDialTimeout || dialer.KeepAlive != DefaultDialKeepAlive {
+		t.Errorf(
+			"want default timeout %v and keep-alive %v, got %v and %v",
+			DefaultDialTimeout,
+			DefaultDialKeepAlive,
+			dialer.Timeout,
+			dialer.KeepAlive,
+		)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// certChainTimeLayout is the time layout used when listing certificate
+// validity dates.
+const certChainTimeLayout string = "2006-01-02 15:04:05 -0700 MST"
+
+// CertChainReport provides a listing of the given certificate chain. This is
+// intended to help sysadmins review a certificate chain presented by a Red
+// Hat Satellite server which could not be (or was not) verified.
+func CertChainReport(certChain []*x509.Certificate) string {
+	var output strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sCERTIFICATE CHAIN%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	if len(certChain) == 0 {
+		_, _ = fmt.Fprintf(
+			&output,
+			"* No certificates available for review%s",
+			nagios.CheckOutputEOL,
+		)
+
+		return output.String()
+	}
+
+	certChainReport(&output, certChain, time.Now())
+
+	return output.String()
+}
+
+// TrustCertChainReport provides a listing of the given certificate chain
+// along with a note that the chain was trusted as-is without verification.
+// This is intended to help sysadmins review what they are trusting and plan
+// a migration to proper CA validation.
+func TrustCertChainReport(certChain []*x509.Certificate) string {
+	var output strings.Builder
+
+	_, _ = fmt.Fprint(&output, CertChainReport(certChain))
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sNOTE: This certificate chain was trusted as-is WITHOUT validation."+
+			" Consider specifying a CA certificate instead.%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	return output.String()
+}
+
+// certChainReport is a helper function that performs the bulk of the
+// certificate chain report output logic.
+func certChainReport(w io.Writer, certChain []*x509.Certificate, now time.Time) {
+	for i, cert := range certChain {
+		if cert == nil {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"* Certificate %d of %d (%s)%s",
+			i+1,
+			len(certChain),
+			certChainPosition(cert, i),
+			nagios.CheckOutputEOL,
+		)
+
+		_, _ = fmt.Fprintf(
+			w,
+			"  * Subject: %s%s",
+			cert.Subject.String(),
+			nagios.CheckOutputEOL,
+		)
+
+		_, _ = fmt.Fprintf(
+			w,
+			"  * Issuer: %s%s",
+			cert.Issuer.String(),
+			nagios.CheckOutputEOL,
+		)
+
+		if sans := certSANs(cert); len(sans) > 0 {
+			_, _ = fmt.Fprintf(
+				w,
+				"  * SANs: %s%s",
+				strings.Join(sans, ", "),
+				nagios.CheckOutputEOL,
+			)
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"  * Valid: %s to %s (%s)%s",
+			cert.NotBefore.Format(certChainTimeLayout),
+			cert.NotAfter.Format(certChainTimeLayout),
+			certValidityStatus(cert, now),
+			nagios.CheckOutputEOL,
+		)
+
+		_, _ = fmt.Fprintf(
+			w,
+			"  * Serial: %s%s",
+			cert.SerialNumber.String(),
+			nagios.CheckOutputEOL,
+		)
+	}
+}
+
+// certChainPosition is a helper function that provides a human readable
+// description of where the given certificate sits within a chain.
+func certChainPosition(cert *x509.Certificate, index int) string {
+	switch {
+	case index == 0:
+		return "leaf"
+	case cert.Subject.String() == cert.Issuer.String():
+		return "root"
+	default:
+		return "intermediate"
+	}
+}
+
+// certSANs is a helper function that collects all Subject Alternate Names for
+// the given certificate.
+func certSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+
+	sans = append(sans, cert.DNSNames...)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return sans
+}
+
+// certValidityStatus is a helper function that provides a brief human
+// readable description of the validity period status for a certificate.
+func certValidityStatus(cert *x509.Certificate, now time.Time) string {
+	switch {
+	case now.Before(cert.NotBefore):
+		return "not yet valid"
+
+	case now.After(cert.NotAfter):
+		return fmt.Sprintf(
+			"EXPIRED %dd ago",
+			int(now.Sub(cert.NotAfter).Hours()/24),
+		)
+
+	default:
+		return fmt.Sprintf(
+			"expires in %dd",
+			int(cert.NotAfter.Sub(now).Hours()/24),
+		)
+	}
+}
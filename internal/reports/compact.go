@@ -0,0 +1,67 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// SyncPlansCompactReport provides a compact listing of Red Hat Satellite
+// organizations with exactly one line per organization and the names of any
+// problem sync plans inline (e.g., "OrgX: 2 stuck [daily-rhel,
+// weekly-epel]"). This is intended for short notification templates (e.g.,
+// SMS) where tables or multi-line listings are not practical.
+//
+// Organizations without problem sync plans are omitted if requested.
+func SyncPlansCompactReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
+	var output strings.Builder
+
+	sortOrgs(orgs, cfg, now)
+
+	for _, org := range orgs {
+		if org.SyncPlans.IsOKState(now) && cfg.OmitOKSyncPlans {
+			continue
+		}
+
+		var stuck, other []string
+		for _, syncPlan := range org.SyncPlans {
+			switch {
+			case syncPlan.IsStuck(now):
+				stuck = append(stuck, syncPlan.Name)
+			case !syncPlan.IsOKState(now):
+				other = append(other, syncPlan.Name)
+			}
+		}
+
+		_, _ = fmt.Fprintf(&output, "%s: %d stuck", org.Name, len(stuck))
+
+		if len(stuck) > 0 {
+			_, _ = fmt.Fprintf(&output, " [%s]", strings.Join(stuck, ", "))
+		}
+
+		if len(other) > 0 {
+			_, _ = fmt.Fprintf(
+				&output,
+				", %d other problems [%s]",
+				len(other),
+				strings.Join(other, ", "),
+			)
+		}
+
+		_, _ = fmt.Fprint(&output, nagios.CheckOutputEOL)
+	}
+
+	return output.String()
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/netutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// ConnectionAttemptsReport provides a listing of the given failed connection
+// attempts. This is intended to help sysadmins diagnose connectivity
+// failures for servers with multiple IP Addresses (e.g., dual-stack or
+// multiple A records) from the alert alone.
+func ConnectionAttemptsReport(attempts []netutils.ConnectionAttempt) string {
+	var output strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sCONNECTION ATTEMPTS%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	if len(attempts) == 0 {
+		_, _ = fmt.Fprintf(
+			&output,
+			"* No connection attempts available for review%s",
+			nagios.CheckOutputEOL,
+		)
+
+		return output.String()
+	}
+
+	for i, attempt := range attempts {
+		_, _ = fmt.Fprintf(
+			&output,
+			"* Attempt %d of %d: %s (%s after %s)%s",
+			i+1,
+			len(attempts),
+			attempt.Address,
+			attempt.ErrorClass(),
+			attempt.Elapsed.Round(time.Millisecond),
+			nagios.CheckOutputEOL,
+		)
+
+		if attempt.Err != nil {
+			_, _ = fmt.Fprintf(
+				&output,
+				"  * Error: %v%s",
+				attempt.Err,
+				nagios.CheckOutputEOL,
+			)
+		}
+	}
+
+	return output.String()
+}
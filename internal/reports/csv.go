@@ -0,0 +1,82 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// Status values used for sync plans in the CSV report.
+const (
+	csvStatusOK      string = "OK"
+	csvStatusProblem string = "PROBLEM"
+)
+
+// csvHeaders is the header row for the CSV report. The columns are fixed
+// (regardless of the sync plans listed) so that imports into spreadsheets
+// and ticketing workflows may rely on them.
+var csvHeaders = []string{
+	"Org Name",
+	"Plan Name",
+	"Enabled",
+	"Interval",
+	"Next Sync",
+	"Days Stuck",
+	"Status",
+}
+
+// SyncPlansCSVReport provides a report of Red Hat Satellite organizations
+// and sync plans in CSV format (one row per sync plan) for import into
+// spreadsheets and ticketing workflows. The next sync time is provided in
+// RFC 3339 format (UTC) and is empty if not scheduled.
+func SyncPlansCSVReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, logger zerolog.Logger) string {
+	var output strings.Builder
+
+	w := csv.NewWriter(&output)
+
+	sortOrgs(orgs, cfg, now)
+
+	records := [][]string{csvHeaders}
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			isOK := syncPlan.IsOKState(now)
+			if isOK && cfg.OmitOKSyncPlans {
+				continue
+			}
+
+			status := csvStatusOK
+			if !isOK {
+				status = csvStatusProblem
+			}
+
+			records = append(records, []string{
+				org.Name,
+				syncPlan.Name,
+				strconv.FormatBool(syncPlan.Enabled),
+				syncPlan.Interval,
+				syncPlan.NextSync.RFC3339(),
+				strconv.Itoa(syncPlan.DaysStuck(now)),
+				status,
+			})
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		logger.Error().Err(err).Msg("Error writing CSV report")
+	}
+
+	return output.String()
+}
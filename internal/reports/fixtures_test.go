@@ -0,0 +1,237 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+)
+
+// fixtureTimes is the collection of time values used by fixture datasets
+// keyed by a stable token. Rendered time values are replaced by these tokens
+// before output is compared against (or written to) golden files.
+type fixtureTimes map[string]time.Time
+
+// newFixtureTimes provides time values relative to the given reference time
+// for use by fixture datasets.
+func newFixtureTimes(now time.Time) fixtureTimes {
+	return fixtureTimes{
+		"<STUCK-3D>":  now.Add(-(3*24*time.Hour + time.Hour)),
+		"<STUCK-12H>": now.Add(-12 * time.Hour),
+		"<FUTURE-6H>": now.Add(6 * time.Hour),
+		"<FUTURE-2D>": now.Add(2 * 24 * time.Hour),
+		"<CREATED>":   now.Add(-90 * 24 * time.Hour),
+	}
+}
+
+// fixtureOrgsWithProblems provides a dataset of organizations with a mix of
+// OK, disabled and stuck sync plans.
+func fixtureOrgsWithProblems(ft fixtureTimes) rsat.Organizations {
+	return rsat.Organizations{
+		{
+			ID:    2,
+			Name:  "Zeta Org",
+			Label: "zeta_org",
+			Title: "Zeta Org",
+			SyncPlans: rsat.SyncPlans{
+				{
+					ID:               20,
+					Name:             "Daily RHEL",
+					Interval:         "daily",
+					Enabled:          true,
+					NextSync:         rsat.SyncTime(ft["<STUCK-3D>"]),
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+				{
+					ID:               21,
+					Name:             "Weekly EPEL",
+					Interval:         "weekly",
+					Enabled:          true,
+					NextSync:         rsat.SyncTime(ft["<FUTURE-2D>"]),
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+			},
+		},
+		{
+			ID:    1,
+			Name:  "Alpha Org",
+			Label: "alpha_org",
+			Title: "Alpha Org",
+			SyncPlans: rsat.SyncPlans{
+				{
+					ID:               10,
+					Name:             "Hourly Tools",
+					Interval:         "hourly",
+					Enabled:          true,
+					NextSync:         rsat.SyncTime(ft["<STUCK-12H>"]),
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+				{
+					ID:               11,
+					Name:             "Legacy Plan",
+					Interval:         "weekly",
+					Enabled:          false,
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+				{
+					ID:               12,
+					Name:             "Daily Satellite",
+					Interval:         "daily",
+					Enabled:          true,
+					NextSync:         rsat.SyncTime(ft["<FUTURE-6H>"]),
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+			},
+		},
+	}
+}
+
+// fixtureOrgsWithOwners provides the dataset of organizations with problem
+// sync plans annotated with owners using both an organization owner and a
+// sync plan naming convention.
+func fixtureOrgsWithOwners(ft fixtureTimes) rsat.Organizations {
+	orgs := fixtureOrgsWithProblems(ft)
+
+	for i := range orgs {
+		if orgs[i].Name == "Zeta Org" {
+			orgs[i].Owner = "platform-team"
+		}
+	}
+
+	orgs.SetSyncPlanOwners(regexp.MustCompile(`^(?P<owner>Legacy) `))
+
+	return orgs
+}
+
+// fixtureOrgsWithProducts provides the dataset of organizations with problem
+// sync plans and products associated with each enabled sync plan. The same
+// product is covered in multiple organizations.
+func fixtureOrgsWithProducts(ft fixtureTimes) rsat.Organizations {
+	orgs := fixtureOrgsWithProblems(ft)
+
+	products := map[string]rsat.Products{
+		"Daily RHEL": {
+			{ID: 200, Name: "Red Hat Enterprise Linux Server", RepositoryCount: 4, SyncState: "Syncing complete.", LastSync: rsat.StandardAPITime(ft["<STUCK-3D>"])},
+			{ID: 201, Name: "Red Hat Satellite Tools", RepositoryCount: 1, SyncState: "Syncing complete.", LastSync: rsat.StandardAPITime(ft["<STUCK-3D>"])},
+		},
+		"Weekly EPEL": {
+			{ID: 210, Name: "EPEL", RepositoryCount: 2, SyncState: "Syncing complete.", LastSync: rsat.StandardAPITime(ft["<STUCK-12H>"])},
+		},
+		"Hourly Tools": {
+			{ID: 100, Name: "Red Hat Satellite Tools", RepositoryCount: 1},
+		},
+		"Daily Satellite": {
+			{ID: 120, Name: "Red Hat Enterprise Linux Server", RepositoryCount: 3, SyncState: "Syncing complete.", LastSync: rsat.StandardAPITime(ft["<STUCK-12H>"])},
+		},
+	}
+
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			orgs[i].SyncPlans[j].Products = products[orgs[i].SyncPlans[j].Name]
+		}
+	}
+
+	return orgs
+}
+
+// fixtureOrgsNoProblems provides a dataset of organizations with only OK or
+// disabled sync plans.
+func fixtureOrgsNoProblems(ft fixtureTimes) rsat.Organizations {
+	return rsat.Organizations{
+		{
+			ID:    1,
+			Name:  "Alpha Org",
+			Label: "alpha_org",
+			Title: "Alpha Org",
+			SyncPlans: rsat.SyncPlans{
+				{
+					ID:               10,
+					Name:             "Daily Satellite",
+					Interval:         "daily",
+					Enabled:          true,
+					NextSync:         rsat.SyncTime(ft["<FUTURE-6H>"]),
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+				{
+					ID:               11,
+					Name:             "Legacy Plan",
+					Interval:         "weekly",
+					Enabled:          false,
+					OriginalSyncDate: rsat.SyncTime(ft["<CREATED>"]),
+				},
+			},
+		},
+		{
+			ID:        3,
+			Name:      "Empty Org",
+			Label:     "empty_org",
+			Title:     "Empty Org",
+			SyncPlans: rsat.SyncPlans{},
+		},
+	}
+}
+
+// fixtureCertChain provides a leaf and root certificate chain with validity
+// periods relative to the given reference time.
+func fixtureCertChain(t *testing.T, now time.Time) []*x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Example Root CA", Organization: []string{"Example"}},
+		NotBefore:             now.Add(-365 * 24 * time.Hour),
+		NotAfter:              now.Add(-10 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(4660),
+		Subject:      pkix.Name{CommonName: "satellite.example.com"},
+		Issuer:       rootTmpl.Subject,
+		NotBefore:    now.Add(-30 * 24 * time.Hour),
+		NotAfter:     now.Add(60 * 24 * time.Hour),
+		DNSNames:     []string{"satellite.example.com", "rsat.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("192.0.2.10")},
+	}
+
+	parse := func(tmpl *x509.Certificate, parent *x509.Certificate) *x509.Certificate {
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, key)
+		if err != nil {
+			t.Fatalf("failed to create certificate: %v", err)
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+
+		return cert
+	}
+
+	return []*x509.Certificate{
+		parse(leafTmpl, rootTmpl),
+		parse(rootTmpl, rootTmpl),
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// htmlReportStyle is the embedded stylesheet used by the HTML report. Styles
+// are embedded so that the report is self-contained and can be served as-is
+// or attached to email.
+const htmlReportStyle string = `body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background-color: #eee; }
+tr.problem td { background-color: #fdd; }
+td.status { font-weight: bold; text-align: center; }
+tr.problem td.status { color: #a00; }
+tr.ok td.status { color: #070; }`
+
+// htmlProblemStateToString is a helper function that formats a given state
+// (problem present, or not) for use in an HTML report as a status indicator.
+func htmlProblemStateToString(problem bool) string {
+	if problem {
+		return "PROBLEM"
+	}
+
+	return "OK"
+}
+
+// htmlRowClass is a helper function that provides the CSS class applied to a
+// table row for the given state (problem present, or not).
+func htmlRowClass(problem bool) string {
+	if problem {
+		return "problem"
+	}
+
+	return "ok"
+}
+
+// htmlCells generates table cells of the given element type (e.g., td) from
+// the given values. Values are escaped.
+func htmlCells(element string, values []string) string {
+	var cells strings.Builder
+
+	for _, value := range values {
+		_, _ = fmt.Fprintf(&cells, "<%s>%s</%s>", element, html.EscapeString(value), element)
+	}
+
+	return cells.String()
+}
+
+// SyncPlansHTMLReport provides a report of Red Hat Satellite organizations
+// and sync plans as a self-contained HTML document suitable for serving from
+// an internal web server or attaching to email. Sync plans in a non-OK state
+// are highlighted.
+func SyncPlansHTMLReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
+	var output strings.Builder
+
+	sortOrgs(orgs, cfg, now)
+
+	_, _ = fmt.Fprint(
+		&output,
+		"<!DOCTYPE html>\n",
+		"<html lang=\"en\">\n",
+		"<head>\n",
+		"<meta charset=\"utf-8\">\n",
+		"<title>Sync Plans Overview</title>\n",
+		"<style>\n", htmlReportStyle, "\n</style>\n",
+		"</head>\n",
+		"<body>\n",
+		"<h1>Sync Plans Overview</h1>\n",
+	)
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"<p>Evaluated at %s: %d organizations, %d sync plans (%d enabled, %d disabled), %d with problems.</p>\n",
+		html.EscapeString(now.UTC().Format(time.RFC3339)),
+		orgs.NumOrgs(),
+		orgs.NumPlans(),
+		orgs.NumPlansEnabled(),
+		orgs.NumPlansDisabled(),
+		orgs.NumProblemPlans(now),
+	)
+
+	syncPlansHTMLReport(&output, cfg, now, orgs)
+
+	addHTMLTopStuckSummary(&output, orgs, cfg, now)
+
+	addHTMLSummary(&output, orgs, cfg, now)
+
+	_, _ = fmt.Fprint(&output, "</body>\n</html>\n")
+
+	return output.String()
+}
+
+// syncPlansHTMLReport is a helper function that performs the bulk of the HTML
+// report output logic.
+func syncPlansHTMLReport(w io.Writer, cfg *config.Config, now time.Time, orgs rsat.Organizations) {
+	showDaysStuck := orgs.NumProblemPlans(now) > 0
+	showOwner := orgs.HasOwners()
+
+	headers := []string{"Org Name", "Plan Name"}
+	if showOwner {
+		headers = append(headers, "Owner")
+	}
+	if showDaysStuck {
+		headers = append(headers, "Days Stuck")
+	}
+	headers = append(headers, "Enabled", "Interval", "Next Sync", "Status")
+
+	_, _ = fmt.Fprint(w, "<table>\n")
+	_, _ = fmt.Fprintf(w, "<thead><tr>%s</tr></thead>\n", htmlCells("th", headers))
+	_, _ = fmt.Fprint(w, "<tbody>\n")
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if syncPlan.IsOKState(now) && cfg.OmitOKSyncPlans {
+				continue
+			}
+
+			problem := !syncPlan.IsOKState(now)
+
+			cells := []string{org.Name, syncPlan.Name}
+			if showOwner {
+				cells = append(cells, syncPlan.Owner)
+			}
+
+			// We evaluate the collection as a whole vs just this specific
+			// sync plan so that we can have consistency across each "row".
+			if showDaysStuck {
+				cells = append(cells, syncPlan.DaysStuckHR(now))
+			}
+
+			cells = append(
+				cells,
+				strconv.FormatBool(syncPlan.Enabled),
+				syncPlan.Interval,
+				formatNextSync(syncPlan.NextSync.String(), syncPlan, cfg, now),
+			)
+
+			_, _ = fmt.Fprintf(
+				w,
+				"<tr class=\"%s\">%s<td class=\"status\">%s</td></tr>\n",
+				htmlRowClass(problem),
+				htmlCells("td", cells),
+				htmlProblemStateToString(problem),
+			)
+		}
+	}
+
+	_, _ = fmt.Fprint(w, "</tbody>\n</table>\n")
+}
+
+// addHTMLTopStuckSummary writes an HTML list of up to the given number of
+// stuck sync plans across all organizations, ordered by the number of days
+// stuck (most first). Nothing is written if the limit is zero or if there are
+// no stuck sync plans.
+func addHTMLTopStuckSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	limit := cfg.TopStuck
+
+	if limit <= 0 {
+		return
+	}
+
+	worst := orgs.WorstStuck(now, limit)
+	if len(worst) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "<h2>Top %d Stuck Sync Plans</h2>\n<ul>\n", limit)
+
+	for _, syncPlan := range worst {
+		_, _ = fmt.Fprintf(
+			w,
+			"<li>%s</li>\n",
+			html.EscapeString(fmt.Sprintf(
+				"%s / %s (Days Stuck: %s, Next Sync: %s)",
+				syncPlan.OrganizationName,
+				syncPlan.Name,
+				syncPlan.DaysStuckHR(now),
+				formatNextSync(syncPlan.NextSyncTime(), syncPlan, cfg, now),
+			)),
+		)
+	}
+
+	_, _ = fmt.Fprint(w, "</ul>\n")
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/check-rsat/internal/schema"
+	"github.com/rs/zerolog"
+)
+
+// jsonReport is the machine-readable (JSON) sync plans report. See the
+// report schema provided by the schema package for the output contract.
+type jsonReport struct {
+	SchemaVersion string                  `json:"schema_version"`
+	Server        string                  `json:"server"`
+	EvaluatedAt   string                  `json:"evaluated_at"`
+	Summary       jsonReportSummary       `json:"summary"`
+	Organizations []jsonReportOrg         `json:"organizations"`
+	TopStuck      rsat.EvaluatedSyncPlans `json:"top_stuck"`
+	Skipped       rsat.SkippedItems       `json:"skipped"`
+}
+
+// jsonReportSummary is the collection of sync plan totals across all
+// organizations in the machine-readable (JSON) sync plans report.
+type jsonReportSummary struct {
+	Organizations int   `json:"organizations"`
+	EmptyOrgs     int   `json:"organizations_without_sync_plans"`
+	SyncPlans     int   `json:"sync_plans"`
+	Enabled       int   `json:"enabled"`
+	Disabled      int   `json:"disabled"`
+	Stuck         int   `json:"stuck"`
+	Problems      int   `json:"problems"`
+	MaxDaysStuck  int   `json:"max_days_stuck"`
+	RetrievalTime int64 `json:"retrieval_time_ms"`
+	IsOK          bool  `json:"is_ok"`
+}
+
+// jsonReportOrg is an organization along with its evaluated sync plans in
+// the machine-readable (JSON) sync plans report.
+type jsonReportOrg struct {
+	ID          int                     `json:"id"`
+	Name        string                  `json:"name"`
+	Label       string                  `json:"label"`
+	Title       string                  `json:"title"`
+	Description rsat.NullString         `json:"description"`
+	Owner       string                  `json:"owner"`
+	NumProblems int                     `json:"problems"`
+	SyncPlans   rsat.EvaluatedSyncPlans `json:"sync_plans"`
+}
+
+// SyncPlansJSONReport provides the full collection of Red Hat Satellite
+// organizations and sync plans (including values derived from evaluating
+// each sync plan at the given reference time) in a machine-readable (JSON)
+// format suitable for processing by tools such as jq. Items intentionally
+// not evaluated and (if requested) the sync plans stuck the longest are
+// included.
+func SyncPlansJSONReport(orgs rsat.Organizations, skipped rsat.SkippedItems, cfg *config.Config, now time.Time, logger zerolog.Logger) string {
+	data, err := json.MarshalIndent(newJSONReport(orgs, skipped, cfg, now), "", "  ")
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to encode sync plans report as JSON")
+
+		return ""
+	}
+
+	return string(data)
+}
+
+// SyncPlansJSONPayload provides the same content as the machine-readable
+// (JSON) sync plans report without indentation for embedding in plugin
+// output as an encoded payload.
+func SyncPlansJSONPayload(orgs rsat.Organizations, skipped rsat.SkippedItems, cfg *config.Config, now time.Time) (json.RawMessage, error) {
+	return json.Marshal(newJSONReport(orgs, skipped, cfg, now))
+}
+
+// newJSONReport is a helper function that assembles the machine-readable
+// (JSON) sync plans report from the given organizations and skipped items.
+func newJSONReport(orgs rsat.Organizations, skipped rsat.SkippedItems, cfg *config.Config, now time.Time) jsonReport {
+	sortOrgs(orgs, cfg, now)
+
+	report := jsonReport{
+		SchemaVersion: schema.Version,
+		Server:        cfg.Server,
+		EvaluatedAt:   now.UTC().Format(time.RFC3339),
+		Summary: jsonReportSummary{
+			Organizations: orgs.NumOrgs(),
+			EmptyOrgs:     orgs.NumOrgsWithoutPlans(),
+			SyncPlans:     orgs.NumPlans(),
+			Enabled:       orgs.NumPlansEnabled(),
+			Disabled:      orgs.NumPlansDisabled(),
+			Stuck:         orgs.NumPlansStuck(now),
+			Problems:      orgs.NumProblemPlans(now),
+			MaxDaysStuck:  orgs.MaxDaysStuck(now),
+			RetrievalTime: cfg.RetrievalTime.Milliseconds(),
+			IsOK:          orgs.IsOKState(now),
+		},
+		Organizations: make([]jsonReportOrg, 0, len(orgs)),
+		TopStuck:      rsat.EvaluatedSyncPlans{},
+		Skipped:       rsat.SkippedItems{},
+	}
+
+	for _, org := range orgs {
+		report.Organizations = append(report.Organizations, jsonReportOrg{
+			ID:          org.ID,
+			Name:        org.Name,
+			Label:       org.Label,
+			Title:       org.Title,
+			Description: org.Description,
+			Owner:       org.Owner,
+			NumProblems: org.SyncPlans.NumProblemPlans(now),
+			SyncPlans:   org.SyncPlans.Evaluate(now),
+		})
+	}
+
+	if cfg.TopStuck > 0 {
+		report.TopStuck = orgs.WorstStuck(now, cfg.TopStuck).Evaluate(now)
+	}
+
+	if len(skipped) > 0 {
+		report.Skipped = skipped
+	}
+
+	return report
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// markdownCellReplacer escapes or replaces characters in cell values which
+// would otherwise break the structure of a Markdown table.
+var markdownCellReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// markdownCell is a helper function that escapes the given value for use as
+// a Markdown table cell.
+func markdownCell(s string) string {
+	return markdownCellReplacer.Replace(s)
+}
+
+// markdownRow generates a Markdown table row from the given cells. Cell
+// values are expected to already be escaped.
+func markdownRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+// markdownHeaderSeparatorRow generates the delimiter row used between the
+// header and data rows of a Markdown table.
+func markdownHeaderSeparatorRow(numCols int) string {
+	return "|" + strings.Repeat(" --- |", numCols) + "\n"
+}
+
+// markdownProblemStateToString is a helper function that formats a given
+// state (problem present, or not) for use in a Markdown report as a status
+// indicator. Problem states are emphasized so that they stand out when
+// rendered.
+func markdownProblemStateToString(problem bool) string {
+	if problem {
+		return "**PROBLEM**"
+	}
+
+	return "OK"
+}
+
+// SyncPlansMarkdownReport provides a report of Red Hat Satellite
+// organizations and sync plans as a Markdown table suitable for pasting into
+// GitLab/GitHub issues and wiki pages.
+//
+// Each sync plan is listed along with relevant status information.
+func SyncPlansMarkdownReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
+	var output strings.Builder
+
+	_, _ = fmt.Fprint(&output, "## Sync Plans Overview\n\n")
+
+	sortOrgs(orgs, cfg, now)
+
+	syncPlansMarkdownReport(&output, cfg, now, orgs)
+
+	addMarkdownTopStuckSummary(&output, orgs, cfg, now)
+
+	addMarkdownSummary(&output, orgs, cfg, now)
+
+	return output.String()
+}
+
+// syncPlansMarkdownReport is a helper function that performs the bulk of the
+// Markdown report output logic.
+func syncPlansMarkdownReport(w io.Writer, cfg *config.Config, now time.Time, orgs rsat.Organizations) {
+	showDaysStuck := orgs.NumProblemPlans(now) > 0
+	showOwner := orgs.HasOwners()
+
+	headers := []string{"Org Name", "Plan Name"}
+	if showOwner {
+		headers = append(headers, "Owner")
+	}
+	if showDaysStuck {
+		headers = append(headers, "Days Stuck")
+	}
+	headers = append(headers, "Enabled", "Interval", "Next Sync", "Status")
+
+	_, _ = fmt.Fprint(w, markdownRow(headers))
+	_, _ = fmt.Fprint(w, markdownHeaderSeparatorRow(len(headers)))
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if syncPlan.IsOKState(now) && cfg.OmitOKSyncPlans {
+				continue
+			}
+
+			cells := []string{markdownCell(org.Name), markdownCell(syncPlan.Name)}
+			if showOwner {
+				cells = append(cells, markdownCell(syncPlan.Owner))
+			}
+
+			// We evaluate the collection as a whole vs just this specific
+			// sync plan so that we can have consistency across each "row".
+			if showDaysStuck {
+				cells = append(cells, syncPlan.DaysStuckHR(now))
+			}
+
+			cells = append(
+				cells,
+				strconv.FormatBool(syncPlan.Enabled),
+				markdownCell(syncPlan.Interval),
+				formatNextSync(syncPlan.NextSync.String(), syncPlan, cfg, now),
+				markdownProblemStateToString(!syncPlan.IsOKState(now)),
+			)
+
+			_, _ = fmt.Fprint(w, markdownRow(cells))
+		}
+	}
+}
+
+// addMarkdownTopStuckSummary writes a Markdown list of up to the given number
+// of stuck sync plans across all organizations, ordered by the number of days
+// stuck (most first). Nothing is written if the limit is zero or if there are
+// no stuck sync plans.
+func addMarkdownTopStuckSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	limit := cfg.TopStuck
+
+	if limit <= 0 {
+		return
+	}
+
+	worst := orgs.WorstStuck(now, limit)
+	if len(worst) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n### Top %d Stuck Sync Plans\n\n", limit)
+
+	for _, syncPlan := range worst {
+		_, _ = fmt.Fprintf(
+			w,
+			"- %s / %s (Days Stuck: %s, Next Sync: %s)\n",
+			markdownCell(syncPlan.OrganizationName),
+			markdownCell(syncPlan.Name),
+			syncPlan.DaysStuckHR(now),
+			formatNextSync(syncPlan.NextSyncTime(), syncPlan, cfg, now),
+		)
+	}
+}
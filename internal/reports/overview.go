@@ -10,6 +10,7 @@ package reports
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
@@ -20,24 +21,30 @@ import (
 // SyncPlansOverviewReport provides a listing of Red Hat Satellite
 // organizations and the overall (high-level) state of sync plans in each
 // organization. This report is intentionally light on specifics.
-func SyncPlansOverviewReport(orgs rsat.Organizations, _ *config.Config, _ zerolog.Logger) string {
+func SyncPlansOverviewReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
 	var output strings.Builder
 
 	addSyncPlansReportLeadIn(&output)
 
-	orgs.Sort()
+	sortOrgs(orgs, cfg, now)
 
 	for _, org := range orgs {
 		_, _ = fmt.Fprintf(
 			&output,
 			"* %s (%d problems, %d enabled, %d disabled)%s",
 			org.Name,
-			org.SyncPlans.NumStuck(),
+			org.SyncPlans.NumProblemPlans(now),
 			org.SyncPlans.NumEnabled(),
 			org.SyncPlans.NumDisabled(),
 			nagios.CheckOutputEOL,
 		)
 	}
 
+	addProblemPlansByOwnerSummary(&output, orgs, now)
+
+	addTopStuckSummary(&output, orgs, cfg, now)
+
+	addSummary(&output, orgs, cfg, now)
+
 	return output.String()
 }
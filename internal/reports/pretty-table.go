@@ -10,6 +10,7 @@ package reports
 import (
 	"io"
 	"strings"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
@@ -22,97 +23,135 @@ import (
 // "polish" while attempting to remain compatible with modern terminals.
 //
 // Each sync plan is listed along with relevant status information.
-func SyncPlansPrettyTableReport(orgs rsat.Organizations, cfg *config.Config, _ zerolog.Logger) string {
+func SyncPlansPrettyTableReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
 	var output strings.Builder
 
 	addSyncPlansReportLeadIn(&output)
 
-	orgs.Sort()
+	sortOrgs(orgs, cfg, now)
 
-	syncPlansPrettyTableReport(&output, cfg, orgs)
+	limit := newProblemPlansLimit(orgs, cfg, now)
+
+	syncPlansPrettyTableReport(&output, cfg, now, orgs, limit)
+
+	addProblemPlansLimitTrailer(&output, limit, cfg)
+
+	addTopStuckSummary(&output, orgs, cfg, now)
+
+	addSummary(&output, orgs, cfg, now)
 
 	return output.String()
 }
 
 // prettyTableFormatColumnHeader is a helper function to format a given column
-// header for use in a "pretty table" report.
-func prettyTableFormatColumnHeader(s string) string {
+// header for use in a "pretty table" report. The header is emphasized (bold)
+// unless color output is disabled.
+func prettyTableFormatColumnHeader(s string, noColor bool) string {
+	if noColor {
+		return s
+	}
+
 	return "\x1b[1m" + s + "\x1b[0m"
 }
 
-// prettyTableProblemState is a helper function that formats a given state
-// (problem present, or not) for use in a "pretty table" report as a status
-// indicator.
-func prettyTableProblemState(v interface{}) string {
-	if b, ok := v.(bool); ok {
+// prettyTableProblemStateFunc is a helper function that provides a function
+// used to format a given state (problem present, or not) for use in a "pretty
+// table" report as a status indicator. The status indicator is colored unless
+// color output is disabled and uses plain ASCII characters if requested.
+func prettyTableProblemStateFunc(noColor bool, asciiOnly bool) acidtab.FormatAsFunc {
+	indicators := map[bool]string{
+		false: " ✔ ",
+		true:  " ✘ ",
+	}
+
+	if asciiOnly {
+		indicators = map[bool]string{
+			false: " OK ",
+			true:  " !! ",
+		}
+	}
+
+	return func(v interface{}) string {
+		b, ok := v.(bool)
+		if !ok {
+			return "\x00"
+		}
+
+		if noColor {
+			return indicators[b]
+		}
+
 		return map[bool]string{
-			false: "\x1b[32m ✔ \x1b[0m",
-			true:  "\x1b[31m ✘ \x1b[0m",
-		}[b]
+			false: "\x1b[32m",
+			true:  "\x1b[31m",
+		}[b] + indicators[b] + "\x1b[0m"
 	}
-	return "\x00"
 }
 
 // syncPlansPrettyTableReport is a helper function that performs the bulk of
 // the pretty table report output logic.
-func syncPlansPrettyTableReport(w io.Writer, cfg *config.Config, orgs rsat.Organizations) {
-	var t *acidtab.Table
-	switch {
-	case orgs.NumProblemPlans() > 0:
-		t = acidtab.New(
-			prettyTableFormatColumnHeader("Org Name"),
-			prettyTableFormatColumnHeader("Plan Name"),
-			prettyTableFormatColumnHeader("Days Stuck"),
-			prettyTableFormatColumnHeader("Enabled"),
-			prettyTableFormatColumnHeader("Interval"),
-			prettyTableFormatColumnHeader("Next Sync"),
-			prettyTableFormatColumnHeader("Status"),
-		).
-			Close(acidtab.CloseAll).
-			AlignCol(6, acidtab.Center).
-			FormatColFunc(6, prettyTableProblemState)
-
-	default:
-		t = acidtab.New(
-			prettyTableFormatColumnHeader("Org Name"),
-			prettyTableFormatColumnHeader("Plan Name"),
-			prettyTableFormatColumnHeader("Enabled"),
-			prettyTableFormatColumnHeader("Interval"),
-			prettyTableFormatColumnHeader("Next Sync"),
-			prettyTableFormatColumnHeader("Status"),
-		).
-			Close(acidtab.CloseAll).
-			AlignCol(5, acidtab.Center).
-			FormatColFunc(5, prettyTableProblemState)
+func syncPlansPrettyTableReport(w io.Writer, cfg *config.Config, now time.Time, orgs rsat.Organizations, limit problemPlansLimit) {
+	showDaysStuck := orgs.NumProblemPlans(now) > 0
+	showOwner := orgs.HasOwners()
+
+	headers := []string{"Org Name", "Plan Name"}
+	if showOwner {
+		headers = append(headers, "Owner")
 	}
+	if showDaysStuck {
+		headers = append(headers, "Days Stuck")
+	}
+	headers = append(headers, "Enabled", "Interval", "Next Sync", "Status")
+
+	noColor := cfg.ColorDisabled()
+
+	for i := range headers {
+		headers[i] = prettyTableFormatColumnHeader(headers[i], noColor)
+	}
+
+	borders := acidtab.BordersDefault
+	if cfg.ASCIIOnly {
+		borders = acidtab.BordersASCII
+	}
+
+	statusCol := len(headers) - 1
+
+	t := acidtab.New(headers...).
+		Close(acidtab.CloseAll).
+		Borders(borders).
+		AlignCol(statusCol, acidtab.Center).
+		FormatColFunc(statusCol, prettyTableProblemStateFunc(noColor, cfg.ASCIIOnly))
 
 	for i, org := range orgs {
 		for _, syncPlan := range org.SyncPlans {
-			switch {
-			case syncPlan.IsOKState() && cfg.OmitOKSyncPlans:
+			if syncPlan.IsOKState(now) && cfg.OmitOKSyncPlans {
 				continue
+			}
+
+			if limit.hides(org.Name, syncPlan, now) {
+				continue
+			}
 
-			case orgs.NumProblemPlans() > 0:
-				t.Row(
-					org.Name,
-					syncPlan.Name,
-					syncPlan.DaysStuckHR(),
-					syncPlan.Enabled,
-					syncPlan.Interval,
-					syncPlan.NextSync.String(),
-					!syncPlan.IsOKState(),
-				)
-
-			default:
-				t.Row(
-					org.Name,
-					syncPlan.Name,
-					syncPlan.Enabled,
-					syncPlan.Interval,
-					syncPlan.NextSync.String(),
-					!syncPlan.IsOKState(),
-				)
+			row := []interface{}{truncateCell(org.Name, cfg), truncateCell(syncPlan.Name, cfg)}
+			if showOwner {
+				row = append(row, truncateCell(syncPlan.Owner, cfg))
 			}
+
+			// We evaluate the collection as a whole vs just this specific
+			// sync plan so that we can have consistency across each "row".
+			if showDaysStuck {
+				row = append(row, syncPlan.DaysStuckHR(now))
+			}
+
+			row = append(
+				row,
+				syncPlan.Enabled,
+				syncPlan.Interval,
+				formatNextSync(syncPlan.NextSync.String(), syncPlan, cfg, now),
+				!syncPlan.IsOKState(now),
+			)
+
+			t.Row(row...)
 		}
 
 		// Group sync plans visually based on Org.
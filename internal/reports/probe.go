@@ -0,0 +1,92 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+)
+
+// ProbeReport provides a listing of the details collected while verifying
+// connectivity to and authentication with a Red Hat Satellite server. This is
+// intended to help sysadmins confirm that monitoring is able to reach the
+// server before relying on sync plan evaluation results.
+func ProbeReport(result rsat.ProbeResult) string {
+	var output strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sCONNECTIVITY PROBE%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	addresses := "not resolved"
+	if len(result.Addresses) > 0 {
+		addresses = strings.Join(result.Addresses, ", ")
+	}
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"* DNS resolution: %s (%s)%s",
+		addresses,
+		result.ResolveTime.Round(time.Millisecond),
+		nagios.CheckOutputEOL,
+	)
+
+	tlsDetails := "not negotiated"
+	if result.TLSVersion != "" {
+		tlsDetails = fmt.Sprintf("%s, %s, %s", result.TLSVersion, result.CipherSuite, result.Protocol)
+	}
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"* TLS handshake: %s (%d certificates presented)%s",
+		tlsDetails,
+		len(result.PeerCertificates),
+		nagios.CheckOutputEOL,
+	)
+
+	authStatus := "not verified"
+	if result.ServerVersion != "" {
+		authStatus = "verified"
+	}
+
+	if result.Username != "" {
+		authStatus += " for user " + result.Username
+	}
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"* Authentication: %s (%s)%s",
+		authStatus,
+		result.RequestTime.Round(time.Millisecond),
+		nagios.CheckOutputEOL,
+	)
+
+	if result.ServerVersion != "" {
+		version := result.ServerVersion
+		if result.SatelliteVersion != "" {
+			version = fmt.Sprintf("%s (Satellite %s)", result.ServerVersion, result.SatelliteVersion)
+		}
+
+		_, _ = fmt.Fprintf(
+			&output,
+			"* Server version: %s%s",
+			version,
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return output.String()
+}
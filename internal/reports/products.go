@@ -0,0 +1,144 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// productLastSync is a helper function that formats the last sync time of
+// the given product for display as an absolute timestamp, a duration relative
+// to the evaluation reference time (e.g., 3d 4h ago) or both.
+func productLastSync(product rsat.Product, cfg *config.Config, now time.Time) string {
+	lastSync := time.Time(product.LastSync)
+	if lastSync.IsZero() {
+		return "Never"
+	}
+
+	relative := relativeDuration(now.Sub(lastSync)) + " ago"
+	if lastSync.After(now) {
+		relative = "in " + relativeDuration(lastSync.Sub(now))
+	}
+
+	return formatReportTime(product.LastSync.String(), relative, cfg)
+}
+
+// productSyncState is a helper function that formats the sync state of the
+// given product for display.
+func productSyncState(product rsat.Product) string {
+	switch {
+	case product.Excluded:
+		return "excluded"
+	case strings.TrimSpace(product.SyncState) == "":
+		return "N/A"
+	default:
+		return product.SyncState
+	}
+}
+
+// SyncPlansByProductReport provides a report of the products associated with
+// Red Hat Satellite sync plans grouped by product (instead of by
+// organization). Each product is listed along with the organization and sync
+// plan covering it and its last sync state.
+//
+// Products not associated with a sync plan are not listed.
+func SyncPlansByProductReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, logger zerolog.Logger) string {
+	var output strings.Builder
+
+	tw := tabwriter.NewWriter(&output, 4, 4, 4, ' ', 0)
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sSYNC PLANS BY PRODUCT%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	coverages := orgs.ProductCoverages()
+
+	if len(coverages) == 0 {
+		_, _ = fmt.Fprintf(
+			&output,
+			"No products are associated with sync plans.%s",
+			nagios.CheckOutputEOL,
+		)
+
+		addSummary(&output, orgs, cfg, now)
+
+		return output.String()
+	}
+
+	// Add some lead-in spacing to better separate any earlier log messages from
+	// summary output
+	_, _ = fmt.Fprintf(tw, "\n\n")
+
+	headerRow := strings.TrimSuffix(
+		simpleTableRow([]string{
+			"Product",
+			"Org Name",
+			"Plan Name",
+			"Last Sync",
+			"Sync State",
+			"Next Sync",
+			"Status",
+		}),
+		"\n",
+	)
+
+	syncPlansByProductReport(tw, cfg, now, headerRow, coverages)
+
+	_, _ = fmt.Fprintln(tw)
+
+	if err := tw.Flush(); err != nil {
+		logger.Error().Err(err).Msg("Error flushing tabwriter")
+	}
+
+	addSummary(&output, orgs, cfg, now)
+
+	return output.String()
+}
+
+// syncPlansByProductReport is a helper function that performs the bulk of
+// the "by product" report output logic.
+func syncPlansByProductReport(w io.Writer, cfg *config.Config, now time.Time, headerRow string, coverages rsat.ProductCoverages) {
+	_, _ = fmt.Fprintln(w, headerRow)
+	_, _ = fmt.Fprintln(w, simpleTableHeaderSeparatorRow(headerRow, "\t"))
+
+	var lastProduct string
+	for _, coverage := range coverages {
+		if coverage.IsOKState(now) && cfg.OmitOKSyncPlans {
+			continue
+		}
+
+		// Group entries visually based on product.
+		if lastProduct != "" && coverage.Product.Name != lastProduct {
+			_, _ = fmt.Fprint(w, simpleTableDataSeparatorRow(headerRow, "\t"))
+		}
+		lastProduct = coverage.Product.Name
+
+		_, _ = fmt.Fprint(w, simpleTableRow([]string{
+			truncateCell(coverage.Product.Name, cfg),
+			truncateCell(coverage.OrganizationName, cfg),
+			truncateCell(coverage.SyncPlan.Name, cfg),
+			productLastSync(coverage.Product, cfg, now),
+			truncateCell(productSyncState(coverage.Product), cfg),
+			formatNextSync(coverage.SyncPlan.NextSync.String(), coverage.SyncPlan, cfg, now),
+			simpleTableProblemStateToString(!coverage.IsOKState(now)),
+		}))
+	}
+}
@@ -10,7 +10,13 @@ package reports
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/check-rsat/internal/textutils"
 	"github.com/atc0005/go-nagios"
 )
 
@@ -24,3 +30,247 @@ func addSyncPlansReportLeadIn(w io.Writer) {
 	)
 
 }
+
+// addProblemPlansByOwnerSummary writes a summary of the number of sync plans
+// with a non-OK state for each owner. Nothing is written if no owners are
+// recorded for sync plans in the collection or if there are no problem sync
+// plans.
+func addProblemPlansByOwnerSummary(w io.Writer, orgs rsat.Organizations, now time.Time) {
+	if !orgs.HasOwners() || orgs.NumProblemPlans(now) == 0 {
+		return
+	}
+
+	byOwner := orgs.ProblemPlansByOwner(now)
+
+	owners := make([]string, 0, len(byOwner))
+	for owner := range byOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	_, _ = fmt.Fprintf(
+		w,
+		"%sPROBLEM SYNC PLANS BY OWNER%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, owner := range owners {
+		_, _ = fmt.Fprintf(
+			w,
+			"* %s: %d%s",
+			ownerDisplayName(owner),
+			byOwner[owner],
+			nagios.CheckOutputEOL,
+		)
+	}
+}
+
+// addTopStuckSummary writes a listing of up to the given number of stuck sync
+// plans across all organizations, ordered by the number of days stuck (most
+// first). Nothing is written if the limit is zero or if there are no stuck
+// sync plans.
+func addTopStuckSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	limit := cfg.TopStuck
+
+	if limit <= 0 {
+		return
+	}
+
+	worst := orgs.WorstStuck(now, limit)
+	if len(worst) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"%sTOP %d STUCK SYNC PLANS%s%s",
+		nagios.CheckOutputEOL,
+		limit,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, syncPlan := range worst {
+		_, _ = fmt.Fprintf(
+			w,
+			"* %s / %s (Days Stuck: %s, Next Sync: %s)%s",
+			syncPlan.OrganizationName,
+			syncPlan.Name,
+			syncPlan.DaysStuckHR(now),
+			formatNextSync(syncPlan.NextSyncTime(), syncPlan, cfg, now),
+			nagios.CheckOutputEOL,
+		)
+	}
+}
+
+// syncPlanKey uniquely identifies a sync plan across organizations.
+type syncPlanKey struct {
+	orgName string
+	id      int
+}
+
+// problemPlansLimit records which problem sync plans are listed in detail
+// when the user has limited the number of problem sync plans shown.
+type problemPlansLimit struct {
+	// shown is the collection of problem sync plans listed in detail. All
+	// problem sync plans are listed if nil.
+	shown map[syncPlanKey]struct{}
+
+	// numHidden is the number of problem sync plans not listed in detail.
+	numHidden int
+}
+
+// newProblemPlansLimit determines which problem sync plans (the longest stuck
+// first) are listed in detail based on the user-specified limit.
+func newProblemPlansLimit(orgs rsat.Organizations, cfg *config.Config, now time.Time) problemPlansLimit {
+	if cfg.MaxProblemsShown <= 0 {
+		return problemPlansLimit{}
+	}
+
+	worst := orgs.WorstProblemPlans(now, cfg.MaxProblemsShown)
+
+	limit := problemPlansLimit{
+		shown:     make(map[syncPlanKey]struct{}, len(worst)),
+		numHidden: orgs.NumProblemPlans(now) - len(worst),
+	}
+
+	for _, syncPlan := range worst {
+		limit.shown[syncPlanKey{orgName: syncPlan.OrganizationName, id: syncPlan.ID}] = struct{}{}
+	}
+
+	return limit
+}
+
+// hides indicates whether the given sync plan for the specified organization
+// is omitted from the detailed listing. Sync plans in an OK state are never
+// hidden by the limit.
+func (l problemPlansLimit) hides(orgName string, syncPlan rsat.SyncPlan, now time.Time) bool {
+	if l.shown == nil || syncPlan.IsOKState(now) {
+		return false
+	}
+
+	_, ok := l.shown[syncPlanKey{orgName: orgName, id: syncPlan.ID}]
+
+	return !ok
+}
+
+// addProblemPlansLimitTrailer writes a trailer noting the number of problem
+// sync plans omitted from the detailed listing. Nothing is written if no
+// problem sync plans were omitted.
+func addProblemPlansLimitTrailer(w io.Writer, limit problemPlansLimit, cfg *config.Config) {
+	if limit.numHidden <= 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"%sand %d more problem sync plans%s%s",
+		nagios.CheckOutputEOL,
+		limit.numHidden,
+		cfg.Ellipsis(),
+		nagios.CheckOutputEOL,
+	)
+}
+
+// sortOrgs sorts the given organizations and their sync plans using the
+// user-specified sort value and order. Organizations are sorted by name if a
+// sort value is not specified.
+func sortOrgs(orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	sortBy := strings.ToLower(cfg.SortBy)
+
+	switch sortBy {
+	case config.SortByPlanName:
+		orgs.SortByPlanName()
+
+	case config.SortByDaysStuck:
+		orgs.SortByDaysStuck(now)
+
+	case config.SortByNextSync:
+		orgs.SortByNextSync()
+
+	default:
+		orgs.Sort()
+	}
+
+	// Sync plans are listed in retrieval order when sorting by organization
+	// name, so only the organizations are reversed.
+	if cfg.SortReversed() {
+		orgs.Reverse(sortBy != config.SortByOrgName && sortBy != "")
+	}
+}
+
+// truncateCell is a helper function that truncates the given table cell
+// value to the user-specified maximum column width.
+func truncateCell(s string, cfg *config.Config) string {
+	return textutils.Truncate(s, cfg.MaxWidth, cfg.Ellipsis())
+}
+
+// relativeDuration provides a compact, human readable version of the given
+// duration using at most two units (e.g., 3d 4h, 6h 5m, 12m).
+func relativeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return "<1m"
+	}
+}
+
+// formatReportTime combines the given absolute and relative representations
+// of a time value as requested by the user.
+func formatReportTime(absolute string, relative string, cfg *config.Config) string {
+	switch strings.ToLower(cfg.TimeFormat) {
+	case config.TimeFormatRelative:
+		return relative
+	case config.TimeFormatBoth:
+		return absolute + " (" + relative + ")"
+	default:
+		return absolute
+	}
+}
+
+// formatNextSync formats the next sync time of the given sync plan for
+// display as an absolute timestamp, a duration relative to the evaluation
+// reference time (e.g., in 6h, overdue by 3d 4h) or both. The given absolute
+// value is used as-is if the next sync time is not set.
+func formatNextSync(absolute string, syncPlan rsat.SyncPlan, cfg *config.Config, now time.Time) string {
+	nextSync := time.Time(syncPlan.NextSync)
+	if nextSync.IsZero() {
+		return absolute
+	}
+
+	relative := "in " + relativeDuration(nextSync.Sub(now))
+	if !nextSync.After(now) {
+		relative = "overdue by " + relativeDuration(now.Sub(nextSync))
+	}
+
+	return formatReportTime(absolute, relative, cfg)
+}
+
+// ownerDisplayName provides a display friendly version of the given owner
+// value.
+func ownerDisplayName(owner string) string {
+	if owner == "" {
+		return "(unassigned)"
+	}
+
+	return owner
+}
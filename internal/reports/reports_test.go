@@ -0,0 +1,341 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// update indicates whether golden files should be updated with the current
+// report output instead of being compared against it.
+//
+// Usage: go test ./internal/reports -update
+var update = flag.Bool("update", false, "update golden files with current report output")
+
+// reportFunc is the signature shared by sync plan report functions.
+type reportFunc func(rsat.Organizations, *config.Config, time.Time, zerolog.Logger) string
+
+// fixtureEvalTime is the evaluation reference time used when generating
+// reports from fixture datasets.
+var fixtureEvalTime = time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+// normalizeOutput replaces rendered fixture time values with stable tokens
+// so that report output can be compared against golden files regardless of
+// the local time zone used when the tests are run.
+func normalizeOutput(output string, ft fixtureTimes) string {
+	for token, t := range ft {
+		output = strings.ReplaceAll(output, rsat.SyncTime(t).String(), token)
+		output = strings.ReplaceAll(output, rsat.StandardAPITime(t).String(), token)
+	}
+
+	return output
+}
+
+// assertGolden compares the given output against the named golden file. If
+// the update flag is specified the golden file is written instead.
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+
+	goldenFile := filepath.Join("testdata", "golden", name+".golden")
+
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(got), 0o600); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", goldenFile, err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(filepath.Clean(goldenFile))
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (use -update to create): %v", goldenFile, err)
+	}
+
+	if got != string(want) {
+		t.Errorf(
+			"ERROR: report output does not match golden file %s\nwant:\n%s\ngot:\n%s",
+			goldenFile,
+			string(want),
+			got,
+		)
+	}
+}
+
+// TestSyncPlansReportsMatchGoldenFiles asserts that each report format
+// produces the expected output for each fixture dataset.
+func TestSyncPlansReportsMatchGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	reportFormats := map[string]reportFunc{
+		config.InspectorOutputFormatOverview:    SyncPlansOverviewReport,
+		config.InspectorOutputFormatSimpleTable: SyncPlansSimpleTableReport,
+		config.InspectorOutputFormatPrettyTable: SyncPlansPrettyTableReport,
+		config.InspectorOutputFormatVerbose:     SyncPlansVerboseReport,
+		"compact":                               SyncPlansCompactReport,
+		config.InspectorOutputFormatCSV:         SyncPlansCSVReport,
+		config.InspectorOutputFormatMarkdown:    SyncPlansMarkdownReport,
+		config.InspectorOutputFormatHTML:        SyncPlansHTMLReport,
+		config.InspectorOutputFormatJSON: func(orgs rsat.Organizations, cfg *config.Config, now time.Time, logger zerolog.Logger) string {
+			return SyncPlansJSONReport(orgs, nil, cfg, now, logger)
+		},
+	}
+
+	datasets := map[string]func(fixtureTimes) rsat.Organizations{
+		"problems":    fixtureOrgsWithProblems,
+		"no-problems": fixtureOrgsNoProblems,
+		"owners":      fixtureOrgsWithOwners,
+	}
+
+	configs := map[string]*config.Config{
+		"all":     {},
+		"omit-ok": {OmitOKSyncPlans: true},
+		"top":     {TopStuck: 2},
+	}
+
+	logger := zerolog.Nop()
+
+	for formatName, report := range reportFormats {
+		for datasetName, dataset := range datasets {
+			for cfgName, cfg := range configs {
+				formatName, report := formatName, report
+				datasetName, dataset := datasetName, dataset
+				cfgName, cfg := cfgName, cfg
+
+				name := strings.Join([]string{formatName, datasetName, cfgName}, "_")
+
+				t.Run(name, func(t *testing.T) {
+					t.Parallel()
+
+					ft := newFixtureTimes(fixtureEvalTime)
+					got := normalizeOutput(report(dataset(ft), cfg, fixtureEvalTime, logger), ft)
+
+					assertGolden(t, name, got)
+				})
+			}
+		}
+	}
+}
+
+// TestCertChainReportMatchesGoldenFile asserts that the certificate chain
+// report produces the expected output for a fixture chain.
+func TestCertChainReportMatchesGoldenFile(t *testing.T) {
+	t.Parallel()
+
+	var output strings.Builder
+	certChainReport(&output, fixtureCertChain(t, fixtureEvalTime), fixtureEvalTime)
+
+	assertGolden(t, "certchain", output.String())
+}
+
+// TestSyncPlansByProductReportMatchesGoldenFiles asserts that the "by
+// product" report produces the expected output for a fixture dataset with
+// products associated with sync plans.
+func TestSyncPlansByProductReportMatchesGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	configs := map[string]*config.Config{
+		"all":     {},
+		"omit-ok": {OmitOKSyncPlans: true},
+	}
+
+	logger := zerolog.Nop()
+
+	for cfgName, cfg := range configs {
+		cfgName, cfg := cfgName, cfg
+
+		name := strings.Join([]string{config.InspectorOutputFormatByProduct, "products", cfgName}, "_")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ft := newFixtureTimes(fixtureEvalTime)
+			orgs := fixtureOrgsWithProducts(ft)
+			got := normalizeOutput(SyncPlansByProductReport(orgs, cfg, fixtureEvalTime, logger), ft)
+
+			assertGolden(t, name, got)
+		})
+	}
+}
+
+// TestSyncPlansVerboseReportWithProductsMatchesGoldenFile asserts that the
+// verbose report lists the products associated with each sync plan when
+// requested.
+func TestSyncPlansVerboseReportWithProductsMatchesGoldenFile(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+	cfg := &config.Config{ShowProducts: true}
+
+	got := normalizeOutput(
+		SyncPlansVerboseReport(fixtureOrgsWithProducts(ft), cfg, fixtureEvalTime, zerolog.Nop()),
+		ft,
+	)
+
+	assertGolden(t, "verbose_products_show-products", got)
+}
+
+// TestSyncPlansPrettyTableReportNoColor asserts that the pretty table report
+// omits ANSI escape sequences if color output is disabled.
+func TestSyncPlansPrettyTableReportNoColor(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+	cfg := &config.Config{NoColor: true}
+
+	got := SyncPlansPrettyTableReport(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, zerolog.Nop())
+
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("ERROR: report output contains ANSI escape sequences:\n%s", got)
+	}
+
+	if !strings.Contains(got, "✘") {
+		t.Errorf("ERROR: report output is missing problem status indicator:\n%s", got)
+	}
+}
+
+// TestSyncPlansPrettyTableReportASCIIOnly asserts that the pretty table
+// report is rendered using only ASCII characters if requested.
+func TestSyncPlansPrettyTableReportASCIIOnly(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+	cfg := &config.Config{ASCIIOnly: true}
+
+	got := SyncPlansPrettyTableReport(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, zerolog.Nop())
+
+	for _, r := range got {
+		if r > unicode.MaxASCII {
+			t.Fatalf("ERROR: report output contains non-ASCII character %q:\n%s", r, got)
+		}
+	}
+
+	if !strings.Contains(got, " !! ") {
+		t.Errorf("ERROR: report output is missing problem status indicator:\n%s", got)
+	}
+}
+
+// TestSyncPlansSimpleTableReportMaxWidth asserts that long values in the
+// simple table report are truncated to the specified maximum column width.
+func TestSyncPlansSimpleTableReportMaxWidth(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+	cfg := &config.Config{MaxWidth: 8, ASCIIOnly: true}
+
+	got := SyncPlansSimpleTableReport(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, zerolog.Nop())
+
+	for _, want := range []string{"Daily...", "Hourl...", "Alpha..."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ERROR: report output is missing truncated value %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "Daily Satellite") {
+		t.Errorf("ERROR: report output contains value exceeding maximum width:\n%s", got)
+	}
+}
+
+// TestSyncPlansReportsMaxProblemsShownMatchGoldenFiles asserts that reports
+// limit the detailed listing of problem sync plans to the longest stuck if
+// requested.
+func TestSyncPlansReportsMaxProblemsShownMatchGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	reportFormats := map[string]reportFunc{
+		config.InspectorOutputFormatSimpleTable: SyncPlansSimpleTableReport,
+		config.InspectorOutputFormatVerbose:     SyncPlansVerboseReport,
+	}
+
+	cfg := &config.Config{MaxProblemsShown: 1, ASCIIOnly: true}
+
+	logger := zerolog.Nop()
+
+	for formatName, report := range reportFormats {
+		formatName, report := formatName, report
+
+		name := strings.Join([]string{formatName, "problems", "max-problems-shown"}, "_")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ft := newFixtureTimes(fixtureEvalTime)
+			got := normalizeOutput(report(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, logger), ft)
+
+			assertGolden(t, name, got)
+		})
+	}
+}
+
+// TestSyncPlansSimpleTableReportRelativeTime asserts that next sync times are
+// rendered as durations relative to the evaluation time if requested.
+func TestSyncPlansSimpleTableReportRelativeTime(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+
+	tests := map[string]struct {
+		timeFormat   string
+		wantAbsolute bool
+	}{
+		config.TimeFormatRelative: {timeFormat: config.TimeFormatRelative, wantAbsolute: false},
+		config.TimeFormatBoth:     {timeFormat: config.TimeFormatBoth, wantAbsolute: true},
+	}
+
+	for name, tt := range tests {
+		name, tt := name, tt
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{TimeFormat: tt.timeFormat}
+			got := SyncPlansSimpleTableReport(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, zerolog.Nop())
+
+			for _, want := range []string{"overdue by 3d 1h", "in 6h"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("ERROR: report output is missing relative time %q:\n%s", want, got)
+				}
+			}
+
+			absolute := rsat.SyncTime(ft["<STUCK-3D>"]).String()
+			if strings.Contains(got, absolute) != tt.wantAbsolute {
+				t.Errorf("ERROR: want absolute time %q listed: %t:\n%s", absolute, tt.wantAbsolute, got)
+			}
+		})
+	}
+}
+
+// TestSyncPlansReportSummaryRetrievalTime asserts that the closing summary
+// lists the retrieval time only if it was recorded.
+func TestSyncPlansReportSummaryRetrievalTime(t *testing.T) {
+	t.Parallel()
+
+	ft := newFixtureTimes(fixtureEvalTime)
+
+	cfg := &config.Config{RetrievalTime: 1234567 * time.Microsecond}
+	got := SyncPlansOverviewReport(fixtureOrgsWithProblems(ft), cfg, fixtureEvalTime, zerolog.Nop())
+
+	if want := "* Retrieval time: 1.235s"; !strings.Contains(got, want) {
+		t.Errorf("ERROR: report summary is missing %q:\n%s", want, got)
+	}
+
+	got = SyncPlansOverviewReport(fixtureOrgsWithProblems(ft), &config.Config{}, fixtureEvalTime, zerolog.Nop())
+
+	if strings.Contains(got, "Retrieval time") {
+		t.Errorf("ERROR: report summary lists unrecorded retrieval time:\n%s", got)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
@@ -47,39 +48,39 @@ func simpleTableProblemStateToString(v interface{}) string {
 
 // syncPlansSimpleTableReport is a helper function that performs the bulk of
 // the "simple table" report output logic.
-func syncPlansSimpleTableReport(w io.Writer, cfg *config.Config, headerRow string, dataRowTmpl string, orgs rsat.Organizations) {
+func syncPlansSimpleTableReport(w io.Writer, cfg *config.Config, now time.Time, headerRow string, showOwner bool, showDaysStuck bool, orgs rsat.Organizations, limit problemPlansLimit) {
 	_, _ = fmt.Fprintln(w, headerRow)
 	_, _ = fmt.Fprintln(w, simpleTableHeaderSeparatorRow(headerRow, "\t"))
 
 	for i, org := range orgs {
 		for _, syncPlan := range org.SyncPlans {
-			switch {
-			case syncPlan.IsOKState() && cfg.OmitOKSyncPlans:
+			if syncPlan.IsOKState(now) && cfg.OmitOKSyncPlans {
 				continue
+			}
+
+			if limit.hides(org.Name, syncPlan, now) {
+				continue
+			}
 
-			case orgs.NumProblemPlans() > 0:
-				_, _ = fmt.Fprintf(
-					w,
-					dataRowTmpl,
-					org.Name,
-					syncPlan.Name,
-					syncPlan.DaysStuckHR(),
-					syncPlan.Interval,
-					syncPlan.NextSync.String(),
-					simpleTableProblemStateToString(!syncPlan.IsOKState()),
-				)
-
-			default:
-				_, _ = fmt.Fprintf(
-					w,
-					dataRowTmpl,
-					org.Name,
-					syncPlan.Name,
-					syncPlan.Interval,
-					syncPlan.NextSync.String(),
-					simpleTableProblemStateToString(!syncPlan.IsOKState()),
-				)
+			cells := []string{truncateCell(org.Name, cfg), truncateCell(syncPlan.Name, cfg)}
+			if showOwner {
+				cells = append(cells, truncateCell(syncPlan.Owner, cfg))
 			}
+
+			// We evaluate the collection as a whole vs just this specific
+			// sync plan so that we can have consistency across each "row".
+			if showDaysStuck {
+				cells = append(cells, syncPlan.DaysStuckHR(now))
+			}
+
+			cells = append(
+				cells,
+				syncPlan.Interval,
+				formatNextSync(syncPlan.NextSync.String(), syncPlan, cfg, now),
+				simpleTableProblemStateToString(!syncPlan.IsOKState(now)),
+			)
+
+			_, _ = fmt.Fprint(w, simpleTableRow(cells))
 		}
 
 		// Group sync plans visually based on Org.
@@ -89,6 +90,20 @@ func syncPlansSimpleTableReport(w io.Writer, cfg *config.Config, headerRow strin
 	}
 }
 
+// simpleTableRow generates a "simple table" row from the given cells. Each
+// cell is tab-terminated (not tab-separated) to form an aligned column.
+func simpleTableRow(cells []string) string {
+	var row strings.Builder
+
+	for _, cell := range cells {
+		_, _ = fmt.Fprint(&row, cell, "\t")
+	}
+
+	_, _ = fmt.Fprint(&row, "\n")
+
+	return row.String()
+}
+
 // addHeaderSeparatorRow generates a separator row intended to be used between
 // the header and data rows. Each "column" in the generated separator row
 // template is of the same length as the header row column above it.
@@ -115,7 +130,7 @@ func simpleTableHeaderSeparatorRow(headerRow string, headerRowSeparator string)
 // simple in an effort for the broadest compatible output.
 //
 // Each sync plan is listed along with relevant status information.
-func SyncPlansSimpleTableReport(orgs rsat.Organizations, cfg *config.Config, logger zerolog.Logger) string {
+func SyncPlansSimpleTableReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, logger zerolog.Logger) string {
 	var output strings.Builder
 
 	tw := tabwriter.NewWriter(&output, 4, 4, 4, ' ', 0)
@@ -126,26 +141,28 @@ func SyncPlansSimpleTableReport(orgs rsat.Organizations, cfg *config.Config, log
 	// summary output
 	_, _ = fmt.Fprintf(tw, "\n\n")
 
-	orgs.Sort()
+	sortOrgs(orgs, cfg, now)
 
-	var (
-		headerRow   string
-		dataRowTmpl string
-	)
+	showDaysStuck := orgs.NumProblemPlans(now) > 0
+	showOwner := orgs.HasOwners()
+
+	headers := []string{"Org Name", "Plan Name"}
+	if showOwner {
+		headers = append(headers, "Owner")
+	}
+	if showDaysStuck {
+		headers = append(headers, "Days Stuck")
+	}
+	headers = append(headers, "Interval", "Next Sync", "Status")
 
 	// REMINDER: Column cells must be tab-terminated, not tab-separated:
 	// non-tab terminated trailing text at the end of a line forms a cell but
 	// that cell is not part of an aligned column.
-	switch {
-	case orgs.NumProblemPlans() > 0:
-		headerRow = "Org Name\tPlan Name\tDays Stuck\tInterval\tNext Sync\tStatus\t"
-		dataRowTmpl = "%s\t%s\t%s\t%s\t%s\t%s\t\n"
-	default:
-		headerRow = "Org Name\tPlan Name\tInterval\tNext Sync\tStatus\t"
-		dataRowTmpl = "%s\t%s\t%s\t%s\t%s\t\n"
-	}
+	headerRow := strings.TrimSuffix(simpleTableRow(headers), "\n")
+
+	limit := newProblemPlansLimit(orgs, cfg, now)
 
-	syncPlansSimpleTableReport(tw, cfg, headerRow, dataRowTmpl, orgs)
+	syncPlansSimpleTableReport(tw, cfg, now, headerRow, showOwner, showDaysStuck, orgs, limit)
 
 	_, _ = fmt.Fprintln(tw)
 
@@ -153,5 +170,11 @@ func SyncPlansSimpleTableReport(orgs rsat.Organizations, cfg *config.Config, log
 		logger.Error().Err(err).Msg("Error flushing tabwriter")
 	}
 
+	addProblemPlansLimitTrailer(&output, limit, cfg)
+
+	addTopStuckSummary(&output, orgs, cfg, now)
+
+	addSummary(&output, orgs, cfg, now)
+
 	return output.String()
 }
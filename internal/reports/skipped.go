@@ -0,0 +1,47 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+)
+
+// SkippedItemsReport provides a listing of the given items which were
+// intentionally not evaluated along with the rule responsible and the reason
+// why. This is intended to help sysadmins audit exactly what monitoring chose
+// not to evaluate. An empty string is returned if no items were skipped.
+func SkippedItemsReport(skipped rsat.SkippedItems) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+
+	var output strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&output,
+		"%sSKIPPED ITEMS%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, item := range skipped {
+		_, _ = fmt.Fprintf(
+			&output,
+			"* %s%s",
+			item.String(),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	return output.String()
+}
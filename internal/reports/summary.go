@@ -0,0 +1,99 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package reports
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/atc0005/go-nagios"
+)
+
+// summaryItem is a labeled value listed in the closing summary of a report.
+type summaryItem struct {
+	label string
+	value string
+}
+
+// summaryItems provides the aggregate statistics listed in the closing
+// summary of a report. The retrieval time is only included if recorded.
+func summaryItems(orgs rsat.Organizations, cfg *config.Config, now time.Time) []summaryItem {
+	items := []summaryItem{
+		{label: "Organizations", value: strconv.Itoa(orgs.NumOrgs())},
+		{label: "Organizations without sync plans", value: strconv.Itoa(orgs.NumOrgsWithoutPlans())},
+		{label: "Sync plans", value: strconv.Itoa(orgs.NumPlans())},
+		{label: "Enabled", value: strconv.Itoa(orgs.NumPlansEnabled())},
+		{label: "Disabled", value: strconv.Itoa(orgs.NumPlansDisabled())},
+		{label: "Stuck", value: strconv.Itoa(orgs.NumPlansStuck(now))},
+		{label: "Max days stuck", value: strconv.Itoa(orgs.MaxDaysStuck(now))},
+	}
+
+	if cfg.RetrievalTime > 0 {
+		items = append(items, summaryItem{
+			label: "Retrieval time",
+			value: cfg.RetrievalTime.Round(time.Millisecond).String(),
+		})
+	}
+
+	return items
+}
+
+// addSummary writes a closing summary of aggregate statistics for the
+// collection so that the key numbers are available without scanning the
+// full report.
+func addSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	_, _ = fmt.Fprintf(
+		w,
+		"%sSUMMARY%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, item := range summaryItems(orgs, cfg, now) {
+		_, _ = fmt.Fprintf(
+			w,
+			"* %s: %s%s",
+			item.label,
+			item.value,
+			nagios.CheckOutputEOL,
+		)
+	}
+}
+
+// addMarkdownSummary writes a closing summary of aggregate statistics for the
+// collection as a Markdown list.
+func addMarkdownSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	_, _ = fmt.Fprint(w, "\n### Summary\n\n")
+
+	for _, item := range summaryItems(orgs, cfg, now) {
+		_, _ = fmt.Fprintf(w, "- %s: %s\n", item.label, item.value)
+	}
+}
+
+// addHTMLSummary writes a closing summary of aggregate statistics for the
+// collection as an HTML list.
+func addHTMLSummary(w io.Writer, orgs rsat.Organizations, cfg *config.Config, now time.Time) {
+	_, _ = fmt.Fprint(w, "<h2>Summary</h2>\n<ul>\n")
+
+	for _, item := range summaryItems(orgs, cfg, now) {
+		_, _ = fmt.Fprintf(
+			w,
+			"<li>%s: %s</li>\n",
+			html.EscapeString(item.label),
+			html.EscapeString(item.value),
+		)
+	}
+
+	_, _ = fmt.Fprint(w, "</ul>\n")
+}
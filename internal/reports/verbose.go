@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/atc0005/check-rsat/internal/config"
 	"github.com/atc0005/check-rsat/internal/rsat"
@@ -24,30 +25,40 @@ import (
 // organization.
 //
 // NOTE: If no problems are detected the output
-func SyncPlansVerboseReport(orgs rsat.Organizations, cfg *config.Config, _ zerolog.Logger) string {
+func SyncPlansVerboseReport(orgs rsat.Organizations, cfg *config.Config, now time.Time, _ zerolog.Logger) string {
 	var output strings.Builder
 
 	addSyncPlansReportLeadIn(&output)
 
-	orgs.Sort()
+	sortOrgs(orgs, cfg, now)
 
-	syncPlansVerboseReport(&output, cfg, orgs)
+	limit := newProblemPlansLimit(orgs, cfg, now)
+
+	syncPlansVerboseReport(&output, cfg, now, orgs, limit)
+
+	addProblemPlansLimitTrailer(&output, limit, cfg)
+
+	addProblemPlansByOwnerSummary(&output, orgs, now)
+
+	addTopStuckSummary(&output, orgs, cfg, now)
+
+	addSummary(&output, orgs, cfg, now)
 
 	return output.String()
 }
 
 // syncPlansVerboseReport is a helper function that performs the bulk of
 // the "verbose" report output logic.
-func syncPlansVerboseReport(w io.Writer, cfg *config.Config, orgs rsat.Organizations) {
+func syncPlansVerboseReport(w io.Writer, cfg *config.Config, now time.Time, orgs rsat.Organizations, limit problemPlansLimit) {
 	for _, org := range orgs {
 		switch {
-		case orgs.NumProblemPlans() > 0:
+		case orgs.NumProblemPlans(now) > 0:
 			_, _ = fmt.Fprintf(
 				w,
 				"%s%s (%d stuck, %d enabled, %d disabled)%s",
 				nagios.CheckOutputEOL,
 				org.Name,
-				org.SyncPlans.NumStuck(),
+				org.SyncPlans.NumStuck(now),
 				org.SyncPlans.NumEnabled(),
 				org.SyncPlans.NumDisabled(),
 				nagios.CheckOutputEOL,
@@ -67,7 +78,10 @@ func syncPlansVerboseReport(w io.Writer, cfg *config.Config, orgs rsat.Organizat
 
 		for _, syncPlan := range org.SyncPlans {
 			switch {
-			case syncPlan.IsOKState() && cfg.OmitOKSyncPlans:
+			case syncPlan.IsOKState(now) && cfg.OmitOKSyncPlans:
+				continue
+
+			case limit.hides(org.Name, syncPlan, now):
 				continue
 
 			// We evaluate the collection as a whole vs just this specific
@@ -75,29 +89,126 @@ func syncPlansVerboseReport(w io.Writer, cfg *config.Config, orgs rsat.Organizat
 			// want to include "days stuck" even if the specific sync plan we
 			// are looking at isn't stuck (to contrast against any plans which
 			// are stuck).
-			case orgs.NumProblemPlans() > 0:
+			case orgs.NumProblemPlans(now) > 0:
 				_, _ = fmt.Fprintf(
 					w,
 					"  * [Name: %s, Days Stuck: %s, Interval: %s, Next Sync: %s]%s",
 					syncPlan.Name,
-					syncPlan.DaysStuckHR(),
+					syncPlan.DaysStuckHR(now),
 					syncPlan.Interval,
-					syncPlan.NextSync.String(),
+					formatNextSync(syncPlan.NextSync.String(), syncPlan, cfg, now),
 					nagios.CheckOutputEOL,
 				)
 
+				if syncPlan.Owner != "" {
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Owner: %s%s",
+						syncPlan.Owner,
+						nagios.CheckOutputEOL,
+					)
+				}
+
+				switch {
+				case syncPlan.CreatedSinceLastRun():
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Created since last run: %s%s",
+						syncPlan.CreatedAt.String(),
+						nagios.CheckOutputEOL,
+					)
+
+				case syncPlan.ModifiedSinceLastRun():
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Modified since last run: %s%s",
+						syncPlan.UpdatedAt.String(),
+						nagios.CheckOutputEOL,
+					)
+				}
+
+				if syncPlan.Enabled && syncPlan.HasInactiveRecurringLogic() {
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Recurring Logic: %s%s",
+						syncPlan.RecurringLogicState,
+						nagios.CheckOutputEOL,
+					)
+				}
+
+				if stale := syncPlan.StaleProducts(now); len(stale) > 0 {
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Stale Products: %s%s",
+						strings.Join(stale.Names(), ", "),
+						nagios.CheckOutputEOL,
+					)
+				}
+
+				if syncPlan.IsDrifting(now) {
+					_, _ = fmt.Fprintf(
+						w,
+						"    * Drifting: products last synced %s ago (interval: %s)%s",
+						now.Sub(syncPlan.LastProductSync()).Truncate(time.Minute).String(),
+						syncPlan.Interval,
+						nagios.CheckOutputEOL,
+					)
+				}
+
+				if syncPlan.EvaluateProductSyncState {
+					if failed := syncPlan.FailedProducts(now); len(failed) > 0 {
+						_, _ = fmt.Fprintf(
+							w,
+							"    * Failed Products: %s%s",
+							strings.Join(failed.Names(), ", "),
+							nagios.CheckOutputEOL,
+						)
+					}
+				}
+
+				if cfg.ShowProducts {
+					verboseProductsList(w, syncPlan.Products, cfg, now)
+				}
+
 			default:
 				_, _ = fmt.Fprintf(
 					w,
 					"  * [Name: %s, Interval: %s, Next Sync: %s]%s",
 					syncPlan.Name,
 					syncPlan.Interval,
-					syncPlan.NextSyncTime(),
+					formatNextSync(syncPlan.NextSyncTime(), syncPlan, cfg, now),
 					nagios.CheckOutputEOL,
 				)
+
+				if cfg.ShowProducts {
+					verboseProductsList(w, syncPlan.Products, cfg, now)
+				}
 			}
 		}
 
 		_, _ = fmt.Fprint(w, nagios.CheckOutputEOL)
 	}
 }
+
+// verboseProductsList is a helper function that lists the given products
+// associated with a sync plan for use in the "verbose" report.
+func verboseProductsList(w io.Writer, products rsat.Products, cfg *config.Config, now time.Time) {
+	if len(products) == 0 {
+		_, _ = fmt.Fprintf(w, "    * Products: None%s", nagios.CheckOutputEOL)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "    * Products:%s", nagios.CheckOutputEOL)
+
+	for _, product := range products {
+		_, _ = fmt.Fprintf(
+			w,
+			"      * [Name: %s, Repositories: %d, Last Sync: %s]%s",
+			product.Name,
+			product.RepositoryCount,
+			productLastSync(product, cfg, now),
+			nagios.CheckOutputEOL,
+		)
+	}
+}
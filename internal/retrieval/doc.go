@@ -0,0 +1,12 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package retrieval provides retrieval of Red Hat Satellite organizations and
+// sync plans shared by applications in this module, using the shared cache
+// daemon or disk cache (if specified) before falling back to the Red Hat
+// Satellite server.
+package retrieval
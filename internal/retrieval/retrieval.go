@@ -0,0 +1,135 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package retrieval
+
+import (
+	"context"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/cache"
+	"github.com/atc0005/check-rsat/internal/config"
+	"github.com/atc0005/check-rsat/internal/rsat"
+	"github.com/rs/zerolog"
+)
+
+// GetOrgsWithSyncPlans retrieves the organizations selected for evaluation
+// along with their sync plans from the shared cache daemon (if specified),
+// falling back to the Red Hat Satellite server associated with the given API
+// client if the cache daemon is unavailable. If a cache directory is
+// specified, cached values are used while fresh. Use of cached values is
+// logged at the given level so that applications may choose how prominently
+// this is noted. A record of each organization skipped is also returned.
+func GetOrgsWithSyncPlans(ctx context.Context, client *rsat.APIClient, cfg *config.Config, logger zerolog.Logger, cacheLogLevel zerolog.Level) (rsat.Organizations, rsat.SkippedItems, error) {
+	filter := rsat.OrgFilter{
+		Include: cfg.Orgs,
+		Exclude: cfg.ExcludeOrgs,
+	}
+
+	if cfg.CacheSocket != "" {
+		// Reserve time for falling back to the Red Hat Satellite server if
+		// the cache daemon does not respond in a timely manner.
+		cacheCtx, cancel := context.WithTimeout(ctx, cfg.Timeout()/2)
+		defer cancel()
+
+		orgs, retrievedAt, err := cache.GetOrgsWithSyncPlans(cacheCtx, cfg.CacheSocket, cfg.Server, cfg.TCPPort)
+		if err == nil {
+			logger.WithLevel(cacheLogLevel).
+				Str("socket", cfg.CacheSocket).
+				Str("cache_age", time.Since(retrievedAt).String()).
+				Msg("Retrieved sync plans from cache daemon")
+
+			// The cache daemon provides all organizations and sync plans, so
+			// filters are applied after retrieval.
+			if cfg.EnabledOnly {
+				orgs.RemoveDisabledSyncPlans()
+			}
+
+			return filter.Apply(orgs)
+		}
+
+		logger.Warn().
+			Err(err).
+			Str("socket", cfg.CacheSocket).
+			Msg("Cache daemon unavailable; retrieving sync plans from Red Hat Satellite server")
+	}
+
+	if cfg.CacheDir != "" {
+		orgs, err := getOrgsWithSyncPlansFromDiskCache(ctx, client, cfg, logger, cacheLogLevel)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// All organizations and sync plans are cached, so filters are
+		// applied after retrieval.
+		if cfg.EnabledOnly {
+			orgs.RemoveDisabledSyncPlans()
+		}
+
+		return filter.Apply(orgs)
+	}
+
+	// Limit retrieval to enabled sync plans so that disabled sync plans are
+	// not transferred.
+	var opts rsat.QueryOptions
+	if cfg.EnabledOnly {
+		opts.Search = rsat.SyncPlansSearchEnabled
+	}
+
+	orgs, skipped, err := rsat.GetFilteredOrgsWithSyncPlans(ctx, client, opts, filter)
+
+	// Saved API responses (fixtures) do not apply the scoped search.
+	if err == nil && cfg.EnabledOnly {
+		orgs.RemoveDisabledSyncPlans()
+	}
+
+	return orgs, skipped, err
+}
+
+// getOrgsWithSyncPlansFromDiskCache retrieves all organizations along with
+// their sync plans from the disk cache if fresh, otherwise from the Red Hat
+// Satellite server associated with the given API client. The disk cache is
+// updated after retrieval from the Red Hat Satellite server. Use of cached
+// values is logged at the given level.
+func getOrgsWithSyncPlansFromDiskCache(ctx context.Context, client *rsat.APIClient, cfg *config.Config, logger zerolog.Logger, cacheLogLevel zerolog.Level) (rsat.Organizations, error) {
+	diskCache := cache.DiskCache{
+		Dir:      cfg.CacheDir,
+		TTL:      cfg.CacheTTL(),
+		Username: cfg.Username,
+	}
+
+	orgs, retrievedAt, readErr := diskCache.Read(cfg.Server, cfg.TCPPort)
+	if readErr == nil {
+		logger.WithLevel(cacheLogLevel).
+			Str("cache_dir", cfg.CacheDir).
+			Str("cache_age", time.Since(retrievedAt).String()).
+			Msg("Retrieved sync plans from disk cache")
+
+		return orgs, nil
+	}
+
+	logger.Debug().
+		Err(readErr).
+		Str("cache_dir", cfg.CacheDir).
+		Msg("Disk cache missing or stale; retrieving sync plans from Red Hat Satellite server")
+
+	retrievedAt = time.Now()
+
+	orgs, fetchErr := rsat.GetOrgsWithSyncPlans(ctx, client, rsat.QueryOptions{})
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	if writeErr := diskCache.Write(cfg.Server, cfg.TCPPort, orgs, retrievedAt); writeErr != nil {
+		logger.Warn().
+			Err(writeErr).
+			Str("cache_dir", cfg.CacheDir).
+			Msg("Failed to update disk cache")
+	}
+
+	return orgs, nil
+}
@@ -0,0 +1,254 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ActivationKeysResponse represents the API response from a request of all
+// activation keys for a specific organization.
+type ActivationKeysResponse struct {
+	Error NullString `json:"error"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// ActivationKeys is the collection of Activation Keys returned in the
+	// API query response.
+	ActivationKeys ActivationKeys `json:"results"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the activation keys provided by the response along with
+// the number of activation keys matching the query across all pages.
+func (r ActivationKeysResponse) pageResults() ([]ActivationKey, int) {
+	return r.ActivationKeys, r.Subtotal
+}
+
+// ActivationKey is used to register hosts with a Red Hat Satellite
+// organization and associate those hosts with specific content (e.g.,
+// content view, lifecycle environment).
+type ActivationKey struct {
+	CreatedAt         StandardAPITime    `json:"created_at"`
+	UpdatedAt         StandardAPITime    `json:"updated_at"`
+	ContentView       *ContentViewRef    `json:"content_view"`
+	Environment       *EnvironmentRef    `json:"environment"`
+	MaxHosts          *int               `json:"max_hosts"` // null if unlimited
+	Description       NullString         `json:"description"`
+	ReleaseVersion    NullString         `json:"release_version"`
+	ServiceLevel      NullString         `json:"service_level"`
+	Name              string             `json:"name"`
+	OrganizationName  string             `json:"-"`
+	OrganizationLabel string             `json:"-"`
+	OrganizationTitle string             `json:"-"`
+	ID                int                `json:"id"`
+	OrganizationID    int                `json:"organization_id"`
+	UsageCount        int                `json:"usage_count"`
+	Permissions       ActivationKeyPerms `json:"permissions"`
+	UnlimitedHosts    bool               `json:"unlimited_hosts"`
+}
+
+// ActivationKeyPerms is the collection of permissions that a user querying
+// the Red Hat Satellite API has for interacting with activation keys.
+type ActivationKeyPerms struct {
+	DestroyActivationKeys bool `json:"destroy_activation_keys"`
+	EditActivationKeys    bool `json:"edit_activation_keys"`
+	ViewActivationKeys    bool `json:"view_activation_keys"`
+}
+
+// ContentViewRef is a brief reference to a content view associated with
+// another Red Hat Satellite resource.
+type ContentViewRef struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// EnvironmentRef is a brief reference to a lifecycle environment associated
+// with another Red Hat Satellite resource.
+type EnvironmentRef struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// ActivationKeys is a collection of Red Hat Satellite activation keys.
+type ActivationKeys []ActivationKey
+
+// GetActivationKeys uses the provided APIClient to retrieve all activation
+// keys for each specified Red Hat Satellite organization. If no organizations
+// are specified then an attempt will be made to retrieve activation keys from
+// all RSAT organizations. The given query options (e.g., a scoped search of
+// name ~ rhel) are used to limit and order the activation keys retrieved.
+func GetActivationKeys(ctx context.Context, client *APIClient, opts QueryOptions, orgs ...Organization) (ActivationKeys, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	if len(orgs) == 0 {
+		var orgsErr error
+		orgs, orgsErr = GetOrganizations(ctx, client, QueryOptions{})
+		if orgsErr != nil {
+			return nil, orgsErr
+		}
+	}
+
+	allActivationKeys := make(ActivationKeys, 0, len(orgs)*3)
+
+	for _, org := range orgs {
+		activationKeys, err := getOrgActivationKeys(ctx, client, opts, org)
+		if err != nil {
+			return nil, err
+		}
+
+		allActivationKeys = append(allActivationKeys, activationKeys...)
+	}
+
+	logger.Debug().
+		Int("activation_keys", len(allActivationKeys)).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed activation keys retrieval for all requested organizations")
+
+	return allActivationKeys, nil
+}
+
+// IsExhausted indicates whether the activation key has reached its host
+// usage limit and can no longer be used to register additional hosts.
+func (ak ActivationKey) IsExhausted() bool {
+	if ak.UnlimitedHosts || ak.MaxHosts == nil {
+		return false
+	}
+
+	return ak.UsageCount >= *ak.MaxHosts
+}
+
+// HasContentView indicates whether the activation key is associated with a
+// content view.
+func (ak ActivationKey) HasContentView() bool {
+	return ak.ContentView != nil
+}
+
+// IsOKState indicates whether any problems have been identified with this
+// activation key.
+func (ak ActivationKey) IsOKState() bool {
+	return !ak.IsExhausted() && ak.HasContentView()
+}
+
+// Exhausted returns a new collection containing all activation keys from the
+// original collection which have reached their host usage limit.
+func (aks ActivationKeys) Exhausted() ActivationKeys {
+	matches := make(ActivationKeys, 0, len(aks))
+
+	for _, ak := range aks {
+		if ak.IsExhausted() {
+			matches = append(matches, ak)
+		}
+	}
+
+	return matches
+}
+
+// MissingContentView returns a new collection containing all activation
+// keys from the original collection which are not associated with a content
+// view.
+func (aks ActivationKeys) MissingContentView() ActivationKeys {
+	matches := make(ActivationKeys, 0, len(aks))
+
+	for _, ak := range aks {
+		if !ak.HasContentView() {
+			matches = append(matches, ak)
+		}
+	}
+
+	return matches
+}
+
+// NumProblemKeys returns the total number of activation keys in the
+// collection with a non-OK state.
+func (aks ActivationKeys) NumProblemKeys() int {
+	var num int
+
+	for _, ak := range aks {
+		if !ak.IsOKState() {
+			num++
+		}
+	}
+
+	return num
+}
+
+// getOrgActivationKeys retrieves all activation keys for the given
+// organization.
+func getOrgActivationKeys(ctx context.Context, client *APIClient, opts QueryOptions, org Organization) (ActivationKeys, error) {
+	funcTimeStart := time.Now()
+
+	subLogger := client.Logger.With().
+		Int("org_id", org.ID).
+		Str("org_name", org.Name).
+		Logger()
+
+	apiURL := fmt.Sprintf(
+		ActivationKeysAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+		org.ID,
+	)
+
+	allActivationKeys, err := fetchAllPages[ActivationKey, ActivationKeysResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		subLogger,
+		"activation keys",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotate Activation Keys with specific Org values for convenience.
+	for i := range allActivationKeys {
+		allActivationKeys[i].OrganizationName = org.Name
+		allActivationKeys[i].OrganizationLabel = org.Label
+		allActivationKeys[i].OrganizationTitle = org.Title
+	}
+
+	subLogger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all activation keys for organization")
+
+	return allActivationKeys, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// auditsSearchTimeLayout is the time layout used when specifying a time
+// window for audits retrieval via the scoped search syntax.
+const auditsSearchTimeLayout string = "2006-01-02 15:04:05 UTC"
+
+// Known audit actions.
+const (
+	AuditActionCreate  string = "create"
+	AuditActionUpdate  string = "update"
+	AuditActionDestroy string = "destroy"
+)
+
+// AuditsResponse represents the API response from a request for audits
+// recorded by the Red Hat Satellite server.
+type AuditsResponse struct {
+	// Audits is the collection of Audits returned in the API query response.
+	Audits Audits `json:"results"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the audits provided by the response along with the
+// number of audits matching the query across all pages.
+func (r AuditsResponse) pageResults() ([]Audit, int) {
+	return r.Audits, r.Subtotal
+}
+
+// Audit is a record of a configuration change made within a Red Hat
+// Satellite server.
+type Audit struct {
+	CreatedAt      StandardAPITime `json:"created_at"`
+	AuditedChanges json.RawMessage `json:"audited_changes"`
+	RemoteAddress  NullString      `json:"remote_address"`
+	UserName       NullString      `json:"user_name"`
+	Comment        NullString      `json:"comment"`
+	RequestUUID    NullString      `json:"request_uuid"`
+	Action         string          `json:"action"`
+	AuditableType  string          `json:"auditable_type"`
+	AuditableName  string          `json:"auditable_name"`
+	ID             int             `json:"id"`
+	AuditableID    int             `json:"auditable_id"`
+	Version        int             `json:"version"`
+}
+
+// Audits is a collection of Red Hat Satellite audits.
+type Audits []Audit
+
+// GetAudits uses the given client to retrieve all Red Hat Satellite audits
+// recorded on or after the given time. The given query options (e.g., a
+// scoped search of type = katello/sync_plan) are used to further limit and
+// order the audits retrieved.
+func GetAudits(ctx context.Context, client *APIClient, opts QueryOptions, since time.Time) (Audits, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger.With().
+		Str("since", since.UTC().Format(auditsSearchTimeLayout)).
+		Logger()
+
+	apiURL := fmt.Sprintf(
+		AuditsAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	opts.Search = JoinSearch(AuditsSearchSince(since), opts.Search)
+
+	allAudits, err := fetchAllPages[Audit, AuditsResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		logger,
+		"audits",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all audits")
+
+	return allAudits, nil
+}
+
+// AuditsSearchSince returns a scoped search query string used to limit
+// audits retrieval to records created on or after the given time.
+func AuditsSearchSince(since time.Time) string {
+	return fmt.Sprintf(
+		`time >= "%s"`,
+		since.UTC().Format(auditsSearchTimeLayout),
+	)
+}
+
+// Sort sorts the audits in the collection by creation date, newest first.
+func (audits Audits) Sort() {
+	sort.SliceStable(audits, func(i int, j int) bool {
+		return time.Time(audits[i].CreatedAt).After(time.Time(audits[j].CreatedAt))
+	})
+}
+
+// ByType returns a new collection containing all audits from the original
+// collection for the given auditable type (e.g., Katello::SyncPlan).
+func (audits Audits) ByType(auditableType string) Audits {
+	matches := make(Audits, 0, len(audits))
+
+	for _, audit := range audits {
+		if audit.AuditableType == auditableType {
+			matches = append(matches, audit)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// isBulkSyncPlansUnsupported indicates whether the given error is the result
+// of the server not supporting retrieval of sync plans across all
+// organizations (e.g., Katello versions requiring an organization).
+func isBulkSyncPlansUnsupported(err error) bool {
+	return errors.Is(err, ErrEndpointNotFound) ||
+		errors.Is(err, ErrRequestRejected)
+}
+
+// retrieveOrgsSyncPlans retrieves the sync plans for each of the given
+// organizations, updating each organization in place. If requested, sync
+// plans for all organizations are retrieved using a single (paged) query
+// instead of one query per organization. Sync plans are retrieved per
+// organization if the server does not support the single query.
+func retrieveOrgsSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions, orgs Organizations, funcTimeStart time.Time) error {
+	if client.Limits.BulkSyncPlans && len(orgs) > 0 {
+		err := getOrgsSyncPlansBulk(ctx, client, opts, orgs)
+
+		switch {
+		case err == nil:
+			return nil
+
+		case isBulkSyncPlansUnsupported(err):
+			client.Logger.Warn().
+				Err(err).
+				Msg("Server does not support retrieving sync plans across organizations; retrieving sync plans per organization")
+
+		default:
+			return fmt.Errorf("failed to retrieve sync plans for all organizations: %w", err)
+		}
+	}
+
+	return getOrgsSyncPlans(ctx, client, opts, orgs, funcTimeStart)
+}
+
+// getOrgsSyncPlansBulk retrieves the sync plans for all of the given
+// organizations using a single (paged) query of the sync plans visible to
+// the user instead of one query per organization. Retrieved sync plans are
+// matched to organizations by organization ID and each organization is
+// updated in place. Sync plans for organizations not in the given
+// collection (e.g., excluded organizations) are discarded.
+func getOrgsSyncPlansBulk(ctx context.Context, client *APIClient, opts QueryOptions, orgs Organizations) error {
+	funcTimeStart := time.Now()
+
+	logger := client.Logger
+
+	logger.Debug().
+		Int("orgs", len(orgs)).
+		Msg("Retrieving sync plans for all organizations in a single query")
+
+	apiURL := fmt.Sprintf(
+		BulkSyncPlansAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	allSyncPlans, err := fetchAllPages[SyncPlan, SyncPlansResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		logger,
+		"sync plans",
+	)
+	if err != nil {
+		return err
+	}
+
+	orgSyncPlans := make(map[int]SyncPlans, len(orgs))
+	for _, syncPlan := range allSyncPlans {
+		orgSyncPlans[syncPlan.OrganizationID] = append(orgSyncPlans[syncPlan.OrganizationID], syncPlan)
+	}
+
+	var numMatched int
+	for i := range orgs {
+		syncPlans := orgSyncPlans[orgs[i].ID]
+		annotateSyncPlans(client, orgs[i], syncPlans)
+
+		orgs[i].SyncPlans = syncPlans
+		numMatched += len(syncPlans)
+	}
+
+	logger.Debug().
+		Int("retrieved_plans", len(allSyncPlans)).
+		Int("discarded_plans", len(allSyncPlans)-numMatched).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of sync plans for all organizations")
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestGetOrgsWithSyncPlansBulk(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		FixturesDir: filepath.Join("testdata", "fixtures"),
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 30, BulkSyncPlans: true}, zerolog.Nop())
+
+	orgs, err := GetOrgsWithSyncPlans(context.Background(), client, QueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to read organizations from fixtures: %v", err)
+	}
+
+	// The sync plan for an organization not visible to the user is
+	// discarded.
+	if got := orgs.NumPlans(); got != 2 {
+		t.Errorf("got %d sync plans, want 2", got)
+	}
+
+	for _, syncPlan := range orgs[0].SyncPlans {
+		if syncPlan.OrganizationName != orgs[0].Name {
+			t.Errorf("got organization name %q for sync plan %q, want %q", syncPlan.OrganizationName, syncPlan.Name, orgs[0].Name)
+		}
+	}
+
+	for _, m := range client.Metrics() {
+		if m.Endpoint == MetricsEndpointSyncPlans {
+			t.Errorf("got %d per-organization sync plans requests, want 0", m.Requests)
+		}
+	}
+}
+
+func TestGetOrgsWithSyncPlansBulkFallback(t *testing.T) {
+	fixturesDir := filepath.Join("testdata", "fixtures")
+
+	// Emulate a server which requires an organization when retrieving sync
+	// plans.
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/katello/api/v2/sync_plans" {
+			http.Error(w, `{"error":{"message":"Couldn't find organization"}}`, http.StatusNotFound)
+
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get(APIEndpointURLQueryParamPageKey))
+
+		content, err := os.ReadFile(FixturePath(fixturesDir, r.URL.Path, page))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(content)
+	}))
+	defer ts.Close()
+
+	server, port := testServerAddress(t, ts)
+
+	authInfo := APIAuthInfo{
+		Server:      server,
+		Port:        port,
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 1, BulkSyncPlans: true}, zerolog.Nop())
+
+	orgs, err := GetOrgsWithSyncPlans(context.Background(), client, QueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to retrieve organizations: %v", err)
+	}
+
+	if got := orgs.NumPlans(); got != 2 {
+		t.Errorf("got %d sync plans, want 2", got)
+	}
+}
+
+// testServerAddress returns the host and port for the given test server.
+func testServerAddress(t *testing.T, ts *httptest.Server) (string, int) {
+	t.Helper()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	return u.Hostname(), port
+}
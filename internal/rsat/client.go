@@ -11,7 +11,10 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/atc0005/check-rsat/internal/netutils"
@@ -21,7 +24,73 @@ import (
 // APILimits represents the settings used to comply with the limits set by an
 // API endpoint.
 type APILimits struct {
+	// PerPage is the pagination limit applied to API query results. This
+	// value is ignored if PerPageAuto is set.
 	PerPage int
+
+	// PerPageAuto indicates whether the pagination limit is determined
+	// automatically by starting with a large limit and stepping down if the
+	// server rejects or truncates the results.
+	PerPageAuto bool
+
+	// MaxConcurrent is the maximum number of organizations for which sync
+	// plans are retrieved concurrently. Sync plans are retrieved for one
+	// organization at a time if not specified.
+	MaxConcurrent int
+
+	// MaxIdleConns is the maximum number of idle (keep-alive) connections
+	// retained for reuse across all hosts. Zero means no limit.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is the maximum number of idle (keep-alive)
+	// connections retained for reuse per host. If zero,
+	// http.DefaultMaxIdleConnsPerHost is used.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is the maximum amount of time an idle (keep-alive)
+	// connection is retained for reuse before it is closed. Zero means no
+	// limit.
+	IdleConnTimeout time.Duration
+
+	// DialTimeout is the maximum amount of time a connection attempt to a
+	// single IP Address for the Red Hat Satellite server may take. The
+	// netutils package default is used if not specified.
+	DialTimeout time.Duration
+
+	// DialKeepAlive is the interval between keep-alive probes for active
+	// connections to the Red Hat Satellite server. The netutils package
+	// default is used if not specified.
+	DialKeepAlive time.Duration
+
+	// EnableHTTP2 indicates whether HTTP/2 is attempted. HTTP/2 is not
+	// attempted by default as a custom dialer and TLS configuration are
+	// used.
+	EnableHTTP2 bool
+
+	// StreamDecode indicates whether the results within each page of a
+	// paginated API query response are decoded as they are read instead of
+	// buffering the whole page.
+	StreamDecode bool
+
+	// BulkSyncPlans indicates whether sync plans for all organizations are
+	// retrieved using a single (paged) query instead of one query per
+	// organization. Sync plans are retrieved per organization if the server
+	// does not support the query.
+	BulkSyncPlans bool
+
+	// Retry is the collection of settings used to retry requests which fail
+	// due to transient problems.
+	Retry RetrySettings
+}
+
+// maxConcurrent returns the maximum number of organizations for which sync
+// plans are retrieved concurrently.
+func (l APILimits) maxConcurrent() int {
+	if l.MaxConcurrent < 1 {
+		return 1
+	}
+
+	return l.MaxConcurrent
 }
 
 // APIClient represents a customized HTTP client for interacting with Red
@@ -32,6 +101,30 @@ type APIClient struct {
 	Logger   zerolog.Logger
 	Limits   APILimits
 	// APIResponseCache CachedAPIResponses
+
+	// ServerVersion is the collection of version details for the Red Hat
+	// Satellite server. This value is populated by GetStatus and may be used
+	// to branch on known API differences between Satellite versions.
+	ServerVersion ServerVersion
+
+	// peerCerts is the certificate chain presented by the Red Hat Satellite
+	// server during the most recent TLS handshake. This chain is recorded
+	// regardless of whether certificate verification is enabled.
+	peerCerts []*x509.Certificate
+
+	// peerCertsMutex guards access to the recorded certificate chain.
+	peerCertsMutex *sync.Mutex
+
+	// autoPerPage is the pagination limit determined automatically if
+	// PerPageAuto is set. Zero until determined.
+	autoPerPage int
+
+	// autoPerPageMutex guards access to the automatically determined
+	// pagination limit.
+	autoPerPageMutex *sync.Mutex
+
+	// metrics records request counts and latency for each API endpoint.
+	metrics *apiMetricsRecorder
 }
 
 // CachedAPIResponses represents specific API responses which are cached to
@@ -74,64 +167,248 @@ func getCustomTLSConfig(apiAuthInfo APIAuthInfo) *tls.Config {
 		}
 	}
 
+	// Present the client certificate to servers requiring mutual TLS
+	// authentication.
+	if apiAuthInfo.ClientCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*apiAuthInfo.ClientCertificate}
+	}
+
 	return tlsConfig
 }
 
+// CertVerificationFailure evaluates the given error and indicates whether it
+// is the result of a failure to verify the certificate chain presented by
+// the Red Hat Satellite server. If so, the unverified certificate chain
+// presented by the server is also returned for review.
+func CertVerificationFailure(err error) ([]*x509.Certificate, bool) {
+	var certVerifyErr *tls.CertificateVerificationError
+	if errors.As(err, &certVerifyErr) {
+		return certVerifyErr.UnverifiedCertificates, true
+	}
+
+	return nil, false
+}
+
 // NewAPIClient uses the provided API Auth details to construct a custom HTTP
 // client used to interact with
 func NewAPIClient(apiAuthInfo APIAuthInfo, apiLimits APILimits, logger zerolog.Logger) *APIClient {
 	tlsConfig := getCustomTLSConfig(apiAuthInfo)
 
+	connector := netutils.NewConnector(apiAuthInfo.NetworkType, logger)
+	connector.Resolver = apiAuthInfo.resolver()
+	connector.Dialer = netutils.NewDialer(apiLimits.DialTimeout, apiLimits.DialKeepAlive)
+
 	transport := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		MaxIdleConns:    1,                // TODO: Allow adjusting this via config package
-		IdleConnTimeout: 30 * time.Second, // TODO: Allow adjusting this via config package
-		DialContext: netutils.DialerWithContext(
-			apiAuthInfo.NetworkType,
-			logger,
-		),
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConns:        apiLimits.MaxIdleConns,
+		MaxIdleConnsPerHost: apiLimits.MaxIdleConnsPerHost,
+		IdleConnTimeout:     apiLimits.IdleConnTimeout,
+		ForceAttemptHTTP2:   apiLimits.EnableHTTP2,
+		DialContext:         connector.DialContext,
 	}
 
+	// Compressed responses are requested and decompressed explicitly instead
+	// of relying on the implicit support provided by the transport.
+	transport.DisableCompression = true
+
 	c := &http.Client{
-		Transport: transport,
+		Transport: gzipTransport{next: transport},
+	}
+
+	// Reuse stored responses which have not been modified since they were
+	// last retrieved.
+	if apiAuthInfo.ResponseCacheDir != "" {
+		c.Transport = conditionalTransport{
+			next:      c.Transport,
+			dir:       apiAuthInfo.ResponseCacheDir,
+			username:  apiAuthInfo.Username,
+			readLimit: apiAuthInfo.ReadLimit,
+			logger:    logger,
+		}
+	}
+
+	// Read saved API responses from disk instead of submitting requests
+	// over the network.
+	if apiAuthInfo.FixturesDir != "" {
+		c.Transport = fixturesTransport{dir: apiAuthInfo.FixturesDir}
 	}
 
-	return &APIClient{
-		Client:   c,
-		AuthInfo: apiAuthInfo,
-		Logger:   logger,
-		Limits:   apiLimits,
+	apiClient := &APIClient{
+		Client:           c,
+		AuthInfo:         apiAuthInfo,
+		Logger:           logger,
+		Limits:           apiLimits,
+		peerCertsMutex:   &sync.Mutex{},
+		autoPerPageMutex: &sync.Mutex{},
+		metrics:          &apiMetricsRecorder{},
 	}
+
+	// Record the certificate chain presented by the server so that it is
+	// available for review even if certificate verification is disabled.
+	tlsConfig.VerifyConnection = apiClient.recordPeerCertificates
+
+	return apiClient
 }
 
-// submitAPIQueryRequest is a helper function used to submit a request to an
-// API endpoint and perform basic validation of the results.
-//
-// TODO: Refactor to be an APIClient method
-func submitAPIQueryRequest(
-	ctx context.Context,
-	client *APIClient,
-	apiURL string,
-	apiURLQueryParams map[string]string,
-	logger zerolog.Logger,
-) (*http.Response, error) {
+// recordPeerCertificates is used as a tls.Config VerifyConnection callback to
+// record the certificate chain presented by the Red Hat Satellite server.
+// This callback is called even if certificate verification is disabled.
+func (c *APIClient) recordPeerCertificates(cs tls.ConnectionState) error {
+	c.peerCertsMutex.Lock()
+	defer c.peerCertsMutex.Unlock()
+
+	c.peerCerts = cs.PeerCertificates
 
+	return nil
+}
+
+// PeerCertificates returns the certificate chain presented by the Red Hat
+// Satellite server during the most recent TLS handshake. An empty collection
+// is returned if a connection has not yet been established.
+func (c *APIClient) PeerCertificates() []*x509.Certificate {
+	if c == nil || c.peerCertsMutex == nil {
+		return nil
+	}
+
+	c.peerCertsMutex.Lock()
+	defer c.peerCertsMutex.Unlock()
+
+	certs := make([]*x509.Certificate, len(c.peerCerts))
+	copy(certs, c.peerCerts)
+
+	return certs
+}
+
+// RequestOptions is the collection of settings used to submit a request to a
+// Red Hat Satellite API endpoint.
+type RequestOptions struct {
+	// Logger is an optional logger used in place of the client logger
+	// (e.g., to provide additional context for a specific request).
+	Logger *zerolog.Logger
+
+	// QueryParams is the collection of URL query parameters applied to the
+	// request. Paginated endpoints require at least the per_page setting,
+	// but an empty collection is permitted for other endpoints.
+	QueryParams map[string]string
+
+	// Endpoint is the fully qualified API endpoint URL.
+	Endpoint string
+
+	// ExpectedStatus is the HTTP status code expected for a successful
+	// response. If not specified, http.StatusOK is expected. Other status
+	// codes within the success range are accepted, but logged.
+	ExpectedStatus int
+}
+
+// logger returns the logger to use for the request.
+func (opts RequestOptions) logger(client *APIClient) zerolog.Logger {
+	if opts.Logger != nil {
+		return *opts.Logger
+	}
+
+	return client.Logger
+}
+
+// expectedStatus returns the HTTP status code expected for a successful
+// response.
+func (opts RequestOptions) expectedStatus() int {
+	if opts.ExpectedStatus == 0 {
+		return http.StatusOK
+	}
+
+	return opts.ExpectedStatus
+}
+
+// SubmitRequest submits a request to an API endpoint using the given options
+// and performs basic validation of the response. The caller is responsible
+// for closing the response body.
+func (c *APIClient) SubmitRequest(ctx context.Context, opts RequestOptions) (*http.Response, error) {
+	if c == nil {
+		return nil, &PrepError{
+			Task:    PrepTaskPrepareRequest,
+			Message: "error preparing HTTP request",
+			Source:  opts.Endpoint,
+			Cause: fmt.Errorf(
+				"required API client was not provided: %w",
+				ErrMissingValue,
+			),
+		}
+	}
+
+	logger := opts.logger(c)
+	retry := c.Limits.Retry
+
+	var response *http.Response
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			delay := retry.retryDelay(attempt-1, err)
+
+			// Fail now instead of waiting for a retry which cannot complete
+			// before the deadline.
+			if !withinBudget(ctx, delay) {
+				logger.Debug().
+					Err(err).
+					Str("delay", delay.String()).
+					Msg("Retry delay exceeds remaining time; not retrying request")
+
+				return nil, fmt.Errorf("%w (%s): %w", ErrRetryBudgetExceeded, delay, err)
+			}
+
+			logger.Warn().
+				Err(err).
+				Int("attempt", attempt).
+				Int("max_attempts", retry.maxAttempts()).
+				Str("delay", delay.String()).
+				Msg("Retrying request after transient failure")
+
+			if waitErr := waitForRetry(ctx, delay); waitErr != nil {
+				return nil, fmt.Errorf("retry of failed request aborted: %w: %w", waitErr, err)
+			}
+		}
+
+		response, err = c.submitRequest(ctx, opts, logger)
+		if !retry.shouldRetry(ctx, err, attempt) {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// submitRequest is a helper method used to submit a single request attempt
+// to an API endpoint and perform basic validation of the response. The
+// response body is closed if validation fails.
+func (c *APIClient) submitRequest(ctx context.Context, opts RequestOptions, logger zerolog.Logger) (*http.Response, error) {
 	logger.Debug().Msg("Preparing request for API query")
-	request, reqErr := prepareRequest(ctx, client, apiURL, apiURLQueryParams)
+	request, reqErr := c.prepareRequest(ctx, opts)
 	if reqErr != nil {
 		return nil, reqErr
 	}
 
 	logger.Debug().Msg("Submitting HTTP request")
-	response, respErr := client.Do(request)
+	requestStart := time.Now()
+	response, respErr := c.Do(request)
+	if c.metrics != nil {
+		c.metrics.record(metricsEndpoint(request.URL), time.Since(requestStart))
+	}
 	if respErr != nil {
 		return nil, respErr
 	}
 	logger.Debug().Msg("Successfully submitted HTTP request")
 
 	// Evaluate the response
-	validateErr := validateResponse(ctx, response, logger, client.AuthInfo.ReadLimit)
+	validateErr := c.validateResponse(ctx, response, opts)
 	if validateErr != nil {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Msg("error closing response body")
+		}
+
 		return nil, validateErr
 	}
 
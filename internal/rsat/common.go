@@ -7,6 +7,8 @@
 
 package rsat
 
+import "strings"
+
 // requestsCounterFunc is a helper function used to track the current request
 // number and the requests remaining for a collection.
 type requestsCounterFunc func() (int, int)
@@ -31,3 +33,35 @@ func newRequestsCounter(start int) requestsCounterFunc {
 		return issued, remaining
 	}
 }
+
+// setQueryParams is a helper function used to apply the optional query
+// settings to the given collection of query parameters. Settings which are
+// not specified are omitted.
+func (opts QueryOptions) setQueryParams(apiURLQueryParams map[string]string) error {
+	if search := strings.TrimSpace(opts.Search); search != "" {
+		apiURLQueryParams[APIEndpointURLQueryParamSearchKey] = search
+	}
+
+	return opts.Sort.setQueryParams(apiURLQueryParams)
+}
+
+// JoinSearch combines the given scoped search queries into a single query
+// requiring that all given queries match. Empty queries are ignored.
+func JoinSearch(queries ...string) string {
+	terms := make([]string, 0, len(queries))
+	for _, query := range queries {
+		if query = strings.TrimSpace(query); query != "" {
+			terms = append(terms, query)
+		}
+	}
+
+	if len(terms) == 1 {
+		return terms[0]
+	}
+
+	for i := range terms {
+		terms[i] = "(" + terms[i] + ")"
+	}
+
+	return strings.Join(terms, " and ")
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// conditionalResponsesDir is the subdirectory of the response cache
+// directory where validated responses are stored.
+const conditionalResponsesDir string = "responses"
+
+// conditionalTransport is a http.RoundTripper which submits conditional
+// requests using the validators (ETag and Last-Modified response headers)
+// provided by the Red Hat Satellite API for previous responses. If the
+// server indicates that a response has not been modified (304 status code),
+// the previous response body is reused instead of being transferred again.
+//
+// Responses are stored on disk within the response cache directory so that
+// validators are reused across executions. Only successful responses to GET
+// requests providing at least one validator are stored.
+type conditionalTransport struct {
+	next      http.RoundTripper
+	dir       string
+	username  string
+	readLimit int64
+	logger    zerolog.Logger
+}
+
+// conditionalResponse is the encoded form of a stored response.
+type conditionalResponse struct {
+	// URL is the request URL for the response.
+	URL string `json:"url"`
+
+	// ETag is the entity tag validator provided for the response.
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified is the modification date validator provided for the
+	// response.
+	LastModified string `json:"last_modified,omitempty"`
+
+	// ContentType is the media type of the response body.
+	ContentType string `json:"content_type,omitempty"`
+
+	// Body is the response body.
+	Body []byte `json:"body"`
+}
+
+// path returns the path to the file storing the response for the given
+// request URL. The user is included when deriving the file name as the
+// response content may vary by user permissions.
+func (t conditionalTransport) path(requestURL string) string {
+	sum := sha256.Sum256([]byte(t.username + "\n" + requestURL))
+
+	return filepath.Join(t.dir, conditionalResponsesDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load retrieves the stored response for the given request URL (if any).
+func (t conditionalTransport) load(requestURL string) (conditionalResponse, bool) {
+	content, err := os.ReadFile(t.path(requestURL))
+	if err != nil {
+		return conditionalResponse{}, false
+	}
+
+	var stored conditionalResponse
+	if err := json.Unmarshal(content, &stored); err != nil || stored.URL != requestURL {
+		return conditionalResponse{}, false
+	}
+
+	return stored, true
+}
+
+// store saves the given response for later reuse. The file is replaced
+// atomically so that concurrent readers do not observe a partially written
+// file.
+func (t conditionalTransport) store(stored conditionalResponse) error {
+	content, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode stored response: %w", err)
+	}
+
+	dir := filepath.Join(t.dir, conditionalResponsesDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create response cache directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary response file: %w", err)
+	}
+
+	// Remove the temporary file if it was not renamed.
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temporary response file: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary response file: %w", err)
+	}
+
+	return os.Rename(tmpFile.Name(), t.path(stored.URL))
+}
+
+// RoundTrip satisfies the http.RoundTripper interface by submitting a
+// conditional request if validators are available for a previous response.
+func (t conditionalTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		return t.next.RoundTrip(request)
+	}
+
+	requestURL := request.URL.String()
+	logger := t.logger.With().Str("url", requestURL).Logger()
+
+	stored, found := t.load(requestURL)
+	if found {
+		// Requests are cloned as RoundTrippers must not modify the given
+		// request.
+		request = request.Clone(request.Context())
+
+		if stored.ETag != "" {
+			request.Header.Set("If-None-Match", stored.ETag)
+		}
+
+		if stored.LastModified != "" {
+			request.Header.Set("If-Modified-Since", stored.LastModified)
+		}
+	}
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && found:
+		logger.Debug().Msg("Response not modified; reusing stored response")
+
+		_ = response.Body.Close()
+
+		response.Status = fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK))
+		response.StatusCode = http.StatusOK
+		response.ContentLength = int64(len(stored.Body))
+		response.Body = io.NopCloser(bytes.NewReader(stored.Body))
+		response.Header.Set("Content-Length", strconv.Itoa(len(stored.Body)))
+		if stored.ContentType != "" {
+			response.Header.Set("Content-Type", stored.ContentType)
+		}
+
+		return response, nil
+
+	case response.StatusCode != http.StatusOK:
+		return response, nil
+	}
+
+	etag := response.Header.Get("ETag")
+	lastModified := response.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return response, nil
+	}
+
+	// Read one byte beyond the read limit so that oversized responses are
+	// passed through as-is (and rejected by the caller) instead of stored.
+	body, readErr := io.ReadAll(io.LimitReader(response.Body, t.readLimit+1))
+	_ = response.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	if int64(len(body)) > t.readLimit {
+		return response, nil
+	}
+
+	storeErr := t.store(conditionalResponse{
+		URL:          requestURL,
+		ETag:         etag,
+		LastModified: lastModified,
+		ContentType:  response.Header.Get("Content-Type"),
+		Body:         body,
+	})
+	if storeErr != nil {
+		logger.Warn().Err(storeErr).Msg("Failed to store response for conditional requests")
+	}
+
+	return response, nil
+}
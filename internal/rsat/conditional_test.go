@@ -0,0 +1,77 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestConditionalRequestsReuseNotModifiedResponses(t *testing.T) {
+	const body = `{"result":"ok","version":"3.5.1.23"}`
+	const etag = `"v1"`
+
+	var full, notModified int
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		full++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	authInfo := APIAuthInfo{
+		Username:         "monitoring",
+		Password:         "secret",
+		NetworkType:      "auto",
+		ReadLimit:        1024,
+		TrustCert:        true,
+		ResponseCacheDir: t.TempDir(),
+	}
+
+	for i := 0; i < 2; i++ {
+		// A new client is used for each request to emulate separate
+		// plugin executions.
+		client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+		response, err := client.SubmitRequest(context.Background(), RequestOptions{
+			Endpoint:    ts.URL + "/api/v2/status",
+			QueryParams: map[string]string{},
+		})
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i+1, err)
+		}
+
+		got, err := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to read response %d: %v", i+1, err)
+		}
+
+		if string(got) != body {
+			t.Errorf("got response %d body %q, want %q", i+1, got, body)
+		}
+	}
+
+	if full != 1 || notModified != 1 {
+		t.Errorf("got %d full and %d not modified responses, want 1 of each", full, notModified)
+	}
+}
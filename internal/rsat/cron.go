@@ -0,0 +1,313 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit is the maximum span of time searched for a scheduled
+// execution time. Schedules which never match (e.g., February 30th) are
+// abandoned once this limit is reached.
+const cronSearchLimit time.Duration = 5 * 366 * 24 * time.Hour
+
+// cronField describes the valid range of values and supported names for a
+// single field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{
+		name: "month", min: 1, max: 12,
+		names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		},
+	}
+
+	// Both 0 and 7 are accepted for Sunday.
+	cronDayOfWeek = cronField{
+		name: "day of week", min: 0, max: 7,
+		names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		},
+	}
+)
+
+// cronAliases maps supported shorthand expressions to their equivalent
+// five-field cron expression.
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule is a parsed standard five-field cron expression (minute,
+// hour, day of month, month, day of week) as used by Red Hat Satellite sync
+// plans with a custom cron interval.
+type CronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// anyDayOfMonth and anyDayOfWeek record whether the day of month or day
+	// of week fields were unrestricted ("*"). Per cron convention, if both
+	// fields are restricted a day matches if either field matches.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCronExpression parses the given standard five-field cron expression.
+// Lists (1,15), ranges (1-5), steps (*/15, 0-30/10), month and day of week
+// names (jan, mon) and common aliases (e.g., @daily) are supported.
+func ParseCronExpression(expression string) (CronSchedule, error) {
+	expr := strings.TrimSpace(expression)
+	if alias, ok := cronAliases[strings.ToLower(expr)]; ok {
+		expr = alias
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return CronSchedule{}, fmt.Errorf(
+			"cron expression %q has %d fields, expected 5: %w",
+			expression,
+			len(fields),
+			ErrInvalidValue,
+		)
+	}
+
+	var schedule CronSchedule
+
+	parsers := []struct {
+		dst   *map[int]bool
+		field cronField
+		value string
+	}{
+		{dst: &schedule.minutes, field: cronMinute, value: fields[0]},
+		{dst: &schedule.hours, field: cronHour, value: fields[1]},
+		{dst: &schedule.daysOfMonth, field: cronDayOfMonth, value: fields[2]},
+		{dst: &schedule.months, field: cronMonth, value: fields[3]},
+		{dst: &schedule.daysOfWeek, field: cronDayOfWeek, value: fields[4]},
+	}
+
+	for _, p := range parsers {
+		values, err := p.field.parse(p.value)
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf(
+				"failed to parse cron expression %q: %w",
+				expression,
+				err,
+			)
+		}
+		*p.dst = values
+	}
+
+	// Normalize Sunday to 0 to match time.Weekday values.
+	if schedule.daysOfWeek[7] {
+		schedule.daysOfWeek[0] = true
+		delete(schedule.daysOfWeek, 7)
+	}
+
+	schedule.anyDayOfMonth = fields[2] == "*" || fields[2] == "?"
+	schedule.anyDayOfWeek = fields[4] == "*" || fields[4] == "?"
+
+	return schedule, nil
+}
+
+// parse parses a single (comma-separated) cron expression field into the set
+// of matching values.
+func (f cronField) parse(field string) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf(
+					"invalid step %q for %s field: %w",
+					stepExpr,
+					f.name,
+					ErrInvalidValue,
+				)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+			start, end = f.min, f.max
+
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+
+			var err error
+			if start, err = f.value(startExpr); err != nil {
+				return nil, err
+			}
+			if end, err = f.value(endExpr); err != nil {
+				return nil, err
+			}
+
+			if end < start {
+				return nil, fmt.Errorf(
+					"invalid range %q for %s field: %w",
+					rangeExpr,
+					f.name,
+					ErrInvalidValue,
+				)
+			}
+
+		default:
+			var err error
+			if start, err = f.value(rangeExpr); err != nil {
+				return nil, err
+			}
+
+			// A single value with a step (e.g., 5/15) applies the step
+			// through the end of the valid range.
+			end = start
+			if hasStep {
+				end = f.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// value converts a single cron field value (number or name) to an integer
+// within the valid range for the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf(
+			"invalid value %q for %s field (valid range %d-%d): %w",
+			s,
+			f.name,
+			f.min,
+			f.max,
+			ErrInvalidValue,
+		)
+	}
+
+	return v, nil
+}
+
+// matchesDay indicates whether the given time falls on a scheduled day.
+func (cs CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := cs.daysOfMonth[t.Day()]
+	dowMatch := cs.daysOfWeek[int(t.Weekday())]
+
+	switch {
+	case cs.anyDayOfMonth && cs.anyDayOfWeek:
+		return true
+	case cs.anyDayOfMonth:
+		return dowMatch
+	case cs.anyDayOfWeek:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// Prev returns the most recent scheduled execution time at or before the
+// given time. A boolean value is returned to indicate whether a scheduled
+// execution time was found. Times are evaluated in the location of the
+// given time.
+func (cs CronSchedule) Prev(before time.Time) (time.Time, bool) {
+	loc := before.Location()
+	floor := before.Add(-cronSearchLimit)
+
+	t := before.Truncate(time.Minute)
+
+	for !t.Before(floor) {
+		switch {
+		case !cs.months[int(t.Month())]:
+			// Move to the last minute of the previous month.
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+
+		case !cs.matchesDay(t):
+			// Move to the last minute of the previous day.
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+
+		case !cs.hours[t.Hour()]:
+			// Move to the last minute of the previous hour.
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+
+		case !cs.minutes[t.Minute()]:
+			t = t.Add(-time.Minute)
+
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Next returns the earliest scheduled execution time after the given time.
+// A boolean value is returned to indicate whether a scheduled execution time
+// was found. Times are evaluated in the location of the given time.
+func (cs CronSchedule) Next(after time.Time) (time.Time, bool) {
+	loc := after.Location()
+	ceiling := after.Add(cronSearchLimit)
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(ceiling) {
+		switch {
+		case !cs.months[int(t.Month())]:
+			// Move to the first minute of the next month.
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+
+		case !cs.matchesDay(t):
+			// Move to the first minute of the next day.
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+
+		case !cs.hours[t.Hour()]:
+			// Move to the first minute of the next hour.
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+
+		case !cs.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
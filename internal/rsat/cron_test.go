@@ -0,0 +1,178 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCronExpressionInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range tests {
+		if _, err := ParseCronExpression(expr); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("ParseCronExpression(%q): got error %v, want %v", expr, err, ErrInvalidValue)
+		}
+	}
+}
+
+func TestCronSchedulePrevNext(t *testing.T) {
+	// Wednesday
+	ref := time.Date(2023, time.March, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		prev time.Time
+		next time.Time
+	}{
+		{
+			expr: "*/15 * * * *",
+			prev: time.Date(2023, time.March, 15, 10, 0, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 2 * * *",
+			prev: time.Date(2023, time.March, 15, 2, 0, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 16, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "@daily",
+			prev: time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			expr: "30 1 * * sun",
+			prev: time.Date(2023, time.March, 12, 1, 30, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 19, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			expr: "30 1 * * 7",
+			prev: time.Date(2023, time.March, 12, 1, 30, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 19, 1, 30, 0, 0, time.UTC),
+		},
+		{
+			expr: "0 0 1 jan-mar,oct *",
+			prev: time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC),
+			next: time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Day of month and day of week are matched using OR logic when
+			// both fields are restricted.
+			expr: "0 12 20 * mon",
+			prev: time.Date(2023, time.March, 13, 12, 0, 0, 0, time.UTC),
+			next: time.Date(2023, time.March, 20, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseCronExpression(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCronExpression(%q): unexpected error: %v", tt.expr, err)
+		}
+
+		if got, ok := schedule.Prev(ref); !ok || !got.Equal(tt.prev) {
+			t.Errorf("%q: Prev() = %v (%t), want %v", tt.expr, got, ok, tt.prev)
+		}
+
+		if got, ok := schedule.Next(ref); !ok || !got.Equal(tt.next) {
+			t.Errorf("%q: Next() = %v (%t), want %v", tt.expr, got, ok, tt.next)
+		}
+	}
+}
+
+func TestCronScheduleNeverMatches(t *testing.T) {
+	schedule, err := ParseCronExpression("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, ok := schedule.Next(time.Now()); ok {
+		t.Errorf("Next() = %v, want no match", got)
+	}
+}
+
+func TestSyncPlanIsStuckCron(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 10, 7, 0, 0, time.UTC)
+	created := SyncTime(time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		expr     string
+		nextSync time.Time
+		want     bool
+	}{
+		{
+			name:     "next sync after last expected run",
+			expr:     "0 2 * * *",
+			nextSync: time.Date(2023, time.March, 16, 2, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "next sync not advanced past last expected run",
+			expr:     "0 2 * * *",
+			nextSync: time.Date(2023, time.March, 15, 2, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+		{
+			name: "past next sync within cron interval",
+			expr: "0 0 1 * *",
+			// Reported inconsistently as slightly in the past, but the most
+			// recent expected run (March 1st) has not been missed.
+			nextSync: time.Date(2023, time.March, 14, 0, 0, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "expected run within grace period",
+			expr:     "5 * * * *",
+			nextSync: time.Date(2023, time.March, 15, 10, 5, 0, 0, time.UTC),
+			want:     false,
+		},
+		{
+			name:     "missing next sync",
+			expr:     "0 2 * * *",
+			nextSync: time.Time{},
+			want:     true,
+		},
+		{
+			name:     "invalid expression falls back to next sync",
+			expr:     "not a cron expression",
+			nextSync: time.Date(2023, time.March, 14, 0, 0, 0, 0, time.UTC),
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := SyncPlan{
+				Enabled:          true,
+				Interval:         SyncPlanIntervalCustomCron,
+				CronExpression:   NullString(tt.expr),
+				OriginalSyncDate: created,
+				NextSync:         SyncTime(tt.nextSync),
+			}
+
+			if got := sp.IsStuck(now); got != tt.want {
+				t.Errorf("IsStuck() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ package rsat
 import (
 	"encoding/json"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -84,6 +85,13 @@ const (
 	//
 	// See also https://rsat.example.com/apidoc/v2/sync_plans/index.html
 	LegacySyncTimeLayout string = "2006/01/02 15:04:05 -0700"
+
+	// ISO8601TimeLayout is the time layout format as used by Candlepin
+	// derived properties (e.g., subscription manifest history) in the Red
+	// Hat Satellite API.
+	//
+	// Example: "created": "2024-05-10T15:16:00+0000"
+	ISO8601TimeLayout string = "2006-01-02T15:04:05-0700"
 )
 
 // StandardAPITime is time value as represented in the Red Hat Satellite API
@@ -96,20 +104,68 @@ type StandardAPITime time.Time
 // format.
 type SyncTime time.Time
 
-// String implements the fmt.Stringer interface as a convenience method.
+// displayLocation is the location (time zone) used when rendering time
+// values for display. The local time zone is used if not set.
+var displayLocation atomic.Pointer[time.Location]
+
+// SetDisplayLocation sets the location (time zone) used when rendering time
+// values for display (e.g., in reports). This is intended to be called once
+// during application startup. The local time zone is used if the given
+// location is nil.
+func SetDisplayLocation(loc *time.Location) {
+	displayLocation.Store(loc)
+}
+
+// DisplayLocation returns the location (time zone) used when rendering time
+// values for display.
+func DisplayLocation() *time.Location {
+	if loc := displayLocation.Load(); loc != nil {
+		return loc
+	}
+
+	return time.Local
+}
+
+// String implements the fmt.Stringer interface as a convenience method. The
+// time value is rendered using the display location.
 func (dt StandardAPITime) String() string {
-	return dt.Format(StandardAPITimeLayoutWithOffset)
+	return time.Time(dt).In(DisplayLocation()).Format(StandardAPITimeLayoutWithOffset)
 }
 
-// String implements the fmt.Stringer interface as a convenience method.
+// String implements the fmt.Stringer interface as a convenience method. The
+// time value is rendered using the display location.
 func (dt SyncTime) String() string {
 	// return dt.Format(StandardAPITimeLayout)
 	switch {
 	case time.Time(dt).IsZero():
 		return "Not scheduled"
 	default:
-		return time.Time(dt).Local().Format(StandardAPITimeLayoutWithOffset)
+		return time.Time(dt).In(DisplayLocation()).Format(StandardAPITimeLayoutWithOffset)
+	}
+}
+
+// RFC3339 provides the time value in RFC 3339 format (UTC) for use in
+// machine-readable output. An empty string is returned if the time value is
+// not set.
+func (dt StandardAPITime) RFC3339() string {
+	return formatRFC3339(time.Time(dt))
+}
+
+// RFC3339 provides the time value in RFC 3339 format (UTC) for use in
+// machine-readable output. An empty string is returned if the time value is
+// not set (e.g., a sync plan which is not scheduled).
+func (dt SyncTime) RFC3339() string {
+	return formatRFC3339(time.Time(dt))
+}
+
+// formatRFC3339 is a helper function used to format the given time value in
+// RFC 3339 format (UTC). An empty string is returned for the zero value.
+func formatRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+
+	return t.UTC().Format(time.RFC3339)
 }
 
 // Format calls (time.Time).Format as a convenience for the caller.
@@ -180,6 +236,15 @@ func (dt *SyncTime) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// isLegacySyncTimeValue is a helper function used to determine whether the
+// given next_sync value uses the time layout of legacy Red Hat Satellite
+// versions. Legacy versions render this value with minute precision.
+func isLegacySyncTimeValue(value string) bool {
+	_, err := time.Parse(LegacySyncTimeLayout, value)
+
+	return err == nil
+}
+
 // parseDate is a helper function that attempts to handle all known datetime
 // formats for legacy and current Red Hat Satellite APIs. An error is returned
 // if the given datetime string does not match a known layout.
@@ -190,6 +255,8 @@ func parseDate(datetime string) (time.Time, error) {
 		SyncTimeLayoutWithTimezone,
 		SyncTimeLayoutWithOffset,
 		LegacySyncTimeLayout,
+		ISO8601TimeLayout,
+		time.RFC3339,
 	}
 
 	var result time.Time
@@ -8,16 +8,103 @@
 package rsat
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+
+	"github.com/atc0005/check-rsat/internal/netutils"
 )
 
+// IsAuthenticationFailure indicates whether the given error was caused by the
+// Red Hat Satellite server rejecting the provided credentials.
+func IsAuthenticationFailure(err error) bool {
+	return errors.Is(err, ErrAuthenticationFailed)
+}
+
+// IsMaintenanceMode indicates whether the given error was caused by the Red
+// Hat Satellite server reporting that it is in maintenance mode.
+func IsMaintenanceMode(err error) bool {
+	return errors.Is(err, ErrMaintenanceMode)
+}
+
+// IsConnectionFailure indicates whether the given error was caused by a
+// failure to connect to or communicate with the Red Hat Satellite server
+// (e.g., name resolution, network connectivity, TLS handshake or timeout
+// failures) as opposed to a problem with the retrieved sync plans.
+func IsConnectionFailure(err error) bool {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, netutils.ErrNetworkConnectionFailed):
+		return true
+	case errors.Is(err, ErrServerUnavailable):
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &netErr):
+		return true
+	default:
+		_, certFailure := CertVerificationFailure(err)
+
+		return certFailure
+	}
+}
+
 // FIXME: Should we consistently use the PrepError type instead of using these
 // sentinel errors?
 var (
 	// ErrMissingValue indicates that an expected value was missing.
 	ErrMissingValue = errors.New("missing expected value")
 
+	// ErrInvalidValue indicates that a provided value was not supported.
+	ErrInvalidValue = errors.New("invalid value")
+
+	// ErrReadLimitReached indicates that the read limit was reached before a
+	// complete response could be read; the response was truncated.
+	ErrReadLimitReached = errors.New("read limit reached")
+
+	// ErrAuthenticationFailed indicates that the Red Hat Satellite server
+	// rejected the provided credentials (or the user lacks permission to
+	// access the requested resource). Requests resulting in this error are
+	// not retried as repeated attempts with invalid credentials risk account
+	// lockout.
+	ErrAuthenticationFailed = errors.New("authentication failed")
+
+	// ErrMaintenanceMode indicates that the Red Hat Satellite server is in
+	// maintenance mode (e.g., enabled via foreman-maintain during a patch
+	// window) and is not servicing API requests.
+	ErrMaintenanceMode = errors.New("server in maintenance mode")
+
+	// ErrServerUnavailable indicates that the Red Hat Satellite server (or a
+	// proxy in front of it) responded with a gateway error or reported that
+	// it is temporarily unavailable. Requests resulting in this error are
+	// retried if retries are enabled.
+	ErrServerUnavailable = errors.New("server temporarily unavailable")
+
+	// ErrRateLimited indicates that the Red Hat Satellite server (or a proxy
+	// in front of it) rejected a request because too many requests were
+	// submitted. Requests resulting in this error are retried after the
+	// delay requested by the server if retries are enabled.
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrRetryBudgetExceeded indicates that a failed request was not retried
+	// because the retry delay exceeds the time remaining before the
+	// context deadline.
+	ErrRetryBudgetExceeded = errors.New("retry delay exceeds remaining time")
+
+	// ErrRequestRejected indicates that the Red Hat Satellite server (or a
+	// proxy in front of it) rejected a request as invalid or too large
+	// (e.g., due to an unsupported per-page limit).
+	ErrRequestRejected = errors.New("request rejected")
+
+	// ErrEndpointNotFound indicates that the Red Hat Satellite server
+	// responded that the requested API endpoint (or object) was not found.
+	ErrEndpointNotFound = errors.New("endpoint not found")
+
+	// ErrOrgNotFound indicates that a requested organization was not found.
+	ErrOrgNotFound = errors.New("organization not found")
+
 	// ErrHTTPResponseOutsideRange indicates that a response was received
 	// which falls outside of an acceptable range.
 	ErrHTTPResponseOutsideRange = errors.New("response is outside acceptable range")
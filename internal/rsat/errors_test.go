@@ -0,0 +1,58 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/atc0005/check-rsat/internal/netutils"
+)
+
+func TestIsConnectionFailure(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"network connection failure": {
+			err:  fmt.Errorf("failed to connect: %w", netutils.ErrNetworkConnectionFailed),
+			want: true,
+		},
+		"name resolution failure": {
+			err:  fmt.Errorf("lookup failed: %w", &net.DNSError{Err: "no such host", Name: "rsat.example.com"}),
+			want: true,
+		},
+		"timeout": {
+			err:  fmt.Errorf("request aborted: %w", context.DeadlineExceeded),
+			want: true,
+		},
+		"server unavailable": {
+			err:  fmt.Errorf("%w: 503 Service Unavailable", ErrServerUnavailable),
+			want: true,
+		},
+		"authentication failure": {
+			err:  fmt.Errorf("%w: 401 Unauthorized", ErrAuthenticationFailed),
+			want: false,
+		},
+		"unrelated error": {
+			err:  errors.New("unexpected response"),
+			want: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsConnectionFailure(tt.err); got != tt.want {
+				t.Errorf("got %t, want %t for %v", got, tt.want, tt.err)
+			}
+		})
+	}
+}
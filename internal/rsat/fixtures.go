@@ -0,0 +1,80 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fixtureFileExt is the file extension used for saved API responses.
+const fixtureFileExt string = ".json"
+
+// fixturesTransport is a http.RoundTripper which provides saved (fixture)
+// API responses from disk instead of submitting requests over the network.
+//
+// Each API response is read from a file named for the API endpoint path
+// within the fixtures directory (e.g., api/v2/organizations.json for the
+// /api/v2/organizations endpoint). Pages beyond the first page of results
+// are read from files with a page suffix (e.g.,
+// api/v2/organizations.page-2.json). Other URL query parameters are ignored.
+type fixturesTransport struct {
+	dir string
+}
+
+// FixturePath returns the path to the file within the given fixtures
+// directory providing the saved API response for the given request URL
+// path and page number.
+func FixturePath(dir string, urlPath string, page int) string {
+	name := strings.Trim(path.Clean("/"+urlPath), "/")
+
+	if page > 1 {
+		name += ".page-" + strconv.Itoa(page)
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(name)+fixtureFileExt)
+}
+
+// RoundTrip satisfies the http.RoundTripper interface by providing the saved
+// API response for the given request.
+func (t fixturesTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	page := 1
+	if value := request.URL.Query().Get(APIEndpointURLQueryParamPageKey); value != "" {
+		var err error
+		page, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page number %q: %w", value, ErrInvalidValue)
+		}
+	}
+
+	fixture := FixturePath(t.dir, request.URL.Path, page)
+
+	content, err := os.ReadFile(filepath.Clean(fixture))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved API response: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusOK, http.StatusText(http.StatusOK)),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(content)),
+		ContentLength: int64(len(content)),
+		Request:       request,
+	}, nil
+}
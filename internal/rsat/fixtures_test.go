@@ -0,0 +1,78 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestFixturePath(t *testing.T) {
+	tests := []struct {
+		urlPath string
+		page    int
+		want    string
+	}{
+		{urlPath: "/api/v2/organizations", page: 1, want: filepath.Join("fx", "api", "v2", "organizations.json")},
+		{urlPath: "/api/v2/organizations", page: 0, want: filepath.Join("fx", "api", "v2", "organizations.json")},
+		{urlPath: "/api/v2/organizations/", page: 3, want: filepath.Join("fx", "api", "v2", "organizations.page-3.json")},
+		{urlPath: "/../../etc/passwd", page: 1, want: filepath.Join("fx", "etc", "passwd.json")},
+	}
+
+	for _, tt := range tests {
+		if got := FixturePath("fx", tt.urlPath, tt.page); got != tt.want {
+			t.Errorf("FixturePath(%q, %d) = %q, want %q", tt.urlPath, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestGetOrgsWithSyncPlansFromFixtures(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		FixturesDir: filepath.Join("testdata", "fixtures"),
+	}
+
+	// A page limit of one requires reading multiple pages of sync plans.
+	client := NewAPIClient(authInfo, APILimits{PerPage: 1}, zerolog.Nop())
+
+	orgs, _, err := GetFilteredOrgsWithSyncPlans(context.Background(), client, QueryOptions{}, OrgFilter{})
+	if err != nil {
+		t.Fatalf("failed to read organizations from fixtures: %v", err)
+	}
+
+	if got := orgs.NumOrgs(); got != 1 {
+		t.Errorf("got %d organizations, want 1", got)
+	}
+
+	if got := orgs.NumPlans(); got != 2 {
+		t.Errorf("got %d sync plans, want 2", got)
+	}
+}
+
+func TestGetOrgsFromMissingFixtures(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		FixturesDir: t.TempDir(),
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+	_, _, err := GetFilteredOrgsWithSyncPlans(context.Background(), client, QueryOptions{}, OrgFilter{})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v, want %v", err, fs.ErrNotExist)
+	}
+}
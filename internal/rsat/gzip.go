@@ -0,0 +1,103 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipEncoding is the content coding used to request compressed responses.
+const gzipEncoding string = "gzip"
+
+// gzipTransport is a http.RoundTripper which explicitly requests gzip
+// compressed responses from the Red Hat Satellite API to reduce transfer
+// time over slow (e.g., WAN) links and transparently decompresses them.
+// Decompressed response bodies are provided to callers so that the read
+// limit applies to decompressed bytes.
+type gzipTransport struct {
+	next http.RoundTripper
+}
+
+// gzipReadCloser decompresses a response body and closes both the
+// decompressor and the underlying response body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the decompressor and the underlying response body.
+func (rc gzipReadCloser) Close() error {
+	gzErr := rc.Reader.Close()
+	if err := rc.body.Close(); err != nil {
+		return err
+	}
+
+	return gzErr
+}
+
+// RoundTrip satisfies the http.RoundTripper interface by requesting a gzip
+// compressed response and decompressing the response if compressed.
+func (t gzipTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Leave requests which explicitly specify content codings as-is.
+	if request.Header.Get("Accept-Encoding") == "" {
+		// Requests are cloned as RoundTrippers must not modify the given
+		// request.
+		request = request.Clone(request.Context())
+		request.Header.Set("Accept-Encoding", gzipEncoding)
+	}
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(response.Header.Get("Content-Encoding"), gzipEncoding) {
+		return response, nil
+	}
+
+	// Responses without a body (e.g., 304 Not Modified responses for
+	// conditional requests) are left as-is; the same guard is applied by
+	// the net/http package when it decompresses responses.
+	if !hasResponseBody(request, response) {
+		return response, nil
+	}
+
+	gzReader, gzErr := gzip.NewReader(response.Body)
+	if gzErr != nil {
+		_ = response.Body.Close()
+
+		return nil, fmt.Errorf("failed to decompress gzip response: %w", gzErr)
+	}
+
+	response.Body = gzipReadCloser{Reader: gzReader, body: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+
+	return response, nil
+}
+
+// hasResponseBody indicates whether the given response to the given request
+// may provide a body.
+func hasResponseBody(request *http.Request, response *http.Response) bool {
+	switch {
+	case request.Method == http.MethodHead:
+		return false
+	case response.StatusCode == http.StatusNoContent, response.StatusCode == http.StatusNotModified:
+		return false
+	case response.ContentLength == 0:
+		return false
+	default:
+		return true
+	}
+}
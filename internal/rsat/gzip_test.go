@@ -0,0 +1,136 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestGzipResponsesDecompressed(t *testing.T) {
+	// A highly compressible response larger than the read limit once
+	// decompressed, but smaller than the read limit while compressed.
+	payload := `{"result":"ok","version":"` + strings.Repeat("a", 4096) + `"}`
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != gzipEncoding {
+			t.Errorf("got Accept-Encoding %q, want %q", r.Header.Get("Accept-Encoding"), gzipEncoding)
+		}
+
+		w.Header().Set("Content-Encoding", gzipEncoding)
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(payload))
+		_ = gz.Close()
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name      string
+		readLimit int64
+		wantErr   error
+	}{
+		{name: "within read limit", readLimit: 8192},
+		{name: "read limit applies to decompressed bytes", readLimit: 1024, wantErr: ErrReadLimitReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authInfo := APIAuthInfo{
+				Username:    "monitoring",
+				Password:    "secret",
+				NetworkType: "auto",
+				ReadLimit:   tt.readLimit,
+				TrustCert:   true,
+			}
+
+			client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+			response, err := client.SubmitRequest(context.Background(), RequestOptions{
+				Endpoint:    ts.URL + "/api/v2/status",
+				QueryParams: map[string]string{},
+			})
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer func() { _ = response.Body.Close() }()
+
+			var status StatusResponse
+			err = decode(&status, response.Body, zerolog.Nop(), "status", tt.readLimit)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+
+			case err != nil:
+				t.Errorf("failed to decode response: %v", err)
+
+			case status.Result != "ok":
+				t.Errorf("got result %q, want %q", status.Result, "ok")
+			}
+		})
+	}
+}
+
+func TestGzipResponsesWithoutBody(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", gzipEncoding)
+
+		switch r.URL.Path {
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Length", "0")
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "not modified", method: http.MethodGet, path: "/not-modified", wantStatus: http.StatusNotModified},
+		{name: "no content", method: http.MethodGet, path: "/no-content", wantStatus: http.StatusNoContent},
+		{name: "empty body", method: http.MethodGet, path: "/empty", wantStatus: http.StatusOK},
+		{name: "head request", method: http.MethodHead, path: "/head", wantStatus: http.StatusOK},
+	}
+
+	transport := gzipTransport{next: ts.Client().Transport}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequestWithContext(context.Background(), tt.method, ts.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("failed to prepare request: %v", err)
+			}
+
+			response, err := transport.RoundTrip(request)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer func() { _ = response.Body.Close() }()
+
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("got status %d, want %d", response.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HostCollectionsResponse represents the API response from a request of all
+// host collections for a specific organization.
+type HostCollectionsResponse struct {
+	Error NullString `json:"error"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// HostCollections is the collection of Host Collections returned in the
+	// API query response.
+	HostCollections HostCollections `json:"results"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the host collections provided by the response along
+// with the number of host collections matching the query across all pages.
+func (r HostCollectionsResponse) pageResults() ([]HostCollection, int) {
+	return r.HostCollections, r.Subtotal
+}
+
+// HostCollection is a named group of content hosts within a Red Hat
+// Satellite organization.
+type HostCollection struct {
+	CreatedAt         StandardAPITime `json:"created_at"`
+	UpdatedAt         StandardAPITime `json:"updated_at"`
+	MaxHosts          *int            `json:"max_hosts"` // null if unlimited
+	Description       NullString      `json:"description"`
+	Name              string          `json:"name"`
+	OrganizationName  string          `json:"-"`
+	OrganizationLabel string          `json:"-"`
+	OrganizationTitle string          `json:"-"`
+	HostIDs           []int           `json:"host_ids"`
+	ID                int             `json:"id"`
+	OrganizationID    int             `json:"organization_id"`
+	TotalHosts        int             `json:"total_hosts"`
+	UnlimitedHosts    bool            `json:"unlimited_hosts"`
+}
+
+// HostCollections is a collection of Red Hat Satellite host collections.
+type HostCollections []HostCollection
+
+// GetHostCollections uses the provided APIClient to retrieve all host
+// collections for each specified Red Hat Satellite organization. If no
+// organizations are specified then an attempt will be made to retrieve host
+// collections from all RSAT organizations. The given query options (e.g., a
+// scoped search of name ~ web) are used to limit and order the host
+// collections retrieved.
+func GetHostCollections(ctx context.Context, client *APIClient, opts QueryOptions, orgs ...Organization) (HostCollections, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	if len(orgs) == 0 {
+		var orgsErr error
+		orgs, orgsErr = GetOrganizations(ctx, client, QueryOptions{})
+		if orgsErr != nil {
+			return nil, orgsErr
+		}
+	}
+
+	allHostCollections := make(HostCollections, 0, len(orgs)*3)
+
+	for _, org := range orgs {
+		hostCollections, err := getOrgHostCollections(ctx, client, opts, org)
+		if err != nil {
+			return nil, err
+		}
+
+		allHostCollections = append(allHostCollections, hostCollections...)
+	}
+
+	logger.Debug().
+		Int("host_collections", len(allHostCollections)).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed host collections retrieval for all requested organizations")
+
+	return allHostCollections, nil
+}
+
+// HasHost indicates whether the host with the given ID is a member of the
+// host collection.
+func (hc HostCollection) HasHost(hostID int) bool {
+	for _, id := range hc.HostIDs {
+		if id == hostID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsFull indicates whether the host collection has reached its host limit.
+func (hc HostCollection) IsFull() bool {
+	if hc.UnlimitedHosts || hc.MaxHosts == nil {
+		return false
+	}
+
+	return hc.TotalHosts >= *hc.MaxHosts
+}
+
+// Lookup returns the host collection with the given name from the specified
+// organization. A boolean value is returned to indicate whether a match was
+// found.
+func (hcs HostCollections) Lookup(orgID int, name string) (HostCollection, bool) {
+	for _, hc := range hcs {
+		if hc.OrganizationID == orgID && hc.Name == name {
+			return hc, true
+		}
+	}
+
+	return HostCollection{}, false
+}
+
+// ForHost returns a new collection containing all host collections from the
+// original collection which have the host with the given ID as a member.
+func (hcs HostCollections) ForHost(hostID int) HostCollections {
+	matches := make(HostCollections, 0, len(hcs))
+
+	for _, hc := range hcs {
+		if hc.HasHost(hostID) {
+			matches = append(matches, hc)
+		}
+	}
+
+	return matches
+}
+
+// getOrgHostCollections retrieves all host collections for the given
+// organization.
+func getOrgHostCollections(ctx context.Context, client *APIClient, opts QueryOptions, org Organization) (HostCollections, error) {
+	funcTimeStart := time.Now()
+
+	subLogger := client.Logger.With().
+		Int("org_id", org.ID).
+		Str("org_name", org.Name).
+		Logger()
+
+	apiURL := fmt.Sprintf(
+		HostCollectionsAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+		org.ID,
+	)
+
+	allHostCollections, err := fetchAllPages[HostCollection, HostCollectionsResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		subLogger,
+		"host collections",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Annotate Host Collections with specific Org values for convenience.
+	for i := range allHostCollections {
+		allHostCollections[i].OrganizationName = org.Name
+		allHostCollections[i].OrganizationLabel = org.Label
+		allHostCollections[i].OrganizationTitle = org.Title
+	}
+
+	subLogger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all host collections for organization")
+
+	return allHostCollections, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// manifestHistoryStatusSuccess is the status value recorded for successful
+// subscription manifest import/refresh operations.
+const manifestHistoryStatusSuccess string = "SUCCESS"
+
+// KatelloOrganizationResponse represents the API response from a request for
+// Katello specific details of a specific organization.
+type KatelloOrganizationResponse struct {
+	OwnerDetails           OwnerDetails    `json:"owner_details"`
+	ManifestExpirationDate StandardAPITime `json:"manifest_expiration_date"`
+	Name                   string          `json:"name"`
+	ID                     int             `json:"id"`
+	ManifestExpired        bool            `json:"manifest_expired"`
+	ManifestExpiringSoon   bool            `json:"manifest_expiring_soon"`
+}
+
+// OwnerDetails represents the Candlepin owner details for a Red Hat
+// Satellite organization.
+type OwnerDetails struct {
+	UpstreamConsumer *UpstreamConsumer `json:"upstreamConsumer"`
+}
+
+// UpstreamConsumer represents the upstream (Red Hat Customer Portal)
+// subscription allocation associated with a Red Hat Satellite organization's
+// subscription manifest.
+type UpstreamConsumer struct {
+	UUID   string     `json:"uuid"`
+	Name   string     `json:"name"`
+	WebURL NullString `json:"webUrl"`
+	APIURL NullString `json:"apiUrl"`
+}
+
+// ManifestHistoryEntry is a record of a subscription manifest import,
+// refresh or deletion operation.
+type ManifestHistoryEntry struct {
+	Created       StandardAPITime `json:"created"`
+	Status        string          `json:"status"`
+	StatusMessage NullString      `json:"statusMessage"`
+}
+
+// ManifestHistory is a collection of subscription manifest history entries.
+type ManifestHistory []ManifestHistoryEntry
+
+// Manifest is the upstream subscription (manifest) details for a Red Hat
+// Satellite organization.
+type Manifest struct {
+	ExpirationDate     StandardAPITime
+	LastRefresh        StandardAPITime
+	OrganizationName   string
+	UpstreamName       string
+	UpstreamUUID       string
+	UpstreamWebURL     string
+	LastRefreshMessage string
+	OrganizationID     int
+	Expired            bool
+	ExpiringSoon       bool
+}
+
+// GetManifest uses the provided APIClient to retrieve the upstream
+// subscription (manifest) details for the given Red Hat Satellite
+// organization. The given query options (e.g., a scoped search of status =
+// SUCCESS) are applied when retrieving the manifest history.
+func GetManifest(ctx context.Context, client *APIClient, opts QueryOptions, org Organization) (Manifest, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return Manifest{}, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	subLogger := client.Logger.With().
+		Int("org_id", org.ID).
+		Str("org_name", org.Name).
+		Logger()
+
+	orgURL := fmt.Sprintf(
+		KatelloOrganizationAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+		org.ID,
+	)
+
+	var orgDetails KatelloOrganizationResponse
+	if err := getSingleResult(ctx, client, orgURL, QueryOptions{}, &orgDetails); err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		ExpirationDate:   orgDetails.ManifestExpirationDate,
+		Expired:          orgDetails.ManifestExpired,
+		ExpiringSoon:     orgDetails.ManifestExpiringSoon,
+	}
+
+	// Without an upstream consumer a manifest has not been imported and so
+	// there is no history to review.
+	if orgDetails.OwnerDetails.UpstreamConsumer == nil {
+		subLogger.Debug().Msg("No subscription manifest imported for organization")
+
+		return manifest, nil
+	}
+
+	manifest.UpstreamName = orgDetails.OwnerDetails.UpstreamConsumer.Name
+	manifest.UpstreamUUID = orgDetails.OwnerDetails.UpstreamConsumer.UUID
+	manifest.UpstreamWebURL = string(orgDetails.OwnerDetails.UpstreamConsumer.WebURL)
+
+	historyURL := fmt.Sprintf(
+		ManifestHistoryAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+		org.ID,
+	)
+
+	var history ManifestHistory
+	if err := getSingleResult(ctx, client, historyURL, opts, &history); err != nil {
+		return Manifest{}, err
+	}
+
+	if lastRefresh, ok := history.LastSuccess(); ok {
+		manifest.LastRefresh = lastRefresh.Created
+		manifest.LastRefreshMessage = string(lastRefresh.StatusMessage)
+	}
+
+	subLogger.Debug().
+		Str("upstream_name", manifest.UpstreamName).
+		Str("expiration_date", manifest.ExpirationDate.String()).
+		Str("last_refresh", manifest.LastRefresh.String()).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of subscription manifest details for organization")
+
+	return manifest, nil
+}
+
+// LastSuccess returns the most recent successful manifest history entry. A
+// boolean value is returned to indicate whether a successful entry was
+// found.
+func (mh ManifestHistory) LastSuccess() (ManifestHistoryEntry, bool) {
+	successful := make(ManifestHistory, 0, len(mh))
+
+	for _, entry := range mh {
+		if strings.EqualFold(entry.Status, manifestHistoryStatusSuccess) {
+			successful = append(successful, entry)
+		}
+	}
+
+	if len(successful) == 0 {
+		return ManifestHistoryEntry{}, false
+	}
+
+	sort.SliceStable(successful, func(i int, j int) bool {
+		return time.Time(successful[i].Created).After(time.Time(successful[j].Created))
+	})
+
+	return successful[0], true
+}
+
+// IsImported indicates whether a subscription manifest has been imported for
+// the organization.
+func (m Manifest) IsImported() bool {
+	return m.UpstreamUUID != ""
+}
+
+// DaysUntilExpiration indicates how many whole days remain before the
+// subscription manifest expires as of the given evaluation reference time. A
+// negative value indicates that the manifest has already expired. Zero is
+// returned if the expiration date is unknown.
+func (m Manifest) DaysUntilExpiration(now time.Time) int {
+	if time.Time(m.ExpirationDate).IsZero() {
+		return 0
+	}
+
+	remaining := time.Time(m.ExpirationDate).Sub(now).Hours()
+
+	// Toss remainder so that we only get the whole number of days
+	return int(math.Trunc(remaining / 24))
+}
+
+// DaysSinceRefresh indicates how many whole days have passed since the
+// subscription manifest was last successfully imported or refreshed as of
+// the given evaluation reference time. Zero is returned if the last refresh
+// date is unknown.
+func (m Manifest) DaysSinceRefresh(now time.Time) int {
+	if time.Time(m.LastRefresh).IsZero() {
+		return 0
+	}
+
+	return daysSince(now, time.Time(m.LastRefresh))
+}
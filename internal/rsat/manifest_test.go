@@ -0,0 +1,200 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestGetManifest(t *testing.T) {
+	const (
+		orgPath     = "/katello/api/v2/organizations/1"
+		historyPath = "/katello/api/v2/organizations/1/subscriptions/manifest_history"
+	)
+
+	tests := []struct {
+		name             string
+		orgResponse      string
+		wantImported     bool
+		wantUpstream     string
+		wantExpiration   time.Time
+		wantLastRefresh  time.Time
+		wantMessage      string
+		wantHistoryReqs  int32
+		wantExpired      bool
+		wantExpiringSoon bool
+	}{
+		{
+			name: "imported",
+			orgResponse: `{"id":1,"name":"Example","manifest_expiration_date":"2024-06-01 00:00:00 UTC",` +
+				`"manifest_expired":false,"manifest_expiring_soon":true,` +
+				`"owner_details":{"upstreamConsumer":{"uuid":"abc","name":"Example Allocation","webUrl":"https://access.example.com"}}}`,
+			wantImported:     true,
+			wantUpstream:     "Example Allocation",
+			wantExpiration:   time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+			wantLastRefresh:  time.Date(2024, time.May, 1, 8, 0, 0, 0, time.UTC),
+			wantMessage:      "refreshed",
+			wantHistoryReqs:  1,
+			wantExpiringSoon: true,
+		},
+		{
+			name:            "not imported",
+			orgResponse:     `{"id":1,"name":"Example","manifest_expiration_date":null,"owner_details":{"upstreamConsumer":null}}`,
+			wantHistoryReqs: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var historyReqs int32
+
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				switch r.URL.Path {
+				case orgPath:
+					_, _ = w.Write([]byte(tt.orgResponse))
+				case historyPath:
+					atomic.AddInt32(&historyReqs, 1)
+
+					// The most recent entry failed and so the most recent
+					// successful entry is used.
+					_, _ = w.Write([]byte(`[` +
+						`{"created":"2024-04-01 08:00:00 UTC","status":"SUCCESS","statusMessage":"imported"},` +
+						`{"created":"2024-05-10 08:00:00 UTC","status":"FAILURE","statusMessage":"failed"},` +
+						`{"created":"2024-05-01 08:00:00 UTC","status":"SUCCESS","statusMessage":"refreshed"}]`))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer ts.Close()
+
+			server, port := testServerAddress(t, ts)
+
+			authInfo := APIAuthInfo{
+				Server:      server,
+				Port:        port,
+				Username:    "monitoring",
+				Password:    "secret",
+				NetworkType: "auto",
+				ReadLimit:   1024 * 1024,
+				TrustCert:   true,
+			}
+
+			client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+			manifest, err := GetManifest(context.Background(), client, QueryOptions{}, Organization{ID: 1, Name: "Example"})
+			if err != nil {
+				t.Fatalf("failed to retrieve manifest: %v", err)
+			}
+
+			if got := manifest.IsImported(); got != tt.wantImported {
+				t.Errorf("got imported %t, want %t", got, tt.wantImported)
+			}
+
+			if manifest.UpstreamName != tt.wantUpstream {
+				t.Errorf("got upstream name %q, want %q", manifest.UpstreamName, tt.wantUpstream)
+			}
+
+			if got := time.Time(manifest.ExpirationDate); !got.Equal(tt.wantExpiration) {
+				t.Errorf("got expiration date %v, want %v", got, tt.wantExpiration)
+			}
+
+			if got := time.Time(manifest.LastRefresh); !got.Equal(tt.wantLastRefresh) {
+				t.Errorf("got last refresh %v, want %v", got, tt.wantLastRefresh)
+			}
+
+			if manifest.LastRefreshMessage != tt.wantMessage {
+				t.Errorf("got last refresh message %q, want %q", manifest.LastRefreshMessage, tt.wantMessage)
+			}
+
+			if manifest.Expired != tt.wantExpired || manifest.ExpiringSoon != tt.wantExpiringSoon {
+				t.Errorf(
+					"got expired %t, expiring soon %t; want %t, %t",
+					manifest.Expired, manifest.ExpiringSoon, tt.wantExpired, tt.wantExpiringSoon,
+				)
+			}
+
+			if got := atomic.LoadInt32(&historyReqs); got != tt.wantHistoryReqs {
+				t.Errorf("got %d manifest history requests, want %d", got, tt.wantHistoryReqs)
+			}
+		})
+	}
+}
+
+func TestManifestDays(t *testing.T) {
+	now := time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                    string
+		manifest                Manifest
+		wantDaysUntilExpiration int
+		wantDaysSinceRefresh    int
+	}{
+		{
+			name: "current",
+			manifest: Manifest{
+				ExpirationDate: StandardAPITime(now.Add(10*24*time.Hour + 5*time.Hour)),
+				LastRefresh:    StandardAPITime(now.Add(-45*24*time.Hour - 23*time.Hour)),
+			},
+			wantDaysUntilExpiration: 10,
+			wantDaysSinceRefresh:    45,
+		},
+		{
+			name: "expires later today",
+			manifest: Manifest{
+				ExpirationDate: StandardAPITime(now.Add(6 * time.Hour)),
+				LastRefresh:    StandardAPITime(now.Add(-6 * time.Hour)),
+			},
+			wantDaysUntilExpiration: 0,
+			wantDaysSinceRefresh:    0,
+		},
+		{
+			name: "expired",
+			manifest: Manifest{
+				ExpirationDate: StandardAPITime(now.Add(-3*24*time.Hour - time.Hour)),
+				LastRefresh:    StandardAPITime(now.Add(-400 * 24 * time.Hour)),
+			},
+			wantDaysUntilExpiration: -3,
+			wantDaysSinceRefresh:    400,
+		},
+		{
+			name: "never refreshed",
+			manifest: Manifest{
+				ExpirationDate: StandardAPITime(now.Add(30 * 24 * time.Hour)),
+			},
+			wantDaysUntilExpiration: 30,
+			wantDaysSinceRefresh:    0,
+		},
+		{
+			name:                    "unknown dates",
+			manifest:                Manifest{},
+			wantDaysUntilExpiration: 0,
+			wantDaysSinceRefresh:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.manifest.DaysUntilExpiration(now); got != tt.wantDaysUntilExpiration {
+				t.Errorf("DaysUntilExpiration() = %d, want %d", got, tt.wantDaysUntilExpiration)
+			}
+
+			if got := tt.manifest.DaysSinceRefresh(now); got != tt.wantDaysSinceRefresh {
+				t.Errorf("DaysSinceRefresh() = %d, want %d", got, tt.wantDaysSinceRefresh)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Normalized API endpoint paths used to look up request metrics. Numeric
+// path segments (e.g., organization IDs) are replaced with a placeholder so
+// that requests for each organization are recorded together.
+const (
+	MetricsEndpointOrganizations string = "/api/v2/organizations"
+	MetricsEndpointSyncPlans     string = "/katello/api/v2/organizations/{id}/sync_plans"
+)
+
+// metricsIDPlaceholder replaces numeric path segments in normalized API
+// endpoint paths.
+const metricsIDPlaceholder string = "{id}"
+
+// EndpointMetrics is the collection of request metrics recorded for an API
+// endpoint.
+type EndpointMetrics struct {
+	// Endpoint is the normalized API endpoint path.
+	Endpoint string
+
+	// Requests is the number of requests submitted to the endpoint
+	// (including retries and requests which failed).
+	Requests int
+
+	// Time is the total time spent waiting for responses from the endpoint
+	// (until the response headers are received).
+	Time time.Duration
+}
+
+// APIMetrics is a collection of request metrics for API endpoints.
+type APIMetrics []EndpointMetrics
+
+// apiMetricsRecorder records request metrics for API endpoints. It is safe
+// for concurrent use.
+type apiMetricsRecorder struct {
+	mutex     sync.Mutex
+	endpoints map[string]*EndpointMetrics
+
+	// queryTimes is the elapsed (wall clock) time spent on each query
+	// indexed by normalized API endpoint path.
+	queryTimes map[string]time.Duration
+}
+
+// metricsEndpoint returns the normalized API endpoint path for the given
+// request URL.
+func metricsEndpoint(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = metricsIDPlaceholder
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// record notes a request submitted to the given API endpoint which took the
+// given amount of time.
+func (r *apiMetricsRecorder) record(endpoint string, elapsed time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.endpoints == nil {
+		r.endpoints = make(map[string]*EndpointMetrics)
+	}
+
+	m, ok := r.endpoints[endpoint]
+	if !ok {
+		m = &EndpointMetrics{Endpoint: endpoint}
+		r.endpoints[endpoint] = m
+	}
+
+	m.Requests++
+	m.Time += elapsed
+}
+
+// recordQuery notes a query (e.g., retrieving all pages of organizations or
+// the sync plans for all organizations) of the given API endpoint which took
+// the given amount of (wall clock) time.
+func (r *apiMetricsRecorder) recordQuery(endpoint string, elapsed time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.queryTimes == nil {
+		r.queryTimes = make(map[string]time.Duration)
+	}
+
+	r.queryTimes[endpoint] += elapsed
+}
+
+// snapshot returns a copy of the recorded metrics sorted by endpoint.
+func (r *apiMetricsRecorder) snapshot() APIMetrics {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	metrics := make(APIMetrics, 0, len(r.endpoints))
+	for _, m := range r.endpoints {
+		metrics = append(metrics, *m)
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Endpoint < metrics[j].Endpoint
+	})
+
+	return metrics
+}
+
+// Requests returns the number of requests submitted across all endpoints.
+func (am APIMetrics) Requests() int {
+	var num int
+	for _, m := range am {
+		num += m.Requests
+	}
+
+	return num
+}
+
+// Time returns the total time spent waiting for responses from the given
+// normalized API endpoint path.
+func (am APIMetrics) Time(endpoint string) time.Duration {
+	for _, m := range am {
+		if m.Endpoint == endpoint {
+			return m.Time
+		}
+	}
+
+	return 0
+}
+
+// Metrics returns the request metrics recorded for each API endpoint
+// accessed by the client sorted by endpoint.
+func (c *APIClient) Metrics() APIMetrics {
+	if c == nil || c.metrics == nil {
+		return APIMetrics{}
+	}
+
+	return c.metrics.snapshot()
+}
+
+// QueryTime returns the elapsed (wall clock) time spent querying the given
+// normalized API endpoint path. Unlike the time spent waiting for responses,
+// this includes reading and decoding responses, pagination, retry delays and
+// time spent waiting on the client's concurrency limit. Zero is returned if
+// the endpoint was not queried (e.g., if cached values were used).
+func (c *APIClient) QueryTime(endpoint string) time.Duration {
+	if c == nil || c.metrics == nil {
+		return 0
+	}
+
+	c.metrics.mutex.Lock()
+	defer c.metrics.mutex.Unlock()
+
+	return c.metrics.queryTimes[endpoint]
+}
+
+// recordQueryTime notes the elapsed (wall clock) time spent querying the
+// given normalized API endpoint path since the given start time.
+func (c *APIClient) recordQueryTime(endpoint string, start time.Time) {
+	if c == nil || c.metrics == nil {
+		return
+	}
+
+	c.metrics.recordQuery(endpoint, time.Since(start))
+}
+
+// LogMetrics logs a summary of the request metrics recorded for each API
+// endpoint accessed by the client at debug level. This helps identify the
+// endpoint responsible for slow retrieval.
+func (c *APIClient) LogMetrics() {
+	metrics := c.Metrics()
+
+	for _, m := range metrics {
+		var avg time.Duration
+		if m.Requests > 0 {
+			avg = m.Time / time.Duration(m.Requests)
+		}
+
+		c.Logger.Debug().
+			Str("endpoint", m.Endpoint).
+			Int("requests", m.Requests).
+			Str("total_time", m.Time.String()).
+			Str("average_time", avg.String()).
+			Msg("API endpoint request metrics")
+	}
+
+	c.Logger.Debug().
+		Int("endpoints", len(metrics)).
+		Int("requests", metrics.Requests()).
+		Msg("API request metrics summary")
+}
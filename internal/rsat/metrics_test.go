@@ -0,0 +1,63 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestAPIClientMetrics(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		FixturesDir: filepath.Join("testdata", "fixtures"),
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+	orgs, err := GetOrgsWithSyncPlans(context.Background(), client, QueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to read organizations from fixtures: %v", err)
+	}
+
+	metrics := client.Metrics()
+
+	var orgsRequests, syncPlansRequests int
+	for _, m := range metrics {
+		switch m.Endpoint {
+		case MetricsEndpointOrganizations:
+			orgsRequests = m.Requests
+		case MetricsEndpointSyncPlans:
+			syncPlansRequests = m.Requests
+		}
+	}
+
+	if orgsRequests != 1 {
+		t.Errorf("got %d organizations requests, want 1", orgsRequests)
+	}
+
+	// Organizations without sync plans are not returned.
+	if syncPlansRequests == 0 || syncPlansRequests < len(orgs) {
+		t.Errorf("got %d sync plans requests, want at least %d (one per organization)", syncPlansRequests, len(orgs))
+	}
+
+	if metrics.Requests() < orgsRequests+syncPlansRequests {
+		t.Errorf("got %d total requests, want at least %d", metrics.Requests(), orgsRequests+syncPlansRequests)
+	}
+
+	for _, endpoint := range []string{MetricsEndpointOrganizations, MetricsEndpointSyncPlans} {
+		if client.QueryTime(endpoint) <= 0 {
+			t.Errorf("query time not recorded for %s", endpoint)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOAuthSignature(t *testing.T) {
+	requestURL, err := url.Parse(
+		"https://RSAT.example.com:443/katello/api/v2/organizations/1/sync_plans" +
+			"?per_page=100&search=name+%3D+%22RHEL+9%22",
+	)
+	if err != nil {
+		t.Fatalf("failed to parse URL: %v", err)
+	}
+
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     "katello",
+		"oauth_nonce":            "abc123",
+		"oauth_signature_method": oauthSignatureMethod,
+		"oauth_timestamp":        "1700000000",
+		"oauth_version":          oauthVersion,
+	}
+
+	// Independently computed using the RFC 5849 signature base string:
+	//
+	// GET&https%3A%2F%2Frsat.example.com%2Fkatello%2Fapi%2Fv2%2Forganizations%2F1%2Fsync_plans&oauth_consumer_key%3Dkatello%26oauth_nonce%3Dabc123%26oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D1700000000%26oauth_version%3D1.0%26per_page%3D100%26search%3Dname%2520%253D%2520%2522RHEL%25209%2522
+	want := "efVUZ1QxwRhWhkuk7mgPV0ttxXY="
+
+	if got := oauthSignature(http.MethodGet, requestURL, oauthParams, "s3cr3t+value"); got != want {
+		t.Errorf("got signature %q, want %q", got, want)
+	}
+}
+
+func TestOAuthBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"https://rsat.example.com:443/api/v2/status?per_page=1": "https://rsat.example.com/api/v2/status",
+		"https://rsat.example.com:8443/api/v2/status":           "https://rsat.example.com:8443/api/v2/status",
+		"https://[2001:DB8::1]:443/api":                         "https://[2001:db8::1]/api",
+		"HTTPS://RSAT.example.com":                              "https://rsat.example.com/",
+	}
+
+	for rawURL, want := range tests {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("failed to parse URL %q: %v", rawURL, err)
+		}
+
+		if got := oauthBaseURL(parsed); got != want {
+			t.Errorf("%s: got %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestSignOAuthRequest(t *testing.T) {
+	request, err := http.NewRequest(http.MethodGet, "https://rsat.example.com/api/v2/status", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	if err := signOAuthRequest(request, "katello", "secret"); err != nil {
+		t.Fatalf("failed to sign request: %v", err)
+	}
+
+	header := request.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "OAuth ") {
+		t.Fatalf("got Authorization header %q, want OAuth scheme", header)
+	}
+
+	for _, field := range []string{
+		`oauth_consumer_key="katello"`,
+		`oauth_signature_method="HMAC-SHA1"`,
+		`oauth_version="1.0"`,
+		`oauth_nonce="`,
+		`oauth_timestamp="`,
+		`oauth_signature="`,
+	} {
+		if !strings.Contains(header, field) {
+			t.Errorf("Authorization header %q missing %s", header, field)
+		}
+	}
+}
@@ -12,9 +12,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/atc0005/check-rsat/internal/textutils"
 	"github.com/atc0005/go-nagios"
 )
 
@@ -54,6 +56,12 @@ type OrganizationsResponse struct {
 	PerPage int `json:"per_page"`
 }
 
+// pageResults returns the organizations provided by the response along with
+// the number of organizations matching the query across all pages.
+func (r OrganizationsResponse) pageResults() ([]Organization, int) {
+	return r.Organizations, r.Subtotal
+}
+
 // Organization is an isolated collection of systems, content, and other
 // functionality within a Red Hat Satellite deployment.
 type Organization struct {
@@ -63,7 +71,13 @@ type Organization struct {
 	Label       string          `json:"label"`
 	Name        string          `json:"name"`
 	Title       string          `json:"title"`
-	SyncPlans   SyncPlans       `json:"-"`
+
+	// Owner is the team or individual responsible for the organization as
+	// recorded via an organization parameter. This value is empty unless
+	// organization parameters were retrieved for evaluation.
+	Owner string `json:"-"`
+
+	SyncPlans SyncPlans `json:"-"`
 	// Products    Products        `json:"-"`
 	// Hosts       Hosts           `json:"-"`
 	ID int `json:"id"`
@@ -73,8 +87,9 @@ type Organization struct {
 type Organizations []Organization
 
 // GetOrganizations uses the given client to retrieve all Red Hat Satellite
-// organizations.
-func GetOrganizations(ctx context.Context, client *APIClient) ([]Organization, error) {
+// organizations. The given query options (e.g., a scoped search of name ~
+// prod) are used to limit and order the organizations retrieved.
+func GetOrganizations(ctx context.Context, client *APIClient, opts QueryOptions) ([]Organization, error) {
 	funcTimeStart := time.Now()
 
 	if client == nil {
@@ -92,91 +107,147 @@ func GetOrganizations(ctx context.Context, client *APIClient) ([]Organization, e
 		client.AuthInfo.Port,
 	)
 
-	allOrgs := make([]Organization, 0, client.Limits.PerPage*2)
+	allOrgs, err := fetchAllPages[Organization, OrganizationsResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		logger,
+		"organizations",
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	apiURLQueryParams := make(map[string]string)
-	apiURLQueryParams[APIEndpointURLQueryParamFullResultKey] = APIEndpointURLQueryParamFullResultDefaultValue
-	apiURLQueryParams[APIEndpointURLQueryParamPerPageKey] = strconv.Itoa(client.Limits.PerPage)
+	logger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all organizations")
 
-	var nextPage int
-	remainingOrgs := true
+	return allOrgs, nil
+}
 
-	for remainingOrgs {
-		logger.Debug().
-			Msg("Collecting organizations from the API")
+// Sort sorts the Organizations in the collection by name.
+func (orgs Organizations) Sort() {
+	sort.SliceStable(orgs, func(i int, j int) bool {
+		return orgs[i].Name < orgs[j].Name
+	})
+}
 
-		nextPage++
-		apiURLQueryParams[APIEndpointURLQueryParamPageKey] = strconv.Itoa(nextPage)
+// SortByPlanName sorts the Organizations in the collection by name and the
+// sync plans for each organization by name.
+func (orgs Organizations) SortByPlanName() {
+	for _, org := range orgs {
+		org.SyncPlans.SortByName()
+	}
 
-		response, respErr := submitAPIQueryRequest(ctx, client, apiURL, apiURLQueryParams, logger)
-		if respErr != nil {
-			return nil, respErr
-		}
+	orgs.Sort()
+}
 
-		logger.Debug().Msgf(
-			"Decoding JSON data from %q using a limit of %d bytes",
-			apiURL,
-			client.AuthInfo.ReadLimit,
-		)
+// SortByDaysStuck sorts the sync plans for each organization in the
+// collection by the number of days stuck as of the given evaluation
+// reference time (most first). Organizations are then sorted by the number of
+// days their "worst" sync plan is stuck (most first) and then by name.
+func (orgs Organizations) SortByDaysStuck(now time.Time) {
+	for _, org := range orgs {
+		org.SyncPlans.SortByDaysStuck(now)
+	}
 
-		var orgsQueryResp OrganizationsResponse
-		decodeErr := decode(&orgsQueryResp, response.Body, logger, apiURL, client.AuthInfo.ReadLimit)
-		if decodeErr != nil {
-			return nil, decodeErr
+	// The sync plans for each organization are already sorted, so the first
+	// sync plan is the "worst".
+	worst := func(org Organization) (bool, int) {
+		if len(org.SyncPlans) == 0 {
+			return false, 0
 		}
 
-		logger.Debug().
-			Str("api_endpoint", apiURL).
-			Msg("Successfully decoded JSON data")
+		return org.SyncPlans[0].IsStuck(now), org.SyncPlans[0].DaysStuck(now)
+	}
 
-		// Close the response body once we're done with it. We explicitly
-		// close here vs deferring via closure to prevent accumulating client
-		// connections to the API if we need to perform multiple paged
-		// requests.
-		if closeErr := response.Body.Close(); closeErr != nil {
-			logger.Error().Err(closeErr).Msg("error closing response body")
+	sort.SliceStable(orgs, func(i int, j int) bool {
+		stuckI, daysI := worst(orgs[i])
+		stuckJ, daysJ := worst(orgs[j])
+
+		switch {
+		case stuckI != stuckJ:
+			return stuckI
+		case daysI != daysJ:
+			return daysI > daysJ
+		default:
+			return orgs[i].Name < orgs[j].Name
 		}
+	})
+}
 
-		allOrgs = append(allOrgs, orgsQueryResp.Organizations...)
+// SortByNextSync sorts the sync plans for each organization in the
+// collection by the next scheduled sync time (earliest first). Organizations
+// are then sorted by the earliest next scheduled sync time of their sync
+// plans and then by name. Sync plans and organizations without a scheduled
+// sync are sorted last.
+func (orgs Organizations) SortByNextSync() {
+	for _, org := range orgs {
+		org.SyncPlans.SortByNextSync()
+	}
 
-		numNewOrgs := len(orgsQueryResp.Organizations)
-		numCollectedOrgs := len(allOrgs)
-		numOrgsRemaining := orgsQueryResp.Subtotal - numCollectedOrgs
+	earliest := func(org Organization) time.Time {
+		if len(org.SyncPlans) == 0 {
+			return time.Time{}
+		}
 
-		logger.Debug().
-			Str("api_endpoint", apiURL).
-			Int("orgs_collected", numCollectedOrgs).
-			Int("orgs_new", numNewOrgs).
-			Int("orgs_remaining", numOrgsRemaining).
-			Msg("Added decoded organizations to collection")
+		return time.Time(org.SyncPlans[0].NextSync)
+	}
 
-		logger.Debug().
-			Msg("Determining if we have collected all organizations from the API")
+	sort.SliceStable(orgs, func(i int, j int) bool {
+		nextI, nextJ := earliest(orgs[i]), earliest(orgs[j])
+
+		switch {
+		case nextI.IsZero() != nextJ.IsZero():
+			return nextJ.IsZero()
+		case !nextI.Equal(nextJ):
+			return nextI.Before(nextJ)
+		default:
+			return orgs[i].Name < orgs[j].Name
+		}
+	})
+}
 
-		remainingOrgs = numOrgsRemaining != 0
+// Reverse reverses the order of the Organizations in the collection. If
+// specified, the order of the sync plans for each organization is also
+// reversed.
+func (orgs Organizations) Reverse(syncPlans bool) {
+	for i, j := 0, len(orgs)-1; i < j; i, j = i+1, j-1 {
+		orgs[i], orgs[j] = orgs[j], orgs[i]
 	}
 
-	logger.Debug().
-		Str("runtime_total", time.Since(funcTimeStart).String()).
-		Msg("Completed retrieval of all organizations")
+	if !syncPlans {
+		return
+	}
 
-	return allOrgs, nil
+	for _, org := range orgs {
+		org.SyncPlans.Reverse()
+	}
 }
 
-// Sort sorts the Organizations in the collection by name.
-func (orgs Organizations) Sort() {
-	sort.SliceStable(orgs, func(i int, j int) bool {
-		return orgs[i].Name < orgs[j].Name
-	})
+// GetOrgsWithSyncPlans uses the provided API client to retrieve all Red Hat
+// Satellite organizations along with their sync plans. The given query
+// options (e.g., a scoped search of enabled = true) are used to limit and
+// order the sync plans retrieved.
+func GetOrgsWithSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions) (Organizations, error) {
+	orgs, _, err := GetFilteredOrgsWithSyncPlans(ctx, client, opts, OrgFilter{})
+
+	return orgs, err
 }
 
-// GetOrgsWithSyncPlans uses the provided API client to retrieve all Red Hat
-// Satellite organizations along with their sync plans.
-func GetOrgsWithSyncPlans(ctx context.Context, client *APIClient) (Organizations, error) {
+// GetFilteredOrgsWithSyncPlans uses the provided API client to retrieve the
+// Red Hat Satellite organizations satisfying the given filter along with
+// their sync plans. Sync plans are not retrieved for skipped organizations;
+// a record of each skipped organization is returned. The given query options
+// (e.g., a scoped search of enabled = true) are used to limit and order the
+// sync plans retrieved. The server version is retrieved and recorded on the
+// client if not already known.
+func GetFilteredOrgsWithSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions, filter OrgFilter) (Organizations, SkippedItems, error) {
 	funcTimeStart := time.Now()
 
 	if client == nil {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"required API client was not provided: %w",
 			ErrMissingValue,
 		)
@@ -186,10 +257,13 @@ func GetOrgsWithSyncPlans(ctx context.Context, client *APIClient) (Organizations
 
 	logger.Debug().Msg("Retrieving organizations")
 
-	orgs, orgsErr := GetOrganizations(ctx, client)
+	orgsQueryStart := time.Now()
+	orgs, orgsErr := GetOrganizations(ctx, client, QueryOptions{})
+	client.recordQueryTime(MetricsEndpointOrganizations, orgsQueryStart)
+
 	if orgsErr != nil {
 		logger.Error().Err(orgsErr).Msg("Failed to retrieve organizations")
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"failed to retrieve organizations: %w",
 			orgsErr,
 		)
@@ -197,48 +271,155 @@ func GetOrgsWithSyncPlans(ctx context.Context, client *APIClient) (Organizations
 
 	logger.Debug().Msg("Successfully retrieved organizations")
 
-	reqsCounter := newRequestsCounter(len(orgs))
+	orgs, skipped, filterErr := filter.Apply(orgs)
+	if filterErr != nil {
+		logger.Error().Err(filterErr).Msg("Failed to filter organizations")
+		return nil, nil, filterErr
+	}
+
+	logger.Debug().
+		Int("orgs_skipped", len(skipped)).
+		Int("orgs_remaining", len(orgs)).
+		Msg("Applied organizations filter")
+
+	// Record the server version so that known API differences are applied
+	// when annotating sync plans. Retrieval of sync plans does not depend on
+	// the server version, so a failure is not fatal.
+	if client.ServerVersion.IsZero() {
+		if _, statusErr := GetStatus(ctx, client); statusErr != nil {
+			logger.Warn().Err(statusErr).Msg("Failed to retrieve server version details")
+		}
+	}
 
 	// Update all organizations with retrieved sync plans.
-	for i := range orgs {
+	syncPlansQueryStart := time.Now()
+	syncPlansErr := retrieveOrgsSyncPlans(ctx, client, opts, orgs, funcTimeStart)
+	client.recordQueryTime(MetricsEndpointSyncPlans, syncPlansQueryStart)
+
+	if syncPlansErr != nil {
+		return nil, nil, syncPlansErr
+	}
 
-		subLogger := logger.With().
-			Int("org_id", orgs[i].ID).
-			Str("org_name", orgs[i].Name).
-			Stack().Logger()
+	logger.Debug().Msg("Successfully retrieved sync plans for all organizations")
 
-		retrievalStart := time.Now()
+	return orgs, skipped, nil
+}
 
-		subLogger.Debug().Msg("Retrieving sync plans for organization")
+// getOrgsSyncPlans retrieves the sync plans for each of the given
+// organizations, retrieving sync plans for up to the client's concurrency
+// limit of organizations at a time. Each organization is updated in place so
+// that the order of the given organizations is preserved regardless of the
+// order in which retrieval completes. Remaining retrievals are abandoned
+// after the first failure and the error for that failure is returned. An
+// error is also returned if the given context is done before retrieval is
+// attempted for every organization.
+func getOrgsSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions, orgs Organizations, funcTimeStart time.Time) error {
+	logger := client.Logger
 
-		syncPlans, syncPlansErr := GetSyncPlans(ctx, client, orgs[i])
-		if syncPlansErr != nil {
-			subLogger.Error().Err(syncPlansErr).Msg("Failed to retrieve sync plans")
-			return nil, fmt.Errorf(
-				"failed to retrieve sync plans for organization"+
-					" (name: %s, id: %d) %w",
-				orgs[i].Name,
-				orgs[i].ID,
-				syncPlansErr,
-			)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxConcurrent := client.Limits.maxConcurrent()
+
+	logger.Debug().
+		Int("orgs", len(orgs)).
+		Int("max_concurrent", maxConcurrent).
+		Msg("Retrieving sync plans for organizations")
+
+	var firstErr error
+	var firstErrOnce sync.Once
+
+	// The requests counter is not safe for concurrent use.
+	var reqsCounterMutex sync.Mutex
+	reqsCounter := newRequestsCounter(len(orgs))
+
+	semaphore := make(chan struct{}, maxConcurrent)
+
+	// Whether retrieval was abandoned before all organizations were
+	// processed.
+	var abandoned bool
+
+	var wg sync.WaitGroup
+	for i := range orgs {
+		semaphore <- struct{}{}
+
+		// Skip launching further retrievals after a failure or if the
+		// context is otherwise done.
+		if ctx.Err() != nil {
+			<-semaphore
+			abandoned = true
+			break
 		}
 
-		requestNum, requestsRemaining := reqsCounter()
+		wg.Add(1)
 
-		subLogger.Debug().
-			Int("retrieved_plans", len(syncPlans)).
-			Int("request", requestNum).
-			Int("requests_remaining", requestsRemaining).
-			Str("runtime_request", time.Since(retrievalStart).String()).
-			Str("runtime_elapsed", time.Since(funcTimeStart).String()).
-			Msg("Finished sync plans retrieval for this organization")
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
 
-		orgs[i].SyncPlans = syncPlans
+			subLogger := logger.With().
+				Int("org_id", orgs[i].ID).
+				Str("org_name", orgs[i].Name).
+				Stack().Logger()
+
+			retrievalStart := time.Now()
+
+			subLogger.Debug().Msg("Retrieving sync plans for organization")
+
+			syncPlans, syncPlansErr := GetSyncPlans(ctx, client, opts, orgs[i])
+			if syncPlansErr != nil {
+				firstErrOnce.Do(func() {
+					subLogger.Error().Err(syncPlansErr).Msg("Failed to retrieve sync plans")
+
+					firstErr = fmt.Errorf(
+						"failed to retrieve sync plans for organization"+
+							" (name: %s, id: %d) %w",
+						orgs[i].Name,
+						orgs[i].ID,
+						syncPlansErr,
+					)
+
+					cancel()
+				})
+
+				return
+			}
+
+			reqsCounterMutex.Lock()
+			requestNum, requestsRemaining := reqsCounter()
+			reqsCounterMutex.Unlock()
+
+			subLogger.Debug().
+				Int("retrieved_plans", len(syncPlans)).
+				Int("request", requestNum).
+				Int("requests_remaining", requestsRemaining).
+				Str("runtime_request", time.Since(retrievalStart).String()).
+				Str("runtime_elapsed", time.Since(funcTimeStart).String()).
+				Msg("Finished sync plans retrieval for this organization")
+
+			orgs[i].SyncPlans = syncPlans
+		}(i)
 	}
+	wg.Wait()
 
-	logger.Debug().Msg("Successfully retrieved sync plans for all organizations")
+	if firstErr == nil && abandoned {
+		return fmt.Errorf(
+			"sync plans retrieval abandoned before all organizations were processed: %w",
+			ctx.Err(),
+		)
+	}
+
+	return firstErr
+}
 
-	return orgs, nil
+// RemoveDisabledSyncPlans removes disabled sync plans from each organization
+// in the collection. This is intended for use with organizations and sync
+// plans retrieved from a cache which holds all sync plans when only enabled
+// sync plans are requested.
+func (orgs Organizations) RemoveDisabledSyncPlans() {
+	for i := range orgs {
+		orgs[i].SyncPlans = orgs[i].SyncPlans.Enabled()
+	}
 }
 
 // NumOrgs returns the number of organizations in the collection.
@@ -246,6 +427,20 @@ func (orgs Organizations) NumOrgs() int {
 	return len(orgs)
 }
 
+// NumOrgsWithoutPlans returns the number of organizations in the collection
+// without any sync plans.
+func (orgs Organizations) NumOrgsWithoutPlans() int {
+	var num int
+
+	for _, org := range orgs {
+		if len(org.SyncPlans) == 0 {
+			num++
+		}
+	}
+
+	return num
+}
+
 // NumPlans returns the number of sync plans for all organizations in the
 // collection.
 func (orgs Organizations) NumPlans() int {
@@ -274,12 +469,13 @@ func (orgs Organizations) NumPlansEnabled() int {
 }
 
 // NumPlansStuck returns the total number of sync plans for all organizations
-// in the collection with Next Sync state set to past date/time.
-func (orgs Organizations) NumPlansStuck() int {
+// in the collection with Next Sync state set to past date/time as of the
+// given evaluation reference time.
+func (orgs Organizations) NumPlansStuck(now time.Time) int {
 	var num int
 
 	for _, org := range orgs {
-		num += org.SyncPlans.NumStuck()
+		num += org.SyncPlans.NumStuck(now)
 	}
 
 	return num
@@ -298,60 +494,523 @@ func (orgs Organizations) NumPlansDisabled() int {
 }
 
 // NumProblemPlans returns the total number of sync plans for all
-// organizations in the collection with a non-OK state.
-func (orgs Organizations) NumProblemPlans() int {
-	// NOTE: While stuck plans are the current focus we may wish to expand the
-	// list of problem "symptoms" to include other attributes in the future.
-	// This method provides a more generic "are there any problems" status
-	// check to cover that possibility.
-	return orgs.NumPlansStuck()
+// organizations in the collection with a non-OK state as of the given
+// evaluation reference time.
+func (orgs Organizations) NumProblemPlans(now time.Time) int {
+	var num int
+
+	for _, org := range orgs {
+		num += org.SyncPlans.NumProblemPlans(now)
+	}
+
+	return num
 }
 
-// IsOKState indicates whether all items in the collection were evaluated to
-// an OK state.
-func (orgs Organizations) IsOKState() bool {
-	// return orgs.NumProblemPlans() == 0
+// NumPlansWithProductProblems returns the total number of sync plans for all
+// organizations in the collection which have one or more problematic
+// products (e.g., failed or stale sync) as of the given evaluation reference
+// time.
+func (orgs Organizations) NumPlansWithProductProblems(now time.Time) int {
+	var num int
 
+	for _, org := range orgs {
+		num += org.SyncPlans.NumWithProductProblems(now)
+	}
+
+	return num
+}
+
+// IgnoreSyncPlans removes all sync plans from the collection whose name
+// matches any of the given shell-style glob or regular expression patterns
+// so that they are excluded from evaluation and reports. A record of each
+// ignored sync plan is returned.
+func (orgs Organizations) IgnoreSyncPlans(patterns []string) SkippedItems {
+	var skipped SkippedItems
+
+	if len(patterns) == 0 {
+		return skipped
+	}
+
+	matcher := textutils.NewPatternMatcher(patterns)
+
+	for i := range orgs {
+		kept := make(SyncPlans, 0, len(orgs[i].SyncPlans))
+
+		for _, syncPlan := range orgs[i].SyncPlans {
+			pattern, ok := matcher.Match(syncPlan.Name)
+			if !ok {
+				kept = append(kept, syncPlan)
+
+				continue
+			}
+
+			skipped.Add(
+				SkippedItemTypeSyncPlan,
+				fmt.Sprintf("%s / %s", orgs[i].Name, syncPlan.Name),
+				SkipRulePlanIgnore,
+				fmt.Sprintf("matched pattern %q", pattern),
+			)
+		}
+
+		orgs[i].SyncPlans = kept
+	}
+
+	return skipped
+}
+
+// ExcludeProducts marks all products associated with sync plans in the
+// collection whose name or label matches any of the given shell-style glob
+// or regular expression patterns as excluded from product-based evaluation.
+// A record of each excluded product is returned.
+func (orgs Organizations) ExcludeProducts(patterns []string) SkippedItems {
+	var skipped SkippedItems
+
+	if len(patterns) == 0 {
+		return skipped
+	}
+
+	matcher := textutils.NewPatternMatcher(patterns)
+
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			syncPlan := &orgs[i].SyncPlans[j]
+
+			for k := range syncPlan.Products {
+				product := &syncPlan.Products[k]
+
+				pattern, ok := matcher.Match(product.Name, product.Label)
+				if !ok {
+					continue
+				}
+
+				product.Excluded = true
+
+				skipped.Add(
+					SkippedItemTypeProduct,
+					fmt.Sprintf("%s / %s / %s", orgs[i].Name, syncPlan.Name, product.Name),
+					SkipRuleProductExclude,
+					fmt.Sprintf("matched pattern %q", pattern),
+				)
+			}
+		}
+	}
+
+	return skipped
+}
+
+// HasOwners indicates whether an owner is recorded for any sync plan in the
+// collection.
+func (orgs Organizations) HasOwners() bool {
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if syncPlan.Owner != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ProblemPlansByOwner returns the number of sync plans with a non-OK state
+// as of the given evaluation reference time for each owner. Sync plans
+// without a recorded owner are tallied using an empty owner value.
+func (orgs Organizations) ProblemPlansByOwner(now time.Time) map[string]int {
+	byOwner := make(map[string]int)
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if !syncPlan.IsOKState(now) {
+				byOwner[syncPlan.Owner]++
+			}
+		}
+	}
+
+	return byOwner
+}
+
+// WorstStuck returns up to the given number of sync plans in a "stuck" state
+// as of the given evaluation reference time across all organizations in the
+// collection, ordered by the number of days stuck (most first). This allows
+// the most urgent sync plans to be listed first regardless of organization
+// name ordering.
+func (orgs Organizations) WorstStuck(now time.Time, limit int) SyncPlans {
+	var stuck SyncPlans
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans.Stuck(now) {
+			syncPlan.OrganizationName = org.Name
+			stuck = append(stuck, syncPlan)
+		}
+	}
+
+	stuck.SortByDaysStuck(now)
+
+	if limit >= 0 && len(stuck) > limit {
+		stuck = stuck[:limit]
+	}
+
+	return stuck
+}
+
+// WorstProblemPlans returns up to the given number of sync plans in a non-OK
+// state as of the given evaluation reference time across all organizations in
+// the collection, ordered by the number of days stuck (most first). Sync
+// plans in a non-OK state which are not stuck (e.g., due to product sync
+// problems) are ordered after stuck sync plans. All problem sync plans are
+// returned if the limit is negative.
+func (orgs Organizations) WorstProblemPlans(now time.Time, limit int) SyncPlans {
+	var problems SyncPlans
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if syncPlan.IsOKState(now) {
+				continue
+			}
+
+			syncPlan.OrganizationName = org.Name
+			problems = append(problems, syncPlan)
+		}
+	}
+
+	problems.SortByDaysStuck(now)
+
+	if limit >= 0 && len(problems) > limit {
+		problems = problems[:limit]
+	}
+
+	return problems
+}
+
+// SetRecurringLogics records the state of the recurring logic used to
+// trigger execution of each sync plan in the collection using the given
+// recurring logics. Sync plans whose recurring logic is not found in the
+// given collection are not modified.
+func (orgs Organizations) SetRecurringLogics(logics RecurringLogics) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			syncPlan := &orgs[i].SyncPlans[j]
+
+			if rl, ok := logics.Lookup(syncPlan.RecurringLogicID); ok {
+				syncPlan.RecurringLogicState = rl.State
+			}
+		}
+	}
+}
+
+// SetMaxProductSyncAge sets the maximum age permitted for the last sync of
+// products associated with each sync plan in the collection. If a non-zero
+// multiplier is given the maximum age is calculated as a multiple of each
+// sync plan's interval; sync plans with an unknown interval duration (e.g.,
+// custom cron) are not evaluated. Otherwise the given fixed duration is
+// used. Zero values disable evaluation of product last sync age.
+func (orgs Organizations) SetMaxProductSyncAge(multiplier float64, fixed time.Duration) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			syncPlan := &orgs[i].SyncPlans[j]
+
+			switch {
+			case multiplier > 0:
+				interval, ok := syncPlan.IntervalDuration()
+				if !ok {
+					syncPlan.MaxProductSyncAge = 0
+					continue
+				}
+
+				syncPlan.MaxProductSyncAge = time.Duration(float64(interval) * multiplier)
+
+			default:
+				syncPlan.MaxProductSyncAge = fixed
+			}
+		}
+	}
+}
+
+// SetMaxIntervalDrift sets the maximum number of sync plan intervals
+// permitted since the most recent product sync for each sync plan in the
+// collection. A zero value disables evaluation of interval drift.
+func (orgs Organizations) SetMaxIntervalDrift(intervals float64) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			orgs[i].SyncPlans[j].MaxIntervalDrift = intervals
+		}
+	}
+}
+
+// SetLastRun sets the time of the previous evaluation for each sync plan in
+// the collection. This is used to note sync plans created or modified since
+// the previous evaluation.
+func (orgs Organizations) SetLastRun(lastRun time.Time) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			orgs[i].SyncPlans[j].LastRun = lastRun
+		}
+	}
+}
+
+// SetProductSyncStateEvaluation sets whether the sync state of products
+// associated with each sync plan in the collection is considered when
+// evaluating whether the sync plan is in a problem state.
+func (orgs Organizations) SetProductSyncStateEvaluation(enabled bool) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			orgs[i].SyncPlans[j].EvaluateProductSyncState = enabled
+		}
+	}
+}
+
+// IsOKState indicates whether all items in the collection were evaluated to
+// an OK state as of the given evaluation reference time.
+//
+// NOTE: This reflects whether any problems were detected, regardless of
+// whether those problems exceed the thresholds used to determine the service
+// state (see ServiceState).
+func (orgs Organizations) IsOKState(now time.Time) bool {
 	// The scope is a higher level than just whether there are problematic
 	// sync plans (e.g., the Org might have problematic subscriptions that we
 	// can alert on in the future).
-	return !orgs.HasWarningState() && !orgs.HasCriticalState()
+	return orgs.NumProblemPlans(now) == 0
+}
+
+// StateThresholds is the collection of thresholds used to determine the
+// service state for the evaluation results of a collection.
+type StateThresholds struct {
+	// DaysStuckWarning is an optional threshold for the number of days a
+	// sync plan has been in a "stuck" state. If set, only sync plans stuck
+	// for a number of days exceeding this threshold result in a WARNING
+	// state. If not set, any stuck sync plan results in a WARNING state.
+	DaysStuckWarning CountThreshold
+
+	// DaysStuckCritical is an optional threshold for the number of days a
+	// sync plan has been in a "stuck" state. If set, a CRITICAL state is
+	// indicated if any sync plan has been stuck for a number of days
+	// exceeding this threshold. If not set, this threshold is disabled.
+	DaysStuckCritical CountThreshold
+
+	// StuckCountWarning is an optional threshold for the number of stuck
+	// sync plans. If set, a WARNING state is indicated only if this
+	// threshold is exceeded by the number of sync plans stuck for a number of
+	// days exceeding the DaysStuckWarning threshold.
+	StuckCountWarning CountThreshold
+
+	// StuckCountCritical is an optional threshold for the number of stuck
+	// sync plans. If set, a CRITICAL state is indicated if this threshold is
+	// exceeded by the number of stuck sync plans.
+	StuckCountCritical CountThreshold
+
+	// StuckState is the service state indicated when the warning thresholds
+	// for stuck sync plans are met. A WARNING state is indicated if not set.
+	StuckState string
+
+	// NeverScheduledState is the optional service state indicated if any
+	// enabled sync plans have never been scheduled (no next sync time). If
+	// set, these sync plans are not subject to the stuck sync plan
+	// thresholds. If not set, these sync plans are evaluated the same as
+	// other stuck sync plans.
+	NeverScheduledState string
+
+	// EmptyOrgState is the optional service state indicated if any
+	// organizations do not have any sync plans (a possible
+	// misconfiguration). Organizations without sync plans do not affect the
+	// service state if not set.
+	EmptyOrgState string
+}
+
+// stuckIsCritical indicates whether meeting the warning thresholds for stuck
+// sync plans results in a CRITICAL state.
+func (t StateThresholds) stuckIsCritical() bool {
+	return strings.EqualFold(t.StuckState, nagios.StateCRITICALLabel)
+}
+
+// excludesNeverScheduled indicates whether sync plans which have never been
+// scheduled are mapped to a service state separately from the stuck sync
+// plan thresholds.
+func (t StateThresholds) excludesNeverScheduled() bool {
+	return t.NeverScheduledState != ""
+}
+
+// CountThreshold is a threshold applied to a count of evaluated items (e.g.,
+// the number of stuck sync plans or the number of days a sync plan has been
+// stuck).
+type CountThreshold interface {
+	// IsSet indicates whether a threshold value was specified.
+	IsSet() bool
+
+	// Exceeded indicates whether the given value exceeds the threshold.
+	Exceeded(value float64) bool
+}
+
+// countThresholdExceeded indicates whether the given optional threshold is
+// set and exceeded by the given count.
+func countThresholdExceeded(threshold CountThreshold, count int) bool {
+	if threshold == nil || !threshold.IsSet() {
+		return false
+	}
+
+	return threshold.Exceeded(float64(count))
+}
+
+// NumPlansStuckFor returns the total number of sync plans for all
+// organizations in the collection which have been in a "stuck" state for at
+// least the given number of days as of the given evaluation reference time.
+func (orgs Organizations) NumPlansStuckFor(now time.Time, days int) int {
+	var num int
+
+	for _, org := range orgs {
+		num += org.SyncPlans.NumStuckFor(now, days)
+	}
+
+	return num
+}
+
+// NumPlansNeverScheduled returns the total number of enabled sync plans for
+// all organizations in the collection which are in a "stuck" state as of the
+// given evaluation reference time because they have never been scheduled
+// (no next sync time).
+func (orgs Organizations) NumPlansNeverScheduled(now time.Time) int {
+	var num int
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if syncPlan.IsNeverScheduled() && syncPlan.IsStuck(now) {
+				num++
+			}
+		}
+	}
+
+	return num
+}
+
+// numPlansStuckForThresholds returns the total number of sync plans for all
+// organizations in the collection which have been in a "stuck" state for a
+// number of days exceeding the given optional days stuck threshold as of the
+// given evaluation reference time and which are subject to the stuck sync
+// plan thresholds. All stuck sync plans are counted if the days stuck
+// threshold is not set.
+func (orgs Organizations) numPlansStuckForThresholds(now time.Time, daysStuck CountThreshold, thresholds StateThresholds) int {
+	var num int
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if thresholds.excludesNeverScheduled() && syncPlan.IsNeverScheduled() {
+				continue
+			}
+
+			if !syncPlan.IsStuck(now) {
+				continue
+			}
+
+			if daysStuck == nil || !daysStuck.IsSet() ||
+				countThresholdExceeded(daysStuck, syncPlan.DaysStuck(now)) {
+				num++
+			}
+		}
+	}
+
+	return num
+}
+
+// stuckWarningExceeded indicates whether the warning thresholds for stuck
+// sync plans are met as of the given evaluation reference time.
+func (orgs Organizations) stuckWarningExceeded(now time.Time, thresholds StateThresholds) bool {
+	numStuck := orgs.numPlansStuckForThresholds(now, thresholds.DaysStuckWarning, thresholds)
+
+	if thresholds.StuckCountWarning != nil && thresholds.StuckCountWarning.IsSet() {
+		return countThresholdExceeded(thresholds.StuckCountWarning, numStuck)
+	}
+
+	return numStuck > 0
+}
+
+// MaxDaysStuck returns the largest number of days that any sync plan in the
+// collection has been in a "stuck" state as of the given evaluation
+// reference time. Zero is returned if no sync plans are stuck.
+func (orgs Organizations) MaxDaysStuck(now time.Time) int {
+	var max int
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			if !syncPlan.IsStuck(now) {
+				continue
+			}
+
+			if daysStuck := syncPlan.DaysStuck(now); daysStuck > max {
+				max = daysStuck
+			}
+		}
+	}
+
+	return max
 }
 
 // HasCriticalState indicates whether any items in the collection were
-// evaluated to a CRITICAL state.
-func (orgs Organizations) HasCriticalState() bool {
-	// TODO: Add support for performing threshold check to determine how many
-	// days in the past a sync plan has been stuck. If greater than given
-	// threshold indicate CRITICAL state.
-	return false
+// evaluated to a CRITICAL state as of the given evaluation reference time
+// using the given thresholds.
+func (orgs Organizations) HasCriticalState(now time.Time, thresholds StateThresholds) bool {
+	if strings.EqualFold(thresholds.NeverScheduledState, nagios.StateCRITICALLabel) &&
+		orgs.NumPlansNeverScheduled(now) > 0 {
+		return true
+	}
+
+	if countThresholdExceeded(thresholds.StuckCountCritical, orgs.numPlansStuckForThresholds(now, nil, thresholds)) {
+		return true
+	}
+
+	if thresholds.stuckIsCritical() && orgs.stuckWarningExceeded(now, thresholds) {
+		return true
+	}
+
+	if thresholds.DaysStuckCritical == nil || !thresholds.DaysStuckCritical.IsSet() {
+		return false
+	}
+
+	return orgs.numPlansStuckForThresholds(now, thresholds.DaysStuckCritical, thresholds) > 0
 }
 
 // HasWarningState indicates whether any items in the collection were
-// evaluated to a WARNING state.
-func (orgs Organizations) HasWarningState() bool {
-	return !orgs.HasCriticalState() && orgs.NumProblemPlans() > 0
+// evaluated to a WARNING state as of the given evaluation reference time
+// using the given thresholds.
+func (orgs Organizations) HasWarningState(now time.Time, thresholds StateThresholds) bool {
+	if orgs.HasCriticalState(now, thresholds) {
+		return false
+	}
+
+	// Failed or stale product syncs are not subject to the stuck sync plan
+	// thresholds.
+	if orgs.NumPlansWithProductProblems(now) > 0 {
+		return true
+	}
+
+	if strings.EqualFold(thresholds.NeverScheduledState, nagios.StateWARNINGLabel) &&
+		orgs.NumPlansNeverScheduled(now) > 0 {
+		return true
+	}
+
+	if strings.EqualFold(thresholds.EmptyOrgState, nagios.StateWARNINGLabel) &&
+		orgs.NumOrgsWithoutPlans() > 0 {
+		return true
+	}
+
+	return orgs.stuckWarningExceeded(now, thresholds)
 }
 
 // ServiceState returns the appropriate Service Check Status label and exit
-// code for the collection's evaluation results.
-func (orgs Organizations) ServiceState() nagios.ServiceState {
+// code for the collection's evaluation results as of the given evaluation
+// reference time using the given thresholds.
+func (orgs Organizations) ServiceState(now time.Time, thresholds StateThresholds) nagios.ServiceState {
 	var stateLabel string
 	var stateExitCode int
 
 	switch {
-	case orgs.HasCriticalState():
+	case orgs.HasCriticalState(now, thresholds):
 		stateLabel = nagios.StateCRITICALLabel
 		stateExitCode = nagios.StateCRITICALExitCode
-	case orgs.HasWarningState():
+	case orgs.HasWarningState(now, thresholds):
 		stateLabel = nagios.StateWARNINGLabel
 		stateExitCode = nagios.StateWARNINGExitCode
-	case orgs.IsOKState():
+	default:
 		stateLabel = nagios.StateOKLabel
 		stateExitCode = nagios.StateOKExitCode
-	default:
-		stateLabel = nagios.StateUNKNOWNLabel
-		stateExitCode = nagios.StateUNKNOWNExitCode
 	}
 
 	return nagios.ServiceState{
@@ -0,0 +1,305 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// syncPlansTestServer is a test server which serves a single sync plan for
+// each organization while recording the number of concurrent requests.
+// Requests for the given failing organization ID are rejected.
+type syncPlansTestServer struct {
+	*httptest.Server
+
+	requests    int32
+	inFlight    int32
+	maxInFlight int32
+}
+
+// newSyncPlansTestServer returns a syncPlansTestServer. Responses for lower
+// organization IDs are delayed longer so that retrieval completes out of
+// order.
+func newSyncPlansTestServer(t *testing.T, numOrgs int, failingOrgID int) *syncPlansTestServer {
+	t.Helper()
+
+	var ts syncPlansTestServer
+
+	ts.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&ts.requests, 1)
+
+		current := atomic.AddInt32(&ts.inFlight, 1)
+		defer atomic.AddInt32(&ts.inFlight, -1)
+
+		for {
+			highest := atomic.LoadInt32(&ts.maxInFlight)
+			if current <= highest || atomic.CompareAndSwapInt32(&ts.maxInFlight, highest, current) {
+				break
+			}
+		}
+
+		// Path format: /katello/api/v2/organizations/{id}/sync_plans
+		fields := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		orgID, err := strconv.Atoi(fields[len(fields)-2])
+		if err != nil {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		time.Sleep(time.Duration(numOrgs-orgID+1) * 10 * time.Millisecond)
+
+		if orgID == failingOrgID {
+			http.Error(w, `{"error":{"message":"invalid request"}}`, http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(
+			w,
+			`{"total":1,"subtotal":1,"page":1,"per_page":30,"search":null,"results":[`+
+				`{"id":%d,"name":"plan-%d","organization_id":%d,"interval":"daily","enabled":true}]}`,
+			orgID*100, orgID, orgID,
+		)
+	}))
+	t.Cleanup(ts.Close)
+
+	return &ts
+}
+
+// client returns an APIClient for the test server using the given
+// concurrency limit.
+func (ts *syncPlansTestServer) client(t *testing.T, maxConcurrent int) *APIClient {
+	t.Helper()
+
+	server, port := testServerAddress(t, ts.Server)
+
+	authInfo := APIAuthInfo{
+		Server:      server,
+		Port:        port,
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	limits := APILimits{
+		PerPage:       30,
+		MaxConcurrent: maxConcurrent,
+	}
+
+	return NewAPIClient(authInfo, limits, zerolog.Nop())
+}
+
+// testOrgs returns a collection of organizations with sequential IDs
+// starting with 1.
+func testOrgs(numOrgs int) Organizations {
+	orgs := make(Organizations, 0, numOrgs)
+	for i := 1; i <= numOrgs; i++ {
+		orgs = append(orgs, Organization{ID: i, Name: fmt.Sprintf("org-%d", i)})
+	}
+
+	return orgs
+}
+
+func TestGetOrgsSyncPlansPreservesOrder(t *testing.T) {
+	const numOrgs = 8
+
+	ts := newSyncPlansTestServer(t, numOrgs, 0)
+	orgs := testOrgs(numOrgs)
+
+	if err := getOrgsSyncPlans(context.Background(), ts.client(t, 4), QueryOptions{}, orgs, time.Now()); err != nil {
+		t.Fatalf("failed to retrieve sync plans: %v", err)
+	}
+
+	for i, org := range orgs {
+		if org.ID != i+1 {
+			t.Errorf("got organization ID %d at index %d, want %d", org.ID, i, i+1)
+		}
+
+		if len(org.SyncPlans) != 1 {
+			t.Errorf("got %d sync plans for organization %q, want 1", len(org.SyncPlans), org.Name)
+
+			continue
+		}
+
+		if want := fmt.Sprintf("plan-%d", org.ID); org.SyncPlans[0].Name != want {
+			t.Errorf("got sync plan %q for organization %q, want %q", org.SyncPlans[0].Name, org.Name, want)
+		}
+	}
+}
+
+func TestGetOrgsSyncPlansLimitsConcurrency(t *testing.T) {
+	const numOrgs = 8
+
+	for _, maxConcurrent := range []int{1, 3} {
+		t.Run(strconv.Itoa(maxConcurrent), func(t *testing.T) {
+			ts := newSyncPlansTestServer(t, numOrgs, 0)
+
+			if err := getOrgsSyncPlans(context.Background(), ts.client(t, maxConcurrent), QueryOptions{}, testOrgs(numOrgs), time.Now()); err != nil {
+				t.Fatalf("failed to retrieve sync plans: %v", err)
+			}
+
+			if got := atomic.LoadInt32(&ts.maxInFlight); got > int32(maxConcurrent) {
+				t.Errorf("got %d concurrent requests, want no more than %d", got, maxConcurrent)
+			}
+
+			if got := atomic.LoadInt32(&ts.requests); got != numOrgs {
+				t.Errorf("got %d requests, want %d", got, numOrgs)
+			}
+		})
+	}
+}
+
+func TestGetOrgsSyncPlansStopsAfterFailure(t *testing.T) {
+	const numOrgs = 8
+	const failingOrgID = 2
+
+	ts := newSyncPlansTestServer(t, numOrgs, failingOrgID)
+
+	err := getOrgsSyncPlans(context.Background(), ts.client(t, 1), QueryOptions{}, testOrgs(numOrgs), time.Now())
+
+	switch {
+	case err == nil:
+		t.Fatal("want error for failed organization, got nil")
+
+	case !errors.Is(err, ErrRequestRejected):
+		t.Errorf("got error %v, want %v", err, ErrRequestRejected)
+
+	case !strings.Contains(err.Error(), fmt.Sprintf("id: %d", failingOrgID)):
+		t.Errorf("got error %v, want error identifying organization %d", err, failingOrgID)
+	}
+
+	// Retrieval is performed one organization at a time, so no further
+	// requests are submitted after the failing organization.
+	if got := atomic.LoadInt32(&ts.requests); got != failingOrgID {
+		t.Errorf("got %d requests, want %d", got, failingOrgID)
+	}
+}
+
+func TestGetOrgsSyncPlansContextCanceled(t *testing.T) {
+	const numOrgs = 4
+
+	ts := newSyncPlansTestServer(t, numOrgs, 0)
+	client := ts.client(t, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the context once retrieval for the first organization has
+	// completed but before retrieval for the next organization is launched.
+	client.Logger = zerolog.New(io.Discard).Level(zerolog.DebugLevel).Hook(
+		zerolog.HookFunc(func(_ *zerolog.Event, _ zerolog.Level, msg string) {
+			if msg == "Finished sync plans retrieval for this organization" {
+				cancel()
+			}
+		}),
+	)
+
+	orgs := testOrgs(numOrgs)
+
+	err := getOrgsSyncPlans(ctx, client, QueryOptions{}, orgs, time.Now())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	if got := atomic.LoadInt32(&ts.requests); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+
+	if len(orgs[0].SyncPlans) != 1 {
+		t.Errorf("got %d sync plans for organization %q, want 1", len(orgs[0].SyncPlans), orgs[0].Name)
+	}
+}
+
+func TestOrganizationsIgnoreSyncPlans(t *testing.T) {
+	orgs := Organizations{
+		{
+			ID:   1,
+			Name: "Example",
+			SyncPlans: SyncPlans{
+				{Name: "Daily RHEL"},
+				{Name: "legacy-epel"},
+				{Name: "Test-Plan"},
+			},
+		},
+	}
+
+	skipped := orgs.IgnoreSyncPlans([]string{"Legacy*", "/^test-/"})
+
+	if got := len(orgs[0].SyncPlans); got != 1 {
+		t.Fatalf("got %d sync plans, want 1", got)
+	}
+
+	if got := orgs[0].SyncPlans[0].Name; got != "Daily RHEL" {
+		t.Errorf("got sync plan %q, want %q", got, "Daily RHEL")
+	}
+
+	ignored := skipped.ByRule(SkipRulePlanIgnore)
+	if len(ignored) != 2 {
+		t.Fatalf("got %d ignored sync plans, want 2", len(ignored))
+	}
+
+	if want := "Example / legacy-epel"; ignored[0].Item != want {
+		t.Errorf("got ignored item %q, want %q", ignored[0].Item, want)
+	}
+
+	if want := `matched pattern "/^test-/"`; ignored[1].Reason != want {
+		t.Errorf("got reason %q, want %q", ignored[1].Reason, want)
+	}
+
+	if skipped := orgs.IgnoreSyncPlans(nil); len(skipped) != 0 {
+		t.Errorf("got %d ignored sync plans without patterns, want 0", len(skipped))
+	}
+}
+
+func TestOrganizationsExcludeProducts(t *testing.T) {
+	orgs := Organizations{
+		{
+			ID:   1,
+			Name: "Example",
+			SyncPlans: SyncPlans{
+				{
+					Name: "Daily",
+					Products: Products{
+						{Name: "Red Hat Enterprise Linux 7 Server", Label: "rhel-7-server"},
+						{Name: "Red Hat Enterprise Linux 9", Label: "rhel-9"},
+						{Name: "EPEL", Label: "epel-8"},
+					},
+				},
+			},
+		},
+	}
+
+	skipped := orgs.ExcludeProducts([]string{"red hat enterprise linux 7*", "/^EPEL-[78]$/"})
+
+	wantExcluded := []bool{true, false, true}
+	for i, product := range orgs[0].SyncPlans[0].Products {
+		if product.Excluded != wantExcluded[i] {
+			t.Errorf("got excluded %t for product %q, want %t", product.Excluded, product.Name, wantExcluded[i])
+		}
+	}
+
+	if got := len(skipped.ByRule(SkipRuleProductExclude)); got != 2 {
+		t.Errorf("got %d excluded products, want 2", got)
+	}
+}
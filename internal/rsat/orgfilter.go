@@ -0,0 +1,114 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OrgFilter is the collection of criteria used to limit the organizations
+// retrieved and evaluated. Organizations are identified by name, label or
+// ID. Names and labels are compared case-insensitively.
+type OrgFilter struct {
+	// Include is the optional list of organizations to evaluate. If
+	// specified, all other organizations are skipped.
+	Include []string
+
+	// Exclude is the optional list of organizations to skip. Organizations
+	// listed here are skipped even if also listed for inclusion.
+	Exclude []string
+}
+
+// IsSet indicates whether any filtering criteria has been specified.
+func (f OrgFilter) IsSet() bool {
+	return len(f.Include) > 0 || len(f.Exclude) > 0
+}
+
+// Apply returns a new collection containing only the organizations from the
+// given collection which satisfy the filter criteria along with a record of
+// each organization skipped. An error is returned if any of the organizations
+// to include are not found in the given collection; organizations to exclude
+// which are not found are ignored.
+func (f OrgFilter) Apply(orgs Organizations) (Organizations, SkippedItems, error) {
+	var skipped SkippedItems
+
+	if !f.IsSet() {
+		return orgs, skipped, nil
+	}
+
+	found := make(map[string]bool, len(f.Include))
+	filtered := make(Organizations, 0, len(orgs))
+
+	for _, org := range orgs {
+		included, isIncluded := org.identifiedBy(f.Include)
+		if isIncluded {
+			found[included] = true
+		}
+
+		if excluded, ok := org.identifiedBy(f.Exclude); ok {
+			skipped.Add(
+				SkippedItemTypeOrganization,
+				org.Name,
+				SkipRuleOrgExclude,
+				fmt.Sprintf("matched excluded organization %q", excluded),
+			)
+
+			continue
+		}
+
+		if len(f.Include) > 0 && !isIncluded {
+			skipped.Add(
+				SkippedItemTypeOrganization,
+				org.Name,
+				SkipRuleOrgInclude,
+				"not in list of organizations to evaluate",
+			)
+
+			continue
+		}
+
+		filtered = append(filtered, org)
+	}
+
+	var missing []string
+	for _, value := range f.Include {
+		if !found[value] {
+			missing = append(missing, value)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf(
+			"%w: %s",
+			ErrOrgNotFound,
+			strings.Join(missing, ", "),
+		)
+	}
+
+	return filtered, skipped, nil
+}
+
+// identifiedBy returns the first value from the given list which identifies
+// the organization by name, label or ID. A boolean value is returned to
+// indicate whether a match was found.
+func (org Organization) identifiedBy(values []string) (string, bool) {
+	id := strconv.Itoa(org.ID)
+
+	for _, value := range values {
+		switch {
+		case strings.EqualFold(value, org.Name),
+			strings.EqualFold(value, org.Label),
+			value == id:
+			return value, true
+		}
+	}
+
+	return "", false
+}
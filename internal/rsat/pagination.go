@@ -0,0 +1,192 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// pagedResponse is implemented by API query response types which provide a
+// single page of results from a paginated collection.
+type pagedResponse[T any] interface {
+	// pageResults returns the results provided by the response along with
+	// the number of objects matching the query across all pages.
+	pageResults() ([]T, int)
+}
+
+// fetchAllPages is a helper function used to retrieve all results from the
+// given paginated API endpoint. Each page of results is decoded as response
+// type R and the results collected. The given query options are applied to
+// each request. The given label (e.g., "sync plans") is used in log
+// messages.
+func fetchAllPages[T any, R pagedResponse[T]](
+	ctx context.Context,
+	client *APIClient,
+	apiURL string,
+	opts QueryOptions,
+	logger zerolog.Logger,
+	label string,
+) ([]T, error) {
+	perPage, perPageKnown := client.pageLimit()
+
+	allResults := make([]T, 0, perPage*2)
+
+	apiURLQueryParams := make(map[string]string)
+	apiURLQueryParams[APIEndpointURLQueryParamFullResultKey] = APIEndpointURLQueryParamFullResultDefaultValue
+	if err := opts.setQueryParams(apiURLQueryParams); err != nil {
+		return nil, err
+	}
+
+	logKey := strings.ReplaceAll(label, " ", "_")
+
+	fetch := func(page int, perPage int) (int, int, error) {
+		logger.Debug().
+			Msgf("Collecting %s from the API", label)
+
+		apiURLQueryParams[APIEndpointURLQueryParamPerPageKey] = strconv.Itoa(perPage)
+		apiURLQueryParams[APIEndpointURLQueryParamPageKey] = strconv.Itoa(page)
+
+		numNew, subtotal, err := fetchPage[T, R](
+			ctx,
+			client,
+			apiURL,
+			apiURLQueryParams,
+			logger,
+			func(result T) { allResults = append(allResults, result) },
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		logger.Debug().
+			Str("api_endpoint", apiURL).
+			Int(logKey+"_collected", len(allResults)).
+			Int(logKey+"_new", numNew).
+			Int(logKey+"_remaining", subtotal-len(allResults)).
+			Msgf("Added decoded %s to collection", label)
+
+		return numNew, subtotal, nil
+	}
+
+	var numNew, subtotal int
+	var fetchErr error
+
+	switch {
+	case perPageKnown:
+		numNew, subtotal, fetchErr = fetch(1, perPage)
+
+	default:
+		// Discard results from a rejected or truncated first page before
+		// stepping down to a lower pagination limit.
+		perPage, numNew, subtotal, fetchErr = client.determinePageLimit(
+			func(perPage int) (int, int, error) {
+				allResults = allResults[:0]
+
+				return fetch(1, perPage)
+			},
+			logger,
+		)
+	}
+
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	logger.Debug().
+		Msgf("Determining if we have collected all %s from the API", label)
+
+	// Guard against an endless loop if the API returns an empty page.
+	for page := 2; subtotal-len(allResults) > 0 && numNew > 0; page++ {
+		numNew, subtotal, fetchErr = fetch(page, perPage)
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+
+		logger.Debug().
+			Msgf("Determining if we have collected all %s from the API", label)
+	}
+
+	return allResults, nil
+}
+
+// fetchPage is a helper function used to retrieve a single page of results
+// from the given paginated API endpoint using the given query parameters.
+// Each decoded result is provided to the given collect function. The number
+// of results decoded is returned along with the number of objects matching
+// the query across all pages.
+func fetchPage[T any, R pagedResponse[T]](
+	ctx context.Context,
+	client *APIClient,
+	apiURL string,
+	apiURLQueryParams map[string]string,
+	logger zerolog.Logger,
+	collect func(T),
+) (int, int, error) {
+	response, respErr := client.SubmitRequest(ctx, RequestOptions{
+		Endpoint:    apiURL,
+		QueryParams: apiURLQueryParams,
+		Logger:      &logger,
+	})
+	if respErr != nil {
+		return 0, 0, respErr
+	}
+
+	// Close the response body once we're done with it. We explicitly close
+	// here vs deferring in the caller to prevent accumulating client
+	// connections to the API if we need to perform multiple paged requests.
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Msg("error closing response body")
+		}
+	}()
+
+	logger.Debug().Msgf(
+		"Decoding JSON data from %q using a limit of %d bytes",
+		apiURL,
+		client.AuthInfo.ReadLimit,
+	)
+
+	var numNew, subtotal int
+	var decodeErr error
+
+	switch {
+	case client.Limits.StreamDecode:
+		numNew, subtotal, decodeErr = decodePageResults(
+			response.Body,
+			logger,
+			apiURL,
+			client.AuthInfo.ReadLimit,
+			collect,
+		)
+
+	default:
+		var queryResp R
+		decodeErr = decode(&queryResp, response.Body, logger, apiURL, client.AuthInfo.ReadLimit)
+
+		var results []T
+		results, subtotal = queryResp.pageResults()
+		for _, result := range results {
+			collect(result)
+		}
+		numNew = len(results)
+	}
+
+	if decodeErr != nil {
+		return 0, 0, decodeErr
+	}
+
+	logger.Debug().
+		Str("api_endpoint", apiURL).
+		Msg("Successfully decoded JSON data")
+
+	return numNew, subtotal, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// pagedTestServer is a test server which serves fixed collections of JSON
+// encoded results for paginated API endpoints using the pagination settings
+// requested by the client. The query parameters for each request are
+// recorded for later review.
+type pagedTestServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	queries map[string][]url.Values
+}
+
+// newPagedTestServer returns a pagedTestServer serving the given JSON encoded
+// results for each API endpoint path. Requests for other paths are rejected.
+func newPagedTestServer(t *testing.T, results map[string][]string) *pagedTestServer {
+	t.Helper()
+
+	ts := pagedTestServer{
+		queries: make(map[string][]url.Values),
+	}
+
+	ts.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pathResults, ok := results[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		ts.mu.Lock()
+		ts.queries[r.URL.Path] = append(ts.queries[r.URL.Path], r.URL.Query())
+		ts.mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get(APIEndpointURLQueryParamPageKey))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get(APIEndpointURLQueryParamPerPageKey))
+
+		start := (page - 1) * perPage
+		end := start + perPage
+		if start > len(pathResults) {
+			start = len(pathResults)
+		}
+		if end > len(pathResults) {
+			end = len(pathResults)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(
+			w,
+			`{"total":%d,"subtotal":%d,"page":%d,"per_page":%d,"search":null,"results":[%s]}`,
+			len(pathResults), len(pathResults), page, perPage,
+			strings.Join(pathResults[start:end], ","),
+		)
+	}))
+	t.Cleanup(ts.Close)
+
+	return &ts
+}
+
+// client returns an APIClient for the test server using the given
+// pagination limit.
+func (ts *pagedTestServer) client(t *testing.T, perPage int) *APIClient {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse test server address: %v", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	authInfo := APIAuthInfo{
+		Server:      host,
+		Port:        port,
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	return NewAPIClient(authInfo, APILimits{PerPage: perPage}, zerolog.Nop())
+}
+
+// requests returns the query parameters for each request received for the
+// given API endpoint path.
+func (ts *pagedTestServer) requests(path string) []url.Values {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return ts.queries[path]
+}
+
+// assertPagedRequests asserts that the given number of page requests were
+// received for the given API endpoint path and that each request used the
+// given scoped search query.
+func assertPagedRequests(t *testing.T, ts *pagedTestServer, path string, wantPages int, wantSearch string) {
+	t.Helper()
+
+	queries := ts.requests(path)
+	if len(queries) != wantPages {
+		t.Fatalf("got %d requests for %s, want %d", len(queries), path, wantPages)
+	}
+
+	for i, query := range queries {
+		if got := query.Get(APIEndpointURLQueryParamPageKey); got != strconv.Itoa(i+1) {
+			t.Errorf("got page %q for request %d to %s, want %d", got, i+1, path, i+1)
+		}
+
+		if got := query.Get(APIEndpointURLQueryParamSearchKey); got != wantSearch {
+			t.Errorf("got search %q for request %d to %s, want %q", got, i+1, path, wantSearch)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ParametersResponse represents the API response from a request for all
+// parameters set for a specific organization.
+type ParametersResponse struct {
+	// Parameters is the collection of Parameters returned in the API query
+	// response.
+	Parameters Parameters `json:"results"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the parameters provided by the response along with
+// the number of parameters matching the query across all pages.
+func (r ParametersResponse) pageResults() ([]Parameter, int) {
+	return r.Parameters, r.Subtotal
+}
+
+// Parameter is a Red Hat Satellite (Foreman) parameter. Parameters are
+// name/value pairs set at various levels (e.g., organization, host group) and
+// are often used to annotate objects with site-specific metadata.
+type Parameter struct {
+	CreatedAt     StandardAPITime `json:"created_at"`
+	UpdatedAt     StandardAPITime `json:"updated_at"`
+	ParameterType NullString      `json:"parameter_type"`
+	Name          string          `json:"name"`
+
+	// Value is the current value for the parameter. Parameter values are
+	// not consistently typed (e.g., string, integer, boolean, array) so the
+	// raw JSON value is retained for evaluation by the caller.
+	Value json.RawMessage `json:"value"`
+
+	ID int `json:"id"`
+}
+
+// Parameters is a collection of Red Hat Satellite parameters.
+type Parameters []Parameter
+
+// ValueString provides a display friendly version of the parameter value.
+func (p Parameter) ValueString() string {
+	if len(p.Value) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(p.Value, &s); err == nil {
+		return s
+	}
+
+	return strings.Trim(string(p.Value), `"`)
+}
+
+// Lookup returns the parameter from the collection with the given name. A
+// boolean value is returned to indicate whether a match was found.
+func (params Parameters) Lookup(name string) (Parameter, bool) {
+	for _, param := range params {
+		if param.Name == name {
+			return param, true
+		}
+	}
+
+	return Parameter{}, false
+}
+
+// GetOrgParameters uses the given client to retrieve all parameters set for
+// the specified Red Hat Satellite organization. The given query options
+// (e.g., a scoped search of name = owner) are used to limit and order the
+// parameters retrieved.
+func GetOrgParameters(ctx context.Context, client *APIClient, opts QueryOptions, org Organization) (Parameters, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	subLogger := client.Logger.With().
+		Int("org_id", org.ID).
+		Str("org_name", org.Name).
+		Logger()
+
+	apiURL := fmt.Sprintf(
+		OrganizationParametersAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+		org.ID,
+	)
+
+	allParameters, err := fetchAllPages[Parameter, ParametersResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		subLogger,
+		"parameters",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subLogger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all parameters for organization")
+
+	return allParameters, nil
+}
+
+// SetOrgOwners uses the given client to retrieve the parameter with the
+// given name for each organization in the collection and records the
+// parameter value as the owner of the organization. Organizations without
+// the parameter are not modified.
+func SetOrgOwners(ctx context.Context, client *APIClient, orgs Organizations, parameterName string) error {
+	for i := range orgs {
+		params, err := GetOrgParameters(ctx, client, QueryOptions{}, orgs[i])
+		if err != nil {
+			return fmt.Errorf(
+				"failed to retrieve parameters for organization"+
+					" (name: %s, id: %d): %w",
+				orgs[i].Name,
+				orgs[i].ID,
+				err,
+			)
+		}
+
+		if param, ok := params.Lookup(parameterName); ok {
+			orgs[i].Owner = param.ValueString()
+		}
+	}
+
+	return nil
+}
+
+// SetSyncPlanOwners records the owner of each sync plan in the collection.
+// If a pattern is given and it matches the sync plan name the owner is taken
+// from the named "owner" capture group (or the first capture group if not
+// named). Otherwise the owner of the organization (if any) is used.
+func (orgs Organizations) SetSyncPlanOwners(pattern *regexp.Regexp) {
+	for i := range orgs {
+		for j := range orgs[i].SyncPlans {
+			syncPlan := &orgs[i].SyncPlans[j]
+
+			syncPlan.Owner = orgs[i].Owner
+
+			if owner := ownerFromName(pattern, syncPlan.Name); owner != "" {
+				syncPlan.Owner = owner
+			}
+		}
+	}
+}
+
+// ownerFromName is a helper function used to obtain an owner value from the
+// given name using the given pattern. An empty string is returned if the
+// pattern is not provided or does not match.
+func ownerFromName(pattern *regexp.Regexp, name string) string {
+	if pattern == nil {
+		return ""
+	}
+
+	matches := pattern.FindStringSubmatch(name)
+	if matches == nil {
+		return ""
+	}
+
+	if idx := pattern.SubexpIndex("owner"); idx > 0 {
+		return matches[idx]
+	}
+
+	if len(matches) > 1 {
+		return matches[1]
+	}
+
+	return ""
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// autoPerPageCandidates is the sequence of pagination limits attempted (in
+// order) when the pagination limit is determined automatically. The last
+// value is the default per-page limit of the Red Hat Satellite API.
+var autoPerPageCandidates = []int{1000, 500, 250, 100, 50, 20}
+
+// pageFetcher retrieves the first page of results using the given
+// pagination limit. The number of results retrieved is returned along with
+// the number of objects matching the query across all pages.
+type pageFetcher func(perPage int) (int, int, error)
+
+// isPerPageRejection indicates whether the given error is the result of the
+// server rejecting a request due to the pagination limit or the response
+// exceeding the read limit.
+func isPerPageRejection(err error) bool {
+	return errors.Is(err, ErrRequestRejected) ||
+		errors.Is(err, ErrReadLimitReached)
+}
+
+// isPerPageTruncated indicates whether the server returned fewer results
+// than requested by the given pagination limit even though more results are
+// available. This occurs if the server enforces a lower pagination limit.
+func isPerPageTruncated(perPage int, numResults int, subtotal int) bool {
+	return numResults < perPage && numResults < subtotal
+}
+
+// pageLimit returns the pagination limit applied to API query results and
+// whether the limit is known. The limit is not known if it is to be
+// determined automatically and has not yet been determined.
+func (c *APIClient) pageLimit() (int, bool) {
+	if !c.Limits.PerPageAuto {
+		return c.Limits.PerPage, true
+	}
+
+	c.autoPerPageMutex.Lock()
+	defer c.autoPerPageMutex.Unlock()
+
+	return c.autoPerPage, c.autoPerPage > 0
+}
+
+// determinePageLimit determines the pagination limit by retrieving the first
+// page of results using the given fetch function, starting with the largest
+// candidate limit and stepping down if the server rejects or truncates the
+// results. The chosen limit is recorded for use by later queries and
+// returned along with the number of results retrieved and the number of
+// objects matching the query across all pages.
+//
+// If the limit has already been determined (e.g., by a concurrent query)
+// the first page of results is retrieved using that limit.
+func (c *APIClient) determinePageLimit(fetch pageFetcher, logger zerolog.Logger) (int, int, int, error) {
+	c.autoPerPageMutex.Lock()
+
+	if perPage := c.autoPerPage; perPage > 0 {
+		c.autoPerPageMutex.Unlock()
+
+		numResults, subtotal, err := fetch(perPage)
+
+		return perPage, numResults, subtotal, err
+	}
+
+	defer c.autoPerPageMutex.Unlock()
+
+	for i, perPage := range autoPerPageCandidates {
+		numResults, subtotal, err := fetch(perPage)
+
+		last := i == len(autoPerPageCandidates)-1
+
+		switch {
+		case err != nil && isPerPageRejection(err) && !last:
+			logger.Debug().
+				Err(err).
+				Int("per_page", perPage).
+				Msg("Pagination limit rejected; stepping down")
+
+			continue
+
+		case err != nil:
+			return 0, 0, 0, err
+
+		case isPerPageTruncated(perPage, numResults, subtotal) && !last:
+			logger.Debug().
+				Int("per_page", perPage).
+				Int("results", numResults).
+				Int("subtotal", subtotal).
+				Msg("Results truncated by server; stepping down")
+
+			continue
+		}
+
+		c.autoPerPage = perPage
+
+		logger.Info().
+			Int("per_page", perPage).
+			Msg("Automatically determined pagination limit")
+
+		return perPage, numResults, subtotal, nil
+	}
+
+	// Not reached; the last candidate is always accepted.
+	return 0, 0, 0, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestFetchAllPagesAutoPerPage(t *testing.T) {
+	const total = 120
+
+	// Reject per-page limits larger than 250 and silently cap the number of
+	// results per page at 100.
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perPage, _ := strconv.Atoi(r.URL.Query().Get(APIEndpointURLQueryParamPerPageKey))
+		page, _ := strconv.Atoi(r.URL.Query().Get(APIEndpointURLQueryParamPageKey))
+
+		if perPage > 250 {
+			http.Error(w, "per_page too large", http.StatusBadRequest)
+
+			return
+		}
+
+		if perPage > 100 {
+			perPage = 100
+		}
+
+		results := make([]string, 0, perPage)
+		for id := (page-1)*perPage + 1; id <= total && len(results) < perPage; id++ {
+			results = append(results, fmt.Sprintf(`{"id":%d,"name":"org%d"}`, id, id))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(
+			w,
+			`{"total":%d,"subtotal":%d,"page":%d,"per_page":%d,"results":[%s]}`,
+			total, total, page, perPage, strings.Join(results, ","),
+		)
+	}))
+	defer ts.Close()
+
+	authInfo := APIAuthInfo{
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPageAuto: true}, zerolog.Nop())
+
+	orgs, err := fetchAllPages[Organization, OrganizationsResponse](
+		context.Background(),
+		client,
+		ts.URL+"/api/v2/organizations",
+		QueryOptions{},
+		zerolog.Nop(),
+		"organizations",
+	)
+	if err != nil {
+		t.Fatalf("failed to retrieve organizations: %v", err)
+	}
+
+	if len(orgs) != total {
+		t.Errorf("got %d organizations, want %d", len(orgs), total)
+	}
+
+	if perPage, ok := client.pageLimit(); !ok || perPage != 100 {
+		t.Errorf("got per-page limit %d (determined: %t), want 100", perPage, ok)
+	}
+}
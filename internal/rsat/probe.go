@@ -0,0 +1,154 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/netutils"
+)
+
+// ProbeResult is the result of verifying connectivity to and authentication
+// with a Red Hat Satellite server without retrieving organizations or sync
+// plans.
+type ProbeResult struct {
+	// Server is the Red Hat Satellite server probed.
+	Server string
+
+	// Username is the user authenticated to the server.
+	Username string
+
+	// Addresses is the collection of IP Addresses the server name resolved
+	// to (filtered to the requested network type).
+	Addresses []string
+
+	// TLSVersion is the TLS version negotiated with the server.
+	TLSVersion string
+
+	// CipherSuite is the TLS cipher suite negotiated with the server.
+	CipherSuite string
+
+	// Protocol is the HTTP protocol version used for the request (e.g.,
+	// HTTP/1.1 or HTTP/2.0).
+	Protocol string
+
+	// PeerCertificates is the certificate chain presented by the server.
+	PeerCertificates []*x509.Certificate
+
+	// ServerVersion is the Foreman version reported by the server.
+	ServerVersion string
+
+	// SatelliteVersion is the Red Hat Satellite version reported by the
+	// server (if available).
+	SatelliteVersion string
+
+	// ResolveTime is the time taken to resolve the server name.
+	ResolveTime time.Duration
+
+	// RequestTime is the time taken to connect to the server, complete the
+	// TLS handshake and submit the authenticated request.
+	RequestTime time.Duration
+}
+
+// Probe verifies connectivity to and authentication with the Red Hat
+// Satellite server associated with the given API client by performing name
+// resolution, a TLS handshake and a single authenticated request for the
+// server status. The details collected prior to a failure are returned
+// along with the error.
+func Probe(ctx context.Context, client *APIClient) (ProbeResult, error) {
+	if client == nil {
+		return ProbeResult{}, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	result := ProbeResult{
+		Server:   client.AuthInfo.Server,
+		Username: client.AuthInfo.Username,
+	}
+
+	// Saved API responses are read without connecting to the server.
+	if client.AuthInfo.FixturesDir == "" {
+		resolveStart := time.Now()
+
+		addrs, resolveErr := netutils.ResolveServer(
+			ctx,
+			client.AuthInfo.resolver(),
+			client.AuthInfo.Server,
+			client.AuthInfo.NetworkType,
+			logger,
+		)
+		result.ResolveTime = time.Since(resolveStart)
+
+		if resolveErr != nil {
+			return result, fmt.Errorf(
+				"failed to resolve %s: %w",
+				client.AuthInfo.Server,
+				resolveErr,
+			)
+		}
+
+		result.Addresses = addrs
+	}
+
+	statusURL := fmt.Sprintf(
+		StatusAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	requestStart := time.Now()
+
+	response, respErr := client.SubmitRequest(ctx, RequestOptions{
+		Endpoint:    statusURL,
+		QueryParams: map[string]string{},
+	})
+	result.RequestTime = time.Since(requestStart)
+	result.PeerCertificates = client.PeerCertificates()
+
+	if respErr != nil {
+		return result, respErr
+	}
+
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Msg("error closing response body")
+		}
+	}()
+
+	result.Protocol = response.Proto
+
+	if response.TLS != nil {
+		result.TLSVersion = tls.VersionName(response.TLS.Version)
+		result.CipherSuite = tls.CipherSuiteName(response.TLS.CipherSuite)
+	}
+
+	var status StatusResponse
+	if err := decode(&status, response.Body, logger, statusURL, client.AuthInfo.ReadLimit); err != nil {
+		return result, err
+	}
+
+	result.ServerVersion = status.Version
+	result.SatelliteVersion = string(status.SatelliteVersion)
+
+	logger.Debug().
+		Strs("addresses", result.Addresses).
+		Str("tls_version", result.TLSVersion).
+		Str("server_version", result.ServerVersion).
+		Str("request_time", result.RequestTime.String()).
+		Msg("Completed connectivity and authentication probe")
+
+	return result, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestProbeFromFixtures(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		Username:    "monitoring",
+		FixturesDir: filepath.Join("testdata", "fixtures"),
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+	result, err := Probe(context.Background(), client)
+	if err != nil {
+		t.Fatalf("failed to probe server using fixtures: %v", err)
+	}
+
+	if result.ServerVersion != "3.5.1.23" {
+		t.Errorf("got server version %q, want %q", result.ServerVersion, "3.5.1.23")
+	}
+
+	if result.SatelliteVersion != "6.13.4" {
+		t.Errorf("got Satellite version %q, want %q", result.SatelliteVersion, "6.13.4")
+	}
+
+	// Name resolution is skipped when reading saved API responses.
+	if len(result.Addresses) != 0 {
+		t.Errorf("got addresses %v, want none", result.Addresses)
+	}
+}
+
+func TestProbeHTTP2(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"ok","version":"3.5.1.23"}`))
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse test server address: %v", err)
+	}
+
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	tests := []struct {
+		enableHTTP2 bool
+		want        string
+	}{
+		{enableHTTP2: false, want: "HTTP/1.1"},
+		{enableHTTP2: true, want: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests {
+		authInfo := APIAuthInfo{
+			Server:      host,
+			Port:        portNum,
+			Username:    "monitoring",
+			Password:    "secret",
+			NetworkType: "auto",
+			ReadLimit:   1024,
+			TrustCert:   true,
+		}
+
+		client := NewAPIClient(authInfo, APILimits{PerPage: 30, EnableHTTP2: tt.enableHTTP2}, zerolog.Nop())
+
+		result, err := Probe(context.Background(), client)
+		if err != nil {
+			t.Fatalf("failed to probe test server: %v", err)
+		}
+
+		if result.Protocol != tt.want {
+			t.Errorf("got protocol %q with HTTP/2 enabled: %t, want %q", result.Protocol, tt.enableHTTP2, tt.want)
+		}
+	}
+}
+
+// newTestClientCertificate generates a self-signed client certificate for
+// use with servers requiring mutual TLS authentication.
+func newTestClientCertificate(t *testing.T) *tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate client key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "monitoring"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create client certificate: %v", err)
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func TestProbeClientCertificate(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"ok","version":"3.5.1.23"}`))
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	host, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse test server address: %v", err)
+	}
+
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+
+	tests := []struct {
+		clientCert *tls.Certificate
+		wantErr    bool
+	}{
+		{clientCert: nil, wantErr: true},
+		{clientCert: newTestClientCertificate(t), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		authInfo := APIAuthInfo{
+			Server:            host,
+			Port:              portNum,
+			Username:          "monitoring",
+			Password:          "secret",
+			NetworkType:       "auto",
+			ReadLimit:         1024,
+			TrustCert:         true,
+			ClientCertificate: tt.clientCert,
+		}
+
+		client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+		_, err := Probe(context.Background(), client)
+		if gotErr := err != nil; gotErr != tt.wantErr {
+			t.Errorf("got error %v with client certificate: %t, want error: %t", err, tt.clientCert != nil, tt.wantErr)
+		}
+	}
+}
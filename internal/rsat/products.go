@@ -0,0 +1,99 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"sort"
+	"time"
+)
+
+// ProductCoverage is a product associated with a sync plan along with the
+// organization and sync plan which cover it. This is used to evaluate sync
+// plans from the perspective of products instead of organizations.
+type ProductCoverage struct {
+	// OrganizationName is the name of the organization the product belongs
+	// to.
+	OrganizationName string
+
+	// SyncPlan is the sync plan covering the product.
+	SyncPlan SyncPlan
+
+	// Product is the product covered by the sync plan.
+	Product Product
+}
+
+// ProductCoverages is a collection of products along with the organization
+// and sync plan which cover each product.
+type ProductCoverages []ProductCoverage
+
+// Contains indicates whether the given product (by ID) is present in the
+// collection.
+func (ps Products) Contains(product Product) bool {
+	for _, p := range ps {
+		if p.ID == product.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOKState indicates whether the product is in an OK state as of the given
+// evaluation reference time. A product is considered to be in a non-OK state
+// if the sync plan covering it is stuck or if the product contributes to a
+// non-OK state for the sync plan (e.g., the product is stale or its most
+// recent sync failed).
+func (pc ProductCoverage) IsOKState(now time.Time) bool {
+	switch {
+	case pc.SyncPlan.IsStuck(now):
+		return false
+
+	case pc.SyncPlan.EvaluateProductSyncState &&
+		pc.SyncPlan.FailedProducts(now).Contains(pc.Product):
+		return false
+
+	case pc.SyncPlan.StaleProducts(now).Contains(pc.Product):
+		return false
+
+	default:
+		return true
+	}
+}
+
+// ProductCoverages returns all products associated with sync plans in the
+// collection along with the organization and sync plan covering each
+// product. Entries are sorted by product name, organization name and then
+// sync plan name. Products not associated with a sync plan are not included.
+func (orgs Organizations) ProductCoverages() ProductCoverages {
+	var coverages ProductCoverages
+
+	for _, org := range orgs {
+		for _, syncPlan := range org.SyncPlans {
+			for _, product := range syncPlan.Products {
+				coverages = append(coverages, ProductCoverage{
+					OrganizationName: org.Name,
+					SyncPlan:         syncPlan,
+					Product:          product,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(coverages, func(i int, j int) bool {
+		switch {
+		case coverages[i].Product.Name != coverages[j].Product.Name:
+			return coverages[i].Product.Name < coverages[j].Product.Name
+		case coverages[i].OrganizationName != coverages[j].OrganizationName:
+			return coverages[i].OrganizationName < coverages[j].OrganizationName
+		default:
+			return coverages[i].SyncPlan.Name < coverages[j].SyncPlan.Name
+		}
+	})
+
+	return coverages
+}
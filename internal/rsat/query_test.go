@@ -0,0 +1,308 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// testQueryOptions is the query options used to assert that the scoped
+// search query and sorting criteria reach the API query string.
+var testQueryOptions = QueryOptions{
+	Search: "name ~ example",
+	Sort:   SortOptions{By: "name", Order: NullString("desc")},
+}
+
+// assertQueryOptions asserts that each request received for the given API
+// endpoint path used the given scoped search query along with the sorting
+// criteria from testQueryOptions.
+func assertQueryOptions(t *testing.T, ts *pagedTestServer, path string, wantPages int, wantSearch string) {
+	t.Helper()
+
+	assertPagedRequests(t, ts, path, wantPages, wantSearch)
+
+	for i, query := range ts.requests(path) {
+		if got := query.Get(APIEndpointURLQueryParamSortByKey); got != "name" {
+			t.Errorf("got sort by %q for request %d to %s, want %q", got, i+1, path, "name")
+		}
+
+		if got := query.Get(APIEndpointURLQueryParamSortOrderKey); got != SortOrderDescending {
+			t.Errorf("got sort order %q for request %d to %s, want %q", got, i+1, path, SortOrderDescending)
+		}
+	}
+}
+
+func TestFetchersApplyQueryOptions(t *testing.T) {
+	org := Organization{ID: 1, Name: "Example"}
+
+	tests := []struct {
+		name       string
+		path       string
+		results    []string
+		wantSearch string
+		fetch      func(ctx context.Context, client *APIClient) (int, error)
+	}{
+		{
+			name:       "organizations",
+			path:       "/api/v2/organizations",
+			results:    []string{`{"id":1,"name":"A"}`, `{"id":2,"name":"B"}`, `{"id":3,"name":"C"}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				orgs, err := GetOrganizations(ctx, client, testQueryOptions)
+				return len(orgs), err
+			},
+		},
+		{
+			name:       "sync plans",
+			path:       "/katello/api/v2/organizations/1/sync_plans",
+			results:    []string{`{"id":1,"name":"A"}`, `{"id":2,"name":"B"}`, `{"id":3,"name":"C"}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				syncPlans, err := GetSyncPlans(ctx, client, testQueryOptions, org)
+				return len(syncPlans), err
+			},
+		},
+		{
+			name:       "recurring logics",
+			path:       "/foreman_tasks/api/recurring_logics",
+			results:    []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				recurringLogics, err := GetRecurringLogics(ctx, client, testQueryOptions)
+				return len(recurringLogics), err
+			},
+		},
+		{
+			name:       "audits",
+			path:       "/api/v2/audits",
+			results:    []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			wantSearch: JoinSearch(AuditsSearchSince(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), testQueryOptions.Search),
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				audits, err := GetAudits(ctx, client, testQueryOptions, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+				return len(audits), err
+			},
+		},
+		{
+			name:       "activation keys",
+			path:       "/katello/api/v2/organizations/1/activation_keys",
+			results:    []string{`{"id":1,"name":"A"}`, `{"id":2,"name":"B"}`, `{"id":3,"name":"C"}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				keys, err := GetActivationKeys(ctx, client, testQueryOptions, org)
+				return len(keys), err
+			},
+		},
+		{
+			name:       "host collections",
+			path:       "/katello/api/v2/organizations/1/host_collections",
+			results:    []string{`{"id":1,"name":"A"}`, `{"id":2,"name":"B"}`, `{"id":3,"name":"C"}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				collections, err := GetHostCollections(ctx, client, testQueryOptions, org)
+				return len(collections), err
+			},
+		},
+		{
+			name:       "organization parameters",
+			path:       "/api/v2/organizations/1/parameters",
+			results:    []string{`{"id":1,"name":"a"}`, `{"id":2,"name":"b"}`, `{"id":3,"name":"c"}`},
+			wantSearch: testQueryOptions.Search,
+			fetch: func(ctx context.Context, client *APIClient) (int, error) {
+				params, err := GetOrgParameters(ctx, client, testQueryOptions, org)
+				return len(params), err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newPagedTestServer(t, map[string][]string{tt.path: tt.results})
+
+			got, err := tt.fetch(context.Background(), ts.client(t, 2))
+			if err != nil {
+				t.Fatalf("failed to retrieve %s: %v", tt.name, err)
+			}
+
+			if got != len(tt.results) {
+				t.Errorf("got %d results, want %d", got, len(tt.results))
+			}
+
+			assertQueryOptions(t, ts, tt.path, 2, tt.wantSearch)
+		})
+	}
+}
+
+func TestFetchersRejectInvalidSortOrder(t *testing.T) {
+	const path = "/api/v2/organizations"
+
+	ts := newPagedTestServer(t, map[string][]string{path: {`{"id":1,"name":"A"}`}})
+
+	opts := QueryOptions{Sort: SortOptions{By: "name", Order: "sideways"}}
+
+	_, err := GetOrganizations(context.Background(), ts.client(t, 2), opts)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidValue)
+	}
+
+	if got := len(ts.requests(path)); got != 0 {
+		t.Errorf("got %d requests, want 0", got)
+	}
+}
+
+func TestGetOrgsWithSyncPlansEnabledOnly(t *testing.T) {
+	const (
+		orgsPath      = "/api/v2/organizations"
+		orgPlansPath  = "/katello/api/v2/organizations/1/sync_plans"
+		bulkPlansPath = "/katello/api/v2/sync_plans"
+	)
+
+	opts := QueryOptions{Search: SyncPlansSearchEnabled}
+
+	for _, bulk := range []bool{false, true} {
+		t.Run(fmt.Sprintf("bulk=%t", bulk), func(t *testing.T) {
+			ts := newPagedTestServer(t, map[string][]string{
+				orgsPath:      {`{"id":1,"name":"A"}`},
+				orgPlansPath:  {`{"id":1,"name":"A","organization_id":1}`},
+				bulkPlansPath: {`{"id":1,"name":"A","organization_id":1}`},
+			})
+
+			client := ts.client(t, 2)
+			client.Limits.BulkSyncPlans = bulk
+
+			orgs, err := GetOrgsWithSyncPlans(context.Background(), client, opts)
+			if err != nil {
+				t.Fatalf("failed to retrieve organizations: %v", err)
+			}
+
+			if got := orgs.NumPlans(); got != 1 {
+				t.Errorf("got %d sync plans, want 1", got)
+			}
+
+			// The scoped search only applies to sync plans.
+			assertPagedRequests(t, ts, orgsPath, 1, "")
+
+			plansPath, unusedPath := orgPlansPath, bulkPlansPath
+			if bulk {
+				plansPath, unusedPath = bulkPlansPath, orgPlansPath
+			}
+
+			assertPagedRequests(t, ts, plansPath, 1, SyncPlansSearchEnabled)
+			assertPagedRequests(t, ts, unusedPath, 0, "")
+		})
+	}
+}
+
+func TestGetManifestAppliesQueryOptions(t *testing.T) {
+	const (
+		orgPath     = "/katello/api/v2/organizations/1"
+		historyPath = "/katello/api/v2/organizations/1/subscriptions/manifest_history"
+	)
+
+	var (
+		mu      sync.Mutex
+		queries = make(map[string]url.Values)
+	)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries[r.URL.Path] = r.URL.Query()
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case orgPath:
+			_, _ = fmt.Fprint(w, `{"id":1,"name":"Example","owner_details":{"upstreamConsumer":{"uuid":"abc","name":"Example"}}}`)
+		case historyPath:
+			_, _ = fmt.Fprint(w, `[{"created":"2024-01-02 03:04:05 UTC","status":"SUCCESS","statusMessage":"imported"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	server, port := testServerAddress(t, ts)
+
+	authInfo := APIAuthInfo{
+		Server:      server,
+		Port:        port,
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	client := NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+
+	opts := QueryOptions{Search: "status = SUCCESS"}
+
+	manifest, err := GetManifest(context.Background(), client, opts, Organization{ID: 1, Name: "Example"})
+	if err != nil {
+		t.Fatalf("failed to retrieve manifest: %v", err)
+	}
+
+	if manifest.UpstreamUUID != "abc" {
+		t.Errorf("got upstream UUID %q, want %q", manifest.UpstreamUUID, "abc")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The query options only apply to the manifest history.
+	if got := queries[orgPath].Get(APIEndpointURLQueryParamSearchKey); got != "" {
+		t.Errorf("got search %q for %s, want none", got, orgPath)
+	}
+
+	if got := queries[historyPath].Get(APIEndpointURLQueryParamSearchKey); got != opts.Search {
+		t.Errorf("got search %q for %s, want %q", got, historyPath, opts.Search)
+	}
+}
+
+func TestNewAPIClientConnectionReuse(t *testing.T) {
+	limits := APILimits{
+		PerPage:             30,
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     45 * time.Second,
+	}
+
+	client := NewAPIClient(APIAuthInfo{NetworkType: "auto"}, limits, zerolog.Nop())
+
+	gzip, ok := client.Transport.(gzipTransport)
+	if !ok {
+		t.Fatalf("got transport %T, want %T", client.Transport, gzipTransport{})
+	}
+
+	transport, ok := gzip.next.(*http.Transport)
+	if !ok {
+		t.Fatalf("got wrapped transport %T, want %T", gzip.next, &http.Transport{})
+	}
+
+	if transport.MaxIdleConns != limits.MaxIdleConns {
+		t.Errorf("got MaxIdleConns %d, want %d", transport.MaxIdleConns, limits.MaxIdleConns)
+	}
+
+	if transport.MaxIdleConnsPerHost != limits.MaxIdleConnsPerHost {
+		t.Errorf("got MaxIdleConnsPerHost %d, want %d", transport.MaxIdleConnsPerHost, limits.MaxIdleConnsPerHost)
+	}
+
+	if transport.IdleConnTimeout != limits.IdleConnTimeout {
+		t.Errorf("got IdleConnTimeout %v, want %v", transport.IdleConnTimeout, limits.IdleConnTimeout)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Known recurring logic states.
+const (
+	RecurringLogicStateActive    string = "active"
+	RecurringLogicStateCancelled string = "cancelled"
+	RecurringLogicStateDisabled  string = "disabled"
+	RecurringLogicStateFinished  string = "finished"
+	RecurringLogicStateFailed    string = "failed"
+)
+
+// RecurringLogicsResponse represents the API response from a request for
+// all recurring logics in the Red Hat Satellite server.
+type RecurringLogicsResponse struct {
+	// RecurringLogics is the collection of Recurring Logics returned in the
+	// API query response.
+	RecurringLogics RecurringLogics `json:"results"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the recurring logics provided by the response along
+// with the number of recurring logics matching the query across all pages.
+func (r RecurringLogicsResponse) pageResults() ([]RecurringLogic, int) {
+	return r.RecurringLogics, r.Subtotal
+}
+
+// RecurringLogic is a Red Hat Satellite (Foreman Tasks) schedule used to
+// trigger recurring tasks such as sync plan execution.
+type RecurringLogic struct {
+	EndTime      StandardAPITime `json:"end_time"`
+	CronLine     NullString      `json:"cron_line"`
+	Purpose      NullString      `json:"purpose"`
+	State        string          `json:"state"`
+	ID           int             `json:"id"`
+	Iteration    int             `json:"iteration"`
+	MaxIteration *int            `json:"max_iteration"` // null if unlimited
+}
+
+// RecurringLogics is a collection of Red Hat Satellite recurring logics.
+type RecurringLogics []RecurringLogic
+
+// GetRecurringLogics uses the given client to retrieve all Red Hat Satellite
+// recurring logics. The given query options (e.g., a scoped search of state
+// = cancelled) are used to limit and order the recurring logics retrieved.
+func GetRecurringLogics(ctx context.Context, client *APIClient, opts QueryOptions) (RecurringLogics, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	apiURL := fmt.Sprintf(
+		RecurringLogicsAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	allRecurringLogics, err := fetchAllPages[RecurringLogic, RecurringLogicsResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		logger,
+		"recurring logics",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all recurring logics")
+
+	return allRecurringLogics, nil
+}
+
+// IsActive indicates whether the recurring logic is active and will
+// continue to trigger new task executions.
+func (rl RecurringLogic) IsActive() bool {
+	return strings.EqualFold(rl.State, RecurringLogicStateActive)
+}
+
+// Lookup returns the recurring logic from the collection with the given ID.
+// A boolean value is returned to indicate whether a match was found.
+func (rls RecurringLogics) Lookup(id int) (RecurringLogic, bool) {
+	for _, rl := range rls {
+		if rl.ID == id {
+			return rl, true
+		}
+	}
+
+	return RecurringLogic{}, false
+}
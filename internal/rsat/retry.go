@@ -0,0 +1,209 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetrySettings is the collection of settings used to retry API requests
+// which fail due to transient problems (e.g., connection resets, gateway
+// errors or timeouts) so that a single blip does not fail retrieval.
+type RetrySettings struct {
+	// MaxAttempts is the maximum number of attempts made for each request
+	// (including the initial attempt). Requests are not retried if this
+	// value is less than two.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles for
+	// each subsequent retry.
+	BaseDelay time.Duration
+
+	// Jitter is the fraction (between 0 and 1) of each delay randomly added
+	// or subtracted so that concurrent requests do not retry in lockstep.
+	Jitter float64
+}
+
+// maxAttempts returns the maximum number of attempts made for each request.
+func (rs RetrySettings) maxAttempts() int {
+	if rs.MaxAttempts < 1 {
+		return 1
+	}
+
+	return rs.MaxAttempts
+}
+
+// rateLimitedMaxAttempts is the maximum number of attempts made for each
+// rate limited request which specifies a Retry-After delay if retries are
+// otherwise disabled (or limited to fewer attempts).
+const rateLimitedMaxAttempts int = 3
+
+// shouldRetry indicates whether a request should be retried after the given
+// attempt (starting with 1 for the initial attempt) failed with the given
+// error. Rate limited requests which specify a Retry-After delay are retried
+// even if retries are disabled so that a busy server does not fail
+// retrieval; the delay is still limited by the context deadline.
+func (rs RetrySettings) shouldRetry(ctx context.Context, err error, attempt int) bool {
+	if !isRetryable(ctx, err) {
+		return false
+	}
+
+	if attempt < rs.maxAttempts() {
+		return true
+	}
+
+	var retryAfterErr *retryAfterError
+
+	return errors.Is(err, ErrRateLimited) &&
+		errors.As(err, &retryAfterErr) &&
+		attempt < rateLimitedMaxAttempts
+}
+
+// delay returns the delay before the given retry (starting with 1 for the
+// first retry).
+func (rs RetrySettings) delay(retry int) time.Duration {
+	if retry < 1 || rs.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := rs.BaseDelay << (retry - 1)
+
+	if rs.Jitter > 0 {
+		// Weak random numbers are sufficient for spreading out retries.
+		offset := rs.Jitter * (2*rand.Float64() - 1) // nolint:gosec
+		delay += time.Duration(float64(delay) * offset)
+	}
+
+	return delay
+}
+
+// isRetryable indicates whether the given error encountered while submitting
+// a request is the result of a transient problem and the request should be
+// retried. Authentication failures (which risk account lockout if repeated)
+// and maintenance mode responses (which are not transient) are not retried.
+func isRetryable(ctx context.Context, err error) bool {
+	// The caller's deadline applies to all attempts.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	switch {
+	case err == nil:
+		return false
+
+	case IsAuthenticationFailure(err), IsMaintenanceMode(err):
+		return false
+
+	case errors.Is(err, ErrServerUnavailable), errors.Is(err, ErrRateLimited):
+		return true
+
+	case errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.EOF):
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// retryAfterError records the delay requested by the server (via the
+// Retry-After response header) before a failed request is retried.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+// Error provides the message for the enclosed error.
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap supports error wrapping by returning the enclosed error.
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// parseRetryAfter parses the given Retry-After response header value
+// (either a number of seconds or an HTTP date) and returns the requested
+// delay relative to the given time.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// retryDelay returns the delay before the given retry (starting with 1 for
+// the first retry) of a request which failed with the given error. The delay
+// requested by the server is used if provided.
+func (rs RetrySettings) retryDelay(retry int, err error) time.Duration {
+	var retryAfterErr *retryAfterError
+	if errors.As(err, &retryAfterErr) {
+		return retryAfterErr.delay
+	}
+
+	return rs.delay(retry)
+}
+
+// withinBudget indicates whether a retry after the given delay would begin
+// before the context deadline (if any).
+func withinBudget(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+
+	return time.Until(deadline) > delay
+}
+
+// waitForRetry waits for the given delay before a request is retried. An
+// error is returned if the context expires before the delay elapses.
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSubmitRequestRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		retryAfter   string
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "gateway error retried", status: http.StatusBadGateway, wantAttempts: 3},
+		{name: "rate limit retried", status: http.StatusTooManyRequests, retryAfter: "0", wantAttempts: 3},
+		{name: "authentication failure not retried", status: http.StatusUnauthorized, wantAttempts: 1, wantErr: true},
+		{name: "maintenance mode not retried", status: http.StatusServiceUnavailable, body: "Maintenance in progress", wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			// Fail the first two attempts.
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) < 3 {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(tt.body))
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer ts.Close()
+
+			authInfo := APIAuthInfo{
+				Username:    "monitoring",
+				Password:    "secret",
+				NetworkType: "auto",
+				ReadLimit:   1024,
+				TrustCert:   true,
+			}
+
+			limits := APILimits{
+				PerPage: 30,
+				Retry:   RetrySettings{MaxAttempts: 3, BaseDelay: time.Millisecond},
+			}
+
+			client := NewAPIClient(authInfo, limits, zerolog.Nop())
+
+			response, err := client.SubmitRequest(context.Background(), RequestOptions{
+				Endpoint:    ts.URL + "/api/v2/status",
+				QueryParams: map[string]string{},
+			})
+			if response != nil {
+				_ = response.Body.Close()
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, tt.wantErr)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestSubmitRequestRateLimitedRetriedByDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryAfter   string
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{name: "retry after delay honored", retryAfter: "0", wantAttempts: 2},
+		{name: "missing retry after delay not retried", wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+
+			// Rate limit the first attempt.
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer ts.Close()
+
+			authInfo := APIAuthInfo{
+				Username:    "monitoring",
+				Password:    "secret",
+				NetworkType: "auto",
+				ReadLimit:   1024,
+				TrustCert:   true,
+			}
+
+			// Retry settings are not specified.
+			limits := APILimits{PerPage: 30}
+
+			client := NewAPIClient(authInfo, limits, zerolog.Nop())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			response, err := client.SubmitRequest(ctx, RequestOptions{
+				Endpoint:    ts.URL + "/api/v2/status",
+				QueryParams: map[string]string{},
+			})
+			if response != nil {
+				_ = response.Body.Close()
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrRateLimited) {
+				t.Errorf("got error %v, want %v", err, ErrRateLimited)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestSubmitRequestRateLimitedAttemptsLimited(t *testing.T) {
+	var attempts int32
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	authInfo := APIAuthInfo{
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024,
+		TrustCert:   true,
+	}
+
+	limits := APILimits{
+		PerPage: 30,
+		Retry:   RetrySettings{MaxAttempts: 1},
+	}
+
+	client := NewAPIClient(authInfo, limits, zerolog.Nop())
+
+	_, err := client.SubmitRequest(context.Background(), RequestOptions{
+		Endpoint:    ts.URL + "/api/v2/status",
+		QueryParams: map[string]string{},
+	})
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got error %v, want %v", err, ErrRateLimited)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != int32(rateLimitedMaxAttempts) {
+		t.Errorf("got %d attempts, want %d", got, rateLimitedMaxAttempts)
+	}
+}
+
+func TestSubmitRequestRetryAfterExceedsBudget(t *testing.T) {
+	var attempts int32
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	authInfo := APIAuthInfo{
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024,
+		TrustCert:   true,
+	}
+
+	limits := APILimits{
+		PerPage: 30,
+		Retry:   RetrySettings{MaxAttempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	client := NewAPIClient(authInfo, limits, zerolog.Nop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := client.SubmitRequest(ctx, RequestOptions{
+		Endpoint:    ts.URL + "/api/v2/status",
+		QueryParams: map[string]string{},
+	})
+
+	if !errors.Is(err, ErrRetryBudgetExceeded) || !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got error %v, want %v and %v", err, ErrRetryBudgetExceeded, ErrRateLimited)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestSubmitRequestRetryWaitCanceled(t *testing.T) {
+	var attempts int32
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	authInfo := APIAuthInfo{
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024,
+		TrustCert:   true,
+	}
+
+	limits := APILimits{
+		PerPage: 30,
+		Retry:   RetrySettings{MaxAttempts: 3, BaseDelay: time.Hour},
+	}
+
+	client := NewAPIClient(authInfo, limits, zerolog.Nop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel while waiting to retry the first failed attempt.
+	timer := time.AfterFunc(100*time.Millisecond, cancel)
+	defer timer.Stop()
+
+	_, err := client.SubmitRequest(ctx, RequestOptions{
+		Endpoint:    ts.URL + "/api/v2/status",
+		QueryParams: map[string]string{},
+	})
+
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("got error %v, want %v and %v", err, context.Canceled, ErrServerUnavailable)
+	}
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: "Thu, 01 Jun 2023 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Thu, 01 Jun 2023 11:00:00 GMT", want: 0, wantOK: true},
+		{value: "", wantOK: false},
+		{value: "-5", wantOK: false},
+		{value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = (%s, %t), want (%s, %t)", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -9,13 +9,17 @@ package rsat
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/atc0005/check-rsat/internal/netutils"
 	"github.com/rs/zerolog"
 )
 
@@ -42,11 +46,74 @@ const (
 	// SyncPlansAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d/sync_plans?full_result=1&per_page=%d&page=%d"
 	SyncPlansAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d/sync_plans"
 
+	// BulkSyncPlansAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving Sync Plans across all Red
+	// Hat Satellite Organizations visible to the user. Some Katello versions
+	// require an organization for this endpoint and reject the request.
+	BulkSyncPlansAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/sync_plans"
+
 	// ProductsAPIEndPointURLTemplate provides a template for a fully
 	// qualified API endpoint URL for retrieving Products associated with a
 	// Red Hat Satellite Organization.
 	// ProductsAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/products?organization_id=%d&full_result=1&per_page=%d&page=%d"
 	ProductsAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/products"
+
+	// SettingsAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving Settings from a Red Hat
+	// Satellite instance.
+	SettingsAPIEndPointURLTemplate string = "https://%s:%d/api/v2/settings"
+
+	// StatusAPIEndPointURLTemplate provides a template for a fully qualified
+	// API endpoint URL for retrieving the (Foreman) status of a Red Hat
+	// Satellite instance. This includes the Foreman and Satellite versions.
+	StatusAPIEndPointURLTemplate string = "https://%s:%d/api/v2/status"
+
+	// KatelloStatusAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving the Katello status of a Red
+	// Hat Satellite instance. This includes the Katello version.
+	KatelloStatusAPIEndPointURLTemplate string = "https://%s:%d/katello/api/status"
+
+	// PingAPIEndPointURLTemplate provides a template for a fully qualified
+	// API endpoint URL for retrieving the health status of services used by
+	// a Red Hat Satellite instance.
+	PingAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/ping"
+
+	// ActivationKeysAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving Activation Keys associated
+	// with a Red Hat Satellite Organization.
+	ActivationKeysAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d/activation_keys"
+
+	// HostCollectionsAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving Host Collections associated
+	// with a Red Hat Satellite Organization.
+	HostCollectionsAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d/host_collections"
+
+	// KatelloOrganizationAPIEndPointURLTemplate provides a template for a
+	// fully qualified API endpoint URL for retrieving Katello specific
+	// details (e.g., upstream subscription manifest) for a Red Hat Satellite
+	// Organization.
+	KatelloOrganizationAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d"
+
+	// ManifestHistoryAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving the subscription manifest
+	// import/refresh history for a Red Hat Satellite Organization.
+	ManifestHistoryAPIEndPointURLTemplate string = "https://%s:%d/katello/api/v2/organizations/%d/subscriptions/manifest_history"
+
+	// AuditsAPIEndPointURLTemplate provides a template for a fully qualified
+	// API endpoint URL for retrieving Audits (records of configuration
+	// changes) from a Red Hat Satellite instance.
+	AuditsAPIEndPointURLTemplate string = "https://%s:%d/api/v2/audits"
+
+	// RecurringLogicsAPIEndPointURLTemplate provides a template for a fully
+	// qualified API endpoint URL for retrieving the recurring logics (task
+	// schedules) used to trigger sync plan execution from a Red Hat
+	// Satellite instance.
+	RecurringLogicsAPIEndPointURLTemplate string = "https://%s:%d/foreman_tasks/api/recurring_logics"
+
+	// OrganizationParametersAPIEndPointURLTemplate provides a template for a
+	// fully qualified API endpoint URL for retrieving the parameters set for
+	// a Red Hat Satellite Organization.
+	OrganizationParametersAPIEndPointURLTemplate string = "https://%s:%d/api/v2/organizations/%d/parameters"
 )
 
 // Common/shared query parameter keys for Red Hat Satellite API endpoint URLs.
@@ -55,6 +122,9 @@ const (
 	APIEndpointURLQueryParamFullResultKey     string = "full_result"
 	APIEndpointURLQueryParamPerPageKey        string = "per_page"
 	APIEndpointURLQueryParamPageKey           string = "page"
+	APIEndpointURLQueryParamSearchKey         string = "search"
+	APIEndpointURLQueryParamSortByKey         string = "sort_by"
+	APIEndpointURLQueryParamSortOrderKey      string = "sort_order"
 )
 
 // Red Hat Satellite API endpoint URL query parameter default values.
@@ -103,6 +173,21 @@ type APIAuthInfo struct {
 	// Server user account.
 	Password string
 
+	// Token is the optional Personal Access Token for the specified Red Hat
+	// Satellite Server user account. If specified, the token is sent in
+	// place of the password.
+	Token string
+
+	// OAuthConsumerKey is the optional OAuth consumer key configured for the
+	// specified Red Hat Satellite server. If specified along with the OAuth
+	// consumer secret, requests are signed using OAuth 1.0a instead of
+	// using a password or token.
+	OAuthConsumerKey string
+
+	// OAuthConsumerSecret is the optional OAuth consumer secret configured
+	// for the specified Red Hat Satellite server.
+	OAuthConsumerSecret string
+
 	// UserAgent is an optional custom user agent string used to override the
 	// default Go user agent ("Go-http-client/1.1").
 	UserAgent string
@@ -112,10 +197,25 @@ type APIAuthInfo struct {
 	// either of IPv4 or IPv6 addresses ("auto").
 	NetworkType string
 
+	// DNSServer is the optional DNS server (IP Address or hostname with
+	// optional port) used to resolve the Red Hat Satellite server name
+	// instead of the nameservers configured for the system.
+	DNSServer string
+
+	// DNSTimeout is the optional limit on the time spent resolving the Red
+	// Hat Satellite server name. Zero means no limit beyond the resolver
+	// defaults.
+	DNSTimeout time.Duration
+
 	// CACert is the optional certificate authority certificate used to
 	// validate the certificate chain used by the Red Hat Satellite server.
 	CACert []byte
 
+	// ClientCertificate is the optional client certificate (and private key)
+	// presented to Red Hat Satellite servers (or fronting proxies) which
+	// require mutual TLS authentication.
+	ClientCertificate *tls.Certificate
+
 	// PermitTLSRenegotiation controls whether the server is allowed to
 	// request TLS renegotiation.
 	PermitTLSRenegotiation bool
@@ -123,9 +223,44 @@ type APIAuthInfo struct {
 	// TrustCert indicates whether the certificate should be trusted as-is
 	// without validation.
 	TrustCert bool
+
+	// FixturesDir is the optional path to a directory of saved API
+	// responses. If specified, API responses are read from this directory
+	// instead of submitting requests to the Red Hat Satellite server.
+	FixturesDir string
+
+	// ResponseCacheDir is the optional path to a directory where responses
+	// providing validators (ETag or Last-Modified headers) are stored. If
+	// specified, conditional requests are submitted using the stored
+	// validators and the stored response is reused if not modified.
+	ResponseCacheDir string
+}
+
+// resolver returns the resolver used to resolve the Red Hat Satellite server
+// name to IP Addresses.
+func (a APIAuthInfo) resolver() netutils.Resolver {
+	return netutils.NewResolver(a.DNSServer, a.DNSTimeout)
+}
+
+// credential returns the secret used to authenticate the specified user. A
+// Personal Access Token is used in place of the password if specified; Red
+// Hat Satellite accepts tokens via HTTP Basic authentication.
+func (a APIAuthInfo) credential() string {
+	if a.Token != "" {
+		return a.Token
+	}
+
+	return a.Password
 }
 
-// SortOptions is the optional sorting criteria for API query responses.
+// Supported sort order values for API query requests and responses.
+const (
+	SortOrderAscending  string = "ASC"
+	SortOrderDescending string = "DESC"
+)
+
+// SortOptions is the optional sorting criteria for API query responses. This
+// type is also used to request server-side sorting of API query results.
 //
 // https://access.redhat.com/documentation/en-us/red_hat_satellite/6.5/html-single/api_guide/index#sect-API_Guide-Understanding_the_JSON_Response_Format
 // https://access.redhat.com/documentation/en-us/red_hat_satellite/6.15/html-single/api_guide/index#sect-API_Guide-Understanding_the_JSON_Response_Format
@@ -138,6 +273,68 @@ type SortOptions struct {
 	Order NullString `json:"order"`
 }
 
+// QueryOptions is the collection of optional settings used to limit or order
+// the results returned by API queries.
+type QueryOptions struct {
+	// Search is the optional scoped search query (e.g., enabled = true) used
+	// to limit the results retrieved.
+	Search string
+
+	// Sort is the optional sorting criteria used to request server-side
+	// sorting of the results retrieved.
+	Sort SortOptions
+}
+
+// IsSet indicates whether any sorting criteria has been specified.
+func (so SortOptions) IsSet() bool {
+	return strings.TrimSpace(string(so.By)) != ""
+}
+
+// setQueryParams is a helper function used to apply the sorting criteria to
+// the given collection of query parameters. The sort order defaults to
+// ascending if not specified. An error is returned if an unsupported sort
+// order is specified.
+func (so SortOptions) setQueryParams(apiURLQueryParams map[string]string) error {
+	if !so.IsSet() {
+		return nil
+	}
+
+	order := strings.ToUpper(strings.TrimSpace(string(so.Order)))
+	switch order {
+	case "":
+		order = SortOrderAscending
+	case SortOrderAscending, SortOrderDescending:
+	default:
+		return fmt.Errorf(
+			"unsupported sort order %q; expected one of %s, %s: %w",
+			string(so.Order),
+			SortOrderAscending,
+			SortOrderDescending,
+			ErrInvalidValue,
+		)
+	}
+
+	apiURLQueryParams[APIEndpointURLQueryParamSortByKey] = strings.TrimSpace(string(so.By))
+	apiURLQueryParams[APIEndpointURLQueryParamSortOrderKey] = order
+
+	return nil
+}
+
+// countingReader is an io.Reader which tracks the number of bytes read from
+// the wrapped reader.
+type countingReader struct {
+	reader    io.Reader
+	bytesRead int64
+}
+
+// Read satisfies the io.Reader interface.
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.bytesRead += int64(n)
+
+	return n, err
+}
+
 // decode is a helper function intended to handle the core JSON decoding tasks
 // for various JSON sources (file, http body, etc.).
 func decode(dst interface{}, reader io.Reader, logger zerolog.Logger, sourceName string, limit int64) error {
@@ -159,14 +356,7 @@ func decode(dst interface{}, reader io.Reader, logger zerolog.Logger, sourceName
 		limit,
 	)
 
-	limitReader := io.LimitReader(reader, limit)
-
-	// If debug or greater logging is enabled write the JSON payload in the
-	// response as-is to stderr for review.
-	if zerolog.GlobalLevel() == zerolog.DebugLevel ||
-		zerolog.GlobalLevel() == zerolog.TraceLevel {
-		limitReader = io.TeeReader(io.LimitReader(reader, limit), os.Stderr)
-	}
+	limitReader, counter := newLimitedReader(reader, limit)
 
 	dec := json.NewDecoder(limitReader)
 
@@ -179,37 +369,88 @@ func decode(dst interface{}, reader io.Reader, logger zerolog.Logger, sourceName
 
 	// Decode the first JSON object.
 	if err := dec.Decode(dst); err != nil {
-		return &PrepError{
-			Task:    PrepTaskDecode,
-			Message: "failed to decode JSON data",
-			Source:  sourceName,
-			Cause:   err,
-		}
+		return decodeFailure(err, counter, limit, sourceName, logger)
 	}
 	logger.Debug().Msg("Successfully decoded JSON input")
 
 	// If there is more than one object, something is off.
 	if dec.More() {
+		return multipleObjectsFailure(sourceName)
+	}
+
+	return nil
+
+}
+
+// newLimitedReader is a helper function used to limit reading from the given
+// reader to the given number of bytes. The number of bytes read is tracked
+// so that we can determine whether a decoding failure is the result of the
+// read limit truncating the input.
+func newLimitedReader(reader io.Reader, limit int64) (io.Reader, *countingReader) {
+	counter := &countingReader{reader: io.LimitReader(reader, limit)}
+
+	var limitReader io.Reader = counter
+
+	// If debug or greater logging is enabled write the JSON payload in the
+	// response as-is to stderr for review.
+	if zerolog.GlobalLevel() == zerolog.DebugLevel ||
+		zerolog.GlobalLevel() == zerolog.TraceLevel {
+		limitReader = io.TeeReader(counter, os.Stderr)
+	}
+
+	return limitReader, counter
+}
+
+// decodeFailure is a helper function used to provide the error for a JSON
+// decoding failure. Failures resulting from the read limit truncating the
+// input are classified separately.
+func decodeFailure(err error, counter *countingReader, limit int64, sourceName string, logger zerolog.Logger) error {
+	if counter.bytesRead >= limit {
+		logger.Debug().
+			Int64("bytes_read", counter.bytesRead).
+			Int64("read_limit", limit).
+			Msg("Read limit reached while decoding JSON input")
 
 		return &PrepError{
 			Task:    PrepTaskDecode,
-			Message: "failed to decode JSON data",
+			Message: "failed to decode truncated JSON data",
 			Source:  sourceName,
 			Cause: fmt.Errorf(
-				"source %s contains multiple JSON objects; only one JSON object is supported: %w",
+				"response from source %s exceeds read limit of %d bytes;"+
+					" increase the read limit or lower the per-page limit: %w",
 				sourceName,
-				ErrJSONUnexpectedObjectCount,
+				limit,
+				ErrReadLimitReached,
 			),
 		}
 	}
 
-	return nil
+	return &PrepError{
+		Task:    PrepTaskDecode,
+		Message: "failed to decode JSON data",
+		Source:  sourceName,
+		Cause:   err,
+	}
+}
 
+// multipleObjectsFailure is a helper function used to provide the error for
+// JSON input containing more than one JSON object.
+func multipleObjectsFailure(sourceName string) error {
+	return &PrepError{
+		Task:    PrepTaskDecode,
+		Message: "failed to decode JSON data",
+		Source:  sourceName,
+		Cause: fmt.Errorf(
+			"source %s contains multiple JSON objects; only one JSON object is supported: %w",
+			sourceName,
+			ErrJSONUnexpectedObjectCount,
+		),
+	}
 }
 
-// validateResponse is a helper function responsible for validating a response
+// validateResponse is a helper method responsible for validating a response
 // from an endpoint after submitting a message.
-func validateResponse(ctx context.Context, response *http.Response, logger zerolog.Logger, limit int64) error {
+func (c *APIClient) validateResponse(ctx context.Context, response *http.Response, opts RequestOptions) error {
 	if response == nil {
 		return &PrepError{
 			Task:    PrepTaskValidateResponse,
@@ -222,6 +463,10 @@ func validateResponse(ctx context.Context, response *http.Response, logger zerol
 		}
 	}
 
+	logger := opts.logger(c)
+	limit := c.AuthInfo.ReadLimit
+	expectedStatus := opts.expectedStatus()
+
 	feedSource := response.Request.URL.RequestURI()
 
 	if err := ctx.Err(); err != nil {
@@ -245,23 +490,22 @@ func validateResponse(ctx context.Context, response *http.Response, logger zerol
 		)
 	}
 
-	// TODO: Refactor this block
 	switch {
 
 	// Successful / expected response.
-	case response.StatusCode == http.StatusOK:
+	case response.StatusCode == expectedStatus:
 		logger.Debug().Msgf("Status code %d received as expected", response.StatusCode)
 
 		return nil
 
 	// Success status range, but not expected value.
-	case response.StatusCode > 200 && response.StatusCode <= 299:
+	case response.StatusCode >= 200 && response.StatusCode <= 299:
 		logger.Debug().Msgf(
 			"Status code %d (%s) received; expected %d (%s), but received value within success range",
 			response.StatusCode,
 			http.StatusText(response.StatusCode),
-			http.StatusOK,
-			http.StatusText(http.StatusOK),
+			expectedStatus,
+			http.StatusText(expectedStatus),
 		)
 
 		return nil
@@ -282,13 +526,53 @@ func validateResponse(ctx context.Context, response *http.Response, logger zerol
 		}
 		responseString := string(responseData)
 
-		statusCodeErr := fmt.Errorf(
+		// Authentication failures are classified separately so that callers
+		// can abort immediately instead of repeating the request (e.g., for
+		// each page or organization) with the same invalid credentials.
+		//
+		// Maintenance mode responses are classified separately so that
+		// callers can report planned maintenance (e.g., patch windows)
+		// differently than an unexpected outage.
+		cause := ErrHTTPResponseOutsideRange
+		switch {
+		case response.StatusCode == http.StatusUnauthorized,
+			response.StatusCode == http.StatusForbidden:
+			cause = fmt.Errorf("%w: %w", ErrAuthenticationFailed, ErrHTTPResponseOutsideRange)
+
+		case isMaintenanceResponse(response.StatusCode, responseString):
+			cause = fmt.Errorf("%w: %w", ErrMaintenanceMode, ErrHTTPResponseOutsideRange)
+
+		case response.StatusCode == http.StatusBadGateway,
+			response.StatusCode == http.StatusServiceUnavailable,
+			response.StatusCode == http.StatusGatewayTimeout:
+			cause = fmt.Errorf("%w: %w", ErrServerUnavailable, ErrHTTPResponseOutsideRange)
+
+		case response.StatusCode == http.StatusTooManyRequests:
+			cause = fmt.Errorf("%w: %w", ErrRateLimited, ErrHTTPResponseOutsideRange)
+
+		case response.StatusCode == http.StatusBadRequest,
+			response.StatusCode == http.StatusRequestEntityTooLarge,
+			response.StatusCode == http.StatusRequestURITooLong,
+			response.StatusCode == http.StatusUnprocessableEntity:
+			cause = fmt.Errorf("%w: %w", ErrRequestRejected, ErrHTTPResponseOutsideRange)
+
+		case response.StatusCode == http.StatusNotFound:
+			cause = fmt.Errorf("%w: %w", ErrEndpointNotFound, ErrHTTPResponseOutsideRange)
+		}
+
+		var statusCodeErr error = fmt.Errorf(
 			"response %v (%s) from API: %w",
 			response.Status,
 			responseString,
-			ErrHTTPResponseOutsideRange,
+			cause,
 		)
 
+		// Record the delay requested by the server (if any) so that the
+		// request is retried no sooner than the server permits.
+		if delay, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+			statusCodeErr = &retryAfterError{err: statusCodeErr, delay: delay}
+		}
+
 		return &PrepError{
 			Task:    PrepTaskValidateResponse,
 			Message: "unexpected response",
@@ -300,20 +584,20 @@ func validateResponse(ctx context.Context, response *http.Response, logger zerol
 
 }
 
-// prepareRequest is a helper function that prepares a http.Request (including
+// isMaintenanceResponse indicates whether the given response status code and
+// body indicate that the Red Hat Satellite server is in maintenance mode.
+// When maintenance mode is enabled (e.g., via foreman-maintain) the web
+// server responds to requests with a 503 status code and a maintenance page.
+func isMaintenanceResponse(statusCode int, body string) bool {
+	return statusCode == http.StatusServiceUnavailable &&
+		strings.Contains(strings.ToLower(body), "maintenance")
+}
+
+// prepareRequest is a helper method that prepares a http.Request (including
 // all desired headers) for submission to an endpoint.
-func prepareRequest(ctx context.Context, client *APIClient, apiURL string, apiURLQueryParams map[string]string) (*http.Request, error) {
-	if client == nil {
-		return nil, &PrepError{
-			Task:    PrepTaskPrepareRequest,
-			Message: "error preparing HTTP request",
-			Source:  apiURL,
-			Cause: fmt.Errorf(
-				"required API client was not provided: %w",
-				ErrMissingValue,
-			),
-		}
-	}
+func (c *APIClient) prepareRequest(ctx context.Context, opts RequestOptions) (*http.Request, error) {
+	apiURL := opts.Endpoint
+	apiURLQueryParams := opts.QueryParams
 
 	if apiURL == "" {
 		return nil, &PrepError{
@@ -327,25 +611,27 @@ func prepareRequest(ctx context.Context, client *APIClient, apiURL string, apiUR
 		}
 	}
 
-	// We require at least the per_page setting.
+	// We require a query parameters collection. Paginated endpoints require
+	// at least the per_page setting, but some endpoints (e.g., status) do not
+	// support pagination and so an empty collection is permitted.
 	//
 	// TODO: Move this into a separate Validate method for the
 	// APIURLQueryParams type so that we can apply multiple validations in one
 	// place (e.g., require per_page setting to be present, value values for
 	// it and other query parameters).
-	if len(apiURLQueryParams) < 1 {
+	if apiURLQueryParams == nil {
 		return nil, &PrepError{
 			Task:    PrepTaskPrepareRequest,
 			Message: "error preparing HTTP request",
 			Source:  apiURL,
 			Cause: fmt.Errorf(
-				"required number of API URL query parameters were not provided: %w",
+				"required API URL query parameters collection was not provided: %w",
 				ErrMissingValue,
 			),
 		}
 	}
 
-	logger := client.Logger
+	logger := opts.logger(c)
 
 	logger.Debug().Msgf("Parsing %q as URL", apiURL)
 	parsedURL, parseErr := url.Parse(apiURL)
@@ -380,14 +666,35 @@ func prepareRequest(ctx context.Context, client *APIClient, apiURL string, apiUR
 	request.Header.Add("Content-Type", "application/json;charset=utf-8")
 
 	// Provide API authentication credentials.
-	// https://stackoverflow.com/questions/16673766/basic-http-auth-in-go
-	request.SetBasicAuth(client.AuthInfo.Username, client.AuthInfo.Password)
+	switch {
+	case c.AuthInfo.UsesOAuth():
+		logger.Debug().Msg("Signing request using OAuth")
+
+		// The user is indicated via header so that the user's permissions
+		// apply if OAuth user mapping is enabled.
+		if c.AuthInfo.Username != "" {
+			request.Header.Set(ForemanUserHeader, c.AuthInfo.Username)
+		}
+
+		if err := signOAuthRequest(request, c.AuthInfo.OAuthConsumerKey, c.AuthInfo.OAuthConsumerSecret); err != nil {
+			return nil, &PrepError{
+				Task:    PrepTaskPrepareRequest,
+				Source:  parsedURL.String(),
+				Message: "error signing request for URL",
+				Cause:   err,
+			}
+		}
+
+	default:
+		// https://stackoverflow.com/questions/16673766/basic-http-auth-in-go
+		request.SetBasicAuth(c.AuthInfo.Username, c.AuthInfo.credential())
+	}
 
 	// If provided, override the default Go user agent ("Go-http-client/1.1")
 	// with custom value.
-	if client.AuthInfo.UserAgent != "" {
+	if c.AuthInfo.UserAgent != "" {
 		logger.Debug().Msg("Setting custom user agent")
-		request.Header.Set("User-Agent", client.AuthInfo.UserAgent)
+		request.Header.Set("User-Agent", c.AuthInfo.UserAgent)
 	}
 
 	return request, nil
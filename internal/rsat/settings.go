@@ -0,0 +1,155 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SettingsResponse represents the API response from a request for all
+// settings in the Red Hat Satellite server.
+//
+// https://access.redhat.com/documentation/en-us/red_hat_satellite/6.15/html-single/api_guide/index#sect-API_Guide-Understanding_the_JSON_Response_Format
+type SettingsResponse struct {
+	// Settings is the collection of Settings returned in the API query
+	// response.
+	Settings Settings `json:"results"`
+
+	// Search is the search string based on scoped_scoped syntax.
+	Search NullString `json:"search"`
+
+	// Sort is the optional sorting criteria for API query responses.
+	Sort SortOptions `json:"sort"`
+
+	// Subtotal is the number of objects returned with the given search
+	// parameters. If there is no search, then subtotal is equal to total.
+	Subtotal int `json:"subtotal"`
+
+	// Total is the total number of objects without any search parameters.
+	Total int `json:"total"`
+
+	// Page is the page number for the current query response results.
+	//
+	// NOTE: See the SyncPlansResponse type for details regarding use of the
+	// json.Number type for this field.
+	Page json.Number `json:"page"`
+
+	// PerPage is the pagination limit applied to API query results. If not
+	// specified by the client this is the default value set by the API.
+	PerPage int `json:"per_page"`
+}
+
+// pageResults returns the settings provided by the response along with the
+// number of settings matching the query across all pages.
+func (r SettingsResponse) pageResults() ([]Setting, int) {
+	return r.Settings, r.Subtotal
+}
+
+// Setting is a Red Hat Satellite configuration setting (e.g., default
+// download policy, sync connection timeout).
+type Setting struct {
+	CreatedAt    StandardAPITime `json:"created_at"`
+	UpdatedAt    StandardAPITime `json:"updated_at"`
+	Description  NullString      `json:"description"`
+	Category     NullString      `json:"category_name"`
+	SettingsType NullString      `json:"settings_type"`
+	FullName     NullString      `json:"full_name"`
+	Name         string          `json:"name"`
+
+	// Value is the current value for the setting. Setting values are not
+	// consistently typed (e.g., string, integer, boolean, array) so the raw
+	// JSON value is retained for evaluation by the caller.
+	Value json.RawMessage `json:"value"`
+
+	// Default is the default value for the setting. See the Value field for
+	// additional details.
+	Default json.RawMessage `json:"default"`
+
+	ReadOnly  bool `json:"readonly"`
+	Encrypted bool `json:"encrypted"`
+}
+
+// Settings is a collection of Red Hat Satellite settings.
+type Settings []Setting
+
+// GetSettings uses the given client to retrieve all Red Hat Satellite
+// settings. The given query options (e.g., a scoped search of name =
+// foreman_url) are used to limit and order the settings retrieved.
+func GetSettings(ctx context.Context, client *APIClient, opts QueryOptions) (Settings, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return nil, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	apiURL := fmt.Sprintf(
+		SettingsAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	allSettings, err := fetchAllPages[Setting, SettingsResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		logger,
+		"settings",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all settings")
+
+	return allSettings, nil
+}
+
+// Lookup returns the setting from the collection with the given name. A
+// boolean value is returned to indicate whether a match was found.
+func (settings Settings) Lookup(name string) (Setting, bool) {
+	for _, setting := range settings {
+		if setting.Name == name {
+			return setting, true
+		}
+	}
+
+	return Setting{}, false
+}
+
+// IsDefault indicates whether the setting value is unchanged from the
+// default value.
+func (s Setting) IsDefault() bool {
+	return string(s.Value) == string(s.Default)
+}
+
+// ValueString provides a display friendly version of the setting value.
+// Encrypted values are masked.
+func (s Setting) ValueString() string {
+	switch {
+	case s.Encrypted:
+		return "********"
+
+	case len(s.Value) == 0:
+		return JSONNullKeyword
+
+	default:
+		return strings.Trim(string(s.Value), `"`)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetSettings(t *testing.T) {
+	const path = "/api/v2/settings"
+
+	ts := newPagedTestServer(t, map[string][]string{
+		path: {
+			`{"name":"foreman_url","category_name":"General","value":"https://rsat.example.com","default":"https://rsat.example.com","readonly":false,"encrypted":false}`,
+			`{"name":"default_download_policy","category_name":"Content","value":"on_demand","default":"immediate","readonly":false,"encrypted":false}`,
+			`{"name":"sync_connect_timeout_v2","category_name":"Content","value":300,"default":300,"readonly":false,"encrypted":false}`,
+			`{"name":"bmc_credentials_accessible","category_name":"Provisioning","value":true,"default":true,"readonly":true,"encrypted":false}`,
+			`{"name":"oauth_consumer_secret","category_name":"Authentication","value":"secret","default":null,"readonly":false,"encrypted":true}`,
+		},
+	})
+
+	opts := QueryOptions{
+		Search: "category_name = Content",
+		Sort:   SortOptions{By: "name", Order: NullString(SortOrderDescending)},
+	}
+
+	settings, err := GetSettings(context.Background(), ts.client(t, 2), opts)
+	if err != nil {
+		t.Fatalf("failed to retrieve settings: %v", err)
+	}
+
+	if len(settings) != 5 {
+		t.Fatalf("got %d settings, want 5", len(settings))
+	}
+
+	assertPagedRequests(t, ts, path, 3, opts.Search)
+
+	if got := ts.requests(path)[0].Get(APIEndpointURLQueryParamSortOrderKey); got != SortOrderDescending {
+		t.Errorf("got sort order %q, want %q", got, SortOrderDescending)
+	}
+
+	tests := []struct {
+		name        string
+		wantValue   string
+		wantDefault bool
+	}{
+		{name: "foreman_url", wantValue: "https://rsat.example.com", wantDefault: true},
+		{name: "default_download_policy", wantValue: "on_demand", wantDefault: false},
+		{name: "sync_connect_timeout_v2", wantValue: "300", wantDefault: true},
+		{name: "oauth_consumer_secret", wantValue: "********", wantDefault: false},
+	}
+
+	for _, tt := range tests {
+		setting, ok := settings.Lookup(tt.name)
+		if !ok {
+			t.Errorf("setting %q not found", tt.name)
+
+			continue
+		}
+
+		if got := setting.ValueString(); got != tt.wantValue {
+			t.Errorf("got value %q for setting %q, want %q", got, tt.name, tt.wantValue)
+		}
+
+		if got := setting.IsDefault(); got != tt.wantDefault {
+			t.Errorf("got default %t for setting %q, want %t", got, tt.name, tt.wantDefault)
+		}
+	}
+
+	if _, ok := settings.Lookup("missing"); ok {
+		t.Error("found unexpected setting")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import "fmt"
+
+// Rules responsible for skipping (not evaluating) an item. These values are
+// recorded along with skipped items so that reviewers can audit exactly what
+// monitoring chose not to evaluate.
+const (
+	// SkipRuleOrgInclude indicates that an organization was skipped because
+	// it was not in the list of organizations to evaluate.
+	SkipRuleOrgInclude string = "org-include"
+
+	// SkipRuleOrgExclude indicates that an organization was skipped because
+	// it was in the list of organizations to exclude.
+	SkipRuleOrgExclude string = "org-exclude"
+
+	// SkipRulePlanIgnore indicates that a sync plan was skipped because it
+	// matched a pattern in the list of sync plans to ignore.
+	SkipRulePlanIgnore string = "plan-ignore"
+
+	// SkipRuleProductExclude indicates that a product was skipped because
+	// it matched a pattern in the list of products to exclude.
+	SkipRuleProductExclude string = "product-exclude"
+
+	// SkipRuleSuppressionTag indicates that an item was skipped because it
+	// was tagged within Red Hat Satellite as suppressed from monitoring.
+	SkipRuleSuppressionTag string = "suppression-tag"
+
+	// SkipRuleBasicMode indicates that an optional capability was skipped
+	// because it requires API access beyond listing organizations and sync
+	// plans and basic mode was enabled.
+	SkipRuleBasicMode string = "basic-mode"
+)
+
+// Types of skipped items.
+const (
+	SkippedItemTypeOrganization string = "organization"
+	SkippedItemTypeSyncPlan     string = "sync_plan"
+	SkippedItemTypeProduct      string = "product"
+	SkippedItemTypeCapability   string = "capability"
+)
+
+// SkippedItem is an item which was intentionally not evaluated along with
+// the rule responsible and the reason why.
+type SkippedItem struct {
+	// Type is the type of item skipped (e.g., organization, sync_plan).
+	Type string `json:"type"`
+
+	// Item is a human readable identifier for the skipped item (e.g.,
+	// "Example Org / Daily RHEL").
+	Item string `json:"item"`
+
+	// Rule is the rule responsible for skipping the item.
+	Rule string `json:"rule"`
+
+	// Reason is a brief explanation of why the item was skipped (e.g., the
+	// matching pattern).
+	Reason string `json:"reason"`
+}
+
+// SkippedItems is a collection of items which were intentionally not
+// evaluated.
+type SkippedItems []SkippedItem
+
+// Add records a new skipped item in the collection.
+func (si *SkippedItems) Add(itemType string, item string, rule string, reason string) {
+	*si = append(*si, SkippedItem{
+		Type:   itemType,
+		Item:   item,
+		Rule:   rule,
+		Reason: reason,
+	})
+}
+
+// String implements the fmt.Stringer interface as a convenience method.
+func (s SkippedItem) String() string {
+	return fmt.Sprintf("%s %q skipped by rule %s: %s", s.Type, s.Item, s.Rule, s.Reason)
+}
+
+// ByRule returns a new collection containing all items from the original
+// collection which were skipped by the given rule.
+func (si SkippedItems) ByRule(rule string) SkippedItems {
+	matches := make(SkippedItems, 0, len(si))
+
+	for _, item := range si {
+		if item.Rule == rule {
+			matches = append(matches, item)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import "testing"
+
+func TestSkippedItemsAdd(t *testing.T) {
+	var skipped SkippedItems
+
+	skipped.Add("organization", "Example Org", "org-exclude", `matched "Example*"`)
+	skipped.Add("sync_plan", "Example Org / Daily RHEL", "plan-ignore", `matched "Daily*"`)
+
+	if len(skipped) != 2 {
+		t.Fatalf("got %d skipped items, want 2", len(skipped))
+	}
+
+	want := SkippedItem{
+		Type:   "sync_plan",
+		Item:   "Example Org / Daily RHEL",
+		Rule:   "plan-ignore",
+		Reason: `matched "Daily*"`,
+	}
+
+	if got := skipped[1]; got != want {
+		t.Errorf("got skipped item %+v, want %+v", got, want)
+	}
+
+	wantString := `sync_plan "Example Org / Daily RHEL" skipped by rule plan-ignore: matched "Daily*"`
+	if got := skipped[1].String(); got != wantString {
+		t.Errorf("got %q, want %q", got, wantString)
+	}
+}
+
+func TestSkippedItemsByRule(t *testing.T) {
+	var skipped SkippedItems
+
+	skipped.Add("organization", "Org A", "org-exclude", "excluded")
+	skipped.Add("sync_plan", "Org B / Daily", "plan-ignore", "ignored")
+	skipped.Add("organization", "Org C", "org-exclude", "excluded")
+
+	tests := []struct {
+		rule      string
+		wantItems []string
+	}{
+		{rule: "org-exclude", wantItems: []string{"Org A", "Org C"}},
+		{rule: "plan-ignore", wantItems: []string{"Org B / Daily"}},
+		{rule: "missing", wantItems: []string{}},
+	}
+
+	for _, tt := range tests {
+		matches := skipped.ByRule(tt.rule)
+
+		if len(matches) != len(tt.wantItems) {
+			t.Errorf("got %d items for rule %q, want %d", len(matches), tt.rule, len(tt.wantItems))
+
+			continue
+		}
+
+		for i, match := range matches {
+			if match.Item != tt.wantItems[i] {
+				t.Errorf("got item %q at index %d for rule %q, want %q", match.Item, i, tt.rule, tt.wantItems[i])
+			}
+		}
+
+		if matches == nil {
+			t.Errorf("got nil collection for rule %q, want empty collection", tt.rule)
+		}
+	}
+
+	// The original collection is not modified.
+	if len(skipped) != 3 {
+		t.Errorf("got %d items in original collection, want 3", len(skipped))
+	}
+}
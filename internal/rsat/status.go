@@ -0,0 +1,333 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Known ping service status values.
+const (
+	PingServiceStatusOK   string = "ok"
+	PingServiceStatusFail string = "FAIL"
+)
+
+// modernSatelliteVersionMajor and modernSatelliteVersionMinor together
+// indicate the oldest Red Hat Satellite version considered to be "current"
+// by this project. Older versions are considered to be "legacy" versions
+// with known API differences (e.g., date/time layouts).
+const (
+	modernSatelliteVersionMajor int = 6
+	modernSatelliteVersionMinor int = 15
+)
+
+// StatusResponse represents the API response from a request for the
+// (Foreman) status of a Red Hat Satellite server.
+type StatusResponse struct {
+	// Result is the overall status result (e.g., "ok").
+	Result string `json:"result"`
+
+	// Version is the Foreman version.
+	Version string `json:"version"`
+
+	// SatelliteVersion is the Red Hat Satellite version. This value is not
+	// provided by upstream Foreman installations.
+	SatelliteVersion NullString `json:"satellite_version"`
+
+	// Status is the HTTP status code reported by the API.
+	Status int `json:"status"`
+
+	// APIVersion is the default API version.
+	APIVersion int `json:"api_version"`
+}
+
+// KatelloStatusResponse represents the API response from a request for the
+// Katello status of a Red Hat Satellite server.
+type KatelloStatusResponse struct {
+	// Version is the Katello version.
+	Version string `json:"version"`
+
+	// TimeUTC is the current time on the Red Hat Satellite server.
+	TimeUTC NullString `json:"timeUTC"`
+}
+
+// ServerVersion is the collection of version details for a Red Hat
+// Satellite server.
+type ServerVersion struct {
+	// Satellite is the Red Hat Satellite version. This value is empty for
+	// upstream Foreman/Katello installations.
+	Satellite string
+
+	// Foreman is the Foreman version.
+	Foreman string
+
+	// Katello is the Katello version.
+	Katello string
+}
+
+// PingResponse represents the API response from a request for the health
+// status of services used by a Red Hat Satellite server.
+type PingResponse struct {
+	// Services is the collection of services evaluated by the API, keyed by
+	// service name (e.g., candlepin, pulp3).
+	Services map[string]PingServiceStatus `json:"services"`
+
+	// Status is the overall status result (e.g., "ok", "FAIL").
+	Status string `json:"status"`
+}
+
+// PingServiceStatus is the health status of a specific service used by a Red
+// Hat Satellite server.
+type PingServiceStatus struct {
+	// Status is the status result for the service (e.g., "ok", "FAIL").
+	Status string `json:"status"`
+
+	// Message is an optional message explaining the status result.
+	Message NullString `json:"message"`
+
+	// DurationMS is the time in milliseconds required to evaluate the
+	// service. This value has been observed as a string value.
+	DurationMS json.Number `json:"duration_ms"`
+}
+
+// GetStatus uses the given client to retrieve the Satellite, Foreman and
+// Katello versions for the Red Hat Satellite server. The retrieved version
+// details are recorded on the client for later use by the caller or by
+// other functions in this package.
+func GetStatus(ctx context.Context, client *APIClient) (ServerVersion, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return ServerVersion{}, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	logger := client.Logger
+
+	statusURL := fmt.Sprintf(
+		StatusAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	var status StatusResponse
+	if err := getSingleResult(ctx, client, statusURL, QueryOptions{}, &status); err != nil {
+		return ServerVersion{}, err
+	}
+
+	katelloStatusURL := fmt.Sprintf(
+		KatelloStatusAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	var katelloStatus KatelloStatusResponse
+	if err := getSingleResult(ctx, client, katelloStatusURL, QueryOptions{}, &katelloStatus); err != nil {
+		return ServerVersion{}, err
+	}
+
+	serverVersion := ServerVersion{
+		Satellite: string(status.SatelliteVersion),
+		Foreman:   status.Version,
+		Katello:   katelloStatus.Version,
+	}
+
+	client.ServerVersion = serverVersion
+
+	logger.Debug().
+		Str("satellite_version", serverVersion.Satellite).
+		Str("foreman_version", serverVersion.Foreman).
+		Str("katello_version", serverVersion.Katello).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of server version details")
+
+	return serverVersion, nil
+}
+
+// GetPing uses the given client to retrieve the health status of services
+// used by the Red Hat Satellite server.
+func GetPing(ctx context.Context, client *APIClient) (PingResponse, error) {
+	funcTimeStart := time.Now()
+
+	if client == nil {
+		return PingResponse{}, fmt.Errorf(
+			"required API client was not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	apiURL := fmt.Sprintf(
+		PingAPIEndPointURLTemplate,
+		client.AuthInfo.Server,
+		client.AuthInfo.Port,
+	)
+
+	var ping PingResponse
+	if err := getSingleResult(ctx, client, apiURL, QueryOptions{}, &ping); err != nil {
+		return PingResponse{}, err
+	}
+
+	client.Logger.Debug().
+		Str("status", ping.Status).
+		Int("services", len(ping.Services)).
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of service health status")
+
+	return ping, nil
+}
+
+// getSingleResult is a helper function used to retrieve and decode a single
+// (non-paginated) result from the given API endpoint using the given query
+// options.
+func getSingleResult(ctx context.Context, client *APIClient, apiURL string, opts QueryOptions, dst interface{}) error {
+	logger := client.Logger
+
+	apiURLQueryParams := make(map[string]string)
+	if err := opts.setQueryParams(apiURLQueryParams); err != nil {
+		return err
+	}
+
+	response, respErr := client.SubmitRequest(ctx, RequestOptions{
+		Endpoint:    apiURL,
+		QueryParams: apiURLQueryParams,
+	})
+	if respErr != nil {
+		return respErr
+	}
+
+	defer func() {
+		if closeErr := response.Body.Close(); closeErr != nil {
+			logger.Error().Err(closeErr).Msg("error closing response body")
+		}
+	}()
+
+	logger.Debug().Msgf(
+		"Decoding JSON data from %q using a limit of %d bytes",
+		apiURL,
+		client.AuthInfo.ReadLimit,
+	)
+
+	if err := decode(dst, response.Body, logger, apiURL, client.AuthInfo.ReadLimit); err != nil {
+		return err
+	}
+
+	logger.Debug().
+		Str("api_endpoint", apiURL).
+		Msg("Successfully decoded JSON data")
+
+	return nil
+}
+
+// IsOKState indicates whether all services evaluated by the API are in an OK
+// state.
+func (pr PingResponse) IsOKState() bool {
+	return strings.EqualFold(pr.Status, PingServiceStatusOK) &&
+		len(pr.FailedServices()) == 0
+}
+
+// FailedServices returns the sorted names of all services not in an OK
+// state.
+func (pr PingResponse) FailedServices() []string {
+	failed := make([]string, 0, len(pr.Services))
+
+	for name, service := range pr.Services {
+		if !strings.EqualFold(service.Status, PingServiceStatusOK) {
+			failed = append(failed, name)
+		}
+	}
+
+	sort.Strings(failed)
+
+	return failed
+}
+
+// IsZero indicates whether version details have been recorded.
+func (sv ServerVersion) IsZero() bool {
+	return sv.Satellite == "" && sv.Foreman == "" && sv.Katello == ""
+}
+
+// IsSatellite indicates whether the version details are for a Red Hat
+// Satellite server (vs an upstream Foreman/Katello installation).
+func (sv ServerVersion) IsSatellite() bool {
+	return sv.Satellite != ""
+}
+
+// SatelliteAtLeast indicates whether the Red Hat Satellite version is equal
+// to or greater than the given major and minor version. False is returned if
+// the Red Hat Satellite version is unknown.
+func (sv ServerVersion) SatelliteAtLeast(major int, minor int) bool {
+	svMajor, svMinor, ok := parseMajorMinor(sv.Satellite)
+	if !ok {
+		return false
+	}
+
+	switch {
+	case svMajor != major:
+		return svMajor > major
+	default:
+		return svMinor >= minor
+	}
+}
+
+// IsLegacy indicates whether the Red Hat Satellite version is known and is
+// older than the versions considered "current" by this project. Legacy
+// versions have known API differences (e.g., date/time layouts).
+func (sv ServerVersion) IsLegacy() bool {
+	if _, _, ok := parseMajorMinor(sv.Satellite); !ok {
+		return false
+	}
+
+	return !sv.SatelliteAtLeast(modernSatelliteVersionMajor, modernSatelliteVersionMinor)
+}
+
+// String implements the fmt.Stringer interface as a convenience method.
+func (sv ServerVersion) String() string {
+	switch {
+	case sv.IsZero():
+		return "unknown"
+	case sv.IsSatellite():
+		return fmt.Sprintf(
+			"Satellite %s (Foreman %s, Katello %s)",
+			sv.Satellite,
+			sv.Foreman,
+			sv.Katello,
+		)
+	default:
+		return fmt.Sprintf(
+			"Foreman %s, Katello %s",
+			sv.Foreman,
+			sv.Katello,
+		)
+	}
+}
+
+// parseMajorMinor is a helper function used to parse the major and minor
+// version values from a version string (e.g., 6.15.0). A boolean value is
+// returned to indicate whether parsing was successful.
+func parseMajorMinor(version string) (int, int, bool) {
+	fields := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+
+	major, majorErr := strconv.Atoi(fields[0])
+	minor, minorErr := strconv.Atoi(fields[1])
+	if majorErr != nil || minorErr != nil {
+		return 0, 0, false
+	}
+
+	return major, minor, true
+}
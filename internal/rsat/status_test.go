@@ -0,0 +1,210 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// newStatusTestClient returns a client for a test server which serves the
+// status endpoints for a server reporting the given Red Hat Satellite version
+// along with a single organization and sync plan using the current next sync
+// time layout.
+func newStatusTestClient(t *testing.T, satelliteVersion string) *APIClient {
+	t.Helper()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2/status":
+			_, _ = fmt.Fprintf(
+				w,
+				`{"result":"ok","status":200,"version":"3.9.1.6","api_version":2,"satellite_version":%q}`,
+				satelliteVersion,
+			)
+		case "/katello/api/status":
+			_, _ = fmt.Fprint(w, `{"version":"4.11.0.9","timeUTC":"2024-05-14 18:30:02 UTC"}`)
+		case "/katello/api/v2/ping":
+			_, _ = fmt.Fprint(
+				w,
+				`{"status":"FAIL","services":{`+
+					`"candlepin":{"status":"ok","duration_ms":"12"},`+
+					`"pulp3":{"status":"FAIL","message":"timeout"},`+
+					`"foreman_tasks":{"status":"FAIL","duration_ms":"5"}}}`,
+			)
+		case "/api/v2/organizations":
+			_, _ = fmt.Fprint(w, `{"total":1,"subtotal":1,"page":1,"per_page":30,"search":null,"results":[{"id":1,"name":"Example"}]}`)
+		case "/katello/api/v2/organizations/1/sync_plans":
+			_, _ = fmt.Fprint(
+				w,
+				`{"total":1,"subtotal":1,"page":1,"per_page":30,"search":null,"results":[`+
+					`{"id":1,"name":"daily","organization_id":1,"interval":"daily","enabled":true,"next_sync":"2024-05-15 12:00:42 UTC"}]}`,
+			)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	server, port := testServerAddress(t, ts)
+
+	authInfo := APIAuthInfo{
+		Server:      server,
+		Port:        port,
+		Username:    "monitoring",
+		Password:    "secret",
+		NetworkType: "auto",
+		ReadLimit:   1024 * 1024,
+		TrustCert:   true,
+	}
+
+	return NewAPIClient(authInfo, APILimits{PerPage: 30}, zerolog.Nop())
+}
+
+func TestGetStatus(t *testing.T) {
+	client := newStatusTestClient(t, "6.15.1")
+
+	serverVersion, err := GetStatus(context.Background(), client)
+	if err != nil {
+		t.Fatalf("failed to retrieve status: %v", err)
+	}
+
+	want := ServerVersion{Satellite: "6.15.1", Foreman: "3.9.1.6", Katello: "4.11.0.9"}
+
+	if serverVersion != want {
+		t.Errorf("got server version %#v, want %#v", serverVersion, want)
+	}
+
+	if client.ServerVersion != want {
+		t.Errorf("got client server version %#v, want %#v", client.ServerVersion, want)
+	}
+
+	if want := "Satellite 6.15.1 (Foreman 3.9.1.6, Katello 4.11.0.9)"; serverVersion.String() != want {
+		t.Errorf("got %q, want %q", serverVersion.String(), want)
+	}
+}
+
+func TestGetPing(t *testing.T) {
+	client := newStatusTestClient(t, "6.15.1")
+
+	ping, err := GetPing(context.Background(), client)
+	if err != nil {
+		t.Fatalf("failed to retrieve ping status: %v", err)
+	}
+
+	if ping.IsOKState() {
+		t.Error("got OK state, want failed state")
+	}
+
+	if got, want := ping.FailedServices(), []string{"foreman_tasks", "pulp3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got failed services %q, want %q", got, want)
+	}
+
+	if got := string(ping.Services["pulp3"].Message); got != "timeout" {
+		t.Errorf("got message %q, want %q", got, "timeout")
+	}
+}
+
+func TestGetOrgsWithSyncPlansRecordsServerVersion(t *testing.T) {
+	tests := []struct {
+		satelliteVersion string
+		wantMinute       bool
+	}{
+		{satelliteVersion: "6.13.4", wantMinute: true},
+		{satelliteVersion: "6.15.1", wantMinute: false},
+		{satelliteVersion: "", wantMinute: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.satelliteVersion, func(t *testing.T) {
+			client := newStatusTestClient(t, tt.satelliteVersion)
+
+			orgs, err := GetOrgsWithSyncPlans(context.Background(), client, QueryOptions{})
+			if err != nil {
+				t.Fatalf("failed to retrieve organizations: %v", err)
+			}
+
+			if client.ServerVersion.Satellite != tt.satelliteVersion {
+				t.Errorf("got Satellite version %q, want %q", client.ServerVersion.Satellite, tt.satelliteVersion)
+			}
+
+			if orgs.NumPlans() != 1 {
+				t.Fatalf("got %d sync plans, want 1", orgs.NumPlans())
+			}
+
+			if got := orgs[0].SyncPlans[0].MinutePrecision; got != tt.wantMinute {
+				t.Errorf("got minute precision %t, want %t", got, tt.wantMinute)
+			}
+		})
+	}
+}
+
+func TestServerVersionSatelliteAtLeast(t *testing.T) {
+	tests := []struct {
+		version    string
+		major      int
+		minor      int
+		want       bool
+		wantLegacy bool
+	}{
+		{version: "6.15.0", major: 6, minor: 15, want: true},
+		{version: "6.16.2", major: 6, minor: 15, want: true},
+		{version: "7.0", major: 6, minor: 15, want: true},
+		{version: "6.5.3", major: 6, minor: 15, want: false, wantLegacy: true},
+		{version: "6.14.9", major: 6, minor: 15, want: false, wantLegacy: true},
+		{version: "5.99", major: 6, minor: 0, want: false, wantLegacy: true},
+		{version: "", major: 6, minor: 0, want: false},
+		{version: "nightly", major: 6, minor: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		sv := ServerVersion{Satellite: tt.version}
+
+		if got := sv.SatelliteAtLeast(tt.major, tt.minor); got != tt.want {
+			t.Errorf("SatelliteAtLeast(%d, %d) for %q = %t, want %t", tt.major, tt.minor, tt.version, got, tt.want)
+		}
+
+		if got := sv.IsLegacy(); got != tt.wantLegacy {
+			t.Errorf("IsLegacy() for %q = %t, want %t", tt.version, got, tt.wantLegacy)
+		}
+	}
+}
+
+func TestParseMajorMinor(t *testing.T) {
+	tests := []struct {
+		version   string
+		wantMajor int
+		wantMinor int
+		wantOK    bool
+	}{
+		{version: "6.15.1", wantMajor: 6, wantMinor: 15, wantOK: true},
+		{version: " 6.5 ", wantMajor: 6, wantMinor: 5, wantOK: true},
+		{version: "6.13.4.1-beta", wantMajor: 6, wantMinor: 13, wantOK: true},
+		{version: "6", wantOK: false},
+		{version: "6.x", wantOK: false},
+		{version: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		major, minor, ok := parseMajorMinor(tt.version)
+		if major != tt.wantMajor || minor != tt.wantMinor || ok != tt.wantOK {
+			t.Errorf(
+				"parseMajorMinor(%q) = %d, %d, %t; want %d, %d, %t",
+				tt.version, major, minor, ok, tt.wantMajor, tt.wantMinor, tt.wantOK,
+			)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Keys within paginated API query responses used when streaming results.
+const (
+	pagedResponseResultsKey  string = "results"
+	pagedResponseSubtotalKey string = "subtotal"
+)
+
+// decodePageResults is a helper function used to decode a single page of
+// results from a paginated API query response by streaming the elements of
+// the results array instead of buffering the whole page. Each decoded result
+// is provided to the given collect function as it is decoded. The number of
+// results decoded is returned along with the number of objects matching the
+// query across all pages. Other response fields are skipped.
+//
+// This reduces peak memory use when the per-page limit is large and the read
+// limit is raised accordingly.
+func decodePageResults[T any](
+	reader io.Reader,
+	logger zerolog.Logger,
+	sourceName string,
+	limit int64,
+	collect func(T),
+) (int, int, error) {
+	if reader == nil {
+		return 0, 0, &PrepError{
+			Task:    PrepTaskDecode,
+			Message: "failed to decode JSON data",
+			Source:  sourceName,
+			Cause: fmt.Errorf(
+				"required JSON source was not provided: %w",
+				ErrMissingValue,
+			),
+		}
+	}
+
+	logger.Debug().Msgf(
+		"Setting up streaming JSON decoder for source %s with a limit of %d bytes",
+		sourceName,
+		limit,
+	)
+
+	limitReader, counter := newLimitedReader(reader, limit)
+
+	dec := json.NewDecoder(limitReader)
+
+	var numResults int
+	var subtotal int
+
+	decodeErr := func() error {
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key, ok := keyToken.(string)
+			if !ok {
+				return fmt.Errorf("unexpected token %v; expected object key: %w", keyToken, ErrInvalidValue)
+			}
+
+			switch key {
+			case pagedResponseResultsKey:
+				if err := expectDelim(dec, '['); err != nil {
+					return err
+				}
+
+				for dec.More() {
+					var result T
+					if err := dec.Decode(&result); err != nil {
+						return err
+					}
+
+					collect(result)
+					numResults++
+				}
+
+				if err := expectDelim(dec, ']'); err != nil {
+					return err
+				}
+
+			case pagedResponseSubtotalKey:
+				if err := dec.Decode(&subtotal); err != nil {
+					return err
+				}
+
+			default:
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return err
+				}
+			}
+		}
+
+		return expectDelim(dec, '}')
+	}()
+
+	if decodeErr != nil {
+		return numResults, subtotal, decodeFailure(decodeErr, counter, limit, sourceName, logger)
+	}
+
+	logger.Debug().
+		Int("results", numResults).
+		Msg("Successfully decoded streamed JSON input")
+
+	// If there is more than one object, something is off.
+	if dec.More() {
+		return numResults, subtotal, multipleObjectsFailure(sourceName)
+	}
+
+	return numResults, subtotal, nil
+}
+
+// expectDelim is a helper function used to read the next JSON token and
+// assert that it is the given delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if got, ok := token.(json.Delim); !ok || got != delim {
+		return fmt.Errorf("unexpected token %v; expected %v: %w", token, delim, ErrInvalidValue)
+	}
+
+	return nil
+}
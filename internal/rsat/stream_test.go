@@ -0,0 +1,70 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestDecodePageResults(t *testing.T) {
+	const page = `{"total":3,"subtotal":3,"page":1,"per_page":2,` +
+		`"search":null,"sort":{"by":null,"order":null},` +
+		`"results":[{"id":1,"name":"Alpha"},{"id":2,"name":"Beta"}]}`
+
+	var orgs []Organization
+	collect := func(org Organization) { orgs = append(orgs, org) }
+
+	numResults, subtotal, err := decodePageResults(strings.NewReader(page), zerolog.Nop(), "page", 1024, collect)
+	if err != nil {
+		t.Fatalf("failed to decode page: %v", err)
+	}
+
+	if numResults != 2 || subtotal != 3 {
+		t.Errorf("got %d results and subtotal %d, want 2 and 3", numResults, subtotal)
+	}
+
+	if len(orgs) != 2 || orgs[1].Name != "Beta" {
+		t.Errorf("got organizations %+v, want Alpha and Beta", orgs)
+	}
+
+	_, _, err = decodePageResults(strings.NewReader(page), zerolog.Nop(), "page", 64, collect)
+	if !errors.Is(err, ErrReadLimitReached) {
+		t.Errorf("got error %v for truncated page, want %v", err, ErrReadLimitReached)
+	}
+
+	_, _, err = decodePageResults(strings.NewReader(page+page), zerolog.Nop(), "page", 1024, collect)
+	if !errors.Is(err, ErrJSONUnexpectedObjectCount) {
+		t.Errorf("got error %v for multiple objects, want %v", err, ErrJSONUnexpectedObjectCount)
+	}
+}
+
+func TestGetOrgsWithSyncPlansStreamDecode(t *testing.T) {
+	authInfo := APIAuthInfo{
+		Server:      "rsat.example.com",
+		Port:        443,
+		ReadLimit:   1024 * 1024,
+		FixturesDir: filepath.Join("testdata", "fixtures"),
+	}
+
+	limits := APILimits{PerPage: 1, StreamDecode: true}
+
+	orgs, err := GetOrgsWithSyncPlans(context.Background(), NewAPIClient(authInfo, limits, zerolog.Nop()), QueryOptions{})
+	if err != nil {
+		t.Fatalf("failed to read organizations from fixtures: %v", err)
+	}
+
+	if got := orgs.NumPlans(); got != 2 {
+		t.Errorf("got %d sync plans, want 2", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SuppressionTag is the tag recognized within the description of an
+// organization or sync plan as a request from Red Hat Satellite admins to
+// exclude the item from monitoring. This provides an in-band suppression
+// mechanism which does not require changes to monitoring configuration.
+const SuppressionTag string = "monitoring:ignore"
+
+// HasSuppressionTag indicates whether the given description contains the
+// suppression tag. The tag is matched case-insensitively as a separate word
+// (e.g., "Paused pending migration monitoring:ignore").
+func HasSuppressionTag(description NullString) bool {
+	for _, field := range strings.Fields(string(description)) {
+		if strings.EqualFold(strings.Trim(field, ".,;()[]\"'"), SuppressionTag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApplySuppressionTags returns a new collection excluding all organizations
+// whose description contains the suppression tag. Sync plans whose
+// description contains the suppression tag are removed from each remaining
+// organization. A record of each suppressed organization and sync plan is
+// returned.
+func (orgs Organizations) ApplySuppressionTags() (Organizations, SkippedItems) {
+	var skipped SkippedItems
+
+	reason := fmt.Sprintf("description contains %q tag", SuppressionTag)
+
+	filtered := make(Organizations, 0, len(orgs))
+
+	for _, org := range orgs {
+		if HasSuppressionTag(org.Description) {
+			skipped.Add(
+				SkippedItemTypeOrganization,
+				org.Name,
+				SkipRuleSuppressionTag,
+				reason,
+			)
+
+			continue
+		}
+
+		kept := make(SyncPlans, 0, len(org.SyncPlans))
+
+		for _, syncPlan := range org.SyncPlans {
+			if HasSuppressionTag(syncPlan.Description) {
+				skipped.Add(
+					SkippedItemTypeSyncPlan,
+					fmt.Sprintf("%s / %s", org.Name, syncPlan.Name),
+					SkipRuleSuppressionTag,
+					reason,
+				)
+
+				continue
+			}
+
+			kept = append(kept, syncPlan)
+		}
+
+		org.SyncPlans = kept
+		filtered = append(filtered, org)
+	}
+
+	return filtered, skipped
+}
@@ -12,7 +12,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,6 +26,27 @@ import (
 // plans.
 const syncTimeGraceMinutes float64 = 5
 
+// Known sync plan interval values.
+const (
+	SyncPlanIntervalHourly     string = "hourly"
+	SyncPlanIntervalDaily      string = "daily"
+	SyncPlanIntervalWeekly     string = "weekly"
+	SyncPlanIntervalCustomCron string = "custom cron"
+)
+
+// SyncPlansSearchEnabled is the scoped search query used to limit sync plans
+// retrieval to enabled sync plans so that disabled sync plans are not
+// transferred.
+const SyncPlansSearchEnabled string = "enabled = true"
+
+// Known product sync_state values which indicate that the most recent sync
+// of the product did not complete successfully.
+const (
+	ProductSyncStateIncomplete string = "Sync Incomplete"
+	ProductSyncStateError      string = "error"
+	ProductSyncStateFailed     string = "failed"
+)
+
 // SyncPlansResponse represents the API response from a request of all sync
 // plans for a specific organization.
 //
@@ -62,6 +85,12 @@ type SyncPlansResponse struct {
 	PerPage int `json:"per_page"`
 }
 
+// pageResults returns the sync plans provided by the response along with the
+// number of sync plans matching the query across all pages.
+func (r SyncPlansResponse) pageResults() ([]SyncPlan, int) {
+	return r.SyncPlans, r.Subtotal
+}
+
 // SyncPlan represents a Red Hat Satellite sync plan. Sync plans are used to
 // schedule execution of content synchronization.
 type SyncPlan struct {
@@ -82,6 +111,65 @@ type SyncPlan struct {
 	OrganizationID    int                 `json:"organization_id"`
 	Permissions       SyncPlanPermissions `json:"permissions"`
 	Enabled           bool                `json:"enabled"`
+
+	// MinutePrecision indicates that the next sync time for the plan is
+	// only provided with minute precision (e.g., by legacy Red Hat
+	// Satellite versions). Comparisons against the next sync time are
+	// truncated to minute granularity for these plans.
+	MinutePrecision bool `json:"-"`
+
+	// EvaluateProductSyncState indicates whether the sync state of products
+	// associated with the sync plan is considered when evaluating whether
+	// the sync plan is in a problem state.
+	EvaluateProductSyncState bool `json:"-"`
+
+	// RecurringLogicState is the state of the recurring logic used to
+	// trigger execution of the sync plan (e.g., active, cancelled). This
+	// value is empty unless recurring logics were retrieved for evaluation.
+	RecurringLogicState string `json:"-"`
+
+	// MaxProductSyncAge is the maximum age permitted for the last sync of
+	// products associated with the sync plan. A value of 0 disables
+	// evaluation of product last sync age.
+	MaxProductSyncAge time.Duration `json:"-"`
+
+	// MaxIntervalDrift is the maximum number of sync plan intervals
+	// permitted since the most recent sync of any product associated with
+	// the sync plan. A value of 0 disables evaluation of interval drift.
+	MaxIntervalDrift float64 `json:"-"`
+
+	// Owner is the team or individual responsible for the sync plan as
+	// determined by a naming convention or organization parameter.
+	Owner string `json:"-"`
+
+	// LastRun is the time of the previous evaluation of the sync plan
+	// (e.g., as recorded in a state file). A zero value indicates that the
+	// time of the previous evaluation is unknown.
+	LastRun time.Time `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. In addition to
+// decoding the sync plan as-is, the next_sync value is examined to determine
+// whether it is provided with only minute precision.
+func (sp *SyncPlan) UnmarshalJSON(data []byte) error {
+	type syncPlan SyncPlan
+
+	var decoded syncPlan
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var raw struct {
+		NextSync NullString `json:"next_sync"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*sp = SyncPlan(decoded)
+	sp.MinutePrecision = isLegacySyncTimeValue(string(raw.NextSync))
+
+	return nil
 }
 
 // SyncPlanPermissions is the collection of permissions that a user querying
@@ -104,20 +192,51 @@ type Product struct {
 	SyncState       string          `json:"sync_state"`
 	ID              int             `json:"id"`
 	RepositoryCount int             `json:"repository_count"`
+
+	// Excluded indicates that the product is excluded from product-based
+	// evaluation (e.g., product sync state).
+	Excluded bool `json:"-"`
 }
 
 // Products is a collection of product values associated with a Red Hat
 // Satellite sync plan.
 type Products []Product
 
+// SyncFailed indicates whether the most recent sync of the product did not
+// complete successfully.
+func (p Product) SyncFailed() bool {
+	for _, state := range []string{
+		ProductSyncStateIncomplete,
+		ProductSyncStateError,
+		ProductSyncStateFailed,
+	} {
+		if strings.EqualFold(strings.TrimSpace(p.SyncState), state) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Names returns the names of all products in the collection.
+func (ps Products) Names() []string {
+	names := make([]string, 0, len(ps))
+	for _, product := range ps {
+		names = append(names, product.Name)
+	}
+
+	return names
+}
+
 // SyncPlans is a collection of Red Hat Satellite sync plans.
 type SyncPlans []SyncPlan
 
 // GetSyncPlans uses the provided APIClient to retrieve all sync plans for
 // each specified Red Hat Satellite organization. If no organizations are
 // specified then an attempt will be made to retrieve sync plans from all RSAT
-// organizations.
-func GetSyncPlans(ctx context.Context, client *APIClient, orgs ...Organization) (SyncPlans, error) {
+// organizations. The given query options (e.g., a scoped search of enabled =
+// true) are used to limit and order the sync plans retrieved.
+func GetSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions, orgs ...Organization) (SyncPlans, error) {
 	funcTimeStart := time.Now()
 
 	if client == nil {
@@ -131,7 +250,7 @@ func GetSyncPlans(ctx context.Context, client *APIClient, orgs ...Organization)
 
 	if len(orgs) == 0 {
 		var orgsErr error
-		orgs, orgsErr = GetOrganizations(ctx, client)
+		orgs, orgsErr = GetOrganizations(ctx, client, QueryOptions{})
 		if orgsErr != nil {
 			return nil, orgsErr
 		}
@@ -154,7 +273,7 @@ func GetSyncPlans(ctx context.Context, client *APIClient, orgs ...Organization)
 
 		subLogger.Debug().Msg("Retrieving sync plans for organization")
 
-		syncPlans, err := getOrgSyncPlans(ctx, client, org)
+		syncPlans, err := getOrgSyncPlans(ctx, client, opts, org)
 		if err != nil {
 			return nil, err
 		}
@@ -191,10 +310,13 @@ func GetSyncPlans(ctx context.Context, client *APIClient, orgs ...Organization)
 }
 
 // IsOKState indicates whether any problems have been identified with this
-// sync plan.
-func (sp SyncPlan) IsOKState() bool {
+// sync plan as of the given evaluation reference time.
+func (sp SyncPlan) IsOKState(now time.Time) bool {
 	switch {
-	case sp.IsStuck():
+	case sp.IsStuck(now):
+		return false
+
+	case sp.HasProductProblems(now):
 		return false
 
 	// NOTE: While stuck plans are the current focus we may wish to expand the
@@ -206,21 +328,203 @@ func (sp SyncPlan) IsOKState() bool {
 	}
 }
 
+// HasProductProblems indicates whether any products associated with the sync
+// plan have been identified as problematic (e.g., failed or stale sync) as
+// of the given evaluation reference time. Only enabled product-based
+// evaluations are considered.
+func (sp SyncPlan) HasProductProblems(now time.Time) bool {
+	switch {
+	case sp.EvaluateProductSyncState && len(sp.FailedProducts(now)) > 0:
+		return true
+
+	case len(sp.StaleProducts(now)) > 0:
+		return true
+
+	case sp.IsDrifting(now):
+		return true
+
+	default:
+		return false
+	}
+}
+
+// IntervalDuration returns the time between scheduled executions of the sync
+// plan. A boolean value is returned to indicate whether the duration is
+// known; the duration of custom cron intervals is not evaluated.
+func (sp SyncPlan) IntervalDuration() (time.Duration, bool) {
+	switch strings.ToLower(sp.Interval) {
+	case SyncPlanIntervalHourly:
+		return time.Hour, true
+
+	case SyncPlanIntervalDaily:
+		return 24 * time.Hour, true
+
+	case SyncPlanIntervalWeekly:
+		return 7 * 24 * time.Hour, true
+
+	default:
+		return 0, false
+	}
+}
+
+// StaleProducts returns the products associated with the (enabled) sync plan
+// which have not synced within the maximum product sync age as of the given
+// evaluation reference time. Products which have never synced are considered
+// stale once the first scheduled execution of the sync plan is older than
+// the maximum product sync age.
+func (sp SyncPlan) StaleProducts(now time.Time) Products {
+	if !sp.Enabled || sp.MaxProductSyncAge <= 0 {
+		return nil
+	}
+
+	var stale Products
+
+	now = sp.comparisonTime(now).UTC()
+
+	for _, product := range sp.Products {
+		lastSync := time.Time(product.LastSync).UTC()
+		if lastSync.IsZero() {
+			lastSync = time.Time(sp.OriginalSyncDate).UTC()
+		}
+
+		switch {
+		case product.Excluded:
+			continue
+
+		case lastSync.IsZero():
+			continue
+
+		case now.Sub(lastSync) > sp.MaxProductSyncAge:
+			stale = append(stale, product)
+		}
+	}
+
+	return stale
+}
+
+// LastProductSync returns the most recent last sync time of all (non-excluded)
+// products associated with the sync plan. The zero value is returned if no
+// products have synced.
+func (sp SyncPlan) LastProductSync() time.Time {
+	var latest time.Time
+
+	for _, product := range sp.Products {
+		if product.Excluded {
+			continue
+		}
+
+		if lastSync := time.Time(product.LastSync); lastSync.After(latest) {
+			latest = lastSync
+		}
+	}
+
+	return latest
+}
+
+// IsDrifting indicates whether the (enabled) sync plan is drifting from its
+// interval as of the given evaluation reference time. A sync plan is
+// considered to be drifting if more than the maximum number of intervals
+// have passed since the most recent sync of any associated product (e.g., a
+// daily sync plan whose products last synced 4 days ago) even if the next
+// sync time is in the future. Sync plans with an unknown interval duration
+// (e.g., custom cron) or without synced products are not evaluated.
+func (sp SyncPlan) IsDrifting(now time.Time) bool {
+	if !sp.Enabled || sp.MaxIntervalDrift <= 0 {
+		return false
+	}
+
+	interval, ok := sp.IntervalDuration()
+	if !ok {
+		return false
+	}
+
+	lastSync := sp.LastProductSync()
+	if lastSync.IsZero() {
+		return false
+	}
+
+	now = sp.comparisonTime(now)
+	maxDrift := time.Duration(float64(interval) * sp.MaxIntervalDrift)
+
+	return now.Sub(lastSync) > maxDrift
+}
+
+// CreatedSinceLastRun indicates whether the sync plan was created after the
+// previous evaluation. False is returned if the time of the previous
+// evaluation is unknown.
+func (sp SyncPlan) CreatedSinceLastRun() bool {
+	return !sp.LastRun.IsZero() &&
+		time.Time(sp.CreatedAt).After(sp.LastRun)
+}
+
+// ModifiedSinceLastRun indicates whether the sync plan was created or
+// modified after the previous evaluation. This is useful for correlating a
+// change in sync plan state with recent changes made by Red Hat Satellite
+// admins. False is returned if the time of the previous evaluation is
+// unknown.
+func (sp SyncPlan) ModifiedSinceLastRun() bool {
+	return !sp.LastRun.IsZero() &&
+		time.Time(sp.UpdatedAt).After(sp.LastRun)
+}
+
+// FailedProducts returns the products associated with the sync plan whose
+// most recent sync did not complete successfully as of the given evaluation
+// reference time. This includes products with a failed sync state and
+// products which have never synced even though the first scheduled execution
+// of the (enabled) sync plan has passed.
+func (sp SyncPlan) FailedProducts(now time.Time) Products {
+	var failed Products
+
+	now = sp.comparisonTime(now).UTC()
+	firstSync := time.Time(sp.OriginalSyncDate).UTC()
+	firstSyncPassed := !firstSync.IsZero() &&
+		now.Sub(firstSync).Minutes() > syncTimeGraceMinutes
+
+	for _, product := range sp.Products {
+		switch {
+		case product.Excluded:
+			continue
+
+		case product.SyncFailed():
+			failed = append(failed, product)
+
+		case sp.Enabled && firstSyncPassed && time.Time(product.LastSync).IsZero():
+			failed = append(failed, product)
+		}
+	}
+
+	return failed
+}
+
 // IsStuck indicates whether (after any applied grace time) the sync plan is
-// considered to be in a "stuck" state (Next Sync state set to past date/time).
+// considered to be in a "stuck" state (Next Sync state set to past date/time)
+// as of the given evaluation reference time.
 //
 // Grace time is applied to help prevent flagging a sync plan that is
 // "spinning up" or in a temporary pending status (e.g., on a busy system) as
 // problematic.
 //
+// Sync plans using a custom cron interval with a valid cron expression are
+// evaluated against the expected schedule instead of relying solely on the
+// next sync time (which Red Hat Satellite sometimes reports inconsistently
+// for cron plans).
+//
 // NOTE: Very busy systems keeping sync plans in a pending state for an
 // extended duration are still likely to be flagged as non-OK by current
 // logic.
-func (sp SyncPlan) IsStuck() bool {
-	now := time.Now().UTC()
+func (sp SyncPlan) IsStuck(now time.Time) bool {
+	now = sp.comparisonTime(now).UTC()
 	nextSync := time.Time(sp.NextSync).UTC()
 
 	switch {
+	// An enabled sync plan whose recurring logic is no longer active will
+	// not be executed regardless of the next sync time.
+	case sp.Enabled && sp.HasInactiveRecurringLogic():
+		return true
+
+	case sp.Enabled && sp.hasCronSchedule():
+		return sp.isCronStuck(now)
+
 	case sp.Enabled && nextSync.Before(now):
 		diff := now.Sub(nextSync).Minutes()
 
@@ -235,9 +539,109 @@ func (sp SyncPlan) IsStuck() bool {
 	}
 }
 
+// IsNeverScheduled indicates whether the sync plan is enabled but has never
+// been scheduled (i.e., Red Hat Satellite does not provide a next sync
+// time).
+func (sp SyncPlan) IsNeverScheduled() bool {
+	return sp.Enabled && time.Time(sp.NextSync).IsZero()
+}
+
+// CronSchedule returns the parsed cron expression for a sync plan using a
+// custom cron interval. A boolean value is returned to indicate whether the
+// sync plan uses a custom cron interval with a valid cron expression.
+func (sp SyncPlan) CronSchedule() (CronSchedule, bool) {
+	if !strings.EqualFold(sp.Interval, SyncPlanIntervalCustomCron) {
+		return CronSchedule{}, false
+	}
+
+	schedule, err := ParseCronExpression(string(sp.CronExpression))
+	if err != nil {
+		return CronSchedule{}, false
+	}
+
+	return schedule, true
+}
+
+// ExpectedLastSync returns the most recent time (after any applied grace
+// time) that the sync plan was scheduled to run as of the given evaluation
+// reference time based on the custom cron expression for the sync plan. A
+// boolean value is returned to indicate whether the expected sync time could
+// be determined.
+//
+// The cron expression is evaluated in the time zone of the next sync time
+// (or the original sync date if the next sync time is not set) reported by
+// Red Hat Satellite.
+func (sp SyncPlan) ExpectedLastSync(now time.Time) (time.Time, bool) {
+	schedule, ok := sp.CronSchedule()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	loc := time.Time(sp.NextSync).Location()
+	if time.Time(sp.NextSync).IsZero() {
+		loc = time.Time(sp.OriginalSyncDate).Location()
+	}
+
+	grace := time.Duration(syncTimeGraceMinutes * float64(time.Minute))
+
+	return schedule.Prev(now.Add(-grace).In(loc))
+}
+
+// hasCronSchedule indicates whether the sync plan uses a custom cron
+// interval with a valid cron expression.
+func (sp SyncPlan) hasCronSchedule() bool {
+	_, ok := sp.CronSchedule()
+
+	return ok
+}
+
+// isCronStuck indicates whether a sync plan using a custom cron interval is
+// considered to be in a "stuck" state as of the given evaluation reference
+// time. The sync plan is considered stuck if the next sync time has not
+// advanced past the most recent (expected) scheduled execution of the sync
+// plan.
+func (sp SyncPlan) isCronStuck(now time.Time) bool {
+	lastExpected, ok := sp.ExpectedLastSync(now)
+	if !ok {
+		return false
+	}
+
+	nextSync := time.Time(sp.NextSync)
+	firstSync := time.Time(sp.OriginalSyncDate)
+
+	switch {
+	// A sync plan which has not yet reached its first scheduled execution
+	// is not expected to have run.
+	case !firstSync.IsZero() && lastExpected.Before(firstSync.Truncate(time.Minute)):
+		return false
+
+	case nextSync.IsZero():
+		return true
+
+	default:
+		return !nextSync.Truncate(time.Minute).After(lastExpected)
+	}
+}
+
+// HasInactiveRecurringLogic indicates whether the recurring logic used to
+// trigger execution of the sync plan is known to no longer be active (e.g.,
+// cancelled or disabled). False is returned if the recurring logic state is
+// unknown.
+func (sp SyncPlan) HasInactiveRecurringLogic() bool {
+	return sp.RecurringLogicState != "" &&
+		!strings.EqualFold(sp.RecurringLogicState, RecurringLogicStateActive)
+}
+
 // DaysStuck indicates how many days the sync plan has been in a "stuck"
-// state.
-func (sp SyncPlan) DaysStuck() int {
+// state as of the given evaluation reference time.
+//
+// Sync plans whose recurring logic is no longer active are stuck even if the
+// next sync time is in the future. For these sync plans the number of days
+// is determined from the first scheduled execution missed since the most
+// recent product sync (see MissedSyncSince).
+func (sp SyncPlan) DaysStuck(now time.Time) int {
+	now = sp.comparisonTime(now)
+
 	switch {
 	case !sp.Enabled:
 		// Disabled sync plans are not considered "stuck" as they have been
@@ -245,43 +649,81 @@ func (sp SyncPlan) DaysStuck() int {
 		return 0
 
 	case time.Time(sp.NextSync).IsZero():
-
 		// Use creation date of the plan instead of the time zero value.
-		timeSinceStuck := time.Since(time.Time(sp.OriginalSyncDate)).Hours()
-
-		// Toss remainder so that we only get the whole number of days
-		daysStuck := int(math.Trunc(timeSinceStuck / 24))
-		if daysStuck < 0 {
-			daysStuck = 0
-		}
+		return daysSince(now, time.Time(sp.OriginalSyncDate))
 
-		return daysStuck
+	case sp.HasInactiveRecurringLogic() && time.Time(sp.NextSync).After(now):
+		return daysSince(now, sp.MissedSyncSince())
 
 	default:
-		timeSinceStuck := time.Since(time.Time(sp.NextSync)).Hours()
+		return daysSince(now, time.Time(sp.NextSync))
+	}
+}
 
-		// Toss remainder so that we only get the whole number of days
-		daysStuck := int(math.Trunc(timeSinceStuck / 24))
-		if daysStuck < 0 {
-			daysStuck = 0
-		}
+// MissedSyncSince returns the first scheduled execution of the sync plan
+// which was missed since the most recent sync of any (non-excluded) product
+// associated with the sync plan. This is used to determine how long a sync
+// plan whose recurring logic is no longer active has been stuck as the next
+// sync time reported for these sync plans is not updated.
+//
+// If no products have synced the original sync date is returned. If the
+// interval duration is not known (e.g., custom cron) the most recent product
+// sync time is returned.
+func (sp SyncPlan) MissedSyncSince() time.Time {
+	lastSync := sp.LastProductSync()
+	if lastSync.IsZero() {
+		return time.Time(sp.OriginalSyncDate)
+	}
+
+	interval, ok := sp.IntervalDuration()
+	if !ok {
+		return lastSync
+	}
+
+	return lastSync.Add(interval)
+}
+
+// daysSince is a helper function used to determine the whole number of days
+// between the given time and the given evaluation reference time. Zero is
+// returned if the given time is after the evaluation reference time.
+func daysSince(now time.Time, since time.Time) int {
+	elapsed := now.Sub(since).Hours()
+
+	// Toss remainder so that we only get the whole number of days
+	days := int(math.Trunc(elapsed / 24))
+	if days < 0 {
+		days = 0
+	}
+
+	return days
+}
 
-		return daysStuck
+// comparisonTime is a helper method used to normalize the given evaluation
+// reference time to the precision of the sync plan's next sync time. This
+// prevents flagging a plan as stuck by a few seconds right at the boundary
+// when the next sync time is only provided with minute precision.
+func (sp SyncPlan) comparisonTime(now time.Time) time.Time {
+	if sp.MinutePrecision {
+		return now.Truncate(time.Minute)
 	}
+
+	return now
 }
 
 // DaysStuckHR provides a human readable indication of how many days in the
-// past the sync plan has been in a "stuck" state.
-func (sp SyncPlan) DaysStuckHR() string {
-	if sp.IsOKState() {
+// past the sync plan has been in a "stuck" state as of the given evaluation
+// reference time.
+func (sp SyncPlan) DaysStuckHR(now time.Time) string {
+	if !sp.IsStuck(now) {
 		return "N/A"
 	}
 
-	if sp.DaysStuck() == 0 {
+	daysStuck := sp.DaysStuck(now)
+	if daysStuck == 0 {
 		return "<1d"
 	}
 
-	return strconv.Itoa(sp.DaysStuck())
+	return strconv.Itoa(daysStuck)
 }
 
 // NextSyncTime provides a display friendly version of the next scheduled sync
@@ -328,12 +770,12 @@ func (sps SyncPlans) NumDisabled() int {
 }
 
 // NumStuck indicates the number of sync plans in the collection are in a
-// "stuck" state.
-func (sps SyncPlans) NumStuck() int {
+// "stuck" state as of the given evaluation reference time.
+func (sps SyncPlans) NumStuck(now time.Time) int {
 	var num int
 
 	for _, syncPlan := range sps {
-		if syncPlan.IsStuck() {
+		if syncPlan.IsStuck(now) {
 			num++
 		}
 	}
@@ -341,20 +783,55 @@ func (sps SyncPlans) NumStuck() int {
 	return num
 }
 
-// NumProblemPlans returns the total number of sync plans with a non-OK state.
-func (sps SyncPlans) NumProblemPlans() int {
-	// NOTE: While stuck plans are the current focus we may wish to expand the
-	// list of problem "symptoms" to include other attributes in the future.
-	// This method provides a more generic "are there any problems" status
-	// check to cover that possibility.
-	return sps.NumStuck()
+// NumStuckFor indicates the number of sync plans in the collection which have
+// been in a "stuck" state for at least the given number of days as of the
+// given evaluation reference time.
+func (sps SyncPlans) NumStuckFor(now time.Time, days int) int {
+	var num int
+
+	for _, syncPlan := range sps {
+		if syncPlan.IsStuck(now) && syncPlan.DaysStuck(now) >= days {
+			num++
+		}
+	}
+
+	return num
+}
+
+// NumProblemPlans returns the total number of sync plans with a non-OK state
+// as of the given evaluation reference time.
+func (sps SyncPlans) NumProblemPlans(now time.Time) int {
+	var num int
+
+	for _, syncPlan := range sps {
+		if !syncPlan.IsOKState(now) {
+			num++
+		}
+	}
+
+	return num
+}
+
+// NumWithProductProblems indicates the number of sync plans in the
+// collection which have one or more problematic products (e.g., failed or
+// stale sync) as of the given evaluation reference time.
+func (sps SyncPlans) NumWithProductProblems(now time.Time) int {
+	var num int
+
+	for _, syncPlan := range sps {
+		if syncPlan.HasProductProblems(now) {
+			num++
+		}
+	}
+
+	return num
 }
 
 // IsOKState indicates whether any problems have been identified with the sync
-// plans in this collection.
-func (sps SyncPlans) IsOKState() bool {
+// plans in this collection as of the given evaluation reference time.
+func (sps SyncPlans) IsOKState(now time.Time) bool {
 	for _, syncPlan := range sps {
-		if !syncPlan.IsOKState() {
+		if !syncPlan.IsOKState(now) {
 			return false
 		}
 	}
@@ -391,13 +868,13 @@ func (sps SyncPlans) Disabled() SyncPlans {
 }
 
 // Stuck returns a new collection containing all sync plans from the original
-// collection which are in a "stuck" state.
-func (sps SyncPlans) Stuck() SyncPlans {
-	matches := make(SyncPlans, 0, sps.NumStuck())
-	now := time.Now()
+// collection which are in a "stuck" state as of the given evaluation
+// reference time.
+func (sps SyncPlans) Stuck(now time.Time) SyncPlans {
+	matches := make(SyncPlans, 0, sps.NumStuck(now))
 
 	for _, syncPlan := range sps {
-		if syncPlan.Enabled && time.Time(syncPlan.NextSync).Before(now) {
+		if syncPlan.IsStuck(now) {
 			matches = append(matches, syncPlan)
 		}
 	}
@@ -405,8 +882,64 @@ func (sps SyncPlans) Stuck() SyncPlans {
 	return matches
 }
 
+// SortByDaysStuck sorts the sync plans in the collection by the number of
+// days stuck as of the given evaluation reference time (most first). Sync
+// plans in a "stuck" state are sorted before those which are not, even if
+// stuck for less than a day. Sync plans stuck for the same number of days are
+// sorted by organization name and then by sync plan name.
+func (sps SyncPlans) SortByDaysStuck(now time.Time) {
+	sort.SliceStable(sps, func(i int, j int) bool {
+		daysI, daysJ := sps[i].DaysStuck(now), sps[j].DaysStuck(now)
+		stuckI, stuckJ := sps[i].IsStuck(now), sps[j].IsStuck(now)
+
+		switch {
+		case stuckI != stuckJ:
+			return stuckI
+		case daysI != daysJ:
+			return daysI > daysJ
+		case sps[i].OrganizationName != sps[j].OrganizationName:
+			return sps[i].OrganizationName < sps[j].OrganizationName
+		default:
+			return sps[i].Name < sps[j].Name
+		}
+	})
+}
+
+// SortByName sorts the sync plans in the collection by name.
+func (sps SyncPlans) SortByName() {
+	sort.SliceStable(sps, func(i int, j int) bool {
+		return sps[i].Name < sps[j].Name
+	})
+}
+
+// SortByNextSync sorts the sync plans in the collection by the next
+// scheduled sync time (earliest first). Sync plans without a scheduled sync
+// are sorted last. Sync plans with the same next sync time are sorted by
+// name.
+func (sps SyncPlans) SortByNextSync() {
+	sort.SliceStable(sps, func(i int, j int) bool {
+		nextI, nextJ := time.Time(sps[i].NextSync), time.Time(sps[j].NextSync)
+
+		switch {
+		case nextI.IsZero() != nextJ.IsZero():
+			return nextJ.IsZero()
+		case !nextI.Equal(nextJ):
+			return nextI.Before(nextJ)
+		default:
+			return sps[i].Name < sps[j].Name
+		}
+	})
+}
+
+// Reverse reverses the order of the sync plans in the collection.
+func (sps SyncPlans) Reverse() {
+	for i, j := 0, len(sps)-1; i < j; i, j = i+1, j-1 {
+		sps[i], sps[j] = sps[j], sps[i]
+	}
+}
+
 // getOrgSyncPlans retrieves all sync plans for the given organization.
-func getOrgSyncPlans(ctx context.Context, client *APIClient, org Organization) (SyncPlans, error) {
+func getOrgSyncPlans(ctx context.Context, client *APIClient, opts QueryOptions, org Organization) (SyncPlans, error) {
 	funcTimeStart := time.Now()
 
 	subLogger := client.Logger.With().
@@ -421,81 +954,130 @@ func getOrgSyncPlans(ctx context.Context, client *APIClient, org Organization) (
 		org.ID,
 	)
 
-	allSyncPlans := make(SyncPlans, 0, client.Limits.PerPage*2)
-
-	apiURLQueryParams := make(map[string]string)
-	apiURLQueryParams[APIEndpointURLQueryParamFullResultKey] = APIEndpointURLQueryParamFullResultDefaultValue
-	apiURLQueryParams[APIEndpointURLQueryParamPerPageKey] = strconv.Itoa(client.Limits.PerPage)
-
-	var nextPage int
-	remainingSyncPlans := true
+	allSyncPlans, err := fetchAllPages[SyncPlan, SyncPlansResponse](
+		ctx,
+		client,
+		apiURL,
+		opts,
+		subLogger,
+		"sync plans",
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	for remainingSyncPlans {
-		subLogger.Debug().
-			Msg("Collecting sync plans from the API")
+	annotateSyncPlans(client, org, allSyncPlans)
 
-		nextPage++
-		apiURLQueryParams[APIEndpointURLQueryParamPageKey] = strconv.Itoa(nextPage)
+	subLogger.Debug().
+		Str("runtime_total", time.Since(funcTimeStart).String()).
+		Msg("Completed retrieval of all sync plans for organization")
 
-		response, respErr := submitAPIQueryRequest(ctx, client, apiURL, apiURLQueryParams, subLogger)
-		if respErr != nil {
-			return nil, respErr
-		}
+	return allSyncPlans, nil
 
-		subLogger.Debug().Msgf(
-			"Decoding JSON data from %q using a limit of %d bytes",
-			apiURL,
-			client.AuthInfo.ReadLimit,
-		)
+}
 
-		var syncPlansQueryResp SyncPlansResponse
-		decodeErr := decode(&syncPlansQueryResp, response.Body, subLogger, apiURL, client.AuthInfo.ReadLimit)
-		if decodeErr != nil {
-			return nil, decodeErr
+// annotateSyncPlans annotates the given sync plans with specific values from
+// the given organization (and server) for convenience.
+func annotateSyncPlans(client *APIClient, org Organization, syncPlans SyncPlans) {
+	for i := range syncPlans {
+		syncPlans[i].OrganizationName = org.Name
+		syncPlans[i].OrganizationLabel = org.Label
+		syncPlans[i].OrganizationTitle = org.Title
+
+		// Legacy Red Hat Satellite versions render the next sync time with
+		// minute precision. This is usually detected from the value itself,
+		// but we also apply it if the server version is known to be legacy.
+		if client.ServerVersion.IsLegacy() {
+			syncPlans[i].MinutePrecision = true
 		}
+	}
+}
 
-		subLogger.Debug().
-			Str("api_endpoint", apiURL).
-			Msg("Successfully decoded JSON data")
+// EvaluatedSyncPlan is a sync plan along with the evaluation reference time
+// used to determine derived values such as whether the sync plan is "stuck".
+// This type is intended for use when exporting sync plans (e.g., as JSON) so
+// that consumers do not have to re-implement stuck detection logic.
+type EvaluatedSyncPlan struct {
+	SyncPlan
 
-		// Close the response body once we're done with it. We explicitly
-		// close here vs deferring via closure to prevent accumulating client
-		// connections to the API if we need to perform multiple paged
-		// requests.
-		if closeErr := response.Body.Close(); closeErr != nil {
-			subLogger.Error().Err(closeErr).Msg("error closing response body")
-		}
+	// EvaluatedAt is the evaluation reference time used to determine
+	// derived values for the sync plan.
+	EvaluatedAt time.Time
+}
 
-		// Annotate Sync Plans with specific Org values for convenience.
-		for i := range syncPlansQueryResp.SyncPlans {
-			syncPlansQueryResp.SyncPlans[i].OrganizationName = org.Name
-			syncPlansQueryResp.SyncPlans[i].OrganizationLabel = org.Label
-			syncPlansQueryResp.SyncPlans[i].OrganizationTitle = org.Title
-		}
+// EvaluatedSyncPlans is a collection of evaluated sync plans.
+type EvaluatedSyncPlans []EvaluatedSyncPlan
 
-		allSyncPlans = append(allSyncPlans, syncPlansQueryResp.SyncPlans...)
+// Evaluate returns the sync plan paired with the given evaluation reference
+// time for use when exporting derived values.
+func (sp SyncPlan) Evaluate(now time.Time) EvaluatedSyncPlan {
+	return EvaluatedSyncPlan{
+		SyncPlan:    sp,
+		EvaluatedAt: now,
+	}
+}
 
-		numNewSyncPlans := len(syncPlansQueryResp.SyncPlans)
-		numCollectedSyncPlans := len(allSyncPlans)
-		numSyncPlansRemaining := syncPlansQueryResp.Subtotal - numCollectedSyncPlans
+// Evaluate returns each sync plan in the collection paired with the given
+// evaluation reference time for use when exporting derived values.
+func (sps SyncPlans) Evaluate(now time.Time) EvaluatedSyncPlans {
+	evaluated := make(EvaluatedSyncPlans, 0, len(sps))
 
-		subLogger.Debug().
-			Str("api_endpoint", apiURL).
-			Int("sync_plans_collected", numCollectedSyncPlans).
-			Int("sync_plans_new", numNewSyncPlans).
-			Int("sync_plans_remaining", numSyncPlansRemaining).
-			Msg("Added decoded sync plans to collection")
+	for _, syncPlan := range sps {
+		evaluated = append(evaluated, syncPlan.Evaluate(now))
+	}
 
-		subLogger.Debug().
-			Msg("Determining if we have collected all sync plans from the API")
+	return evaluated
+}
 
-		remainingSyncPlans = numSyncPlansRemaining != 0
+// MarshalJSON implements the json.Marshaler interface. The sync plan is
+// marshaled along with derived values calculated using the evaluation
+// reference time. All time values are rendered in RFC 3339 format (UTC) for
+// the benefit of downstream consumers.
+func (esp EvaluatedSyncPlan) MarshalJSON() ([]byte, error) {
+	type exportProduct struct {
+		Product
+		LastSync string `json:"last_sync"`
 	}
 
-	subLogger.Debug().
-		Str("runtime_total", time.Since(funcTimeStart).String()).
-		Msg("Completed retrieval of all sync plans for organization")
-
-	return allSyncPlans, nil
+	products := make([]exportProduct, 0, len(esp.Products))
+	for _, product := range esp.Products {
+		products = append(products, exportProduct{
+			Product:  product,
+			LastSync: product.LastSync.RFC3339(),
+		})
+	}
 
+	// Fields at this level take precedence over the embedded sync plan
+	// fields of the same JSON name.
+	return json.Marshal(struct {
+		SyncPlan
+		OriginalSyncDate     string          `json:"sync_date"`
+		NextSync             string          `json:"next_sync"`
+		UpdatedAt            string          `json:"updated_at"`
+		CreatedAt            string          `json:"created_at"`
+		Products             []exportProduct `json:"products"`
+		NextSyncRFC3339      string          `json:"next_sync_rfc3339"`
+		EvaluatedAt          string          `json:"evaluated_at"`
+		DaysStuck            int             `json:"days_stuck"`
+		IsStuck              bool            `json:"is_stuck"`
+		IsDrifting           bool            `json:"is_drifting"`
+		IsOK                 bool            `json:"is_ok"`
+		CreatedSinceLastRun  bool            `json:"created_since_last_run"`
+		ModifiedSinceLastRun bool            `json:"modified_since_last_run"`
+	}{
+		SyncPlan:             esp.SyncPlan,
+		OriginalSyncDate:     esp.OriginalSyncDate.RFC3339(),
+		NextSync:             esp.NextSync.RFC3339(),
+		UpdatedAt:            esp.UpdatedAt.RFC3339(),
+		CreatedAt:            esp.CreatedAt.RFC3339(),
+		Products:             products,
+		NextSyncRFC3339:      esp.NextSync.RFC3339(),
+		EvaluatedAt:          formatRFC3339(esp.EvaluatedAt),
+		DaysStuck:            esp.DaysStuck(esp.EvaluatedAt),
+		IsStuck:              esp.IsStuck(esp.EvaluatedAt),
+		IsDrifting:           esp.IsDrifting(esp.EvaluatedAt),
+		IsOK:                 esp.IsOKState(esp.EvaluatedAt),
+		CreatedSinceLastRun:  esp.CreatedSinceLastRun(),
+		ModifiedSinceLastRun: esp.ModifiedSinceLastRun(),
+	})
 }
@@ -0,0 +1,645 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package rsat
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestSyncPlanIsDrifting(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+	nextSync := SyncTime(now.Add(12 * time.Hour))
+
+	daysAgo := func(days int) StandardAPITime {
+		return StandardAPITime(now.Add(-time.Duration(days) * 24 * time.Hour))
+	}
+
+	tests := []struct {
+		name     string
+		interval string
+		enabled  bool
+		drift    float64
+		products Products
+		want     bool
+	}{
+		{
+			name:     "daily plan with products synced 4 days ago",
+			interval: SyncPlanIntervalDaily,
+			enabled:  true,
+			drift:    2,
+			products: Products{{Name: "A", LastSync: daysAgo(4)}},
+			want:     true,
+		},
+		{
+			name:     "most recent product sync within limit",
+			interval: SyncPlanIntervalDaily,
+			enabled:  true,
+			drift:    2,
+			products: Products{
+				{Name: "A", LastSync: daysAgo(4)},
+				{Name: "B", LastSync: daysAgo(1)},
+			},
+			want: false,
+		},
+		{
+			name:     "excluded products are not considered",
+			interval: SyncPlanIntervalDaily,
+			enabled:  true,
+			drift:    2,
+			products: Products{
+				{Name: "A", LastSync: daysAgo(4)},
+				{Name: "B", LastSync: daysAgo(1), Excluded: true},
+			},
+			want: true,
+		},
+		{
+			name:     "weekly plan within limit",
+			interval: SyncPlanIntervalWeekly,
+			enabled:  true,
+			drift:    2,
+			products: Products{{Name: "A", LastSync: daysAgo(10)}},
+			want:     false,
+		},
+		{
+			name:     "evaluation disabled",
+			interval: SyncPlanIntervalDaily,
+			enabled:  true,
+			drift:    0,
+			products: Products{{Name: "A", LastSync: daysAgo(4)}},
+			want:     false,
+		},
+		{
+			name:     "disabled plan",
+			interval: SyncPlanIntervalDaily,
+			enabled:  false,
+			drift:    2,
+			products: Products{{Name: "A", LastSync: daysAgo(4)}},
+			want:     false,
+		},
+		{
+			name:     "custom cron interval",
+			interval: SyncPlanIntervalCustomCron,
+			enabled:  true,
+			drift:    2,
+			products: Products{{Name: "A", LastSync: daysAgo(4)}},
+			want:     false,
+		},
+		{
+			name:     "products never synced",
+			interval: SyncPlanIntervalDaily,
+			enabled:  true,
+			drift:    2,
+			products: Products{{Name: "A"}},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := SyncPlan{
+				Enabled:          tt.enabled,
+				Interval:         tt.interval,
+				NextSync:         nextSync,
+				Products:         tt.products,
+				MaxIntervalDrift: tt.drift,
+			}
+
+			if got := sp.IsDrifting(now); got != tt.want {
+				t.Errorf("IsDrifting() = %t, want %t", got, tt.want)
+			}
+
+			if got := sp.IsOKState(now); got == tt.want {
+				t.Errorf("IsOKState() = %t, want %t", got, !tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncPlanChangedSinceLastRun(t *testing.T) {
+	lastRun := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	before := StandardAPITime(lastRun.Add(-time.Hour))
+	after := StandardAPITime(lastRun.Add(time.Hour))
+
+	tests := []struct {
+		name         string
+		lastRun      time.Time
+		createdAt    StandardAPITime
+		updatedAt    StandardAPITime
+		wantCreated  bool
+		wantModified bool
+	}{
+		{
+			name:      "unchanged",
+			lastRun:   lastRun,
+			createdAt: before,
+			updatedAt: before,
+		},
+		{
+			name:         "modified",
+			lastRun:      lastRun,
+			createdAt:    before,
+			updatedAt:    after,
+			wantModified: true,
+		},
+		{
+			name:         "created",
+			lastRun:      lastRun,
+			createdAt:    after,
+			updatedAt:    after,
+			wantCreated:  true,
+			wantModified: true,
+		},
+		{
+			name:      "previous run unknown",
+			createdAt: after,
+			updatedAt: after,
+		},
+	}
+
+	for _, tt := range tests {
+		sp := SyncPlan{
+			LastRun:   tt.lastRun,
+			CreatedAt: tt.createdAt,
+			UpdatedAt: tt.updatedAt,
+		}
+
+		if got := sp.CreatedSinceLastRun(); got != tt.wantCreated {
+			t.Errorf("%s: CreatedSinceLastRun() = %t, want %t", tt.name, got, tt.wantCreated)
+		}
+
+		if got := sp.ModifiedSinceLastRun(); got != tt.wantModified {
+			t.Errorf("%s: ModifiedSinceLastRun() = %t, want %t", tt.name, got, tt.wantModified)
+		}
+	}
+}
+
+func TestOrganizationsSortBy(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	plan := func(name string, nextSync time.Duration) SyncPlan {
+		return SyncPlan{
+			Name:     name,
+			Enabled:  true,
+			Interval: SyncPlanIntervalDaily,
+			NextSync: SyncTime(now.Add(nextSync)),
+		}
+	}
+
+	unscheduled := SyncPlan{Name: "unscheduled", Interval: SyncPlanIntervalDaily}
+
+	newOrgs := func() Organizations {
+		return Organizations{
+			{
+				Name: "alpha",
+				SyncPlans: SyncPlans{
+					unscheduled,
+					plan("future", 6*time.Hour),
+					plan("stuck-12h", -12*time.Hour),
+				},
+			},
+			{
+				Name: "beta",
+				SyncPlans: SyncPlans{
+					plan("soon", time.Hour),
+					plan("stuck-3d", -72*time.Hour),
+				},
+			},
+			{
+				Name:      "gamma",
+				SyncPlans: SyncPlans{plan("later", 48*time.Hour)},
+			},
+		}
+	}
+
+	order := func(orgs Organizations) []string {
+		var names []string
+		for _, org := range orgs {
+			for _, syncPlan := range org.SyncPlans {
+				names = append(names, org.Name+"/"+syncPlan.Name)
+			}
+		}
+
+		return names
+	}
+
+	tests := []struct {
+		name string
+		sort func(Organizations)
+		want []string
+	}{
+		{
+			name: "plan name",
+			sort: func(orgs Organizations) { orgs.SortByPlanName() },
+			want: []string{
+				"alpha/future", "alpha/stuck-12h", "alpha/unscheduled",
+				"beta/soon", "beta/stuck-3d",
+				"gamma/later",
+			},
+		},
+		{
+			name: "days stuck",
+			sort: func(orgs Organizations) { orgs.SortByDaysStuck(now) },
+			want: []string{
+				"beta/stuck-3d", "beta/soon",
+				"alpha/stuck-12h", "alpha/future", "alpha/unscheduled",
+				"gamma/later",
+			},
+		},
+		{
+			name: "next sync",
+			sort: func(orgs Organizations) { orgs.SortByNextSync() },
+			want: []string{
+				"beta/stuck-3d", "beta/soon",
+				"alpha/stuck-12h", "alpha/future", "alpha/unscheduled",
+				"gamma/later",
+			},
+		},
+		{
+			name: "reversed organizations only",
+			sort: func(orgs Organizations) { orgs.Reverse(false) },
+			want: []string{
+				"gamma/later",
+				"beta/soon", "beta/stuck-3d",
+				"alpha/unscheduled", "alpha/future", "alpha/stuck-12h",
+			},
+		},
+		{
+			name: "reversed organizations and sync plans",
+			sort: func(orgs Organizations) { orgs.Reverse(true) },
+			want: []string{
+				"gamma/later",
+				"beta/stuck-3d", "beta/soon",
+				"alpha/stuck-12h", "alpha/future", "alpha/unscheduled",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		orgs := newOrgs()
+		tt.sort(orgs)
+
+		got := order(orgs)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got order %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyncTimeStringUsesDisplayLocation(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	SetDisplayLocation(chicago)
+	defer SetDisplayLocation(nil)
+
+	ts := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{
+			name: "sync time",
+			got:  SyncTime(ts).String(),
+			want: "2023-03-15 07:00:00 -0500",
+		},
+		{
+			name: "standard API time",
+			got:  StandardAPITime(ts).String(),
+			want: "2023-03-15 07:00:00 -0500",
+		},
+		{
+			name: "unscheduled sync time",
+			got:  SyncTime{}.String(),
+			want: "Not scheduled",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// aboveThreshold is a CountThreshold which is exceeded by values greater
+// than the threshold value.
+type aboveThreshold int
+
+func (t aboveThreshold) IsSet() bool { return true }
+
+func (t aboveThreshold) Exceeded(value float64) bool { return value > float64(t) }
+
+func TestOrganizationsServiceStateMapping(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+	created := SyncTime(now.Add(-90 * 24 * time.Hour))
+
+	stuck := SyncPlan{
+		Name:             "stuck",
+		Enabled:          true,
+		NextSync:         SyncTime(now.Add(-72 * time.Hour)),
+		OriginalSyncDate: created,
+	}
+
+	neverScheduled := SyncPlan{
+		Name:             "never-scheduled",
+		Enabled:          true,
+		OriginalSyncDate: created,
+	}
+
+	tests := []struct {
+		name       string
+		syncPlans  SyncPlans
+		thresholds StateThresholds
+		want       string
+	}{
+		{
+			name:      "stuck default",
+			syncPlans: SyncPlans{stuck},
+			want:      nagios.StateWARNINGLabel,
+		},
+		{
+			name:       "stuck critical",
+			syncPlans:  SyncPlans{stuck},
+			thresholds: StateThresholds{StuckState: nagios.StateCRITICALLabel},
+			want:       nagios.StateCRITICALLabel,
+		},
+		{
+			name:       "stuck below days stuck warning",
+			syncPlans:  SyncPlans{stuck},
+			thresholds: StateThresholds{DaysStuckWarning: aboveThreshold(5)},
+			want:       nagios.StateOKLabel,
+		},
+		{
+			name:       "stuck exceeds days stuck critical",
+			syncPlans:  SyncPlans{stuck},
+			thresholds: StateThresholds{DaysStuckCritical: aboveThreshold(2)},
+			want:       nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "never scheduled default",
+			syncPlans: SyncPlans{neverScheduled},
+			want:      nagios.StateWARNINGLabel,
+		},
+		{
+			name:       "never scheduled OK",
+			syncPlans:  SyncPlans{neverScheduled},
+			thresholds: StateThresholds{NeverScheduledState: nagios.StateOKLabel},
+			want:       nagios.StateOKLabel,
+		},
+		{
+			name:       "never scheduled critical",
+			syncPlans:  SyncPlans{neverScheduled},
+			thresholds: StateThresholds{NeverScheduledState: nagios.StateCRITICALLabel},
+			want:       nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "never scheduled excluded from stuck thresholds",
+			syncPlans: SyncPlans{stuck, neverScheduled},
+			thresholds: StateThresholds{
+				StuckState:          nagios.StateCRITICALLabel,
+				NeverScheduledState: nagios.StateOKLabel,
+			},
+			want: nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "never scheduled not subject to days stuck threshold",
+			syncPlans: SyncPlans{neverScheduled},
+			thresholds: StateThresholds{
+				DaysStuckWarning:    aboveThreshold(100),
+				NeverScheduledState: nagios.StateWARNINGLabel,
+			},
+			want: nagios.StateWARNINGLabel,
+		},
+		{
+			name: "empty org default",
+			want: nagios.StateOKLabel,
+		},
+		{
+			name:       "empty org warning",
+			thresholds: StateThresholds{EmptyOrgState: nagios.StateWARNINGLabel},
+			want:       nagios.StateWARNINGLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		orgs := Organizations{{Name: "org", SyncPlans: tt.syncPlans}}
+
+		if got := orgs.ServiceState(now, tt.thresholds).Label; got != tt.want {
+			t.Errorf("%s: ServiceState() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyncPlanUnmarshalJSONMinutePrecision(t *testing.T) {
+	tests := []struct {
+		name         string
+		nextSync     string
+		wantNextSync time.Time
+		wantMinute   bool
+	}{
+		{
+			name:         "legacy layout",
+			nextSync:     `"2023/03/15 12:00:00 +0000"`,
+			wantNextSync: time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC),
+			wantMinute:   true,
+		},
+		{
+			name:         "current layout",
+			nextSync:     `"2023-03-15 12:00:42 UTC"`,
+			wantNextSync: time.Date(2023, time.March, 15, 12, 0, 42, 0, time.UTC),
+			wantMinute:   false,
+		},
+		{
+			name:       "not scheduled",
+			nextSync:   `null`,
+			wantMinute: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := `{"id":1,"name":"daily","interval":"daily","enabled":true,"next_sync":` + tt.nextSync + `}`
+
+			var sp SyncPlan
+			if err := json.Unmarshal([]byte(payload), &sp); err != nil {
+				t.Fatalf("failed to decode sync plan: %v", err)
+			}
+
+			if got := time.Time(sp.NextSync); !got.Equal(tt.wantNextSync) {
+				t.Errorf("got next sync %v, want %v", got, tt.wantNextSync)
+			}
+
+			if got := sp.MinutePrecision; got != tt.wantMinute {
+				t.Errorf("MinutePrecision = %t, want %t", got, tt.wantMinute)
+			}
+		})
+	}
+}
+
+func TestSyncPlanIsStuckMinutePrecision(t *testing.T) {
+	nextSync := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+	grace := time.Duration(syncTimeGraceMinutes * float64(time.Minute))
+
+	tests := []struct {
+		name            string
+		now             time.Time
+		minutePrecision bool
+		wantStuck       bool
+	}{
+		{
+			name:            "seconds past grace time with minute precision",
+			now:             nextSync.Add(grace + 30*time.Second),
+			minutePrecision: true,
+			wantStuck:       false,
+		},
+		{
+			name:            "seconds past grace time without minute precision",
+			now:             nextSync.Add(grace + 30*time.Second),
+			minutePrecision: false,
+			wantStuck:       true,
+		},
+		{
+			name:            "minute past grace time with minute precision",
+			now:             nextSync.Add(grace + time.Minute + 30*time.Second),
+			minutePrecision: true,
+			wantStuck:       true,
+		},
+		{
+			name:            "within grace time without minute precision",
+			now:             nextSync.Add(grace - 30*time.Second),
+			minutePrecision: false,
+			wantStuck:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := SyncPlan{
+				Enabled:         true,
+				Interval:        SyncPlanIntervalDaily,
+				NextSync:        SyncTime(nextSync),
+				MinutePrecision: tt.minutePrecision,
+			}
+
+			if got := sp.IsStuck(tt.now); got != tt.wantStuck {
+				t.Errorf("IsStuck() = %t, want %t", got, tt.wantStuck)
+			}
+
+			// A sync plan stuck for less than a day reports zero days
+			// regardless of precision.
+			if got := sp.DaysStuck(tt.now); got != 0 {
+				t.Errorf("DaysStuck() = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestSyncPlanDaysStuckInactiveRecurringLogic(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+
+	tests := []struct {
+		name       string
+		interval   string
+		logicState string
+		nextSync   time.Time
+		products   Products
+		wantStuck  bool
+		wantDays   int
+		wantDaysHR string
+	}{
+		{
+			name:       "cancelled logic with future next sync",
+			interval:   SyncPlanIntervalDaily,
+			logicState: RecurringLogicStateCancelled,
+			nextSync:   now.Add(6 * time.Hour),
+			products:   Products{{Name: "A", LastSync: StandardAPITime(daysAgo(10))}},
+			wantStuck:  true,
+			wantDays:   9,
+			wantDaysHR: "9",
+		},
+		{
+			name:       "disabled logic with future next sync and weekly interval",
+			interval:   SyncPlanIntervalWeekly,
+			logicState: RecurringLogicStateDisabled,
+			nextSync:   now.Add(48 * time.Hour),
+			products:   Products{{Name: "A", LastSync: StandardAPITime(daysAgo(10))}},
+			wantStuck:  true,
+			wantDays:   3,
+			wantDaysHR: "3",
+		},
+		{
+			name:       "cancelled logic without synced products",
+			interval:   SyncPlanIntervalDaily,
+			logicState: RecurringLogicStateCancelled,
+			nextSync:   now.Add(6 * time.Hour),
+			products:   Products{{Name: "A"}},
+			wantStuck:  true,
+			wantDays:   20,
+			wantDaysHR: "20",
+		},
+		{
+			name:       "cancelled logic with past next sync",
+			interval:   SyncPlanIntervalDaily,
+			logicState: RecurringLogicStateCancelled,
+			nextSync:   daysAgo(4),
+			products:   Products{{Name: "A", LastSync: StandardAPITime(daysAgo(10))}},
+			wantStuck:  true,
+			wantDays:   4,
+			wantDaysHR: "4",
+		},
+		{
+			name:       "active logic with future next sync",
+			interval:   SyncPlanIntervalDaily,
+			logicState: RecurringLogicStateActive,
+			nextSync:   now.Add(6 * time.Hour),
+			products:   Products{{Name: "A", LastSync: StandardAPITime(daysAgo(10))}},
+			wantStuck:  false,
+			wantDays:   0,
+			wantDaysHR: "N/A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := SyncPlan{
+				Enabled:             true,
+				Interval:            tt.interval,
+				NextSync:            SyncTime(tt.nextSync),
+				OriginalSyncDate:    SyncTime(daysAgo(20)),
+				Products:            tt.products,
+				RecurringLogicState: tt.logicState,
+			}
+
+			if got := sp.IsStuck(now); got != tt.wantStuck {
+				t.Errorf("IsStuck() = %t, want %t", got, tt.wantStuck)
+			}
+
+			if got := sp.DaysStuck(now); got != tt.wantDays {
+				t.Errorf("DaysStuck() = %d, want %d", got, tt.wantDays)
+			}
+
+			if got := sp.DaysStuckHR(now); got != tt.wantDaysHR {
+				t.Errorf("DaysStuckHR() = %q, want %q", got, tt.wantDaysHR)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package schema provides the JSON Schema documents describing the
+// machine-readable output formats provided by this project. The schema
+// documents are versioned alongside the code so that downstream consumers
+// may validate and generate code against the output contract.
+package schema
+
+import (
+	_ "embed"
+)
+
+// Version is the version of the machine-readable output contract described
+// by the schema documents provided by this package. This value is
+// incremented whenever a backwards incompatible change is made to the
+// output formats (e.g., a field is removed or renamed).
+const Version string = "1"
+
+// syncPlanSchema is the JSON Schema for an evaluated sync plan.
+//
+//go:embed sync-plan.schema.json
+var syncPlanSchema []byte
+
+// reportSchema is the JSON Schema for the sync plans report.
+//
+//go:embed report.schema.json
+var reportSchema []byte
+
+// SyncPlan returns the JSON Schema for an evaluated sync plan as exported in
+// machine-readable output formats.
+func SyncPlan() []byte {
+	return append([]byte(nil), syncPlanSchema...)
+}
+
+// Report returns the JSON Schema for the machine-readable (JSON) sync plans
+// report. Sync plans within the report are described by the sync plan
+// schema.
+func Report() []byte {
+	return append([]byte(nil), reportSchema...)
+}
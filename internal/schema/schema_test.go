@@ -0,0 +1,117 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package schema
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-rsat/internal/rsat"
+)
+
+// objectSchema is the subset of a JSON Schema object definition used to
+// compare the documented fields against exported output.
+type objectSchema struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+	Defs       map[string]objectSchema    `json:"$defs"`
+}
+
+// assertFields asserts that the given exported object provides exactly the
+// fields documented (and required) by the given schema.
+func assertFields(t *testing.T, name string, s objectSchema, exported map[string]json.RawMessage) {
+	t.Helper()
+
+	var got []string
+	for field := range exported {
+		got = append(got, field)
+
+		if _, ok := s.Properties[field]; !ok {
+			t.Errorf("%s: exported field %q not documented in schema", name, field)
+		}
+	}
+
+	sort.Strings(got)
+	want := append([]string(nil), s.Required...)
+	sort.Strings(want)
+
+	if len(got) != len(want) {
+		t.Errorf("%s: exported fields %v do not match required schema fields %v", name, got, want)
+	}
+
+	for _, field := range s.Required {
+		if _, ok := exported[field]; !ok {
+			t.Errorf("%s: required schema field %q not exported", name, field)
+		}
+	}
+}
+
+// TestSyncPlanSchemaMatchesExportedFields asserts that the sync plan schema
+// documents exactly the fields exported for an evaluated sync plan so that
+// the schema and output contract do not drift apart.
+func TestSyncPlanSchemaMatchesExportedFields(t *testing.T) {
+	var s objectSchema
+	if err := json.Unmarshal(SyncPlan(), &s); err != nil {
+		t.Fatalf("failed to decode schema: %v", err)
+	}
+
+	syncPlan := rsat.SyncPlan{Products: rsat.Products{{Name: "RHEL"}}}
+
+	data, err := json.Marshal(syncPlan.Evaluate(time.Now()))
+	if err != nil {
+		t.Fatalf("failed to encode sync plan: %v", err)
+	}
+
+	var exported map[string]json.RawMessage
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("failed to decode exported sync plan: %v", err)
+	}
+
+	assertFields(t, "sync plan", s, exported)
+
+	var products []map[string]json.RawMessage
+	if err := json.Unmarshal(exported["products"], &products); err != nil {
+		t.Fatalf("failed to decode exported products: %v", err)
+	}
+
+	assertFields(t, "product", s.Defs["product"], products[0])
+}
+
+// TestReportSchemaIsValidJSON asserts that the report schema is a valid JSON
+// document and references the sync plan schema by its identifier.
+func TestReportSchemaIsValidJSON(t *testing.T) {
+	var s struct {
+		objectSchema
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal(Report(), &s); err != nil {
+		t.Fatalf("failed to decode report schema: %v", err)
+	}
+
+	var syncPlan struct {
+		ID string `json:"$id"`
+	}
+	if err := json.Unmarshal(SyncPlan(), &syncPlan); err != nil {
+		t.Fatalf("failed to decode sync plan schema: %v", err)
+	}
+
+	if !strings.HasSuffix(syncPlan.ID, "/sync-plan.schema.json") {
+		t.Errorf("got sync plan schema ID %q, want suffix /sync-plan.schema.json", syncPlan.ID)
+	}
+
+	if !strings.Contains(string(Report()), `"$ref": "sync-plan.schema.json"`) {
+		t.Error("report schema does not reference the sync plan schema")
+	}
+
+	if len(s.Required) == 0 || s.Defs["organization"].Properties == nil {
+		t.Error("report schema does not define required fields or organizations")
+	}
+}
@@ -7,7 +7,12 @@
 
 package textutils
 
-import "strings"
+import (
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
 
 // InList is a helper function to emulate Python's `if "x" in list:`
 // functionality. The caller can optionally ignore case of compared items.
@@ -29,3 +34,162 @@ func InList(needle string, haystack []string, ignoreCase bool) bool {
 
 	return false
 }
+
+// isRegexPattern indicates whether the given pattern is a regular expression
+// enclosed in forward slashes (e.g., "/^RHEL [78]/") instead of a shell-style
+// glob pattern.
+func isRegexPattern(pattern string) bool {
+	return len(pattern) >= 2 &&
+		strings.HasPrefix(pattern, "/") &&
+		strings.HasSuffix(pattern, "/")
+}
+
+// compileRegexPattern compiles the given regular expression pattern (enclosed
+// in forward slashes) for case-insensitive matching.
+func compileRegexPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+}
+
+// compiledPattern is a shell-style glob or regular expression pattern
+// prepared for repeated case-insensitive matching.
+type compiledPattern struct {
+	// raw is the pattern as specified.
+	raw string
+
+	// glob is the lowercase form of a shell-style glob pattern. This is
+	// empty for regular expression patterns.
+	glob string
+
+	// re is the compiled form of a regular expression pattern. This is nil
+	// for shell-style glob patterns.
+	re *regexp.Regexp
+
+	// invalid indicates that the pattern is malformed and never matches.
+	invalid bool
+}
+
+// compilePattern prepares the given shell-style glob or regular expression
+// pattern for repeated matching.
+func compilePattern(pattern string) compiledPattern {
+	cp := compiledPattern{raw: pattern}
+
+	if !isRegexPattern(pattern) {
+		cp.glob = strings.ToLower(pattern)
+		cp.invalid = ValidatePattern(pattern) != nil
+
+		return cp
+	}
+
+	re, err := compileRegexPattern(pattern)
+	if err != nil {
+		cp.invalid = true
+
+		return cp
+	}
+
+	cp.re = re
+
+	return cp
+}
+
+// matches indicates whether the given value matches the pattern.
+func (cp compiledPattern) matches(value string) bool {
+	switch {
+	case cp.invalid:
+		return false
+
+	case cp.re != nil:
+		return cp.re.MatchString(value)
+
+	default:
+		matched, err := path.Match(cp.glob, strings.ToLower(value))
+
+		return err == nil && matched
+	}
+}
+
+// PatternMatcher matches values against a collection of shell-style glob
+// patterns (e.g., "RHEL 7*") and regular expressions enclosed in forward
+// slashes (e.g., "/^RHEL [78]/"). Patterns are compiled once so that a
+// matcher may be used to efficiently evaluate many values. Values are
+// compared case-insensitively. Invalid patterns never match.
+type PatternMatcher struct {
+	patterns []compiledPattern
+}
+
+// NewPatternMatcher returns a PatternMatcher for the given shell-style glob
+// and regular expression patterns.
+func NewPatternMatcher(patterns []string) PatternMatcher {
+	compiled := make([]compiledPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, compilePattern(pattern))
+	}
+
+	return PatternMatcher{patterns: compiled}
+}
+
+// Match returns the first pattern (as specified) which matches any of the
+// given values. A boolean value is returned to indicate whether a match was
+// found.
+func (pm PatternMatcher) Match(values ...string) (string, bool) {
+	for _, pattern := range pm.patterns {
+		for _, value := range values {
+			if pattern.matches(value) {
+				return pattern.raw, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// MatchesPattern indicates whether the given value matches the given
+// shell-style glob pattern (e.g., "RHEL 7*") or regular expression enclosed
+// in forward slashes (e.g., "/^RHEL [78]/"). Values are compared
+// case-insensitively. An invalid pattern never matches.
+func MatchesPattern(value string, pattern string) bool {
+	return compilePattern(pattern).matches(value)
+}
+
+// MatchingPattern returns the first pattern from the given list of
+// shell-style glob or regular expression patterns which matches any of the
+// given values. A boolean value is returned to indicate whether a match was
+// found. Use a PatternMatcher to evaluate many values against the same
+// patterns.
+func MatchingPattern(patterns []string, values ...string) (string, bool) {
+	return NewPatternMatcher(patterns).Match(values...)
+}
+
+// ValidatePattern indicates whether the given shell-style glob pattern or
+// regular expression enclosed in forward slashes is valid. An error is
+// returned if the pattern is malformed.
+func ValidatePattern(pattern string) error {
+	if isRegexPattern(pattern) {
+		_, err := compileRegexPattern(pattern)
+
+		return err
+	}
+
+	_, err := path.Match(pattern, "")
+
+	return err
+}
+
+// Truncate shortens the given value to at most the given number of
+// characters, replacing the trailing characters with the given ellipsis if
+// the value is too long. The value is returned as-is if the limit is zero or
+// less.
+func Truncate(s string, limit int, ellipsis string) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	runes := []rune(s)
+
+	keep := limit - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return string(runes[:limit])
+	}
+
+	return string(runes[:keep]) + ellipsis
+}
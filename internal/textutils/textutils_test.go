@@ -0,0 +1,92 @@
+// Copyright 2023 Adam Chalkley
+//
+// https://github.com/atc0005/check-rsat
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package textutils
+
+import "testing"
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		pattern string
+		want    bool
+	}{
+		{name: "glob prefix", value: "RHEL 7 Server", pattern: "RHEL 7*", want: true},
+		{name: "glob no match", value: "RHEL 8 BaseOS", pattern: "RHEL 7*", want: false},
+		{name: "glob single character", value: "RHEL 8 BaseOS", pattern: "RHEL ? BaseOS", want: true},
+		{name: "glob case folding", value: "rhel 7 server", pattern: "RHEL 7*", want: true},
+		{name: "exact name", value: "EPEL", pattern: "epel", want: true},
+		{name: "regex", value: "RHEL 8 AppStream", pattern: "/^RHEL [78] /", want: true},
+		{name: "regex no match", value: "RHEL 9 AppStream", pattern: "/^RHEL [78] /", want: false},
+		{name: "regex unanchored", value: "legacy-epel", pattern: "/epel/", want: true},
+		{name: "regex case folding", value: "Legacy-Plan", pattern: "/^legacy-/", want: true},
+		{name: "invalid glob", value: "RHEL [7", pattern: "RHEL [7", want: false},
+		{name: "invalid regex", value: "RHEL (7", pattern: "/RHEL (7/", want: false},
+		{name: "single slash is glob", value: "/", pattern: "/", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesPattern(tt.value, tt.pattern); got != tt.want {
+				t.Errorf("MatchesPattern(%q, %q) = %t, want %t", tt.value, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternMatcherMatch(t *testing.T) {
+	patterns := []string{"/RHEL (7/", "Legacy*", "/^test-/"}
+
+	matcher := NewPatternMatcher(patterns)
+
+	tests := []struct {
+		name        string
+		values      []string
+		wantPattern string
+		wantOK      bool
+	}{
+		{name: "glob", values: []string{"legacy plan"}, wantPattern: "Legacy*", wantOK: true},
+		{name: "regex", values: []string{"Test-Plan"}, wantPattern: "/^test-/", wantOK: true},
+		{name: "any value", values: []string{"Red Hat Enterprise Linux", "test-label"}, wantPattern: "/^test-/", wantOK: true},
+		{name: "first pattern wins", values: []string{"legacy", "test-label"}, wantPattern: "Legacy*", wantOK: true},
+		{name: "no match", values: []string{"Production"}, wantOK: false},
+		{name: "no values", values: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, ok := matcher.Match(tt.values...)
+			if pattern != tt.wantPattern || ok != tt.wantOK {
+				t.Errorf("Match(%q) = %q, %t; want %q, %t", tt.values, pattern, ok, tt.wantPattern, tt.wantOK)
+			}
+
+			pattern, ok = MatchingPattern(patterns, tt.values...)
+			if pattern != tt.wantPattern || ok != tt.wantOK {
+				t.Errorf("MatchingPattern(%q) = %q, %t; want %q, %t", tt.values, pattern, ok, tt.wantPattern, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "RHEL 7*"},
+		{pattern: "/^RHEL [78] /"},
+		{pattern: "RHEL [7", wantErr: true},
+		{pattern: "/RHEL (7/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePattern(tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePattern(%q) = %v, want error: %t", tt.pattern, err, tt.wantErr)
+		}
+	}
+}